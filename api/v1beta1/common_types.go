@@ -16,7 +16,256 @@ limitations under the License.
 
 package v1beta1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 const (
-	RingConfigMapName = "swift-ring-files"
+	RingConfigMapName   = "swift-ring-files"
 	DeviceConfigMapName = "swift-storage-devices"
+
+	// RingAuditConfigMapName - history of the last ring changes, not owned
+	// by the SwiftRing so it survives its deletion
+	RingAuditConfigMapName = "swift-ring-audit"
+	// RingBackupSecretName - copy of the ring ConfigMap including the
+	// builder files, not owned by the SwiftRing so it survives its deletion
+	RingBackupSecretName = "swift-ring-backup"
+
+	// RingBackupOwnerAnnotation - UID of the Swift CR, or of a SwiftRing
+	// without one, whose rings are in the ring backup Secret. Only a
+	// matching CR restores them, the rings of a deleted cluster are not
+	// restored into a new one unless the UID is changed deliberately.
+	RingBackupOwnerAnnotation = "swift.openstack.org/ring-backup-owner"
+
+	// DryRunAnnotation - if set to "true" the controllers only report the
+	// changes they would make to the sub-resources instead of applying them.
+	// Set on a Swift CR it is passed on to its SwiftRing, SwiftStorage and
+	// SwiftProxy.
+	DryRunAnnotation = "swift.openstack.org/dry-run"
+
+	// HashChangeAnnotation - if set to "true" on the swift.conf Secret the
+	// hash path prefix and suffix of an existing cluster can be changed
+	HashChangeAnnotation = "swift.openstack.org/allow-hash-change"
+
+	// ScaleDownAnnotation - if set to "true" the storage replicas can be
+	// reduced and the devices of the removed pods are drained from the rings
+	// before the pods and their PVCs are removed. Without it the validating
+	// webhooks reject the change. Set on a Swift CR it is passed on to its
+	// SwiftStorage.
+	ScaleDownAnnotation = "swift.openstack.org/allow-scale-down"
+
+	// PropagatedLabelsAnnotation - labels of a child CR set from the Swift
+	// CR, they are removed from the child once removed from the Swift CR
+	PropagatedLabelsAnnotation = "swift.openstack.org/propagated-labels"
+
+	// PropagatedAnnotationsAnnotation - annotations of a child CR set from
+	// the Swift CR
+	PropagatedAnnotationsAnnotation = "swift.openstack.org/propagated-annotations"
 )
+
+// RingSyncStatus - ring version last synced by a pod
+type RingSyncStatus struct {
+	// Md5 of the ring tarball last extracted by the pod
+	Md5 string `json:"md5,omitempty"`
+
+	// Timestamp of the last ring sync
+	LastSync string `json:"lastSync,omitempty"`
+
+	// Timestamp of the oldest of the last completed account, container and
+	// object replication cycles
+	LastReplication string `json:"lastReplication,omitempty"`
+}
+
+// ContainerTermination - last failed termination of a container
+type ContainerTermination struct {
+	// Exit code of the container
+	ExitCode int32 `json:"exitCode"`
+
+	// Reason of the termination, e.g. Error or OOMKilled
+	Reason string `json:"reason,omitempty"`
+
+	// End of the termination message, the tail of the container log if the
+	// container did not write one
+	Message string `json:"message,omitempty"`
+
+	// Timestamp of the termination
+	FinishedAt string `json:"finishedAt,omitempty"`
+}
+
+// SecretStore - secrets mounted with the Secrets Store CSI driver instead of
+// a Secret, e.g. from Vault or AWS Secrets Manager
+type SecretStore struct {
+	// +kubebuilder:validation:Required
+	// SecretProviderClass - Name of the SecretProviderClass in the namespace
+	// defining the objects to mount
+	SecretProviderClass string `json:"secretProviderClass"`
+
+	// +kubebuilder:validation:Optional
+	// NodePublishSecretRef - Secret with the credentials of the provider, if
+	// it needs any
+	NodePublishSecretRef string `json:"nodePublishSecretRef,omitempty"`
+}
+
+// ContainerEnv - additional environment of a single container
+type ContainerEnv struct {
+	// +kubebuilder:validation:Optional
+	// Env - environment variables added to the container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// EnvFrom - sources of environment variables added to the container
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+}
+
+// ContainerResourceRecommendation - resources recommended for a container by
+// the VerticalPodAutoscaler
+type ContainerResourceRecommendation struct {
+	// Recommended resources
+	Target corev1.ResourceList `json:"target,omitempty"`
+
+	// Minimum recommended resources
+	LowerBound corev1.ResourceList `json:"lowerBound,omitempty"`
+
+	// Maximum recommended resources
+	UpperBound corev1.ResourceList `json:"upperBound,omitempty"`
+}
+
+// SwiftStoragePolicy - an erasure coding storage policy. Policy 0 stays the
+// default replication policy, the objects of a container are stored with
+// the policy selected by its X-Storage-Policy header.
+type SwiftStoragePolicy struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// Index - Index of the policy, its objects are stored with the
+	// object-<index> ring. It must never change while the policy is in use.
+	Index int32 `json:"index"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9-]+$`
+	// Name - Name of the policy used in the X-Storage-Policy header
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=liberasurecode_rs_vand
+	// ECType - Erasure coding backend of PyECLib
+	ECType string `json:"ecType,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// ECNumDataFragments - Number of data fragments of each object segment
+	ECNumDataFragments int32 `json:"ecNumDataFragments"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// ECNumParityFragments - Number of parity fragments of each object
+	// segment
+	ECNumParityFragments int32 `json:"ecNumParityFragments"`
+}
+
+// ExtraVolMounts - additional volumes mounted into the containers, like the
+// extraMounts of the other openstack-k8s-operators
+type ExtraVolMounts struct {
+	// +kubebuilder:validation:Optional
+	// Propagation - Containers the volumes are mounted into, by container
+	// name (e.g. object-server) or role group (e.g. servers). Empty mounts
+	// them into all containers, including the init containers.
+	Propagation []string `json:"propagation,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Volumes - Volumes added to the pods
+	Volumes []ExtraVolume `json:"volumes"`
+
+	// +kubebuilder:validation:Required
+	// Mounts - Mounts of the volumes added to the containers
+	Mounts []corev1.VolumeMount `json:"mounts"`
+}
+
+// ExtraVolume - a volume of the supported types, the full core Volume would
+// bloat the CRDs
+type ExtraVolume struct {
+	// +kubebuilder:validation:Required
+	// Name of the volume, referenced by the mounts
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	Secret *corev1.SecretVolumeSource `json:"secret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ConfigMap *corev1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	HostPath *corev1.HostPathVolumeSource `json:"hostPath,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	CSI *corev1.CSIVolumeSource `json:"csi,omitempty"`
+}
+
+// ToCoreVolume returns the core Volume of the extra volume
+func (v *ExtraVolume) ToCoreVolume() corev1.Volume {
+	c := v.DeepCopy()
+	return corev1.Volume{
+		Name: c.Name,
+		VolumeSource: corev1.VolumeSource{
+			Secret:                c.Secret,
+			ConfigMap:             c.ConfigMap,
+			EmptyDir:              c.EmptyDir,
+			HostPath:              c.HostPath,
+			PersistentVolumeClaim: c.PersistentVolumeClaim,
+			CSI:                   c.CSI,
+		},
+	}
+}
+
+// SwiftContainerSync defines the realms of the clusters containers can be
+// synced with
+type SwiftContainerSync struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Realms - Realms of clusters sharing a container sync key
+	Realms []SwiftContainerSyncRealm `json:"realms"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=1
+	// Interval - Minimum seconds between two container sync passes of a
+	// storage pod
+	Interval int32 `json:"interval,omitempty"`
+}
+
+// SwiftContainerSyncRealm is a realm of clusters sharing a container sync key
+type SwiftContainerSyncRealm struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	// Name of the realm, used in the X-Container-Sync-To header, e.g.
+	// //realm/cluster/account/container
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// KeySecret - Secret with the key of the realm and optionally key2, a
+	// second key accepted during a key rotation
+	KeySecret string `json:"keySecret"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Clusters of the realm, including this one
+	Clusters []SwiftContainerSyncCluster `json:"clusters"`
+}
+
+// SwiftContainerSyncCluster is a cluster of a container sync realm
+type SwiftContainerSyncCluster struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	// Name of the cluster
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// Endpoint - Storage URL of the cluster without the account, e.g.
+	// https://swift.example.com/v1/
+	Endpoint string `json:"endpoint"`
+}
@@ -29,6 +29,63 @@ const (
 
 	// SwiftProxyReadyCondition Status=True condition which indicates if the SwiftProxy is configured and operational
 	SwiftProxyReadyCondition condition.Type = "SwiftProxyReady"
+
+	// ClockSkewDetectedCondition Status=True condition which indicates that the clock of at least one pod differs from the API server clock
+	ClockSkewDetectedCondition condition.Type = "ClockSkewDetected"
+
+	// DriftDetectedCondition Status=True condition which indicates that a sub-resource was changed out-of-band
+	DriftDetectedCondition condition.Type = "DriftDetected"
+
+	// RollbackInProgressCondition Status=True condition which indicates that the images are rolled back to the last known-good set
+	RollbackInProgressCondition condition.Type = "RollbackInProgress"
+
+	// PreUpgradeCheckReadyCondition Status=True condition which indicates that the new images passed the pre-upgrade check
+	PreUpgradeCheckReadyCondition condition.Type = "PreUpgradeCheckReady"
+
+	// ConsistencyDivergenceDetectedCondition Status=True condition which indicates that the last consistency check found listings diverging from the object servers
+	ConsistencyDivergenceDetectedCondition condition.Type = "ConsistencyDivergenceDetected"
+
+	// ScaleDownInProgressCondition Status=True condition which indicates that storage pods are drained from the rings before they are removed
+	ScaleDownInProgressCondition condition.Type = "ScaleDownInProgress"
+
+	// DrainedCondition Status=True condition which indicates that the storage pods of drainOrdinals hold no more partitions
+	DrainedCondition condition.Type = "Drained"
+
+	// FeaturesUnavailableCondition Status=True condition which indicates that requested features are disabled in the operator or miss RBAC permissions
+	FeaturesUnavailableCondition condition.Type = "FeaturesUnavailable"
+
+	// RingUpdatePendingCondition Status=True condition which indicates that the rings do not include the devices of the device list yet
+	RingUpdatePendingCondition condition.Type = "RingUpdatePending"
+
+	// ScaleUpInProgressCondition Status=True condition which indicates that storage pods are added in batches
+	ScaleUpInProgressCondition condition.Type = "ScaleUpInProgress"
+
+	// HealthCondition Status=False condition which indicates that the last recon health check found lagging replication, unmounted drives or unreachable servers
+	HealthCondition condition.Type = "Health"
+
+	// RolloutStuckCondition Status=True condition which indicates that a StatefulSet rollout is stuck on a crash-looping pod
+	RolloutStuckCondition condition.Type = "RolloutStuck"
+
+	// ClusterReadOnlyCondition Status=True condition which indicates that the proxy rejects writes
+	ClusterReadOnlyCondition condition.Type = "ClusterReadOnly"
+
+	// BreakGlassEnabledCondition Status=True condition which indicates that the unauthenticated break-glass proxy pool is deployed
+	BreakGlassEnabledCondition condition.Type = "BreakGlassEnabled"
+
+	// ConsumerReadyCondition Status=True condition which indicates that a canary object was written and read back through the proxy, other operators wait for it before using Swift
+	ConsumerReadyCondition condition.Type = "ConsumerReady"
+
+	// DataVerificationCondition Status=True condition which indicates that a sample of the objects written before the last ring change was verified
+	DataVerificationCondition condition.Type = "DataVerification"
+
+	// StalledCondition Status=True condition which indicates that the SwiftStorage is not ready after its progress deadline
+	StalledCondition condition.Type = "Stalled"
+
+	// PartPowerNotAppliedCondition Status=True condition which indicates that the partPower of the spec can not be applied to the object rings
+	PartPowerNotAppliedCondition condition.Type = "PartPowerNotApplied"
+
+	// HashPathChangedCondition Status=True condition which indicates that the hash path prefix or suffix of swift.conf differs from the deployed one
+	HashPathChangedCondition condition.Type = "HashPathChanged"
 )
 
 // Common Messages used by API objects.
@@ -59,4 +116,215 @@ const (
 
 	// SwiftProxyReadyErrorMessage
 	SwiftProxyReadyErrorMessage = "SwiftProxy error occured %s"
+
+	//
+	// Ready condition messages
+	//
+	// InvalidSpecMessage
+	InvalidSpecMessage = "Invalid spec, rejected by the validating webhook: %s"
+
+	//
+	// ClockSkewDetected condition messages
+	//
+	// ClockSkewDetectedMessage
+	ClockSkewDetectedMessage = "Clock skew above %d seconds detected on pods: %s"
+
+	//
+	// DriftDetected condition messages
+	//
+	// DriftDetectedMessage
+	DriftDetectedMessage = "Out-of-band changes not reverted: %s"
+
+	//
+	// RollbackInProgress condition messages
+	//
+	// RollbackInProgressMessage
+	RollbackInProgressMessage = "Rolling back to images %s"
+
+	//
+	// PreUpgradeCheckReady condition messages
+	//
+	// PreUpgradeCheckReadyRunningMessage
+	PreUpgradeCheckReadyRunningMessage = "Pre-upgrade check running"
+
+	// PreUpgradeCheckReadyMessage
+	PreUpgradeCheckReadyMessage = "Pre-upgrade check passed"
+
+	// PreUpgradeCheckReadyErrorMessage
+	PreUpgradeCheckReadyErrorMessage = "Pre-upgrade check failed, new images not applied: %s"
+
+	//
+	// ConsistencyDivergenceDetected condition messages
+	//
+	// ConsistencyDivergenceDetectedMessage
+	ConsistencyDivergenceDetectedMessage = "Consistency check at %s found %d container count mismatches, %d missing and %d under-replicated objects"
+
+	//
+	// ScaleDownInProgress condition messages
+	//
+	// ScaleDownInProgressRingMessage
+	ScaleDownInProgressRingMessage = "Scaling down from %d to %d replicas, waiting for the rings to drain %s"
+
+	// ScaleDownInProgressReplicationMessage
+	ScaleDownInProgressReplicationMessage = "Scaling down from %d to %d replicas, waiting for replication to move the partitions off %s"
+
+	//
+	// Drained condition messages
+	//
+	// DrainedMessage
+	DrainedMessage = "Pods %s are drained and can be removed"
+
+	// DrainedRingMessage
+	DrainedRingMessage = "Waiting for the rings to drain %s"
+
+	// DrainedReplicationMessage
+	DrainedReplicationMessage = "Waiting for replication to move the partitions off %s"
+
+	//
+	// FeaturesUnavailable condition messages
+	//
+	// FeaturesUnavailableMessage
+	FeaturesUnavailableMessage = "Features not available: %s"
+
+	//
+	// RingUpdatePending condition messages
+	//
+	// RingUpdatePendingMessage
+	RingUpdatePendingMessage = "Waiting for the rings to match the device list: %s"
+
+	// RingUpdateQueuedMessage
+	RingUpdateQueuedMessage = "Waiting at position %d of the ring build queue of %s"
+
+	//
+	// PartPowerNotApplied condition messages
+	//
+	// PartPowerNotAppliedMessage
+	PartPowerNotAppliedMessage = "partPower %d is not applied, the object rings keep part power %d: %s"
+
+	//
+	// HashPathChanged condition messages
+	//
+	// HashPathChangedMessage
+	HashPathChangedMessage = "The hash path prefix or suffix of Secret %s changed, the stored objects would be unreachable. " +
+		"Restore the previous values or set the %s annotation to \"true\" on the Secret to deploy them"
+
+	//
+	// ScaleUpInProgress condition messages
+	//
+	// ScaleUpInProgressMessage
+	ScaleUpInProgressMessage = "Scaling up to %d replicas in batches of %d, %d replicas deployed"
+
+	//
+	// Health condition messages
+	//
+	// HealthMessage
+	HealthMessage = "Health check at %s passed"
+
+	// HealthErrorMessage
+	HealthErrorMessage = "Health check at %s failed: %s"
+
+	//
+	// RolloutStuck condition messages
+	//
+	// RolloutStuckMessage
+	RolloutStuckMessage = "Rollout to revision %s stuck, container %s of pod %s is crash-looping: %s"
+
+	// RolloutStuckRolledBackMessage
+	RolloutStuckRolledBackMessage = "Rolled back to revision %s, container %s of pod %s was crash-looping: %s"
+
+	//
+	// Stalled condition messages
+	//
+	// StalledMessage
+	StalledMessage = "Not ready after %d seconds: %s"
+
+	// StalledUnknownMessage
+	StalledUnknownMessage = "no known cause found, see the events of the storage pods"
+
+	//
+	// SwiftOperatorConfig Ready condition messages
+	//
+	// SwiftOperatorConfigAppliedMessage
+	SwiftOperatorConfigAppliedMessage = "Operator configuration applied"
+
+	// SwiftOperatorConfigIgnoredMessage
+	SwiftOperatorConfigIgnoredMessage = "Ignored, only the SwiftOperatorConfig named %s is used"
+
+	//
+	// ClusterReadOnly condition messages
+	//
+	// ClusterReadOnlyMessage
+	ClusterReadOnlyMessage = "Writes are rejected, writable accounts: %s"
+
+	//
+	// BreakGlassEnabled condition messages
+	//
+	// BreakGlassEnabledMessage
+	BreakGlassEnabledMessage = "WARNING: Service %s serves Swift without authentication to pods matching %s"
+
+	//
+	// ConsumerReady condition messages
+	//
+	// ConsumerReadyMessage
+	ConsumerReadyMessage = "Canary write succeeded"
+
+	// ConsumerReadyWaitingMessage
+	ConsumerReadyWaitingMessage = "Waiting for the proxy to run the canary write"
+
+	// ConsumerReadyRunningMessage
+	ConsumerReadyRunningMessage = "Canary write running"
+
+	// ConsumerReadyErrorMessage
+	ConsumerReadyErrorMessage = "Canary write failed, see the logs of Job %s"
+
+	//
+	// DataVerification condition messages
+	//
+	// DataVerificationMessage
+	DataVerificationMessage = "Sampled objects verified after the ring change"
+
+	// DataVerificationRunningMessage
+	DataVerificationRunningMessage = "Data verification running"
+
+	// DataVerificationErrorMessage
+	DataVerificationErrorMessage = "Data verification failed, see the logs of Job %s"
+
+	//
+	// SwiftDisk condition messages
+	//
+	// SwiftDiskAvailableMessage
+	SwiftDiskAvailableMessage = "PersistentVolume %s is available"
+
+	// SwiftDiskBoundMessage
+	SwiftDiskBoundMessage = "PersistentVolume %s is claimed by %s"
+
+	//
+	// SwiftAccount and SwiftContainer condition messages
+	//
+	// SwiftProxyWaitingMessage
+	SwiftProxyWaitingMessage = "Waiting for SwiftProxy %s to be ready"
+
+	// SwiftAccountRunningMessage
+	SwiftAccountRunningMessage = "Applying the account settings"
+
+	// SwiftAccountReadyMessage
+	SwiftAccountReadyMessage = "Account %s is set up"
+
+	//
+	// SwiftContainer condition messages
+	//
+	// SwiftContainerRunningMessage
+	SwiftContainerRunningMessage = "Applying the container settings"
+
+	// SwiftContainerReadyMessage
+	SwiftContainerReadyMessage = "Container %s/%s is set up"
+
+	// SwiftContainerLifecycleMessage
+	SwiftContainerLifecycleMessage = "The lifecycle of the container requires the lifecycle section of SwiftProxy %s"
+
+	//
+	// SwiftSecretGrant condition messages
+	//
+	// SwiftSecretGrantReadyMessage
+	SwiftSecretGrantReadyMessage = "Secrets copied to %d namespaces"
 )
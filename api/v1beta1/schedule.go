@@ -0,0 +1,106 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronRanges are the minimum and maximum values of the minute, hour, day of
+// month, month and day of week fields of a cron expression, 0 and 7 are
+// both Sunday
+var cronRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// CronSchedule is a parsed cron expression of the schedule windows
+type CronSchedule struct {
+	fields        [5]map[int]bool
+	dayRestricted [2]bool
+}
+
+// ParseSchedule parses a cron expression with 5 fields, supporting *,
+// lists, ranges and steps
+func ParseSchedule(schedule string) (*CronSchedule, error) {
+	parts := strings.Fields(schedule)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q, expected 5 fields", schedule)
+	}
+	s := &CronSchedule{dayRestricted: [2]bool{parts[2] != "*", parts[4] != "*"}}
+	for i, part := range parts {
+		values, err := parseCronField(part, cronRanges[i][0], cronRanges[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+		}
+		s.fields[i] = values
+	}
+	if s.fields[4][7] {
+		s.fields[4][0] = true
+	}
+	return s, nil
+}
+
+// parseCronField returns the values matched by a cron field
+func parseCronField(field string, min int, max int) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if r, s, found := strings.Cut(part, "/"); found {
+			var err error
+			if step, err = strconv.Atoi(s); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step in %q", field)
+			}
+			part = r
+		}
+		first, last := min, max
+		if part != "*" {
+			f, l, isRange := strings.Cut(part, "-")
+			var err error
+			if first, err = strconv.Atoi(f); err != nil {
+				return nil, fmt.Errorf("invalid value in %q", field)
+			}
+			last = first
+			if isRange {
+				if last, err = strconv.Atoi(l); err != nil {
+					return nil, fmt.Errorf("invalid range in %q", field)
+				}
+			}
+		}
+		if first < min || last > max || first > last {
+			return nil, fmt.Errorf("%q out of range %d-%d", field, min, max)
+		}
+		for v := first; v <= last; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// Matches returns true if the minute of t matches the schedule. Like cron,
+// either day field matches if both are restricted.
+func (s *CronSchedule) Matches(t time.Time) bool {
+	if !s.fields[0][t.Minute()] || !s.fields[1][t.Hour()] || !s.fields[3][int(t.Month())] {
+		return false
+	}
+	dom := s.fields[2][t.Day()]
+	dow := s.fields[4][int(t.Weekday())]
+	if s.dayRestricted[0] && s.dayRestricted[1] {
+		return dom || dow
+	}
+	return dom && dow
+}
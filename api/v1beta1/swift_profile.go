@@ -0,0 +1,144 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// swiftProfile are the tuned values of a profile
+type swiftProfile struct {
+	storageReplicas         int32
+	ringReplicas            int64
+	storageRequest          string
+	proxyReplicas           int32
+	autoscaling             *SwiftProxyAutoscaling
+	workers                 int32
+	nofileLimit             int64
+	resourceRecommendations bool
+	rsyncMetrics            bool
+	crashCollector          bool
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+var swiftProfiles = map[string]swiftProfile{
+	SwiftProfileSmall: {
+		storageReplicas: 3,
+		ringReplicas:    3,
+		storageRequest:  "10Gi",
+		proxyReplicas:   2,
+		workers:         2,
+	},
+	SwiftProfileMedium: {
+		storageReplicas: 5,
+		ringReplicas:    3,
+		storageRequest:  "100Gi",
+		autoscaling: &SwiftProxyAutoscaling{
+			MinReplicas:          3,
+			MaxReplicas:          6,
+			TargetCPUUtilization: int32Ptr(80),
+		},
+		workers:                 4,
+		nofileLimit:             65536,
+		resourceRecommendations: true,
+		rsyncMetrics:            true,
+	},
+	SwiftProfileLarge: {
+		storageReplicas: 10,
+		ringReplicas:    3,
+		storageRequest:  "1Ti",
+		autoscaling: &SwiftProxyAutoscaling{
+			MinReplicas:          5,
+			MaxReplicas:          15,
+			TargetCPUUtilization: int32Ptr(70),
+		},
+		workers:                 8,
+		nofileLimit:             131072,
+		resourceRecommendations: true,
+		rsyncMetrics:            true,
+		crashCollector:          true,
+	},
+}
+
+// ApplyProfile - replace the unset and defaulted fields with the values of
+// the profile. Nothing is changed without a profile or in aio mode. It is
+// only applied to new resources, the storage request and the ring replicas
+// can not be changed afterwards.
+func (spec *SwiftSpec) ApplyProfile() {
+	profile, ok := swiftProfiles[spec.Profile]
+	if !ok || spec.Mode == SwiftModeAIO {
+		return
+	}
+
+	// ring
+	if spec.SwiftRing.RingReplicas <= 1 {
+		spec.SwiftRing.RingReplicas = profile.ringReplicas
+	}
+
+	// storage
+	if spec.SwiftStorage.Replicas <= 1 {
+		spec.SwiftStorage.Replicas = profile.storageReplicas
+	}
+	if spec.SwiftStorage.StorageRequest == "" {
+		spec.SwiftStorage.StorageRequest = profile.storageRequest
+	}
+	if profile.resourceRecommendations {
+		spec.SwiftStorage.ResourceRecommendations = true
+	}
+	if profile.rsyncMetrics {
+		spec.SwiftStorage.RsyncMetrics = true
+	}
+	if profile.crashCollector && spec.SwiftStorage.CrashCollector == nil {
+		spec.SwiftStorage.CrashCollector = &SwiftStorageCrashCollector{
+			StorageRequest: "1Gi",
+			RetentionDays:  7,
+		}
+	}
+	if profile.nofileLimit > 0 {
+		spec.SwiftStorage.NofileLimits = mergeNofileLimits(spec.SwiftStorage.NofileLimits, profile.nofileLimit,
+			"account-server", "container-server", "object-server", "rsync")
+	}
+
+	// proxy
+	if spec.SwiftProxy.Autoscaling == nil && profile.autoscaling != nil {
+		spec.SwiftProxy.Autoscaling = profile.autoscaling.DeepCopy()
+	}
+	if spec.SwiftProxy.Replicas <= 1 && profile.proxyReplicas > 0 {
+		spec.SwiftProxy.Replicas = profile.proxyReplicas
+	}
+	if spec.SwiftProxy.Workers == 0 {
+		spec.SwiftProxy.Workers = profile.workers
+	}
+	if profile.nofileLimit > 0 {
+		spec.SwiftProxy.NofileLimits = mergeNofileLimits(spec.SwiftProxy.NofileLimits, profile.nofileLimit,
+			"proxy-server")
+	}
+}
+
+// mergeNofileLimits returns the limits with the given containers added if
+// they are not set yet
+func mergeNofileLimits(limits map[string]int64, limit int64, containers ...string) map[string]int64 {
+	result := map[string]int64{}
+	for k, v := range limits {
+		result[k] = v
+	}
+	for _, c := range containers {
+		if _, ok := result[c]; !ok {
+			result[c] = limit
+		}
+	}
+	return result
+}
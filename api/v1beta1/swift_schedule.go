@@ -0,0 +1,70 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+)
+
+// ApplyScalingSchedules - raise the proxy replicas and pause the background
+// daemons as requested by the active scaling schedule windows
+func (spec *SwiftSpec) ApplyScalingSchedules(active []string) {
+	isActive := map[string]bool{}
+	for _, name := range active {
+		isActive[name] = true
+	}
+
+	for _, s := range spec.ScalingSchedule {
+		if !isActive[s.Name] {
+			continue
+		}
+		if s.ProxyReplicas != nil {
+			if spec.SwiftProxy.Autoscaling != nil {
+				if spec.SwiftProxy.Autoscaling.MinReplicas < *s.ProxyReplicas {
+					spec.SwiftProxy.Autoscaling.MinReplicas = *s.ProxyReplicas
+				}
+				if spec.SwiftProxy.Autoscaling.MaxReplicas < *s.ProxyReplicas {
+					spec.SwiftProxy.Autoscaling.MaxReplicas = *s.ProxyReplicas
+				}
+			} else if spec.SwiftProxy.Replicas < *s.ProxyReplicas {
+				spec.SwiftProxy.Replicas = *s.ProxyReplicas
+			}
+		}
+		if s.PauseBackgroundDaemons {
+			spec.SwiftStorage.PauseBackgroundDaemons = true
+		}
+	}
+}
+
+// validateScalingSchedules - the windows are named in the status, their
+// schedules are parsed each reconcile
+func validateScalingSchedules(schedules []SwiftScalingSchedule) error {
+	names := map[string]bool{}
+	for _, s := range schedules {
+		if names[s.Name] {
+			return fmt.Errorf("scaling schedule %s is listed twice", s.Name)
+		}
+		names[s.Name] = true
+		if s.Duration.Duration <= 0 {
+			return fmt.Errorf("scaling schedule %s: duration must be positive, got %s", s.Name, s.Duration.Duration)
+		}
+		if _, err := ParseSchedule(s.Schedule); err != nil {
+			return fmt.Errorf("scaling schedule %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
@@ -29,6 +29,25 @@ const (
 	ContainerImageObject    = "quay.io/podified-antelope-centos9/openstack-swift-object:current-podified"
 	ContainerImageProxy     = "quay.io/podified-antelope-centos9/openstack-swift-proxy-server:current-podified"
 	ContainerImageMemcached = "quay.io/podified-antelope-centos9/openstack-memcached:current-podified"
+
+	// SwiftModeDefault deploys Swift for production use
+	SwiftModeDefault = "default"
+	// SwiftModeAIO deploys a single replica all-in-one Swift for development
+	SwiftModeAIO = "aio"
+
+	// SwiftProfileSmall - three storage and two proxy replicas
+	SwiftProfileSmall = "small"
+	// SwiftProfileMedium - five storage replicas and an autoscaled proxy
+	SwiftProfileMedium = "medium"
+	// SwiftProfileLarge - ten storage replicas, an autoscaled proxy and
+	// crash collection
+	SwiftProfileLarge = "large"
+
+	// PreUpgradeCheckHash hash
+	PreUpgradeCheckHash = "preupgradecheck"
+	// SwiftConfHashPathHash - hash of the swift_hash_path_prefix and
+	// swift_hash_path_suffix of the deployed swift.conf
+	SwiftConfHashPathHash = "swiftconfhashpath"
 )
 
 // SwiftSpec defines the desired state of Swift
@@ -47,14 +66,140 @@ type SwiftSpec struct {
 
 	// +kubebuilder:validation:Required
 	// +kubebuilder:default=swift-conf
-	// Name of Secret containing swift.conf
+	// Name of Secret containing swift.conf. A Secret of another namespace,
+	// granted by a SwiftSecretGrant, is referenced as <namespace>/<name>.
 	SwiftConfSecret string `json:"swiftConfSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SwiftConfSecretStore - Mount swift.conf with the Secrets Store CSI
+	// driver instead of the SwiftConfSecret, keeping the hash path prefix
+	// and suffix out of etcd. The SecretProviderClass must provide a
+	// swift.conf object, the SwiftConfSecret is not created then.
+	SwiftConfSecretStore *SecretStore `json:"swiftConfSecretStore,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=default
+	// +kubebuilder:validation:Enum=default;aio
+	// Mode - aio deploys a single storage replica with one ring replica and
+	// only the containers needed to serve requests, e.g. on kind or CRC for
+	// development. The storage request defaults to 1Gi in aio mode.
+	Mode string `json:"mode,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=small;medium;large
+	// Profile - Tuned defaults for the ring, storage and proxy, applied when
+	// the Swift is created. Fields that are unset or still have their
+	// default value take the value of the profile, fields set explicitly are
+	// kept. Can not be used in aio mode.
+	Profile string `json:"profile,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Rollback - Deploy the previous known-good images recorded in the
+	// status. Once all services are ready with them, the images are written
+	// to the spec and rollback is set back to false.
+	Rollback bool `json:"rollback,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=1
+	// UpgradeCheckReplicationLag - Maximum time in seconds since the last
+	// object replication pass of every storage server before new images are
+	// applied
+	UpgradeCheckReplicationLag int64 `json:"upgradeCheckReplicationLag,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=90
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// UpgradeCheckDiskUsage - Maximum disk usage in percent of every storage
+	// device before new images are applied
+	UpgradeCheckDiskUsage int32 `json:"upgradeCheckDiskUsage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ForceUpgrade - Apply new images without running the pre-upgrade check
+	ForceUpgrade bool `json:"forceUpgrade,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ScalingSchedule - Windows of predictable load, e.g. nightly batch
+	// ingests, during which the proxies are scaled up in advance and the
+	// background daemons of the storage pods are paused
+	ScalingSchedule []SwiftScalingSchedule `json:"scalingSchedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerSync - Realms of the clusters containers are synced with,
+	// passed on to the SwiftStorage and SwiftProxy
+	ContainerSync *SwiftContainerSync `json:"containerSync,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// MemcachedInstance - Name of a Memcached CR of the infra-operator shared
+	// by the SwiftProxy and SwiftStorage instead of a memcached container per
+	// pod
+	MemcachedInstance string `json:"memcachedInstance,omitempty"`
+}
+
+// SwiftScalingSchedule defines a recurring window of predictable load
+type SwiftScalingSchedule struct {
+	// +kubebuilder:validation:Required
+	// Name - Name of the window, reported in the status while it is active
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// Schedule - Start of the window as a cron expression in UTC, e.g.
+	// "0 1 * * *"
+	Schedule string `json:"schedule"`
+
+	// +kubebuilder:validation:Required
+	// Duration - Length of the window, e.g. "4h"
+	Duration metav1.Duration `json:"duration"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// ProxyReplicas - Proxy replicas during the window. The replicas in the
+	// spec, or the minimum replicas with autoscaling, are kept if they are
+	// higher.
+	ProxyReplicas *int32 `json:"proxyReplicas,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PauseBackgroundDaemons - Pause the replicators, auditors, updaters,
+	// the object expirer and reconstructor of the storage pods during the
+	// window
+	PauseBackgroundDaemons bool `json:"pauseBackgroundDaemons,omitempty"`
 }
 
 // SwiftStatus defines the observed state of Swift
 type SwiftStatus struct {
 	// Conditions
 	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// Images - Images per component of the last deployment where all
+	// services were ready
+	Images map[string]string `json:"images,omitempty"`
+
+	// ImageDigests - Images resolved to the digests run by the pods, the
+	// tag is kept for images not run by any pod
+	ImageDigests map[string]string `json:"imageDigests,omitempty"`
+
+	// PreviousImages - Known-good digests per component deployed before
+	// Images, used for rollback
+	PreviousImages map[string]string `json:"previousImages,omitempty"`
+
+	// RollbackImages - Images per component deployed while a rollback is
+	// in progress
+	RollbackImages map[string]string `json:"rollbackImages,omitempty"`
+
+	// UpgradeCheckedImages - Images per component that passed the
+	// pre-upgrade check
+	UpgradeCheckedImages map[string]string `json:"upgradeCheckedImages,omitempty"`
+
+	// Map of hashes to track e.g. job status
+	Hash map[string]string `json:"hash,omitempty"`
+
+	// ActiveSchedules - Names of the scaling schedule windows in effect
+	ActiveSchedules []string `json:"activeSchedules,omitempty"`
+
+	// PublicContainers - URLs of the containers readable without
+	// authentication
+	PublicContainers []string `json:"publicContainers,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -106,16 +251,25 @@ func (instance Swift) IsReady() bool {
 		instance.Status.Conditions.IsTrue(SwiftProxyReadyCondition)
 }
 
+// IsConsumerReady - returns true once a canary object was written and read
+// back through the proxy. Services storing data in Swift, e.g. Glance or
+// cinder-backup, should wait for it instead of the Ready condition.
+func (instance Swift) IsConsumerReady() bool {
+	return instance.Status.Conditions.IsTrue(ConsumerReadyCondition)
+}
+
 // SetupDefaults - initializes any CRD field defaults based on environment variables (the defaulting mechanism itself is implemented via webhooks)
 func SetupDefaults() {
-	// Acquire environmental defaults and initialize Swift defaults with them
-	swiftDefaults := SwiftDefaults{
+	SetupSwiftDefaults(getEnvDefaults())
+}
+
+// getEnvDefaults - acquire environmental defaults
+func getEnvDefaults() SwiftDefaults {
+	return SwiftDefaults{
 		AccountContainerImageURL:   util.GetEnvVar("SWIFT_ACCOUNT_IMAGE_URL_DEFAULT", ContainerImageAccount),
 		ContainerContainerImageURL: util.GetEnvVar("SWIFT_CONTAINER_IMAGE_URL_DEFAULT", ContainerImageContainer),
 		ObjectContainerImageURL:    util.GetEnvVar("SWIFT_OBJECT_IMAGE_URL_DEFAULT", ContainerImageObject),
 		ProxyContainerImageURL:     util.GetEnvVar("SWIFT_PROXY_IMAGE_URL_DEFAULT", ContainerImageProxy),
 		MemcachedContainerImageURL: util.GetEnvVar("SWIFT_MEMCACHED_IMAGE_URL_DEFAULT", ContainerImageMemcached),
 	}
-
-	SetupSwiftDefaults(swiftDefaults)
 }
@@ -17,8 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -30,9 +36,15 @@ type SwiftDefaults struct {
 	ObjectContainerImageURL		string
 	ProxyContainerImageURL		string
 	MemcachedContainerImageURL	string
+	// Metrics enabled in new SwiftStorages
+	RsyncMetrics	bool
+	StorageMetrics	bool
 }
 
-var swiftDefaults SwiftDefaults
+var (
+	swiftDefaults     SwiftDefaults
+	swiftDefaultsLock sync.RWMutex
+)
 
 
 
@@ -40,11 +52,25 @@ var swiftDefaults SwiftDefaults
 var swiftlog = logf.Log.WithName("swift-resource")
 
 func SetupSwiftDefaults(defaults SwiftDefaults) {
+	swiftDefaultsLock.Lock()
+	defer swiftDefaultsLock.Unlock()
 	swiftDefaults = defaults
 	swiftlog.Info("Swift defaults initialized", "defaults", defaults)
 }
 
+// getSwiftDefaults returns the defaults, they change when the
+// SwiftOperatorConfig is updated
+func getSwiftDefaults() SwiftDefaults {
+	swiftDefaultsLock.RLock()
+	defer swiftDefaultsLock.RUnlock()
+	return swiftDefaults
+}
+
+// webhookClient is used by the validations that look up other resources
+var webhookClient client.Client
+
 func (r *Swift) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	webhookClient = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -61,46 +87,35 @@ var _ webhook.Defaulter = &Swift{}
 func (r *Swift) Default() {
 	swiftlog.Info("default", "name", r.Name)
 
+	// The aio mode and the profile are applied before the defaults, to
+	// tell the unset fields from the ones set explicitly
+	if r.CreationTimestamp.IsZero() {
+		r.Spec.SwiftStorage.DefaultMetrics()
+		r.Spec.DefaultAIO()
+		r.Spec.ApplyProfile()
+	}
 	r.Spec.Default()
 }
 
-// Default - set defaults for this Swift spec
-func (spec *SwiftSpec) Default() {
-	// ring
-	if spec.SwiftRing.ContainerImage == "" {
-		spec.SwiftRing.ContainerImage = swiftDefaults.ProxyContainerImageURL
-	}
-
-	// storage
-	if spec.SwiftStorage.ContainerImageAccount == "" {
-		spec.SwiftStorage.ContainerImageAccount = swiftDefaults.AccountContainerImageURL
-	}
-
-	if spec.SwiftStorage.ContainerImageContainer == "" {
-		spec.SwiftStorage.ContainerImageContainer = swiftDefaults.ContainerContainerImageURL
-	}
-
-	if spec.SwiftStorage.ContainerImageObject == "" {
-		spec.SwiftStorage.ContainerImageObject = swiftDefaults.ObjectContainerImageURL
-	}
-
-
-	if spec.SwiftStorage.ContainerImageProxy == "" {
-		spec.SwiftStorage.ContainerImageProxy = swiftDefaults.ProxyContainerImageURL
-	}
-
-	if spec.SwiftStorage.ContainerImageMemcached == "" {
-		spec.SwiftStorage.ContainerImageMemcached = swiftDefaults.MemcachedContainerImageURL
+// DefaultAIO - set the defaults of the aio mode. The storage request is
+// only lowered for new resources, the PVC templates of the StatefulSet can
+// not be changed afterwards.
+func (spec *SwiftSpec) DefaultAIO() {
+	if spec.Mode != SwiftModeAIO {
+		return
 	}
-
-	// proxy
-	if spec.SwiftProxy.ContainerImageProxy == "" {
-		spec.SwiftProxy.ContainerImageProxy = swiftDefaults.ProxyContainerImageURL
+	if spec.SwiftStorage.StorageRequest == "" {
+		spec.SwiftStorage.StorageRequest = "1Gi"
 	}
+}
 
-	if spec.SwiftProxy.ContainerImageMemcached == "" {
-		spec.SwiftProxy.ContainerImageMemcached = swiftDefaults.MemcachedContainerImageURL
-	}
+// Default - set defaults for this Swift spec. The sections missing in a
+// minimal CR are added with the defaults of the SwiftRing, SwiftStorage and
+// SwiftProxy.
+func (spec *SwiftSpec) Default() {
+	spec.SwiftRing.Default()
+	spec.SwiftStorage.Default()
+	spec.SwiftProxy.Default()
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -112,7 +127,34 @@ var _ webhook.Validator = &Swift{}
 func (r *Swift) ValidateCreate() error {
 	swiftlog.Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+	if r.Spec.Rollback {
+		return fmt.Errorf("rollback requested but no previous known-good images are recorded")
+	}
+	if err := validateDeviceInventory(r.Namespace, r.Spec.SwiftStorage.DeviceInventory); err != nil {
+		return err
+	}
+	return validateSingleSwift(r.Namespace, r.Name)
+}
+
+// validateSingleSwift - only one Swift is supported per namespace, as they
+// would share the ring ConfigMap, the labels of the pods and the services
+func validateSingleSwift(namespace string, name string) error {
+	if webhookClient == nil {
+		return nil
+	}
+	swifts := &SwiftList{}
+	if err := webhookClient.List(context.TODO(), swifts, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for _, s := range swifts.Items {
+		if s.Name != name {
+			return fmt.Errorf("Swift %s already exists in namespace %s, only one Swift per namespace is supported",
+				s.Name, namespace)
+		}
+	}
 	return nil
 }
 
@@ -120,7 +162,143 @@ func (r *Swift) ValidateCreate() error {
 func (r *Swift) ValidateUpdate(old runtime.Object) error {
 	swiftlog.Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+	if err := validateDeviceInventory(r.Namespace, r.Spec.SwiftStorage.DeviceInventory); err != nil {
+		return err
+	}
+	oldSwift, ok := old.(*Swift)
+	if !ok {
+		return fmt.Errorf("expected a Swift, got %T", old)
+	}
+	if err := validateImmutableFields(&oldSwift.Spec, &r.Spec); err != nil {
+		return err
+	}
+	if r.Spec.Rollback && !oldSwift.Spec.Rollback && len(oldSwift.Status.PreviousImages) == 0 {
+		return fmt.Errorf("rollback requested but no previous known-good images are recorded")
+	}
+	return validateStorageScaleDown(oldSwift.Spec.SwiftStorage.Replicas, r.Spec.SwiftStorage.Replicas, r.Annotations)
+}
+
+// validateImmutableFields - the storage request is set in the PVC templates
+// of the StatefulSet, which can not be changed, and the rings are not
+// rebuilt for other ring replicas or by the Job from native rings
+func validateImmutableFields(old *SwiftSpec, spec *SwiftSpec) error {
+	if spec.SwiftStorage.StorageRequest != old.SwiftStorage.StorageRequest {
+		return fmt.Errorf("storageRequest can not be changed from %s to %s",
+			old.SwiftStorage.StorageRequest, spec.SwiftStorage.StorageRequest)
+	}
+	if spec.SwiftRing.RingReplicas != old.SwiftRing.RingReplicas {
+		return fmt.Errorf("ringReplicas can not be changed from %d to %d",
+			old.SwiftRing.RingReplicas, spec.SwiftRing.RingReplicas)
+	}
+	return validateRingBuilderUpdate(old.SwiftRing.RingBuilder, spec.SwiftRing.RingBuilder)
+}
+
+// Validate - validate the Swift spec. In aio mode the storage and the ring
+// replicas must be 1.
+func (spec *SwiftSpec) Validate() error {
+	if err := validateStoragePolicies(spec.SwiftRing.StoragePolicies); err != nil {
+		return err
+	}
+	if err := validateContainerSync(spec.ContainerSync); err != nil {
+		return err
+	}
+	if err := validateScalingSchedules(spec.ScalingSchedule); err != nil {
+		return err
+	}
+	if err := spec.SwiftRing.Validate(); err != nil {
+		return err
+	}
+	if err := spec.SwiftProxy.Validate(); err != nil {
+		return err
+	}
+	if err := validateSecretReferences(spec); err != nil {
+		return err
+	}
+	if spec.SwiftProxy.SortingMethod == "affinity" && spec.SwiftProxy.ReadAffinity == "" {
+		return fmt.Errorf("the affinity sortingMethod requires readAffinity")
+	}
+	if spec.Mode == SwiftModeAIO {
+		if spec.Profile != "" {
+			return fmt.Errorf("profile %s can not be used in aio mode", spec.Profile)
+		}
+		if spec.SwiftStorage.Replicas != 1 || spec.SwiftRing.RingReplicas != 1 {
+			return fmt.Errorf("aio mode requires 1 storage replica and 1 ring replica, got %d and %d",
+				spec.SwiftStorage.Replicas, spec.SwiftRing.RingReplicas)
+		}
+	}
+	return nil
+}
+
+// validateStoragePolicies - the index and the name of each storage policy
+// must be unique, Policy-0 is the default replication policy
+func validateStoragePolicies(policies []SwiftStoragePolicy) error {
+	indexes := map[int32]bool{}
+	names := map[string]bool{"policy-0": true}
+	for _, p := range policies {
+		if indexes[p.Index] {
+			return fmt.Errorf("duplicate storage policy index %d", p.Index)
+		}
+		if names[strings.ToLower(p.Name)] {
+			return fmt.Errorf("duplicate storage policy name %s", p.Name)
+		}
+		indexes[p.Index] = true
+		names[strings.ToLower(p.Name)] = true
+	}
+	return nil
+}
+
+// validateDeviceWeights - the device weights are keyed by <host>/<device>
+// and can not be negative
+func validateDeviceWeights(weights map[string]int64) error {
+	for name, weight := range weights {
+		host, device, found := strings.Cut(name, "/")
+		if !found || host == "" || device == "" {
+			return fmt.Errorf("invalid device %q in deviceWeights, expected <host>/<device>", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("negative weight %d of device %s", weight, name)
+		}
+	}
+	return nil
+}
+
+// validateFailedDevices - the failed devices are <host>/<device> entries
+func validateFailedDevices(devices []string) error {
+	for _, name := range devices {
+		host, device, found := strings.Cut(name, "/")
+		if !found || host == "" || device == "" {
+			return fmt.Errorf("invalid device %q in failedDevices, expected <host>/<device>", name)
+		}
+	}
+	return nil
+}
+
+// validateSecretReferences - Secrets of other namespaces are referenced as
+// <namespace>/<name>, cert-manager can not issue certificates into them
+func validateSecretReferences(spec *SwiftSpec) error {
+	refs := map[string]bool{spec.SwiftConfSecret: false}
+	if tls := spec.SwiftProxy.TLS; tls != nil {
+		refs[tls.SecretName] = tls.Issuer != ""
+		refs[tls.RouteSecretName] = tls.RouteIssuer != ""
+	}
+	if tls := spec.SwiftStorage.TLS; tls != nil {
+		refs[tls.SecretName] = tls.Issuer != ""
+	}
+	for ref, issued := range refs {
+		namespace, name, found := strings.Cut(ref, "/")
+		if !found {
+			continue
+		}
+		if namespace == "" || name == "" {
+			return fmt.Errorf("invalid Secret reference %q, expected <namespace>/<name>", ref)
+		}
+		if issued {
+			return fmt.Errorf("Secret %s of another namespace can not be issued by cert-manager", ref)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,265 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSwift(name string, spec SwiftSpec) *Swift {
+	spec.SwiftRing = newSwiftRing(name, spec.SwiftRing).Spec
+	spec.SwiftStorage = newSwiftStorage(name, spec.SwiftStorage).Spec
+	spec.SwiftProxy = newSwiftProxy(name, spec.SwiftProxy).Spec
+	return &Swift{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+}
+
+var _ = Describe("Swift webhook", func() {
+	Context("in aio mode", func() {
+		It("defaults a small storage request", func() {
+			spec := SwiftSpec{Mode: SwiftModeAIO}
+			spec.DefaultAIO()
+			Expect(spec.SwiftStorage.StorageRequest).To(Equal("1Gi"))
+
+			spec = SwiftSpec{Mode: SwiftModeAIO, SwiftStorage: SwiftStorageSpec{StorageRequest: "5Gi"}}
+			spec.DefaultAIO()
+			Expect(spec.SwiftStorage.StorageRequest).To(Equal("5Gi"))
+
+			spec = SwiftSpec{}
+			spec.DefaultAIO()
+			Expect(spec.SwiftStorage.StorageRequest).To(BeEmpty())
+		})
+
+		It("requires a single storage and ring replica", func() {
+			spec := SwiftSpec{
+				Mode:         SwiftModeAIO,
+				SwiftStorage: SwiftStorageSpec{Replicas: 1},
+				SwiftRing:    SwiftRingSpec{RingReplicas: 1},
+			}
+			Expect(spec.Validate()).To(Succeed())
+
+			spec.SwiftStorage.Replicas = 3
+			Expect(spec.Validate()).To(MatchError("aio mode requires 1 storage replica and 1 ring replica, got 3 and 1"))
+			spec.SwiftStorage.Replicas = 1
+			spec.SwiftRing.RingReplicas = 3
+			Expect(spec.Validate()).To(MatchError("aio mode requires 1 storage replica and 1 ring replica, got 1 and 3"))
+		})
+
+		It("rejects a Swift with 3 storage replicas", func() {
+			swift := newSwift("aio-swift", SwiftSpec{
+				Mode:         SwiftModeAIO,
+				SwiftStorage: SwiftStorageSpec{Replicas: 3},
+				SwiftRing:    SwiftRingSpec{RingReplicas: 1},
+				SwiftProxy:   SwiftProxySpec{Replicas: 1},
+			})
+			Expect(k8sClient.Create(ctx, swift)).To(
+				MatchError(ContainSubstring("aio mode requires 1 storage replica and 1 ring replica")))
+		})
+	})
+
+	Context("with scaling schedules", func() {
+		schedule := func(name string, cron string) SwiftScalingSchedule {
+			return SwiftScalingSchedule{Name: name, Schedule: cron, Duration: metav1.Duration{Duration: 2 * time.Hour}}
+		}
+
+		It("accepts windows with unique names", func() {
+			Expect(validateScalingSchedules(nil)).To(Succeed())
+			Expect(validateScalingSchedules([]SwiftScalingSchedule{
+				schedule("nightly", "0 1 * * *"),
+				schedule("weekend", "0 8 * * 6,0"),
+			})).To(Succeed())
+		})
+
+		It("rejects a name listed twice, a non-positive duration or an invalid schedule", func() {
+			Expect(validateScalingSchedules([]SwiftScalingSchedule{
+				schedule("nightly", "0 1 * * *"),
+				schedule("nightly", "0 2 * * *"),
+			})).To(MatchError("scaling schedule nightly is listed twice"))
+			Expect(validateScalingSchedules([]SwiftScalingSchedule{{Name: "nightly", Schedule: "0 1 * * *"}})).To(
+				MatchError("scaling schedule nightly: duration must be positive, got 0s"))
+			Expect(validateScalingSchedules([]SwiftScalingSchedule{schedule("nightly", "0 1 * * 8")})).To(
+				MatchError(ContainSubstring(`scaling schedule nightly: invalid schedule "0 1 * * 8"`)))
+		})
+
+		It("rejects a Swift with an invalid schedule", func() {
+			swift := newSwift("scheduled-swift", SwiftSpec{
+				ScalingSchedule: []SwiftScalingSchedule{schedule("nightly", "0 1 * *")},
+				SwiftStorage:    SwiftStorageSpec{Replicas: 1},
+				SwiftRing:       SwiftRingSpec{RingReplicas: 1},
+				SwiftProxy:      SwiftProxySpec{Replicas: 1},
+			})
+			Expect(k8sClient.Create(ctx, swift)).To(
+				MatchError(ContainSubstring(`scaling schedule nightly: invalid schedule "0 1 * *"`)))
+		})
+	})
+
+	Context("with a profile", func() {
+		It("changes nothing without a profile or in aio mode", func() {
+			spec := SwiftSpec{}
+			spec.ApplyProfile()
+			Expect(spec).To(Equal(SwiftSpec{}))
+
+			spec = SwiftSpec{Profile: SwiftProfileLarge, Mode: SwiftModeAIO}
+			spec.ApplyProfile()
+			Expect(spec.SwiftStorage.Replicas).To(BeZero())
+			Expect(spec.SwiftProxy.Autoscaling).To(BeNil())
+			Expect(spec.Validate()).To(MatchError("profile large can not be used in aio mode"))
+		})
+
+		It("applies the replicas and the workers of the small profile", func() {
+			spec := SwiftSpec{Profile: SwiftProfileSmall}
+			spec.ApplyProfile()
+			Expect(spec.SwiftStorage.Replicas).To(Equal(int32(3)))
+			Expect(spec.SwiftRing.RingReplicas).To(Equal(int64(3)))
+			Expect(spec.SwiftProxy.Replicas).To(Equal(int32(2)))
+			Expect(spec.SwiftStorage.StorageRequest).To(Equal("10Gi"))
+			Expect(spec.SwiftProxy.Workers).To(Equal(int32(2)))
+			Expect(spec.SwiftProxy.Autoscaling).To(BeNil())
+			Expect(spec.SwiftStorage.NofileLimits).To(BeNil())
+		})
+
+		It("keeps the fields set in the medium profile", func() {
+			spec := SwiftSpec{
+				Profile: SwiftProfileMedium,
+				SwiftStorage: SwiftStorageSpec{
+					Replicas:       7,
+					StorageRequest: "5Gi",
+					NofileLimits:   map[string]int64{"object-server": 1024},
+				},
+				SwiftProxy: SwiftProxySpec{Workers: 1},
+			}
+			spec.ApplyProfile()
+			Expect(spec.SwiftStorage.Replicas).To(Equal(int32(7)))
+			Expect(spec.SwiftStorage.StorageRequest).To(Equal("5Gi"))
+			Expect(spec.SwiftProxy.Workers).To(Equal(int32(1)))
+			Expect(spec.SwiftStorage.NofileLimits).To(Equal(map[string]int64{
+				"account-server":   65536,
+				"container-server": 65536,
+				"object-server":    1024,
+				"rsync":            65536,
+			}))
+			Expect(spec.SwiftProxy.Autoscaling.MinReplicas).To(Equal(int32(3)))
+			Expect(spec.SwiftProxy.Autoscaling.MaxReplicas).To(Equal(int32(6)))
+			Expect(spec.SwiftStorage.CrashCollector).To(BeNil())
+		})
+
+		It("enables the crash collector and the metrics of the large profile", func() {
+			spec := SwiftSpec{Profile: SwiftProfileLarge}
+			spec.ApplyProfile()
+			Expect(spec.SwiftStorage.Replicas).To(Equal(int32(10)))
+			Expect(spec.SwiftStorage.StorageRequest).To(Equal("1Ti"))
+			Expect(spec.SwiftStorage.CrashCollector).To(Equal(&SwiftStorageCrashCollector{
+				StorageRequest: "1Gi",
+				RetentionDays:  7,
+			}))
+			Expect(spec.SwiftStorage.ResourceRecommendations).To(BeTrue())
+			Expect(spec.SwiftStorage.RsyncMetrics).To(BeTrue())
+		})
+
+		It("copies the autoscaling of the profile", func() {
+			spec := SwiftSpec{Profile: SwiftProfileMedium}
+			spec.ApplyProfile()
+			spec.SwiftProxy.Autoscaling.MaxReplicas = 100
+			Expect(swiftProfiles[SwiftProfileMedium].autoscaling.MaxReplicas).To(Equal(int32(6)))
+		})
+	})
+
+	Context("with erasure coding storage policies", func() {
+		ec := func(index int32, name string) SwiftStoragePolicy {
+			return SwiftStoragePolicy{Index: index, Name: name, ECNumDataFragments: 4, ECNumParityFragments: 2}
+		}
+
+		It("accepts unique indexes and names", func() {
+			Expect(validateStoragePolicies(nil)).To(Succeed())
+			Expect(validateStoragePolicies([]SwiftStoragePolicy{ec(1, "ec42"), ec(2, "ec84")})).To(Succeed())
+		})
+
+		It("rejects a duplicate index or name", func() {
+			Expect(validateStoragePolicies([]SwiftStoragePolicy{ec(1, "ec42"), ec(1, "ec84")})).To(
+				MatchError("duplicate storage policy index 1"))
+			Expect(validateStoragePolicies([]SwiftStoragePolicy{ec(1, "ec42"), ec(2, "EC42")})).To(
+				MatchError("duplicate storage policy name EC42"))
+		})
+
+		It("reserves the name of the default replication policy", func() {
+			Expect(validateStoragePolicies([]SwiftStoragePolicy{ec(1, "Policy-0")})).To(
+				MatchError("duplicate storage policy name Policy-0"))
+		})
+
+		It("rejects a Swift with a duplicate storage policy index", func() {
+			swift := newSwift("ec-swift", SwiftSpec{
+				SwiftStorage: SwiftStorageSpec{Replicas: 1},
+				SwiftRing: SwiftRingSpec{
+					RingReplicas:    1,
+					StoragePolicies: []SwiftStoragePolicy{ec(1, "ec42"), ec(1, "ec84")},
+				},
+				SwiftProxy: SwiftProxySpec{Replicas: 1},
+			})
+			Expect(k8sClient.Create(ctx, swift)).To(MatchError(ContainSubstring("duplicate storage policy index 1")))
+		})
+	})
+
+	Context("with Secrets of another namespace", func() {
+		It("accepts <namespace>/<name> references", func() {
+			spec := &SwiftSpec{SwiftConfSecret: "openstack-secrets/swift-conf"}
+			spec.SwiftProxy.TLS = &SwiftProxyTLS{SecretName: "swift-proxy-tls", Issuer: "rootca-internal"}
+			spec.SwiftStorage.TLS = &SwiftStorageTLS{SecretName: "openstack-secrets/swift-storage-tls"}
+			Expect(validateSecretReferences(spec)).To(Succeed())
+		})
+
+		It("rejects a reference without a namespace or a name", func() {
+			for _, ref := range []string{"/swift-conf", "openstack-secrets/"} {
+				Expect(validateSecretReferences(&SwiftSpec{SwiftConfSecret: ref})).To(
+					MatchError(fmt.Sprintf("invalid Secret reference %q, expected <namespace>/<name>", ref)))
+			}
+		})
+
+		It("rejects a Secret of another namespace issued by cert-manager", func() {
+			spec := &SwiftSpec{}
+			spec.SwiftProxy.TLS = &SwiftProxyTLS{RouteSecretName: "openstack-secrets/swift-route-tls", RouteIssuer: "letsencrypt"}
+			Expect(validateSecretReferences(spec)).To(
+				MatchError("Secret openstack-secrets/swift-route-tls of another namespace can not be issued by cert-manager"))
+
+			spec = &SwiftSpec{}
+			spec.SwiftStorage.TLS = &SwiftStorageTLS{SecretName: "openstack-secrets/swift-storage-tls", Issuer: "rootca-internal"}
+			Expect(validateSecretReferences(spec)).To(
+				MatchError(ContainSubstring("can not be issued by cert-manager")))
+		})
+
+		It("rejects a Swift with a Secret reference without a name", func() {
+			swift := newSwift("granted-swift", SwiftSpec{
+				SwiftConfSecret: "openstack-secrets/",
+				SwiftStorage:    SwiftStorageSpec{Replicas: 1},
+				SwiftRing:       SwiftRingSpec{RingReplicas: 1},
+				SwiftProxy:      SwiftProxySpec{Replicas: 1},
+			})
+			Expect(k8sClient.Create(ctx, swift)).To(
+				MatchError(ContainSubstring(`invalid Secret reference "openstack-secrets/"`)))
+		})
+	})
+})
@@ -0,0 +1,132 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SwiftAccountHash hash of the last applied account settings
+	SwiftAccountHash = "swiftaccount"
+)
+
+// SwiftAccountSpec defines the desired state of SwiftAccount
+type SwiftAccountSpec struct {
+	// +kubebuilder:validation:Required
+	// SwiftProxy - Name of the SwiftProxy in the namespace whose internal
+	// client applies the account settings
+	SwiftProxy string `json:"swiftProxy"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]+$`
+	// ProjectID - ID of the Keystone project of the account, the account is
+	// AUTH_<projectID>
+	ProjectID string `json:"projectID"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// QuotaBytes - Maximum bytes stored in the account, unlimited if unset
+	QuotaBytes *int64 `json:"quotaBytes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// TempURLKeys - Generate the two temp URL keys of the account into the
+	// <name>-temp-url-keys Secret, with the key and key2 keys
+	TempURLKeys bool `json:"tempURLKeys,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ACL - Account ACL granting access to other Keystone users or projects,
+	// e.g. <project id>:<user id> or <project id>:*
+	ACL *SwiftAccountACL `json:"acl,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// S3Credentials - Create Keystone EC2 credentials of a user in the
+	// project for the S3 API into the <name>-s3-credentials Secret, with
+	// the access and secret keys. The service user requires the admin role.
+	S3Credentials *SwiftAccountS3Credentials `json:"s3Credentials,omitempty"`
+}
+
+// SwiftAccountACL defines the account ACL
+type SwiftAccountACL struct {
+	// +kubebuilder:validation:Optional
+	// ReadOnly - Users allowed to list and read the containers and objects
+	ReadOnly []string `json:"readOnly,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ReadWrite - Users allowed to create, change and delete the containers
+	// and objects
+	ReadWrite []string `json:"readWrite,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Admin - Users with the access of the account owner
+	Admin []string `json:"admin,omitempty"`
+}
+
+// SwiftAccountS3Credentials defines the EC2 credentials of the account
+type SwiftAccountS3Credentials struct {
+	// +kubebuilder:validation:Required
+	// UserID - ID of the Keystone user of the credentials, it requires a
+	// role in the project
+	UserID string `json:"userID"`
+}
+
+// SwiftAccountStatus defines the observed state of SwiftAccount
+type SwiftAccountStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// Map of hashes to track e.g. job status
+	Hash map[string]string `json:"hash,omitempty"`
+
+	// Account - Name of the Swift account
+	Account string `json:"account,omitempty"`
+
+	// ObservedGeneration - generation of the spec applied to the account
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Account",type="string",JSONPath=".status.account",description="Account"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// SwiftAccount is the Schema for the swiftaccounts API. It creates the
+// account of a Keystone project with its quota, temp URL keys, ACL and S3
+// credentials. Deleting it keeps the account and its data.
+type SwiftAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwiftAccountSpec   `json:"spec,omitempty"`
+	Status SwiftAccountStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwiftAccountList contains a list of SwiftAccount
+type SwiftAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwiftAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwiftAccount{}, &SwiftAccountList{})
+}
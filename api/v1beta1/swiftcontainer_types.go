@@ -0,0 +1,140 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SwiftContainerHash hash of the last applied container settings
+	SwiftContainerHash = "swiftcontainer"
+)
+
+// SwiftContainerSpec defines the desired state of SwiftContainer
+type SwiftContainerSpec struct {
+	// +kubebuilder:validation:Required
+	// SwiftProxy - Name of the SwiftProxy in the namespace whose internal
+	// client creates the container
+	SwiftProxy string `json:"swiftProxy"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^AUTH_[0-9a-f]+$`
+	// Account - Account of the container, e.g. AUTH_<project id>. It is
+	// created if missing.
+	Account string `json:"account"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[^/]+$`
+	// +kubebuilder:validation:MaxLength=256
+	// ContainerName - Name of the container
+	ContainerName string `json:"containerName"`
+
+	// +kubebuilder:validation:Optional
+	// StoragePolicy - Storage policy of the container, the default policy if
+	// unset. The policy of an existing container can not be changed.
+	StoragePolicy string `json:"storagePolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ReadACL - Container read ACL, e.g. .r:*,.rlistings for public
+	// containers or <project id>:<user id>
+	ReadACL string `json:"readACL,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// WriteACL - Container write ACL, e.g. <project id>:*
+	WriteACL string `json:"writeACL,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// VersionsContainer - Keep the previous versions of the overwritten
+	// objects in this container of the account, it is created with the
+	// policy of the container
+	VersionsContainer string `json:"versionsContainer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// QuotaBytes - Maximum bytes stored in the container
+	QuotaBytes *int64 `json:"quotaBytes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// QuotaCount - Maximum number of objects in the container
+	QuotaCount *int64 `json:"quotaCount,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Lifecycle - Expiration of the new objects of the container, applied by
+	// the lifecycle middleware of the SwiftProxy
+	Lifecycle *SwiftContainerLifecycle `json:"lifecycle,omitempty"`
+}
+
+// SwiftContainerLifecycle defines the expiration of the new objects of a
+// container. It is stored in the system metadata of the container, existing
+// objects are not changed.
+type SwiftContainerLifecycle struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// ExpireAfterDays - Days after which new objects expire
+	ExpireAfterDays int32 `json:"expireAfterDays,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DeleteAt - Time at which all new objects expire, it takes precedence
+	// over ExpireAfterDays until it passed
+	DeleteAt *metav1.Time `json:"deleteAt,omitempty"`
+}
+
+// SwiftContainerStatus defines the observed state of SwiftContainer
+type SwiftContainerStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// Map of hashes to track e.g. job status
+	Hash map[string]string `json:"hash,omitempty"`
+
+	// ObservedGeneration - generation of the spec applied to the container
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Account",type="string",JSONPath=".spec.account",description="Account"
+//+kubebuilder:printcolumn:name="Container",type="string",JSONPath=".spec.containerName",description="Container"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// SwiftContainer is the Schema for the swiftcontainers API. It creates a
+// container with its policy, ACLs, versioning and quota. Deleting it keeps
+// the container and its objects.
+type SwiftContainer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwiftContainerSpec   `json:"spec,omitempty"`
+	Status SwiftContainerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwiftContainerList contains a list of SwiftContainer
+type SwiftContainerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwiftContainer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwiftContainer{}, &SwiftContainerList{})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SwiftDiskLabel is the label of the PersistentVolume of a SwiftDisk
+	// with the name of the SwiftDisk
+	SwiftDiskLabel = "swift.openstack.org/disk"
+	// SwiftDiskNamespaceLabel is the label of the PersistentVolume of a
+	// SwiftDisk with the namespace of the SwiftDisk
+	SwiftDiskNamespaceLabel = "swift.openstack.org/disk-namespace"
+)
+
+// SwiftDiskSpec defines the desired state of SwiftDisk
+type SwiftDiskSpec struct {
+	// +kubebuilder:validation:Required
+	// NodeName - Node the disk is attached to
+	NodeName string `json:"nodeName"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^/`
+	// Path - Mount point of the formatted disk on the node, e.g.
+	// /mnt/swift/nvme0n1. The disk is mounted by the storage pod claiming it.
+	Path string `json:"path"`
+
+	// +kubebuilder:validation:Required
+	// Capacity - Capacity of the disk
+	Capacity resource.Quantity `json:"capacity"`
+
+	// +kubebuilder:validation:Required
+	// StorageClass - StorageClass of the local PersistentVolume of the disk,
+	// the storageClass of the SwiftStorage claiming it
+	StorageClass string `json:"storageClass"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// Region - Ring region of the disk
+	Region int32 `json:"region,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// Zone - Ring zone of the disk
+	Zone int32 `json:"zone,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Weight - Ring weight of the disk. Defaults to the capacity in GB.
+	Weight *int64 `json:"weight,omitempty"`
+}
+
+// SwiftDiskStatus defines the observed state of SwiftDisk
+type SwiftDiskStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// VolumeName - PersistentVolume of the disk
+	VolumeName string `json:"volumeName,omitempty"`
+
+	// ClaimName - PersistentVolumeClaim of the storage pod using the disk
+	ClaimName string `json:"claimName,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName",description="Node"
+//+kubebuilder:printcolumn:name="Path",type="string",JSONPath=".spec.path",description="Path"
+//+kubebuilder:printcolumn:name="Claim",type="string",JSONPath=".status.claimName",description="Claim"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+
+// SwiftDisk is the Schema for the swiftdisks API. It describes a local disk
+// of a node, which is offered to the SwiftStorages with its StorageClass as
+// a local PersistentVolume and added to the rings with its region, zone and
+// weight.
+type SwiftDisk struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwiftDiskSpec   `json:"spec,omitempty"`
+	Status SwiftDiskStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwiftDiskList contains a list of SwiftDisk
+type SwiftDiskList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwiftDisk `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwiftDisk{}, &SwiftDiskList{})
+}
+
+// GetWeight returns the ring weight of the disk
+func (spec *SwiftDiskSpec) GetWeight() int64 {
+	if spec.Weight != nil {
+		return *spec.Weight
+	}
+	return spec.Capacity.Value() / (1000 * 1000 * 1000)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwiftOperatorConfigName is the name of the SwiftOperatorConfig used by the
+// operator, SwiftOperatorConfigs with other names are ignored
+const SwiftOperatorConfigName = "cluster"
+
+// SwiftOperatorConfigSpec defines the desired state of SwiftOperatorConfig
+type SwiftOperatorConfigSpec struct {
+	// +kubebuilder:validation:Optional
+	// DefaultImages - Images used for the image fields missing in new
+	// resources. Images not set here default to the
+	// SWIFT_*_IMAGE_URL_DEFAULT environment variables of the operator.
+	DefaultImages SwiftOperatorConfigImages `json:"defaultImages,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// RequeueInterval - Seconds between the reconciles while waiting for a
+	// dependency, e.g. a Secret, a Job or the Keystone endpoints
+	RequeueInterval int32 `json:"requeueInterval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// ResyncInterval - Seconds between the reconciles of resources waiting
+	// for their pods, e.g. while the storage pods are drained or rolled out
+	ResyncInterval int32 `json:"resyncInterval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Features - Disable optional features. Features disabled with the
+	// operator flags can not be enabled here, as their watches are only set
+	// up at start.
+	Features SwiftOperatorConfigFeatures `json:"features,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Metrics - Metrics enabled in new resources
+	Metrics SwiftOperatorConfigMetrics `json:"metrics,omitempty"`
+}
+
+// SwiftOperatorConfigImages are the default images of the operator
+type SwiftOperatorConfigImages struct {
+	// +kubebuilder:validation:Optional
+	// Account - Image URL for Swift account service
+	Account string `json:"account,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Container - Image URL for Swift container service
+	Container string `json:"container,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Object - Image URL for Swift object service
+	Object string `json:"object,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Proxy - Image URL for Swift proxy service, also used for the rings
+	Proxy string `json:"proxy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Memcached - Image URL for Memcache service
+	Memcached string `json:"memcached,omitempty"`
+}
+
+// SwiftOperatorConfigFeatures are the optional features of the operator
+type SwiftOperatorConfigFeatures struct {
+	// +kubebuilder:validation:Optional
+	// Routes - Expose the public endpoint with an OpenShift Route
+	Routes *bool `json:"routes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Autoscaling - Create HorizontalPodAutoscalers for the proxy
+	Autoscaling *bool `json:"autoscaling,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// KeystoneEndpoints - Register the service and its endpoints in Keystone
+	KeystoneEndpoints *bool `json:"keystoneEndpoints,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// KEDA - Scale the object expirers with KEDA ScaledObjects
+	KEDA *bool `json:"keda,omitempty"`
+}
+
+// SwiftOperatorConfigMetrics are the metrics enabled in new SwiftStorages
+type SwiftOperatorConfigMetrics struct {
+	// +kubebuilder:validation:Optional
+	// RsyncMetrics - Enable rsyncMetrics in new SwiftStorages
+	RsyncMetrics bool `json:"rsyncMetrics,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// StorageMetrics - Enable storageMetrics in new SwiftStorages
+	StorageMetrics bool `json:"storageMetrics,omitempty"`
+}
+
+// SwiftOperatorConfigStatus defines the observed state of SwiftOperatorConfig
+type SwiftOperatorConfigStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// ObservedGeneration - generation of the spec applied by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// SwiftOperatorConfig is the Schema for the swiftoperatorconfigs API. Only the
+// one named cluster is used.
+type SwiftOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwiftOperatorConfigSpec   `json:"spec,omitempty"`
+	Status SwiftOperatorConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwiftOperatorConfigList contains a list of SwiftOperatorConfig
+type SwiftOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwiftOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwiftOperatorConfig{}, &SwiftOperatorConfigList{})
+}
+
+// GetSwiftDefaults returns the defaults of the webhooks, the images missing
+// in the spec are taken from the environment
+func (spec *SwiftOperatorConfigSpec) GetSwiftDefaults() SwiftDefaults {
+	defaults := getEnvDefaults()
+	images := []struct {
+		image string
+		field *string
+	}{
+		{spec.DefaultImages.Account, &defaults.AccountContainerImageURL},
+		{spec.DefaultImages.Container, &defaults.ContainerContainerImageURL},
+		{spec.DefaultImages.Object, &defaults.ObjectContainerImageURL},
+		{spec.DefaultImages.Proxy, &defaults.ProxyContainerImageURL},
+		{spec.DefaultImages.Memcached, &defaults.MemcachedContainerImageURL},
+	}
+	for _, i := range images {
+		if i.image != "" {
+			*i.field = i.image
+		}
+	}
+	defaults.RsyncMetrics = spec.Metrics.RsyncMetrics
+	defaults.StorageMetrics = spec.Metrics.StorageMetrics
+	return defaults
+}
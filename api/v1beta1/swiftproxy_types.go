@@ -18,9 +18,29 @@ package v1beta1
 
 import (
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// AccountPoliciesHash hash
+	AccountPoliciesHash = "accountpolicies"
+	// CertificateHash hash of the proxy certificate Secret
+	CertificateHash = "certificate"
+	// ReadOnlyAccountsHash hash of the writable accounts of the read-only mode
+	ReadOnlyAccountsHash = "readonlyaccounts"
+	// PublicContainersHash hash
+	PublicContainersHash = "publiccontainers"
+	// CanaryHash hash of the last passed canary write
+	CanaryHash = "canary"
+	// DataVerificationHash hash of the last passed data verification
+	DataVerificationHash = "dataverification"
+	// BreakGlassPoolName is the name of the proxy pool without auth, it can
+	// not be used by the pools of the spec
+	BreakGlassPoolName = "break-glass"
+)
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
@@ -69,6 +89,527 @@ type SwiftProxySpec struct {
 	// +kubebuilder:default=swift-conf
 	// Name of Secret containing swift.conf
 	SwiftConfSecret string `json:"swiftConfSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SwiftConfSecretStore - Mount swift.conf with the Secrets Store CSI
+	// driver instead of the SwiftConfSecret. The SecretProviderClass must
+	// provide a swift.conf object.
+	SwiftConfSecretStore *SecretStore `json:"swiftConfSecretStore,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CredentialsSecretStore - Mount the service password with the Secrets
+	// Store CSI driver instead of rendering it from the Secret into the proxy
+	// config. The SecretProviderClass must provide an object named like the
+	// service password selector. The KeystoneService still reads the Secret.
+	CredentialsSecretStore *SecretStore `json:"credentialsSecretStore,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Autoscaling - Scale the proxy with a HorizontalPodAutoscaler instead
+	// of a fixed number of replicas
+	Autoscaling *SwiftProxyAutoscaling `json:"autoscaling,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerEnv - Additional environment per container, keyed by the
+	// container name (e.g. proxy-server)
+	ContainerEnv map[string]ContainerEnv `json:"containerEnv,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NofileLimits - Minimum open file limit per container, keyed by the
+	// container name (e.g. proxy-server). The container refuses to start if
+	// the limit can not be raised to this value.
+	NofileLimits map[string]int64 `json:"nofileLimits,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Resources - Resource requests and limits per container, keyed by the
+	// container name (e.g. proxy-server) or by the role group (e.g. servers)
+	Resources map[string]corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ExtraMounts - Additional volumes mounted into the proxy pods, including
+	// the pools, e.g. CA bundles, Secrets or debugging tools
+	ExtraMounts []ExtraVolMounts `json:"extraMounts,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// ClockSkewThreshold - Maximum difference in seconds between the clock
+	// of a pod and the API server before ClockSkewDetected is set
+	ClockSkewThreshold int64 `json:"clockSkewThreshold,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Workers - Number of proxy-server worker processes per pod, 0 uses the
+	// Swift default of one worker per CPU core
+	Workers int32 `json:"workers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// ErrorSuppressionInterval - Seconds a storage node is not used after
+	// ErrorSuppressionLimit errors, 0 uses the Swift default of 60
+	ErrorSuppressionInterval int32 `json:"errorSuppressionInterval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// ErrorSuppressionLimit - Number of errors after which a storage node is
+	// error limited, 0 uses the Swift default of 10
+	ErrorSuppressionLimit int32 `json:"errorSuppressionLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ConcurrentGets - Send GET requests to the next replica after
+	// ConcurrencyTimeout instead of waiting for a slow storage node
+	ConcurrentGets bool `json:"concurrentGets,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// ConcurrencyTimeout - Seconds to wait for a storage node before the
+	// next concurrent GET is sent, defaults to the Swift conn_timeout
+	ConcurrencyTimeout string `json:"concurrencyTimeout,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// S3API - Enable the s3api and s3token middlewares so the proxy also
+	// serves the S3 API. Requests are authenticated with Keystone EC2
+	// credentials.
+	S3API bool `json:"s3API,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=shuffle
+	// +kubebuilder:validation:Enum=shuffle;timing;affinity
+	// SortingMethod - Order in which the proxy contacts the storage nodes.
+	// timing prefers the nodes with the lowest recent response time,
+	// affinity the nodes matching ReadAffinity.
+	SortingMethod string `json:"sortingMethod,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// TimingExpiry - Seconds the response time of a storage node is kept
+	// with the timing SortingMethod, 0 uses the Swift default of 300
+	TimingExpiry int32 `json:"timingExpiry,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ReadAffinity - Regions and zones preferred with the affinity
+	// SortingMethod, e.g. "r1z1=100, r1=200"
+	ReadAffinity string `json:"readAffinity,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// KeystoneAuth - Settings of the keystoneauth middleware
+	KeystoneAuth *SwiftProxyKeystoneAuth `json:"keystoneAuth,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TLS - Serve the proxy endpoints with TLS. With Routes the public
+	// endpoint is exposed with a re-encrypt Route.
+	TLS *SwiftProxyTLS `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ConsistencyCheck - Periodically sample containers and compare their
+	// listings with the objects on the object servers
+	ConsistencyCheck *SwiftProxyConsistencyCheck `json:"consistencyCheck,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DataVerification - Verify a sample of objects written before each ring
+	// change once the rings are rebalanced, before the proxy is ready
+	DataVerification *SwiftProxyDataVerification `json:"dataVerification,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// UsageExport - Periodically aggregate the proxy access logs per account
+	// into a report stored in the account of the service user, e.g. for
+	// chargeback
+	UsageExport *SwiftProxyUsageExport `json:"usageExport,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Pools - Additional proxy pools with their own pipeline and placement.
+	// Each pool gets its own Deployment and Service and can take over some
+	// of the Keystone endpoints from the main proxy.
+	Pools []SwiftProxyPool `json:"pools,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// AccountPolicies - Containers created with a storage policy in managed
+	// accounts, e.g. the ones of internal service users like Glance. The
+	// service user requires the ResellerAdmin role for accounts other than
+	// its own.
+	AccountPolicies []SwiftAccountPolicy `json:"accountPolicies,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PublicContainers - Containers readable without authentication,
+	// optionally served as static websites by the staticweb middleware. The
+	// service user requires the ResellerAdmin role for accounts other than
+	// its own.
+	PublicContainers []SwiftPublicContainer `json:"publicContainers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// TopologyAwareRouting - Prefer proxy endpoints in the zone of the client
+	// on the endpoint and pool Services. Each proxy pod also prefers the
+	// storage nodes in the ring zones of its node with read and write
+	// affinity. The ring zones are the ones assigned to the topology zones
+	// by SwiftStorages with ZoneAwareRings.
+	TopologyAwareRouting bool `json:"topologyAwareRouting,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// InternalTrafficPolicy of the endpoint and pool Services, Cluster if
+	// empty. Local only routes in-cluster clients to proxy pods on their own
+	// node and drops the traffic of nodes without one.
+	InternalTrafficPolicy string `json:"internalTrafficPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// FlushCacheOnRingChange - Flush the memcached of each proxy pod once it
+	// synced new rings. Cached account and container info may point to
+	// devices removed from the rings and cause 404 responses otherwise. A
+	// shared memcachedInstance is not flushed, it holds the keys of other
+	// services too.
+	FlushCacheOnRingChange bool `json:"flushCacheOnRingChange,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// MemcachedInstance - Name of a Memcached CR of the infra-operator whose
+	// servers are used as memcache_servers instead of a memcached container
+	// in each proxy pod
+	MemcachedInstance string `json:"memcachedInstance,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NodeSelector - Pin the proxy pods to matching nodes. Pools without a
+	// nodeSelector use it too.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tolerations - Tolerations of the proxy pods, including the pools
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ReadOnly - Reject writes to all accounts with the read_only
+	// middleware, e.g. during maintenance or a migration freeze
+	ReadOnly *SwiftProxyReadOnly `json:"readOnly,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// BreakGlass - Emergency proxy pool without authentication for disaster
+	// recovery tooling. Anyone reaching it has full access to all accounts,
+	// only enable it while needed.
+	BreakGlass *SwiftProxyBreakGlass `json:"breakGlass,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DefaultConfigOverwrite - Config snippets merged into the config files
+	// of the proxy and its pools, keyed by the file name, e.g.
+	// {"proxy-server.conf": "[app:proxy-server]\nnode_timeout = 20"}.
+	// Files not rendered by the operator are added. Changes are applied when
+	// the pods restart.
+	DefaultConfigOverwrite map[string]string `json:"defaultConfigOverwrite,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerSync - Realms of the clusters containers are synced with,
+	// rendered to container-sync-realms.conf. Changes are applied when the
+	// pods restart.
+	ContainerSync *SwiftContainerSync `json:"containerSync,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Lifecycle - Expire new objects with the lifecycle middleware. Objects
+	// expire as set in the lifecycle of their SwiftContainer, or after
+	// DefaultExpireAfterDays. Objects uploaded with X-Delete-At or
+	// X-Delete-After keep their expiration.
+	Lifecycle *SwiftProxyLifecycle `json:"lifecycle,omitempty"`
+}
+
+// SwiftProxyLifecycle defines the default expiration of new objects
+type SwiftProxyLifecycle struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// DefaultExpireAfterDays - Days after which new objects of containers
+	// without a lifecycle expire, 0 keeps them
+	DefaultExpireAfterDays int32 `json:"defaultExpireAfterDays,omitempty"`
+}
+
+// SwiftProxyBreakGlass defines the unauthenticated emergency proxy pool. It
+// is only served by an internal Service and a NetworkPolicy restricts its
+// clients.
+type SwiftProxyBreakGlass struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// Replicas of the break-glass pool
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// AllowedClients - Pods in the namespace of the proxy allowed to connect
+	// to the break-glass pool. An empty selector is rejected.
+	AllowedClients metav1.LabelSelector `json:"allowedClients"`
+}
+
+// SwiftProxyReadOnly defines the read-only mode of the proxy
+type SwiftProxyReadOnly struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// AllowDeletes - Still allow DELETE requests
+	AllowDeletes bool `json:"allowDeletes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// WritableAccounts - Accounts that stay writable, e.g. AUTH_<project id>.
+	// They are marked with the read-only account system metadata by a Job.
+	WritableAccounts []string `json:"writableAccounts,omitempty"`
+}
+
+// SwiftAccountPolicy defines the storage policy of the containers of a
+// managed account
+type SwiftAccountPolicy struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Account - Swift account, e.g. AUTH_<project id>
+	Account string `json:"account"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Policy - Name of the storage policy, sent as X-Storage-Policy
+	Policy string `json:"policy"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Containers - Containers created in the account with the policy. The
+	// policy of an existing container can not be changed.
+	Containers []string `json:"containers"`
+}
+
+// SwiftPublicContainer defines a container readable without authentication
+type SwiftPublicContainer struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Account - Swift account, e.g. AUTH_<project id>
+	Account string `json:"account"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Container - Name of the container, created if missing
+	Container string `json:"container"`
+
+	// +kubebuilder:validation:Optional
+	// WebIndex - Object served for the container and pseudo-directory
+	// URLs, e.g. index.html
+	WebIndex string `json:"webIndex,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// WebListings - Allow listing the objects of the container
+	WebListings bool `json:"webListings,omitempty"`
+}
+
+// SwiftProxyPool defines an additional pool of proxy servers
+type SwiftProxyPool struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// Name of the pool, appended to the Deployment and Service names
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// Replicas of the pool
+	Replicas int32 `json:"replicas"`
+
+	// +kubebuilder:validation:Optional
+	// Pipeline - proxy-server pipeline of the pool, defaults to the
+	// pipeline of the main proxy
+	Pipeline string `json:"pipeline,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NodeSelector - Pin the pool pods to matching nodes, defaults to the
+	// nodeSelector of the proxy
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Endpoints - Keystone endpoint types (admin, internal, public) served
+	// by this pool instead of the main proxy
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// SwiftProxyAutoscaling defines the HorizontalPodAutoscaler for the proxy
+type SwiftProxyAutoscaling struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// Lower limit for the number of proxy replicas
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// Upper limit for the number of proxy replicas
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Target average CPU utilization (in percent of the requested CPU)
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Scale on the per-pod request rate
+	RequestRate *SwiftProxyMetricTarget `json:"requestRate,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Scale on the per-pod p95 request latency
+	Latency *SwiftProxyMetricTarget `json:"latency,omitempty"`
+}
+
+// SwiftProxyMetricTarget defines a custom per-pod metric to scale on
+type SwiftProxyMetricTarget struct {
+	// +kubebuilder:validation:Optional
+	// Name of the metric as exposed by the custom metrics adapter, defaults
+	// to the name of the metric of the proxy-exporter sidecar
+	MetricName string `json:"metricName,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Target average value of the metric across all proxy pods
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+}
+
+// SwiftProxyConsistencyCheck defines the periodic consistency sampling
+type SwiftProxyConsistencyCheck struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="0 * * * *"
+	// Schedule of the check in cron format
+	Schedule string `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Accounts to sample containers from, e.g. AUTH_<project id>. Defaults
+	// to the account of the service user.
+	Accounts []string `json:"accounts,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// Number of containers sampled per account
+	Containers int32 `json:"containers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// Number of listed objects checked on the object servers per container
+	Objects int32 `json:"objects,omitempty"`
+}
+
+// SwiftProxyDataVerification defines the verification of the data after a
+// ring change
+type SwiftProxyDataVerification struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	// Number of verification objects kept in the account of the service user
+	Objects int32 `json:"objects,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// Number of randomly sampled objects checked after each ring change
+	Sample int32 `json:"sample,omitempty"`
+}
+
+// SwiftProxyUsageExport defines the periodic export of the usage per account
+type SwiftProxyUsageExport struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="0 * * * *"
+	// Schedule of the export in cron format. Each report covers the access
+	// logs since the previous one, logs rotated away in between are lost.
+	Schedule string `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="swift-operator-usage"
+	// Container of the reports in the account of the service user
+	Container string `json:"container,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="csv"
+	// +kubebuilder:validation:Enum=csv;json
+	// Format of the reports
+	Format string `json:"format,omitempty"`
+}
+
+// SwiftProxyTLS defines the certificates of the proxy endpoints
+type SwiftProxyTLS struct {
+	// +kubebuilder:validation:Optional
+	// SecretName - Secret with the tls.crt, tls.key and ca.crt of the proxy.
+	// Defaults to <name>-tls, which is created by cert-manager if Issuer is
+	// set. The ca.crt is the destination CA of the Route and is trusted by
+	// the jobs calling the proxy. A Secret of another namespace, granted by a
+	// SwiftSecretGrant, is referenced as <namespace>/<name>.
+	SecretName string `json:"secretName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Issuer - cert-manager issuer of a Certificate for the Service names of
+	// the proxy endpoints
+	Issuer string `json:"issuer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// IssuerKind - Kind of the cert-manager issuer
+	IssuerKind string `json:"issuerKind,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RouteSecretName - Secret with the tls.crt, tls.key and optional ca.crt
+	// served by the Route of the public endpoint. Defaults to <name>-route-tls
+	// if RouteIssuer is set, to the certificate of the router otherwise. A
+	// Secret of another namespace is referenced as <namespace>/<name>.
+	RouteSecretName string `json:"routeSecretName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RouteIssuer - cert-manager issuer of a Certificate for the hostname of
+	// the Route, e.g. a public ACME issuer while Issuer is an internal CA
+	RouteIssuer string `json:"routeIssuer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// RouteIssuerKind - Kind of the cert-manager issuer of the Route
+	RouteIssuerKind string `json:"routeIssuerKind,omitempty"`
+}
+
+// SwiftProxyKeystoneAuth are the settings of the keystoneauth middleware
+type SwiftProxyKeystoneAuth struct {
+	// +kubebuilder:validation:Optional
+	// OperatorRoles - Roles allowed to own the account of their project,
+	// defaults to admin and SwiftOperator
+	OperatorRoles []string `json:"operatorRoles,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// AllowOverrides - Allow other middlewares to skip the keystoneauth
+	// authorization, e.g. for tempurl and formpost
+	AllowOverrides *bool `json:"allowOverrides,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// AllowNamesInACLs - Allow project and domain names in container ACLs,
+	// otherwise only IDs are matched
+	AllowNamesInACLs *bool `json:"allowNamesInACLs,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SystemReaderRoles - System scoped roles with read access to all
+	// accounts
+	SystemReaderRoles []string `json:"systemReaderRoles,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ProjectReaderRoles - Project scoped roles with read access to the
+	// account of their project
+	ProjectReaderRoles []string `json:"projectReaderRoles,omitempty"`
+}
+
+// SwiftProxyConsistencyResult is the result of the last consistency check
+type SwiftProxyConsistencyResult struct {
+	// Time the check finished
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// Number of containers sampled
+	ContainersSampled int64 `json:"containersSampled"`
+
+	// Containers whose object count differs from their listing
+	ContainerCountMismatches int64 `json:"containerCountMismatches"`
+
+	// Number of listed objects checked on the object servers
+	ObjectsSampled int64 `json:"objectsSampled"`
+
+	// Listed objects not found on any primary object server
+	ObjectsMissing int64 `json:"objectsMissing"`
+
+	// Listed objects found on fewer primary object servers than replicas
+	ObjectsUnderReplicated int64 `json:"objectsUnderReplicated"`
+
+	// Errors while sampling, e.g. unreachable servers
+	Errors int64 `json:"errors"`
 }
 
 // SwiftProxyStatus defines the observed state of SwiftProxy
@@ -81,6 +622,31 @@ type SwiftProxyStatus struct {
 
 	// API endpoints
 	APIEndpoints map[string]map[string]string `json:"apiEndpoints,omitempty"`
+
+	// Ring version synced by each pod, keyed by pod name
+	RingSync map[string]RingSyncStatus `json:"ringSync,omitempty"`
+
+	// Last failed termination of the containers, keyed by "<pod>/<container>"
+	Terminations map[string]ContainerTermination `json:"terminations,omitempty"`
+
+	// Changes that would be applied to the sub-resources, only set in dry-run mode
+	DryRunDiff []string `json:"dryRunDiff,omitempty"`
+
+	// Map of hashes to track e.g. job status
+	Hash map[string]string `json:"hash,omitempty"`
+
+	// Result of the last consistency check
+	ConsistencyCheck *SwiftProxyConsistencyResult `json:"consistencyCheck,omitempty"`
+
+	// Accounts marked writable in the read-only mode
+	WritableAccounts []string `json:"writableAccounts,omitempty"`
+
+	// URLs of the public containers
+	PublicContainers []string `json:"publicContainers,omitempty"`
+
+	// ObservedGeneration - Generation of the spec the Ready condition was
+	// reported for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,168 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var swiftproxylog = logf.Log.WithName("swiftproxy-resource")
+
+// SetupWebhookWithManager sets up the webhook with the Manager.
+func (r *SwiftProxy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-swift-openstack-org-v1beta1-swiftproxy,mutating=true,failurePolicy=fail,sideEffects=None,groups=swift.openstack.org,resources=swiftproxies,verbs=create;update,versions=v1beta1,name=mswiftproxy.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &SwiftProxy{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *SwiftProxy) Default() {
+	swiftproxylog.Info("default", "name", r.Name)
+
+	r.Spec.Default()
+}
+
+// Default - set defaults for this SwiftProxy spec. The CRD defaults are
+// repeated as they are not applied to a section added by the Swift webhook.
+func (spec *SwiftProxySpec) Default() {
+	defaults := getSwiftDefaults()
+	if spec.Replicas == 0 {
+		spec.Replicas = 1
+	}
+	if spec.ServiceUser == "" {
+		spec.ServiceUser = "swift"
+	}
+	if spec.ContainerImageProxy == "" {
+		spec.ContainerImageProxy = defaults.ProxyContainerImageURL
+	}
+	if spec.ContainerImageMemcached == "" {
+		spec.ContainerImageMemcached = defaults.MemcachedContainerImageURL
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-swift-openstack-org-v1beta1-swiftproxy,mutating=false,failurePolicy=fail,sideEffects=None,groups=swift.openstack.org,resources=swiftproxies,verbs=create;update,versions=v1beta1,name=vswiftproxy.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SwiftProxy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftProxy) ValidateCreate() error {
+	swiftproxylog.Info("validate create", "name", r.Name)
+
+	return r.Spec.Validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftProxy) ValidateUpdate(old runtime.Object) error {
+	swiftproxylog.Info("validate update", "name", r.Name)
+
+	return r.Spec.Validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftProxy) ValidateDelete() error {
+	return nil
+}
+
+// Validate - validate the SwiftProxy spec
+func (spec *SwiftProxySpec) Validate() error {
+	if err := validateBreakGlass(spec.BreakGlass); err != nil {
+		return err
+	}
+	if err := validateDefaultConfigOverwrite(spec.DefaultConfigOverwrite); err != nil {
+		return err
+	}
+	if err := validateProxyPools(spec.Pools); err != nil {
+		return err
+	}
+	// The read affinity of each pod is derived from the zone of its node
+	if spec.TopologyAwareRouting && spec.ReadAffinity != "" {
+		return fmt.Errorf("readAffinity can not be set with topologyAwareRouting")
+	}
+	return validateAutoscaling(spec.Autoscaling)
+}
+
+// validateBreakGlass - an empty allowedClients selector would select every
+// pod of the namespace and open the unauthenticated pool to all of them
+func validateBreakGlass(breakGlass *SwiftProxyBreakGlass) error {
+	if breakGlass == nil {
+		return nil
+	}
+	selector := breakGlass.AllowedClients
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return fmt.Errorf("breakGlass allowedClients must not be empty, it would allow every pod of the namespace")
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&selector); err != nil {
+		return fmt.Errorf("invalid breakGlass allowedClients: %w", err)
+	}
+	return nil
+}
+
+// validateProxyPools - the pool names are unique and each Keystone endpoint
+// type is claimed by one pool at most
+func validateProxyPools(pools []SwiftProxyPool) error {
+	names := map[string]bool{}
+	endpoints := map[string]string{}
+	for _, pool := range pools {
+		if names[pool.Name] {
+			return fmt.Errorf("duplicate proxy pool name %q", pool.Name)
+		}
+		if pool.Name == BreakGlassPoolName {
+			return fmt.Errorf("proxy pool name %q is reserved for breakGlass", pool.Name)
+		}
+		names[pool.Name] = true
+		for _, e := range pool.Endpoints {
+			switch e {
+			case "admin", "internal", "public":
+			default:
+				return fmt.Errorf("invalid endpoint type %q in proxy pool %q", e, pool.Name)
+			}
+			if other, ok := endpoints[e]; ok {
+				return fmt.Errorf("endpoint type %q claimed by proxy pools %q and %q", e, other, pool.Name)
+			}
+			endpoints[e] = pool.Name
+		}
+	}
+	return nil
+}
+
+// validateAutoscaling - the HorizontalPodAutoscaler rejects a maxReplicas
+// lower than the minReplicas, which defaults to 1
+func validateAutoscaling(as *SwiftProxyAutoscaling) error {
+	if as == nil {
+		return nil
+	}
+	minReplicas := as.MinReplicas
+	if minReplicas == 0 {
+		minReplicas = 1
+	}
+	if as.MaxReplicas < minReplicas {
+		return fmt.Errorf("autoscaling maxReplicas %d is lower than minReplicas %d",
+			as.MaxReplicas, minReplicas)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSwiftProxy(name string, spec SwiftProxySpec) *SwiftProxy {
+	spec.ContainerImageProxy = "swift-proxy"
+	spec.ContainerImageMemcached = "memcached"
+	return &SwiftProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+}
+
+var _ = Describe("SwiftProxy webhook", func() {
+	Context("with topology aware routing", func() {
+		It("derives the read affinity from the zone of the node", func() {
+			spec := SwiftProxySpec{TopologyAwareRouting: true}
+			Expect(spec.Validate()).To(Succeed())
+			spec.ReadAffinity = "r1z1=100"
+			Expect(spec.Validate()).To(MatchError("readAffinity can not be set with topologyAwareRouting"))
+		})
+
+		It("rejects a SwiftProxy with a read affinity", func() {
+			proxy := newSwiftProxy("zoned-proxy", SwiftProxySpec{
+				Replicas:             1,
+				TopologyAwareRouting: true,
+				ReadAffinity:         "r1z1=100",
+			})
+			Expect(k8sClient.Create(ctx, proxy)).To(
+				MatchError(ContainSubstring("readAffinity can not be set with topologyAwareRouting")))
+		})
+	})
+
+	Context("with proxy pools", func() {
+		It("accepts pools with unique names and endpoint types", func() {
+			Expect(validateProxyPools(nil)).To(Succeed())
+			Expect(validateProxyPools([]SwiftProxyPool{
+				{Name: "internal", Endpoints: []string{"internal", "admin"}},
+				{Name: "public", Endpoints: []string{"public"}},
+				{Name: "batch"},
+			})).To(Succeed())
+		})
+
+		It("rejects a pool name listed twice or reserved for breakGlass", func() {
+			Expect(validateProxyPools([]SwiftProxyPool{{Name: "internal"}, {Name: "internal"}})).To(
+				MatchError(`duplicate proxy pool name "internal"`))
+			Expect(validateProxyPools([]SwiftProxyPool{{Name: BreakGlassPoolName}})).To(
+				MatchError(ContainSubstring("is reserved for breakGlass")))
+		})
+
+		It("rejects an endpoint type claimed by two pools", func() {
+			Expect(validateProxyPools([]SwiftProxyPool{{Name: "internal", Endpoints: []string{"private"}}})).To(
+				MatchError(`invalid endpoint type "private" in proxy pool "internal"`))
+			Expect(validateProxyPools([]SwiftProxyPool{
+				{Name: "internal", Endpoints: []string{"internal"}},
+				{Name: "other", Endpoints: []string{"internal"}},
+			})).To(MatchError(`endpoint type "internal" claimed by proxy pools "internal" and "other"`))
+		})
+
+		It("rejects a SwiftProxy with a pool named like the break-glass pool", func() {
+			proxy := newSwiftProxy("pooled-proxy", SwiftProxySpec{
+				Replicas: 1,
+				Pools:    []SwiftProxyPool{{Name: BreakGlassPoolName, Replicas: 1}},
+			})
+			Expect(k8sClient.Create(ctx, proxy)).To(
+				MatchError(ContainSubstring(`proxy pool name "break-glass" is reserved for breakGlass`)))
+		})
+	})
+
+	Context("with autoscaling", func() {
+		It("accepts maxReplicas from minReplicas on", func() {
+			Expect(validateAutoscaling(nil)).To(Succeed())
+			Expect(validateAutoscaling(&SwiftProxyAutoscaling{MinReplicas: 2, MaxReplicas: 4})).To(Succeed())
+			Expect(validateAutoscaling(&SwiftProxyAutoscaling{MinReplicas: 3, MaxReplicas: 3})).To(Succeed())
+		})
+
+		It("compares maxReplicas with the default minReplicas", func() {
+			Expect(validateAutoscaling(&SwiftProxyAutoscaling{MaxReplicas: 1})).To(Succeed())
+			Expect(validateAutoscaling(&SwiftProxyAutoscaling{})).To(
+				MatchError("autoscaling maxReplicas 0 is lower than minReplicas 1"))
+		})
+
+		It("rejects a SwiftProxy with maxReplicas lower than minReplicas", func() {
+			proxy := newSwiftProxy("autoscaled-proxy", SwiftProxySpec{
+				Replicas:    1,
+				Autoscaling: &SwiftProxyAutoscaling{MinReplicas: 3, MaxReplicas: 2},
+			})
+			Expect(k8sClient.Create(ctx, proxy)).To(
+				MatchError(ContainSubstring("autoscaling maxReplicas 2 is lower than minReplicas 3")))
+		})
+	})
+
+	Context("with a break-glass pool", func() {
+		It("accepts a selector of the allowed clients", func() {
+			Expect(validateBreakGlass(nil)).To(Succeed())
+			Expect(validateBreakGlass(&SwiftProxyBreakGlass{
+				AllowedClients: metav1.LabelSelector{MatchLabels: map[string]string{"app": "swift-recovery"}},
+			})).To(Succeed())
+		})
+
+		It("rejects an empty selector opening the pool to every pod", func() {
+			Expect(validateBreakGlass(&SwiftProxyBreakGlass{})).To(
+				MatchError(ContainSubstring("breakGlass allowedClients must not be empty")))
+		})
+
+		It("rejects an invalid selector", func() {
+			Expect(validateBreakGlass(&SwiftProxyBreakGlass{
+				AllowedClients: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: "Matches", Values: []string{"swift-recovery"}},
+				}},
+			})).To(MatchError(ContainSubstring("invalid breakGlass allowedClients")))
+		})
+
+		It("rejects a SwiftProxy with an empty selector", func() {
+			proxy := newSwiftProxy("break-glass-proxy", SwiftProxySpec{
+				Replicas:   1,
+				BreakGlass: &SwiftProxyBreakGlass{Replicas: 1},
+			})
+			Expect(k8sClient.Create(ctx, proxy)).To(
+				MatchError(ContainSubstring("breakGlass allowedClients must not be empty")))
+		})
+	})
+})
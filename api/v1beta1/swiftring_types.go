@@ -24,6 +24,39 @@ import (
 const (
 	RingCreateHash = "ringcreate"
 	DeviceListHash = "devicelist"
+	// StoragePoliciesHash - hash of the storage policy rings of the last
+	// rebalance
+	StoragePoliciesHash = "storagepolicies"
+	// DeviceWeightsHash - hash of the device weights of the last rebalance
+	DeviceWeightsHash = "deviceweights"
+	// FailedDevicesHash - hash of the failed devices removed from the rings
+	// of the last rebalance
+	FailedDevicesHash = "faileddevices"
+	// RingBackupHash - hash of the last ring backup Job
+	RingBackupHash = "ringbackup"
+
+	// RingBuilderJob builds the rings with swift-ring-builder in a Job
+	RingBuilderJob = "Job"
+	// RingBuilderNative builds the rings in-process in the operator
+	RingBuilderNative = "Native"
+
+	// PartPowerPhasePrepare prepares the object rings for the increase
+	PartPowerPhasePrepare = "Prepare"
+	// PartPowerPhaseRelink relinks the objects into the new partitions
+	PartPowerPhaseRelink = "Relink"
+	// PartPowerPhaseIncrease switches the object rings to the new part power
+	PartPowerPhaseIncrease = "Increase"
+	// PartPowerPhaseCleanup removes the links of the old partitions
+	PartPowerPhaseCleanup = "Cleanup"
+	// PartPowerPhaseFinish finishes the increase in the object rings
+	PartPowerPhaseFinish = "Finish"
+
+	// FailedDevicePhaseRemoving removes the failed device from the rings
+	FailedDevicePhaseRemoving = "Removing"
+	// FailedDevicePhaseReplacing waits for the new PVC of the device
+	FailedDevicePhaseReplacing = "Replacing"
+	// FailedDevicePhaseReplaced adds the replaced device back to the rings
+	FailedDevicePhaseReplaced = "Replaced"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -47,6 +80,172 @@ type SwiftRingSpec struct {
 	// +kubebuilder:default=swift-conf
 	// Name of Secret containing swift.conf
 	SwiftConfSecret string `json:"swiftConfSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SwiftConfSecretStore - Mount swift.conf with the Secrets Store CSI
+	// driver instead of the SwiftConfSecret. The SecretProviderClass must
+	// provide a swift.conf object.
+	SwiftConfSecretStore *SecretStore `json:"swiftConfSecretStore,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Job
+	// +kubebuilder:validation:Enum=Job;Native
+	// RingBuilder - Build the rings with swift-ring-builder in a Job, or
+	// in-process in the operator. The native builder only produces the
+	// *.ring.gz files and keeps no *.builder files, the rings can not be
+	// switched from Native back to Job.
+	RingBuilder string `json:"ringBuilder,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Encryption - Encrypt the *.builder files stored in the ring ConfigMap.
+	// The *.ring.gz files are needed by every pod and stay unencrypted.
+	Encryption *SwiftRingEncryption `json:"encryption,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// BackupTarget - Also copy the rings and builder files to a bucket of an
+	// S3-compatible object store after every rebalance. The credentials are
+	// requested with a bound service account token, no static keys are
+	// stored.
+	BackupTarget *SwiftRingBackupTarget `json:"backupTarget,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// StoragePolicies - Erasure coding storage policies. An object-<index>
+	// ring with one replica per fragment is built for each policy. Set on a
+	// Swift CR the policies are added to the generated swift.conf, the
+	// servers load them when their pods restart.
+	StoragePolicies []SwiftStoragePolicy `json:"storagePolicies,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DeviceWeights - Ring weights of devices of the device list, keyed by
+	// <host>/<device>, e.g. {"swift-storage-0.swift-storage/d1": 500}. They
+	// replace the weight derived from the PVC capacity, e.g. for disks of
+	// different speeds or to deliberately de-weight a device.
+	DeviceWeights map[string]int64 `json:"deviceWeights,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// FailedDevices - Failed devices to replace, as <host>/<device>, e.g.
+	// "swift-storage-0.swift-storage/d1". A failed device is removed from
+	// the rings, then its PVC and pod are deleted and the device is added
+	// back once the new PVC is bound. Remove the entry after the replacement
+	// to be able to replace the device again.
+	FailedDevices []string `json:"failedDevices,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=8
+	// +kubebuilder:validation:Minimum=8
+	// +kubebuilder:validation:Maximum=23
+	// PartPower - Part power of the object rings. The Native ring builder
+	// always builds rings with part power 8 and can not change it.
+	// Increasing it relinks the objects of every storage pod into the new
+	// partitions, one step at a time, before the rings are switched. It can
+	// not be decreased.
+	PartPower int32 `json:"partPower,omitempty"`
+}
+
+// SwiftRingPartPowerIncrease is the state of a part power increase
+type SwiftRingPartPowerIncrease struct {
+	// Part power the object rings are increased to
+	PartPower int32 `json:"partPower"`
+
+	// Phase of the increase: Prepare, Relink, Increase, Cleanup or Finish
+	Phase string `json:"phase"`
+
+	// Storage pods that completed the relink or cleanup of the phase
+	CompletedPods []string `json:"completedPods,omitempty"`
+}
+
+// SwiftRingFailedDevice is the state of the replacement of a failed device
+type SwiftRingFailedDevice struct {
+	// Phase of the replacement: Removing, Replacing or Replaced
+	Phase string `json:"phase"`
+
+	// ClaimUID - UID of the deleted PVC of the device
+	ClaimUID string `json:"claimUID,omitempty"`
+}
+
+// SwiftRingEncryption defines the envelope encryption of the ring builder
+// files. Each rebalance Job encrypts the builders with a new data key, which
+// is wrapped with the active key encryption key, before it stores them. The
+// key encryption keys are read from Barbican or from a Secret.
+type SwiftRingEncryption struct {
+	// +kubebuilder:validation:Optional
+	// KeySecret - Secret with the key encryption keys, 32 bytes each.
+	// Mutually exclusive with Barbican.
+	KeySecret string `json:"keySecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Barbican - Fetch the key encryption keys from Barbican in the
+	// rebalance Job, they are never stored in the cluster. Mutually
+	// exclusive with KeySecret.
+	Barbican *SwiftRingBarbicanKeys `json:"barbican,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// ActiveKey - Key used to wrap the data key. Changing it re-runs the
+	// rebalance Job, which re-encrypts the builders with the new key. The
+	// previous key must be kept until the rotation is done.
+	ActiveKey string `json:"activeKey"`
+}
+
+// SwiftRingBarbicanKeys defines the Barbican secrets holding the key
+// encryption keys. The rebalance Job authenticates as ServiceUser in the
+// service project.
+type SwiftRingBarbicanKeys struct {
+	// +kubebuilder:validation:Required
+	// Keys - Barbican secret UUIDs of the 32 byte key encryption keys by
+	// key name
+	Keys map[string]string `json:"keys"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=osp-secret
+	// Secret containing the password of the ServiceUser
+	Secret string `json:"secret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=SwiftPassword
+	// PasswordSelector - Key of the Secret containing the password
+	PasswordSelector string `json:"passwordSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=swift
+	// ServiceUser - Keystone user reading the Barbican secrets
+	ServiceUser string `json:"serviceUser,omitempty"`
+}
+
+// SwiftRingBackupTarget defines the S3-compatible bucket the rings are backed
+// up to. Like IRSA, the Job assumes RoleARN with a projected service account
+// token through AssumeRoleWithWebIdentity, the role must trust the OIDC issuer
+// of the cluster for the swift-swift service account of the namespace.
+type SwiftRingBackupTarget struct {
+	// +kubebuilder:validation:Required
+	// Endpoint - URL of the S3 API, e.g. https://s3.us-east-1.amazonaws.com
+	Endpoint string `json:"endpoint"`
+
+	// +kubebuilder:validation:Required
+	// Bucket the rings are stored in
+	Bucket string `json:"bucket"`
+
+	// +kubebuilder:validation:Optional
+	// Prefix of the object names, e.g. a cluster name ending with /
+	Prefix string `json:"prefix,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=us-east-1
+	// Region of the bucket, used to sign the requests
+	Region string `json:"region,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// RoleARN - Role assumed with the service account token
+	RoleARN string `json:"roleARN"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="https://sts.amazonaws.com"
+	// STSEndpoint - URL of the STS API issuing the temporary credentials
+	STSEndpoint string `json:"stsEndpoint,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=sts.amazonaws.com
+	// Audience of the projected service account token
+	Audience string `json:"audience,omitempty"`
 }
 
 // SwiftRingStatus defines the observed state of SwiftRing
@@ -56,6 +255,28 @@ type SwiftRingStatus struct {
 
 	// Map of hashes to track e.g. job status
 	Hash map[string]string `json:"hash,omitempty"`
+
+	// DryRunDiff - Changes that would be applied to the rings and the
+	// sub-resources, only set in dry-run mode
+	DryRunDiff []string `json:"dryRunDiff,omitempty"`
+
+	// EncryptionKey - Key encryption key wrapping the data key of the ring
+	// builders
+	EncryptionKey string `json:"encryptionKey,omitempty"`
+
+	// PartPower - Part power of the object rings
+	PartPower int32 `json:"partPower,omitempty"`
+
+	// PartPowerIncrease - Part power increase in progress, if any
+	PartPowerIncrease *SwiftRingPartPowerIncrease `json:"partPowerIncrease,omitempty"`
+
+	// FailedDevices - Replacement state of the failed devices, keyed by
+	// <host>/<device>
+	FailedDevices map[string]SwiftRingFailedDevice `json:"failedDevices,omitempty"`
+
+	// ObservedGeneration - Generation of the spec the Ready condition was
+	// reported for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,186 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var swiftringlog = logf.Log.WithName("swiftring-resource")
+
+// SetupWebhookWithManager sets up the webhook with the Manager.
+func (r *SwiftRing) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-swift-openstack-org-v1beta1-swiftring,mutating=true,failurePolicy=fail,sideEffects=None,groups=swift.openstack.org,resources=swiftrings,verbs=create;update,versions=v1beta1,name=mswiftring.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &SwiftRing{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *SwiftRing) Default() {
+	swiftringlog.Info("default", "name", r.Name)
+
+	r.Spec.Default()
+}
+
+// Default - set defaults for this SwiftRing spec. The CRD defaults are
+// repeated as they are not applied to a section added by the Swift webhook.
+func (spec *SwiftRingSpec) Default() {
+	defaults := getSwiftDefaults()
+	if spec.RingReplicas == 0 {
+		spec.RingReplicas = 1
+	}
+	if spec.ContainerImage == "" {
+		spec.ContainerImage = defaults.ProxyContainerImageURL
+	}
+	if e := spec.Encryption; e != nil && e.Barbican != nil {
+		if e.Barbican.Secret == "" {
+			e.Barbican.Secret = "osp-secret"
+		}
+		if e.Barbican.PasswordSelector == "" {
+			e.Barbican.PasswordSelector = "SwiftPassword"
+		}
+		if e.Barbican.ServiceUser == "" {
+			e.Barbican.ServiceUser = "swift"
+		}
+	}
+	if t := spec.BackupTarget; t != nil {
+		if t.Region == "" {
+			t.Region = "us-east-1"
+		}
+		if t.STSEndpoint == "" {
+			t.STSEndpoint = "https://sts.amazonaws.com"
+		}
+		if t.Audience == "" {
+			t.Audience = "sts.amazonaws.com"
+		}
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-swift-openstack-org-v1beta1-swiftring,mutating=false,failurePolicy=fail,sideEffects=None,groups=swift.openstack.org,resources=swiftrings,verbs=create;update,versions=v1beta1,name=vswiftring.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SwiftRing{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftRing) ValidateCreate() error {
+	swiftringlog.Info("validate create", "name", r.Name)
+
+	return r.Spec.Validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftRing) ValidateUpdate(old runtime.Object) error {
+	swiftringlog.Info("validate update", "name", r.Name)
+
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+	oldRing, ok := old.(*SwiftRing)
+	if !ok {
+		return fmt.Errorf("expected a SwiftRing, got %T", old)
+	}
+	return validateRingBuilderUpdate(oldRing.Spec.RingBuilder, r.Spec.RingBuilder)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftRing) ValidateDelete() error {
+	return nil
+}
+
+// Validate - validate the SwiftRing spec. The Native ring builder only
+// builds rings with the default part power, it can not relink the objects
+// into the partitions of another part power.
+func (spec *SwiftRingSpec) Validate() error {
+	if err := validateDeviceWeights(spec.DeviceWeights); err != nil {
+		return err
+	}
+	if err := validateFailedDevices(spec.FailedDevices); err != nil {
+		return err
+	}
+	if spec.RingBuilder == RingBuilderNative && spec.PartPower != 0 && spec.PartPower != 8 {
+		return fmt.Errorf("partPower %d requires the %s ring builder", spec.PartPower, RingBuilderJob)
+	}
+	if err := validateEncryption(spec.Encryption, spec.RingBuilder); err != nil {
+		return err
+	}
+	return validateBackupTarget(spec.BackupTarget)
+}
+
+// validateRingBuilderUpdate - the Native ring builder keeps no *.builder
+// files, the rebalance Job would create new ones and reassign every partition
+func validateRingBuilderUpdate(oldRingBuilder string, ringBuilder string) error {
+	if oldRingBuilder == RingBuilderNative && ringBuilder != RingBuilderNative {
+		return fmt.Errorf("ringBuilder can not be changed from %s to %s, the rings have no *.builder files",
+			RingBuilderNative, ringBuilder)
+	}
+	return nil
+}
+
+// validateEncryption - the keys are either read from a Secret or from
+// Barbican and the active key must be one of them. Only the rebalance Job
+// writes builder files, the Native ring builder has none to encrypt.
+func validateEncryption(encryption *SwiftRingEncryption, ringBuilder string) error {
+	if encryption == nil {
+		return nil
+	}
+	if ringBuilder == RingBuilderNative {
+		return fmt.Errorf("encryption requires the %s ring builder", RingBuilderJob)
+	}
+	if (encryption.KeySecret == "") == (encryption.Barbican == nil) {
+		return fmt.Errorf("encryption requires exactly one of keySecret and barbican")
+	}
+	if encryption.Barbican != nil {
+		if _, ok := encryption.Barbican.Keys[encryption.ActiveKey]; !ok {
+			return fmt.Errorf("active key %s not found in the barbican keys", encryption.ActiveKey)
+		}
+		for name, uuid := range encryption.Barbican.Keys {
+			if uuid == "" || strings.Contains(uuid, "/") {
+				return fmt.Errorf("invalid barbican secret UUID %q of key %s", uuid, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateBackupTarget - the S3 and STS endpoints must be http(s) URLs
+func validateBackupTarget(target *SwiftRingBackupTarget) error {
+	if target == nil {
+		return nil
+	}
+	endpoints := map[string]string{"endpoint": target.Endpoint}
+	if target.STSEndpoint != "" {
+		endpoints["stsEndpoint"] = target.STSEndpoint
+	}
+	for name, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid backupTarget %s %q, expected an http(s) URL", name, endpoint)
+		}
+	}
+	return nil
+}
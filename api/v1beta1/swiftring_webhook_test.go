@@ -0,0 +1,189 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newSwiftRing(name string, spec SwiftRingSpec) *SwiftRing {
+	spec.ContainerImage = "swift-proxy"
+	return &SwiftRing{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+}
+
+var _ = Describe("SwiftRing webhook", func() {
+	Context("with the Native ring builder", func() {
+		It("accepts the default part power", func() {
+			Expect((&SwiftRingSpec{RingBuilder: RingBuilderNative}).Validate()).To(Succeed())
+			Expect((&SwiftRingSpec{RingBuilder: RingBuilderNative, PartPower: 8}).Validate()).To(Succeed())
+		})
+
+		It("rejects another part power", func() {
+			err := (&SwiftRingSpec{RingBuilder: RingBuilderNative, PartPower: 12}).Validate()
+			Expect(err).To(MatchError(ContainSubstring("partPower 12 requires the Job ring builder")))
+			Expect((&SwiftRingSpec{RingBuilder: RingBuilderJob, PartPower: 12}).Validate()).To(Succeed())
+		})
+
+		It("rejects switching the rings back to the rebalance Job", func() {
+			Expect(validateRingBuilderUpdate(RingBuilderNative, RingBuilderJob)).To(
+				MatchError(ContainSubstring("can not be changed from Native to Job")))
+			Expect(validateRingBuilderUpdate(RingBuilderJob, RingBuilderNative)).To(Succeed())
+			Expect(validateRingBuilderUpdate(RingBuilderNative, RingBuilderNative)).To(Succeed())
+		})
+
+		It("rejects the update of a SwiftRing back to the rebalance Job", func() {
+			ring := newSwiftRing("native-ring", SwiftRingSpec{RingReplicas: 1, RingBuilder: RingBuilderNative})
+			Expect(k8sClient.Create(ctx, ring)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, ring)).To(Succeed())
+			})
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ring.Name, Namespace: ring.Namespace}, ring)).To(Succeed())
+			ring.Spec.RingBuilder = RingBuilderJob
+			Expect(k8sClient.Update(ctx, ring)).To(MatchError(ContainSubstring("the rings have no *.builder files")))
+		})
+	})
+
+	Context("with a backup target", func() {
+		It("accepts http(s) endpoints", func() {
+			Expect(validateBackupTarget(nil)).To(Succeed())
+			Expect(validateBackupTarget(&SwiftRingBackupTarget{Endpoint: "https://s3.example.com"})).To(Succeed())
+			Expect(validateBackupTarget(&SwiftRingBackupTarget{
+				Endpoint:    "http://minio:9000",
+				STSEndpoint: "https://sts.example.com",
+			})).To(Succeed())
+		})
+
+		It("rejects an endpoint without a scheme or host", func() {
+			Expect(validateBackupTarget(&SwiftRingBackupTarget{Endpoint: "s3.example.com"})).To(
+				MatchError(ContainSubstring(`invalid backupTarget endpoint "s3.example.com"`)))
+			Expect(validateBackupTarget(&SwiftRingBackupTarget{Endpoint: "ftp://s3.example.com"})).NotTo(Succeed())
+			Expect(validateBackupTarget(&SwiftRingBackupTarget{
+				Endpoint:    "https://s3.example.com",
+				STSEndpoint: "https://",
+			})).To(MatchError(ContainSubstring("invalid backupTarget stsEndpoint")))
+		})
+
+		It("defaults the STS endpoint before the validation", func() {
+			ring := newSwiftRing("backup-ring", SwiftRingSpec{
+				RingReplicas: 1,
+				BackupTarget: &SwiftRingBackupTarget{Endpoint: "https://s3.example.com"},
+			})
+			ring.Default()
+			Expect(ring.Spec.BackupTarget.STSEndpoint).To(Equal("https://sts.amazonaws.com"))
+			Expect(ring.ValidateCreate()).To(Succeed())
+		})
+	})
+
+	Context("with encryption", func() {
+		barbican := &SwiftRingBarbicanKeys{Keys: map[string]string{"key1": "8a5f1b7e-0c1d-4b2a-9d3e-6f7a8b9c0d1e"}}
+
+		It("accepts the keys of a Secret or of Barbican", func() {
+			Expect(validateEncryption(nil, RingBuilderNative)).To(Succeed())
+			Expect(validateEncryption(&SwiftRingEncryption{KeySecret: "ring-keys", ActiveKey: "key1"}, RingBuilderJob)).To(Succeed())
+			Expect(validateEncryption(&SwiftRingEncryption{Barbican: barbican, ActiveKey: "key1"}, RingBuilderJob)).To(Succeed())
+		})
+
+		It("requires the rebalance Job writing the builder files", func() {
+			Expect(validateEncryption(&SwiftRingEncryption{KeySecret: "ring-keys", ActiveKey: "key1"}, RingBuilderNative)).To(
+				MatchError("encryption requires the Job ring builder"))
+		})
+
+		It("requires exactly one source of the keys", func() {
+			Expect(validateEncryption(&SwiftRingEncryption{ActiveKey: "key1"}, RingBuilderJob)).To(
+				MatchError("encryption requires exactly one of keySecret and barbican"))
+			Expect(validateEncryption(&SwiftRingEncryption{KeySecret: "ring-keys", Barbican: barbican, ActiveKey: "key1"}, RingBuilderJob)).To(
+				MatchError("encryption requires exactly one of keySecret and barbican"))
+		})
+
+		It("rejects an unknown active key and an invalid secret UUID", func() {
+			Expect(validateEncryption(&SwiftRingEncryption{Barbican: barbican, ActiveKey: "key2"}, RingBuilderJob)).To(
+				MatchError("active key key2 not found in the barbican keys"))
+			invalid := &SwiftRingBarbicanKeys{Keys: map[string]string{"key1": "secrets/8a5f1b7e"}}
+			Expect(validateEncryption(&SwiftRingEncryption{Barbican: invalid, ActiveKey: "key1"}, RingBuilderJob)).To(
+				MatchError(`invalid barbican secret UUID "secrets/8a5f1b7e" of key key1`))
+		})
+
+		It("rejects a SwiftRing encrypted without a key source", func() {
+			ring := newSwiftRing("encrypted-ring", SwiftRingSpec{
+				RingReplicas: 1,
+				RingBuilder:  RingBuilderJob,
+				Encryption:   &SwiftRingEncryption{ActiveKey: "key1"},
+			})
+			Expect(k8sClient.Create(ctx, ring)).To(
+				MatchError(ContainSubstring("encryption requires exactly one of keySecret and barbican")))
+		})
+	})
+
+	Context("with device weights", func() {
+		It("accepts the weights of <host>/<device> entries", func() {
+			Expect(validateDeviceWeights(nil)).To(Succeed())
+			Expect(validateDeviceWeights(map[string]int64{
+				"swift-storage-0.swift-storage/d1": 100,
+				"swift-storage-1.swift-storage/d1": 0,
+			})).To(Succeed())
+		})
+
+		It("rejects a device without a host or a negative weight", func() {
+			for _, name := range []string{"d1", "/d1", "swift-storage-0.swift-storage/"} {
+				Expect(validateDeviceWeights(map[string]int64{name: 100})).To(
+					MatchError(fmt.Sprintf("invalid device %q in deviceWeights, expected <host>/<device>", name)))
+			}
+			Expect(validateDeviceWeights(map[string]int64{"swift-storage-0.swift-storage/d1": -1})).To(
+				MatchError("negative weight -1 of device swift-storage-0.swift-storage/d1"))
+		})
+
+		It("rejects a SwiftRing with a negative weight", func() {
+			ring := newSwiftRing("weighted-ring", SwiftRingSpec{
+				RingReplicas:  1,
+				DeviceWeights: map[string]int64{"swift-storage-0.swift-storage/d1": -1},
+			})
+			Expect(k8sClient.Create(ctx, ring)).To(MatchError(ContainSubstring("negative weight -1")))
+		})
+	})
+
+	Context("with failed devices", func() {
+		It("accepts <host>/<device> entries", func() {
+			Expect(validateFailedDevices(nil)).To(Succeed())
+			Expect(validateFailedDevices([]string{"swift-storage-0.swift-storage/d1"})).To(Succeed())
+		})
+
+		It("rejects a device without a host", func() {
+			for _, name := range []string{"d1", "/d1", "swift-storage-0.swift-storage/"} {
+				Expect(validateFailedDevices([]string{name})).To(
+					MatchError(fmt.Sprintf("invalid device %q in failedDevices, expected <host>/<device>", name)))
+			}
+		})
+
+		It("rejects a SwiftRing with a failed device without a host", func() {
+			ring := newSwiftRing("failed-ring", SwiftRingSpec{RingReplicas: 1, FailedDevices: []string{"d1"}})
+			Expect(k8sClient.Create(ctx, ring)).To(MatchError(ContainSubstring(`invalid device "d1" in failedDevices`)))
+		})
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwiftSecretGrantSpec defines the desired state of SwiftSecretGrant
+type SwiftSecretGrantSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// From - Namespaces allowed to reference the Secrets
+	From []SwiftSecretGrantFrom `json:"from"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// SecretNames - Secrets of the namespace of the grant the namespaces may
+	// reference, e.g. the swift.conf or TLS Secrets
+	SecretNames []string `json:"secretNames"`
+}
+
+// SwiftSecretGrantFrom is a namespace allowed to reference the Secrets
+type SwiftSecretGrantFrom struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Namespace - Namespace of the Swift resources referencing the Secrets
+	Namespace string `json:"namespace"`
+}
+
+// SwiftSecretGrantStatus defines the observed state of SwiftSecretGrant
+type SwiftSecretGrantStatus struct {
+	// Conditions
+	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// Copies - Copies of the Secrets in the granted namespaces, as
+	// <namespace>/<name>
+	Copies []string `json:"copies,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[0].status",description="Status"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[0].message",description="Message"
+
+// SwiftSecretGrant is the Schema for the swiftsecretgrants API. It grants
+// namespaces the use of Secrets of its namespace, referenced as
+// <namespace>/<name>.
+type SwiftSecretGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwiftSecretGrantSpec   `json:"spec,omitempty"`
+	Status SwiftSecretGrantStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwiftSecretGrantList contains a list of SwiftSecretGrant
+type SwiftSecretGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwiftSecretGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwiftSecretGrant{}, &SwiftSecretGrantList{})
+}
+
+// Grants returns true if the grant allows the namespace to reference the
+// Secret of the namespace of the grant
+func (g *SwiftSecretGrant) Grants(namespace string, secretName string) bool {
+	from := false
+	for _, f := range g.Spec.From {
+		if f.Namespace == namespace {
+			from = true
+		}
+	}
+	if !from {
+		return false
+	}
+	for _, name := range g.Spec.SecretNames {
+		if name == secretName {
+			return true
+		}
+	}
+	return false
+}
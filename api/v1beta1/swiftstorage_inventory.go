@@ -0,0 +1,113 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DeviceInventoryKey is the key of the device list in a device inventory
+// ConfigMap
+const DeviceInventoryKey = "devices.csv"
+
+// InventoryDevice is a device of a device inventory, e.g. of a storage node
+// migrated from an existing cluster
+// +kubebuilder:object:generate=false
+type InventoryDevice struct {
+	Host string
+	// Port of the object server, the container and account servers listen
+	// on the next two ports
+	Port   int32
+	Device string
+	Weight float64
+	Region int
+	Zone   int
+}
+
+// ParseDeviceInventory parses a device inventory, one
+// "host,port,device,weight,region,zone" entry per line. Empty lines and
+// lines starting with # are skipped.
+func ParseDeviceInventory(data string) ([]InventoryDevice, error) {
+	result := []InventoryDevice{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("invalid device inventory entry %q, expected host,port,device,weight,region,zone", line)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		d := InventoryDevice{Host: fields[0], Device: fields[2]}
+		if d.Host == "" || d.Device == "" || strings.Contains(d.Device, "/") {
+			return nil, fmt.Errorf("invalid host or device in device inventory entry %q", line)
+		}
+		port, err := strconv.ParseInt(fields[1], 10, 32)
+		if err != nil || port < 1 || port > 65533 {
+			return nil, fmt.Errorf("invalid port in device inventory entry %q", line)
+		}
+		d.Port = int32(port)
+		d.Weight, err = strconv.ParseFloat(fields[3], 64)
+		if err != nil || d.Weight < 0 {
+			return nil, fmt.Errorf("invalid weight in device inventory entry %q", line)
+		}
+		d.Region, err = strconv.Atoi(fields[4])
+		if err != nil || d.Region < 1 {
+			return nil, fmt.Errorf("invalid region in device inventory entry %q", line)
+		}
+		d.Zone, err = strconv.Atoi(fields[5])
+		if err != nil || d.Zone < 1 {
+			return nil, fmt.Errorf("invalid zone in device inventory entry %q", line)
+		}
+		key := d.Host + "/" + d.Device
+		if seen[key] {
+			return nil, fmt.Errorf("device %s is listed twice in the device inventory", key)
+		}
+		seen[key] = true
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// validateDeviceInventory - the device inventory ConfigMap must exist and
+// contain a valid device list
+func validateDeviceInventory(namespace string, name string) error {
+	if name == "" || webhookClient == nil {
+		return nil
+	}
+	cm := &corev1.ConfigMap{}
+	err := webhookClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, cm)
+	if err != nil {
+		return fmt.Errorf("device inventory ConfigMap %s: %w", name, err)
+	}
+	data, ok := cm.Data[DeviceInventoryKey]
+	if !ok {
+		return fmt.Errorf("device inventory ConfigMap %s has no %s key", name, DeviceInventoryKey)
+	}
+	_, err = ParseDeviceInventory(data)
+	return err
+}
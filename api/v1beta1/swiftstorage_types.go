@@ -18,9 +18,21 @@ package v1beta1
 
 import (
 	condition "github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// DebugPodAnnotation - name of the storage pod to inject an ephemeral
+	// debug container into
+	DebugPodAnnotation = "swift.openstack.org/debug-pod"
+
+	// DebugImageAnnotation - image of the ephemeral debug container,
+	// defaults to the proxy image which includes the swift CLI tools and
+	// ring utilities
+	DebugImageAnnotation = "swift.openstack.org/debug-image"
+)
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
@@ -29,6 +41,8 @@ type SwiftStorageSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// +kubebuilder:default=1
+	// Replicas of Swift Storage
 	Replicas int32 `json:"replicas"`
 
 	// +kubebuilder:validation:Required
@@ -36,10 +50,10 @@ type SwiftStorageSpec struct {
 	// Name of StorageClass to use for Swift PVs
 	StorageClass string `json:"storageClass,omitempty"`
 
-	// +kubebuilder:validation:Required
-	// +kubebuilder:default="10Gi"
-	// Minimum size for Swift PVs
-	StorageRequest string `json:"storageRequest"`
+	// +kubebuilder:validation:Optional
+	// Minimum size for Swift PVs, 10Gi if unset, 1Gi in aio mode or the
+	// size of the profile of a Swift
+	StorageRequest string `json:"storageRequest,omitempty"`
 
 	// +kubebuilder:validation:Required
 	// Image URL for Swift account service
@@ -65,6 +79,639 @@ type SwiftStorageSpec struct {
 	// +kubebuilder:default=swift-conf
 	// Name of Secret containing swift.conf
 	SwiftConfSecret string `json:"swiftConfSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SwiftConfSecretStore - Mount swift.conf with the Secrets Store CSI
+	// driver instead of the SwiftConfSecret. The SecretProviderClass must
+	// provide a swift.conf object.
+	SwiftConfSecretStore *SecretStore `json:"swiftConfSecretStore,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=d1
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	// Name of the Swift device, used for the mount point below NodeRoot
+	// and for the device entries in the rings
+	DeviceName string `json:"deviceName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// DisksPerReplica - Number of PVCs per storage pod, each used as a
+	// separate Swift device. The first one is DeviceName, the others
+	// continue its trailing number, e.g. d1, d2, d3. VolumeClaimTemplates
+	// are immutable, this needs to be set when the SwiftStorage is created.
+	DisksPerReplica int32 `json:"disksPerReplica,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=/srv/node
+	// Root path for Swift devices, used as "devices" in the server configs
+	// and as path for the rsync modules
+	NodeRoot string `json:"nodeRoot,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Create a VerticalPodAutoscaler in recommendation-only mode for the
+	// storage pods and report its recommendations in the status
+	ResourceRecommendations bool `json:"resourceRecommendations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CrashCollector - Preserve the logs of crashed containers on a PVC.
+	// VolumeClaimTemplates are immutable, this needs to be set when the
+	// SwiftStorage is created.
+	CrashCollector *SwiftStorageCrashCollector `json:"crashCollector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerEnv - Additional environment per container, keyed by the
+	// container name (e.g. object-server)
+	ContainerEnv map[string]ContainerEnv `json:"containerEnv,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NofileLimits - Minimum open file limit per container, keyed by the
+	// container name (e.g. object-server). The container refuses to start if
+	// the limit can not be raised to this value.
+	NofileLimits map[string]int64 `json:"nofileLimits,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Resources - Resource requests and limits per container, keyed by the
+	// container name (e.g. object-server) or by the role group, the plural
+	// of the part after the last dash (e.g. servers, replicators, auditors
+	// or updaters). The container name takes precedence.
+	Resources map[string]corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ExtraMounts - Additional volumes mounted into the storage pods, e.g.
+	// CA bundles, Secrets or debugging tools
+	ExtraMounts []ExtraVolMounts `json:"extraMounts,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// ClockSkewThreshold - Maximum difference in seconds between the clock
+	// of a pod and the API server before ClockSkewDetected is set
+	ClockSkewThreshold int64 `json:"clockSkewThreshold,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Enforce
+	// +kubebuilder:validation:Enum=Enforce;Report
+	// DriftPolicy - How out-of-band changes to the StatefulSet and Service
+	// are handled. Enforce reverts them, Report leaves them in place and
+	// sets the DriftDetected condition.
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Architectures - Schedule the storage pods only on nodes with one of
+	// these CPU architectures (e.g. amd64, arm64)
+	Architectures []string `json:"architectures,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ArchitectureImages - Image overrides keyed by CPU architecture. All
+	// storage pods share one pod template, the overrides are only used if
+	// exactly one architecture is set in Architectures.
+	ArchitectureImages map[string]SwiftStorageImages `json:"architectureImages,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Affinity - Scheduling constraints of the storage pods, e.g. to pin them
+	// to dedicated nodes. Defaults to spreading the storage pods across the
+	// nodes if possible. The Architectures are added to the node affinity.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NodeSelector - Pin the storage pods to matching nodes
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tolerations - Tolerations of the storage pods, e.g. for the taints of
+	// nodes dedicated to storage
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ConfigOverrides - Config snippets merged into the server config files
+	// of single pods, keyed by the pod ordinal without leading zeros and then
+	// by the file name, e.g.
+	// {"2": {"object-server.conf": "[object-replicator]\nconcurrency = 1"}}.
+	// Changes are applied when the pod restarts.
+	ConfigOverrides map[string]map[string]string `json:"configOverrides,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DefaultConfigOverwrite - Config snippets merged into the server config
+	// files of all pods, keyed by the file name, e.g.
+	// {"object-server.conf": "[object-replicator]\nconcurrency = 4"}. Files
+	// not rendered by the operator are added. The configOverrides of a pod
+	// take precedence. Changes are applied when the pods restart.
+	DefaultConfigOverwrite map[string]string `json:"defaultConfigOverwrite,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// RsyncMetrics - Run a sidecar exposing the rsync transfers and errors as
+	// Prometheus metrics on the rsync-metrics container port
+	RsyncMetrics bool `json:"rsyncMetrics,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// StorageMetrics - Run a sidecar exposing the replication lag, async
+	// pendings, quarantined items and disk usage as Prometheus metrics on
+	// the storage-metrics container port
+	StorageMetrics bool `json:"storageMetrics,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// MinimalContainers - Only deploy the servers and the container updater,
+	// without replication, auditing and rsync. Only suitable for a single
+	// replica.
+	MinimalContainers bool `json:"minimalContainers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// ObjectReconstructor - Run the object reconstructor, needed by erasure
+	// coding storage policies. Set by the Swift controller if the SwiftRing
+	// has any storage policy.
+	ObjectReconstructor bool `json:"objectReconstructor,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// ScaleUpBatchSize - Maximum number of storage pods added at once. Larger
+	// scale ups are done in steps, the next step starts once the rings
+	// include the new devices and every pod completed a replication cycle
+	// with them. 0 adds all pods at once.
+	ScaleUpBatchSize int32 `json:"scaleUpBatchSize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TLS - Serve the account, container and object server ports with TLS.
+	// The proxy servers, the background daemons and the checks connect to
+	// them with TLS, verified with the ca.crt of the certificate. The rsync
+	// replication of objects is not encrypted.
+	TLS *SwiftStorageTLS `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PauseBackgroundDaemons - Pause the replicators, auditors, updaters,
+	// the account reaper, the object expirer and reconstructor without
+	// restarting the pods. Set by the Swift controller during scaling
+	// schedule windows.
+	PauseBackgroundDaemons bool `json:"pauseBackgroundDaemons,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// HealthCheck - Periodically query the recon middleware of the storage
+	// servers and report their health in the status
+	HealthCheck *SwiftStorageHealthCheck `json:"healthCheck,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	// RolloutTimeout - Seconds a pod of a StatefulSet rollout may crash-loop
+	// before the rollout is reported as stuck
+	RolloutTimeout int32 `json:"rolloutTimeout,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=0
+	// ProgressDeadlineSeconds - Seconds the SwiftStorage may be not ready,
+	// after a spec change or since it was last ready, before the Stalled
+	// condition is set with a diagnosis of the wait. 0 disables it.
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// AutoRollback - Roll the StatefulSet back to its previous revision when
+	// a rollout is stuck. The spec is not applied to the StatefulSet again
+	// until it is changed.
+	AutoRollback bool `json:"autoRollback,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// MemcachedInstance - Name of a Memcached CR of the infra-operator whose
+	// servers are used as memcache_servers instead of a memcached container
+	// in each storage pod
+	MemcachedInstance string `json:"memcachedInstance,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DiskDiscovery - Run a DaemonSet inventorying the local disks of the
+	// selected nodes into the <name>-disk-inventory ConfigMap. Approved disks
+	// are formatted and mounted on their node and offered to the rings as
+	// SwiftDisks of the storageClass of the SwiftStorage.
+	DiskDiscovery *SwiftStorageDiskDiscovery `json:"diskDiscovery,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CordonedOrdinals - Ordinals of storage pods whose devices are removed
+	// from the rings, e.g. because of a broken node or volume. The pods and
+	// their PVCs are kept but not required to be ready.
+	CordonedOrdinals []int32 `json:"cordonedOrdinals,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DrainOrdinals - Ordinals of storage pods whose devices get a weight of
+	// zero, e.g. before their node is retired. The pods keep serving until
+	// replication moved all partitions off their devices, then the Drained
+	// condition is set and they can be cordoned or scaled down.
+	DrainOrdinals []int32 `json:"drainOrdinals,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Scrub - Pace the object auditors and limit their runs to a scrub
+	// window. Without it the auditors run continuously with the Swift
+	// defaults.
+	Scrub *SwiftStorageScrub `json:"scrub,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={}
+	// Expirer - The object expirer StatefulSet, it deletes the expired
+	// objects of all storage pods
+	Expirer SwiftStorageExpirer `json:"expirer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DeviceInventory - ConfigMap with a devices.csv device list added to
+	// the devices of the storage pods, e.g. to migrate the devices of an
+	// existing cluster. One "host,port,device,weight,region,zone" entry per
+	// line, the port is the one of the object server, the container and
+	// account servers listen on the next two ports.
+	DeviceInventory string `json:"deviceInventory,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DatabaseDevice - An additional PVC per storage pod for the account and
+	// container databases, e.g. on SSDs. It is only added to the account and
+	// container rings, the other devices only to the object rings.
+	// VolumeClaimTemplates are immutable, this needs to be set when the
+	// SwiftStorage is created.
+	DatabaseDevice *SwiftStorageDatabaseDevice `json:"databaseDevice,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// MetadataTier - Run the account and container servers in a separate
+	// <name>-metadata StatefulSet, so they can be scaled and placed
+	// independently of the object servers. The storage pods then only run
+	// the object servers and their devices are only added to the object
+	// rings, the devices of the metadata pods only to the account and
+	// container rings. Each StatefulSet runs its own rsync daemon as it
+	// needs access to the devices of its pods. This needs to be set when the
+	// SwiftStorage is created.
+	MetadataTier *SwiftStorageMetadataTier `json:"metadataTier,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerSync - Realms of the clusters containers are synced with,
+	// rendered to container-sync-realms.conf, and run the container sync
+	// daemon. Changes are applied when the pods restart.
+	ContainerSync *SwiftContainerSync `json:"containerSync,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RestartDaemons - Restart background daemons in every storage pod
+	// without restarting the pods, keyed by container name, e.g.
+	// {"object-replicator": "2023-06-20T10:00"}. Changing the token of a
+	// daemon restarts it with the current config, including config changes
+	// otherwise applied when the pods restart.
+	RestartDaemons map[string]string `json:"restartDaemons,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ZoneAwareRings - Place the devices of each storage pod in the ring
+	// zone of the topology.kubernetes.io/zone label of its node, so the
+	// replicas are spread across the failure domains. Without it all devices
+	// are in zone 1. Only devices added to the rings afterwards are placed
+	// when the rings are built by a Job.
+	ZoneAwareRings bool `json:"zoneAwareRings,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// Region - Ring region of the devices of the storage pods, e.g. 2 for a
+	// second site whose rings also include the devices of the first one
+	// through the deviceInventory. The replicas of a partition are spread
+	// across the regions first. Only devices added to the rings afterwards
+	// are placed in the region when the rings are built by a Job.
+	Region int32 `json:"region,omitempty"`
+}
+
+// SwiftStorageExpirer defines the object expirer StatefulSet
+type SwiftStorageExpirer struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// Replicas - Number of object expirer pods, 0 disables the expiration.
+	// It is replaced by processes if processes is set.
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// Processes - Number of parts the expiration tasks are divided into,
+	// one expirer pod runs per part and processes the part of its ordinal.
+	// 0 processes all tasks in every expirer.
+	Processes int32 `json:"processes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// Process - Deprecated, the expirer pods process the part of their
+	// ordinal if processes is set
+	Process int32 `json:"process,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=1
+	// Interval - Seconds between the runs of the expirers
+	Interval int32 `json:"interval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// Concurrency - Number of concurrent object deletions of each expirer
+	Concurrency int32 `json:"concurrency,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DelayReaping - Seconds the deletion of expired objects is delayed,
+	// keyed by "<account>" or "<account>/<container>"
+	DelayReaping map[string]int64 `json:"delayReaping,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Autoscaling - Scale the expirer with a KEDA ScaledObject on the
+	// expiration tasks due, as reported by the storage-metrics sidecars.
+	// Requires storageMetrics and can not be used with processes.
+	Autoscaling *SwiftStorageExpirerAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// SwiftStorageExpirerAutoscaling defines the KEDA ScaledObject of the object
+// expirer
+type SwiftStorageExpirerAutoscaling struct {
+	// +kubebuilder:validation:Required
+	// ServerAddress - Address of the Prometheus server scraping the
+	// storage-metrics sidecars, e.g. http://prometheus.monitoring:9090
+	ServerAddress string `json:"serverAddress"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// MinReplicas - Number of expirer pods without a backlog
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// MaxReplicas - Maximum number of expirer pods
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10000
+	// +kubebuilder:validation:Minimum=1
+	// TasksPerReplica - Expiration tasks due per expirer pod
+	TasksPerReplica int64 `json:"tasksPerReplica,omitempty"`
+}
+
+// SwiftStorageScrub defines the object auditor pacing and the scrub window
+type SwiftStorageScrub struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Full
+	// +kubebuilder:validation:Enum=Full;ZeroByteOnly
+	// Checksum - Full verifies the checksums of all objects in addition to
+	// the zero byte file passes, ZeroByteOnly only runs the zero byte file
+	// passes that do not read the object data
+	Checksum string `json:"checksum,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=1
+	// FilesPerSecond - Maximum files audited per second by a full pass
+	FilesPerSecond int32 `json:"filesPerSecond,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10000000
+	// +kubebuilder:validation:Minimum=1
+	// BytesPerSecond - Maximum bytes read per second by a full pass
+	BytesPerSecond int64 `json:"bytesPerSecond,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=50
+	// +kubebuilder:validation:Minimum=1
+	// ZeroByteFilesPerSecond - Maximum files audited per second by a zero
+	// byte file pass
+	ZeroByteFilesPerSecond int32 `json:"zeroByteFilesPerSecond,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=1
+	// Interval - Minimum seconds between the start of two passes
+	Interval int32 `json:"interval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Window - Only audit during the windows of this schedule
+	Window *SwiftStorageScrubWindow `json:"window,omitempty"`
+}
+
+// SwiftStorageScrubWindow is a recurring window in which the object auditors
+// run. The window is split into slots so that at most MaxConcurrentPods
+// storage pods audit at the same time.
+type SwiftStorageScrubWindow struct {
+	// +kubebuilder:validation:Required
+	// Schedule - Start of the window in cron format, in UTC
+	Schedule string `json:"schedule"`
+
+	// +kubebuilder:validation:Required
+	// Duration - Length of the window, e.g. 6h
+	Duration metav1.Duration `json:"duration"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// MaxConcurrentPods - Maximum number of pods auditing at the same time
+	MaxConcurrentPods int32 `json:"maxConcurrentPods,omitempty"`
+}
+
+// SwiftStorageHealthCheck defines the periodic recon health check
+type SwiftStorageHealthCheck struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="*/15 * * * *"
+	// Schedule of the check in cron format
+	Schedule string `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=1
+	// ReplicationLagThreshold - Maximum time in seconds since the last
+	// replication pass of any server before the storage is unhealthy
+	ReplicationLagThreshold int64 `json:"replicationLagThreshold,omitempty"`
+}
+
+// SwiftStorageRevisions are the revisions of the storage StatefulSet
+type SwiftStorageRevisions struct {
+	// Revision of the pods of the finished rollout
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
+	// Revision being rolled out, the same as currentRevision once all pods
+	// run it
+	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// Generation of the StatefulSet the revisions were observed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Number of storage pods of each revision
+	Pods map[string]int32 `json:"pods,omitempty"`
+}
+
+// SwiftStorageHealth is the result of the last recon health check
+type SwiftStorageHealth struct {
+	// Time the check finished
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// Seconds since the oldest last replication pass, keyed by account,
+	// container and object
+	ReplicationLag map[string]int64 `json:"replicationLag,omitempty"`
+
+	// Drives reported as unmounted, as <host>/<device>
+	UnmountedDrives []string `json:"unmountedDrives,omitempty"`
+
+	// Servers whose recon middleware did not respond
+	UnreachableServers []string `json:"unreachableServers,omitempty"`
+
+	// Number of quarantined accounts
+	QuarantinedAccounts int64 `json:"quarantinedAccounts"`
+
+	// Number of quarantined containers
+	QuarantinedContainers int64 `json:"quarantinedContainers"`
+
+	// Number of quarantined objects of all storage policies
+	QuarantinedObjects int64 `json:"quarantinedObjects"`
+}
+
+// SwiftStorageTLS defines the certificate of the storage servers
+type SwiftStorageTLS struct {
+	// +kubebuilder:validation:Optional
+	// SecretName - Secret with the tls.crt, tls.key and ca.crt of the servers.
+	// Defaults to <name>-tls, which is created by cert-manager if Issuer is
+	// set. A Secret of another namespace, granted by a SwiftSecretGrant, is
+	// referenced as <namespace>/<name>.
+	SecretName string `json:"secretName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Issuer - cert-manager issuer of a Certificate for the headless Service
+	// names of the storage pods
+	Issuer string `json:"issuer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// IssuerKind - Kind of the cert-manager issuer
+	IssuerKind string `json:"issuerKind,omitempty"`
+}
+
+// SwiftStorageImages defines image overrides for the storage services
+type SwiftStorageImages struct {
+	// +kubebuilder:validation:Optional
+	// Image URL for Swift account service
+	ContainerImageAccount string `json:"containerImageAccount,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Image URL for Swift container service
+	ContainerImageContainer string `json:"containerImageContainer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Image URL for Swift object service
+	ContainerImageObject string `json:"containerImageObject,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Image URL for Swift proxy service
+	ContainerImageProxy string `json:"containerImageProxy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Image URL for Memcache service
+	ContainerImageMemcached string `json:"containerImageMemcached,omitempty"`
+}
+
+const (
+	// DriftPolicyEnforce - revert out-of-band changes to sub-resources
+	DriftPolicyEnforce = "Enforce"
+	// DriftPolicyReport - only report out-of-band changes to sub-resources
+	DriftPolicyReport = "Report"
+)
+
+// SwiftStorageCrashCollector defines the crash artifact collection
+type SwiftStorageCrashCollector struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1Gi"
+	// Size of the PVC used for crash artifacts
+	StorageRequest string `json:"storageRequest,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	// Number of days crash artifacts are kept
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+}
+
+// SwiftStorageDatabaseDevice defines the device of the account and container
+// databases of each storage pod
+type SwiftStorageDatabaseDevice struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=db
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	// Name of the Swift device, it must differ from the other devices
+	DeviceName string `json:"deviceName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Name of StorageClass to use for the database PVs, defaults to the
+	// StorageClass of the SwiftStorage
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1Gi"
+	// Minimum size for the database PVs
+	StorageRequest string `json:"storageRequest,omitempty"`
+}
+
+// SwiftStorageMetadataTier defines the StatefulSet of the account and
+// container servers. Unset fields default to the ones of the SwiftStorage.
+type SwiftStorageMetadataTier struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// Replicas of the metadata pods, they can not be scaled down
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Name of StorageClass to use for the PVs of the metadata pods, e.g. one
+	// backed by SSDs
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Minimum size for the PVs of the metadata pods
+	StorageRequest string `json:"storageRequest,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Image URL for Swift account service
+	ContainerImageAccount string `json:"containerImageAccount,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Image URL for Swift container service
+	ContainerImageContainer string `json:"containerImageContainer,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NodeSelector - Pin the metadata pods to matching nodes
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tolerations - Tolerations of the metadata pods
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// SwiftStorageDiskDiscovery defines the discovery of the local disks of the
+// storage nodes
+type SwiftStorageDiskDiscovery struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinProperties=1
+	// NodeSelector - Labels of the nodes whose disks are discovered
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// +kubebuilder:validation:Optional
+	// Tolerations - Tolerations of the discovery pods
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ContainerImage - Image of the discovery pods, it needs lsblk,
+	// mkfs.xfs and mount. Defaults to the containerImageObject.
+	ContainerImage string `json:"containerImage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=/mnt/swift
+	// +kubebuilder:validation:Pattern=`^/`
+	// MountRoot - Directory on the nodes the approved disks are mounted in,
+	// one directory per disk serial
+	MountRoot string `json:"mountRoot,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ApprovedDisks - Discovered disks to use, as <node>/<serial>. A disk
+	// without a filesystem is formatted, one with a filesystem only used if
+	// it was formatted by the discovery, other disks are never touched.
+	// Removing a disk from the list does not remove its SwiftDisk.
+	ApprovedDisks []string `json:"approvedDisks,omitempty"`
 }
 
 // SwiftStorageStatus defines the observed state of SwiftStorage
@@ -72,6 +719,47 @@ type SwiftStorageStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions condition.Conditions `json:"conditions,omitempty" optional:"true"`
+
+	// Ring version synced by each pod, keyed by pod name
+	RingSync map[string]RingSyncStatus `json:"ringSync,omitempty"`
+
+	// Last failed termination of the containers, keyed by "<pod>/<container>"
+	Terminations map[string]ContainerTermination `json:"terminations,omitempty"`
+
+	// Resources recommended by the VerticalPodAutoscaler, keyed by container name
+	ResourceRecommendations map[string]ContainerResourceRecommendation `json:"resourceRecommendations,omitempty"`
+
+	// Changes that would be applied to the sub-resources, only set in dry-run mode
+	DryRunDiff []string `json:"dryRunDiff,omitempty"`
+
+	// Result of the last recon health check
+	Health *SwiftStorageHealth `json:"health,omitempty"`
+
+	// Generation of the spec whose stuck rollout was rolled back
+	RolledBackGeneration int64 `json:"rolledBackGeneration,omitempty"`
+
+	// Revisions of the storage StatefulSet and their pods
+	Revisions *SwiftStorageRevisions `json:"revisions,omitempty"`
+
+	// Ring zone of each topology zone of the storage nodes, ring zones are
+	// kept once assigned
+	RingZones map[string]int32 `json:"ringZones,omitempty"`
+
+	// Start of the progress deadline, the time the SwiftStorage was seen
+	// not ready for the spec generation ProgressGeneration
+	ProgressStartTime *metav1.Time `json:"progressStartTime,omitempty"`
+
+	// Spec generation of ProgressStartTime
+	ProgressGeneration int64 `json:"progressGeneration,omitempty"`
+
+	// Position in the ring build queue shared with the other SwiftStorages
+	// of the Swift, 1 while the device list changes of this SwiftStorage
+	// are rebalanced and 0 without pending changes
+	RingBuildQueuePosition int32 `json:"ringBuildQueuePosition,omitempty"`
+
+	// ObservedGeneration - Generation of the spec the Ready condition was
+	// reported for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,466 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var swiftstoragelog = logf.Log.WithName("swiftstorage-resource")
+
+// SetupWebhookWithManager sets up the webhook with the Manager.
+func (r *SwiftStorage) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	webhookClient = mgr.GetClient()
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-swift-openstack-org-v1beta1-swiftstorage,mutating=true,failurePolicy=fail,sideEffects=None,groups=swift.openstack.org,resources=swiftstorages,verbs=create;update,versions=v1beta1,name=mswiftstorage.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &SwiftStorage{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *SwiftStorage) Default() {
+	swiftstoragelog.Info("default", "name", r.Name)
+
+	r.Spec.Default()
+	if r.CreationTimestamp.IsZero() {
+		r.Spec.DefaultMetrics()
+	}
+}
+
+// Default - set defaults for this SwiftStorage spec. The CRD defaults are
+// repeated as they are not applied to a section added by the Swift webhook.
+func (spec *SwiftStorageSpec) Default() {
+	defaults := getSwiftDefaults()
+	if spec.Replicas == 0 {
+		spec.Replicas = 1
+	}
+	if spec.StorageClass == "" {
+		spec.StorageClass = "local-storage"
+	}
+	if spec.StorageRequest == "" {
+		spec.StorageRequest = "10Gi"
+	}
+	if spec.RolloutTimeout == 0 {
+		spec.RolloutTimeout = 600
+	}
+	if spec.ContainerImageAccount == "" {
+		spec.ContainerImageAccount = defaults.AccountContainerImageURL
+	}
+	if spec.ContainerImageContainer == "" {
+		spec.ContainerImageContainer = defaults.ContainerContainerImageURL
+	}
+	if spec.ContainerImageObject == "" {
+		spec.ContainerImageObject = defaults.ObjectContainerImageURL
+	}
+	if spec.ContainerImageProxy == "" {
+		spec.ContainerImageProxy = defaults.ProxyContainerImageURL
+	}
+	if spec.ContainerImageMemcached == "" {
+		spec.ContainerImageMemcached = defaults.MemcachedContainerImageURL
+	}
+	if spec.CrashCollector != nil && spec.CrashCollector.StorageRequest == "" {
+		spec.CrashCollector.StorageRequest = "1Gi"
+	}
+	if spec.DiskDiscovery != nil && spec.DiskDiscovery.MountRoot == "" {
+		spec.DiskDiscovery.MountRoot = "/mnt/swift"
+	}
+	if as := spec.Expirer.Autoscaling; as != nil {
+		if as.MinReplicas == 0 {
+			as.MinReplicas = 1
+		}
+		if as.TasksPerReplica == 0 {
+			as.TasksPerReplica = 10000
+		}
+	}
+}
+
+// DefaultMetrics - enable the metrics of the SwiftOperatorConfig. It is only
+// called for new resources, as the metrics can be disabled afterwards.
+func (spec *SwiftStorageSpec) DefaultMetrics() {
+	defaults := getSwiftDefaults()
+	if defaults.RsyncMetrics {
+		spec.RsyncMetrics = true
+	}
+	if defaults.StorageMetrics {
+		spec.StorageMetrics = true
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-swift-openstack-org-v1beta1-swiftstorage,mutating=false,failurePolicy=fail,sideEffects=None,groups=swift.openstack.org,resources=swiftstorages,verbs=create;update,versions=v1beta1,name=vswiftstorage.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SwiftStorage{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftStorage) ValidateCreate() error {
+	swiftstoragelog.Info("validate create", "name", r.Name)
+
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+	if err := validateDeviceInventory(r.Namespace, r.Spec.DeviceInventory); err != nil {
+		return err
+	}
+	return validateSingleStorage(r.Namespace, r.Name)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftStorage) ValidateUpdate(old runtime.Object) error {
+	swiftstoragelog.Info("validate update", "name", r.Name)
+
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+	if err := validateDeviceInventory(r.Namespace, r.Spec.DeviceInventory); err != nil {
+		return err
+	}
+	oldStorage, ok := old.(*SwiftStorage)
+	if !ok {
+		return fmt.Errorf("expected a SwiftStorage, got %T", old)
+	}
+	if err := validateMetadataTierUpdate(oldStorage.Spec.MetadataTier, r.Spec.MetadataTier); err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(oldStorage.Spec.DatabaseDevice, r.Spec.DatabaseDevice) {
+		return fmt.Errorf("databaseDevice can only be set when the SwiftStorage is created")
+	}
+	return validateStorageScaleDown(oldStorage.Spec.Replicas, r.Spec.Replicas, r.Annotations)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SwiftStorage) ValidateDelete() error {
+	return nil
+}
+
+// Validate - validate the SwiftStorage spec. There must be at least one
+// replica that is not cordoned, a parseable storage request and an image
+// for each service.
+func (spec *SwiftStorageSpec) Validate() error {
+	if spec.Replicas < 1 {
+		return fmt.Errorf("replicas must be at least 1, got %d", spec.Replicas)
+	}
+	if _, err := resource.ParseQuantity(spec.StorageRequest); err != nil {
+		return fmt.Errorf("invalid storageRequest %q: %w", spec.StorageRequest, err)
+	}
+	cordoned := map[int32]bool{}
+	for _, o := range spec.CordonedOrdinals {
+		if o < 0 || o >= spec.Replicas {
+			return fmt.Errorf("cordonedOrdinals %d is not a pod ordinal, expected 0 to %d", o, spec.Replicas-1)
+		}
+		if cordoned[o] {
+			return fmt.Errorf("cordonedOrdinals %d is listed twice", o)
+		}
+		cordoned[o] = true
+	}
+	if int32(len(cordoned)) >= spec.Replicas {
+		return fmt.Errorf("at least one storage pod must not be cordoned")
+	}
+	draining := map[int32]bool{}
+	for _, o := range spec.DrainOrdinals {
+		if o < 0 || o >= spec.Replicas {
+			return fmt.Errorf("drainOrdinals %d is not a pod ordinal, expected 0 to %d", o, spec.Replicas-1)
+		}
+		if draining[o] {
+			return fmt.Errorf("drainOrdinals %d is listed twice", o)
+		}
+		draining[o] = true
+		cordoned[o] = true
+	}
+	if int32(len(cordoned)) >= spec.Replicas {
+		return fmt.Errorf("at least one storage pod must be neither cordoned nor drained")
+	}
+	if spec.Scrub != nil && spec.Scrub.Window != nil {
+		if spec.Scrub.Window.Duration.Duration <= 0 {
+			return fmt.Errorf("scrub window duration must be positive, got %s", spec.Scrub.Window.Duration.Duration)
+		}
+		if _, err := ParseSchedule(spec.Scrub.Window.Schedule); err != nil {
+			return fmt.Errorf("scrub window: %w", err)
+		}
+	}
+	if err := validateContainerSync(spec.ContainerSync); err != nil {
+		return err
+	}
+	if err := validateConfigOverrides(spec.ConfigOverrides); err != nil {
+		return err
+	}
+	if err := validateDefaultConfigOverwrite(spec.DefaultConfigOverwrite); err != nil {
+		return err
+	}
+	if err := validateRestartDaemons(spec.RestartDaemons); err != nil {
+		return err
+	}
+	if spec.MetadataTier != nil && spec.MetadataTier.StorageRequest != "" {
+		if _, err := resource.ParseQuantity(spec.MetadataTier.StorageRequest); err != nil {
+			return fmt.Errorf("invalid metadataTier storageRequest %q: %w", spec.MetadataTier.StorageRequest, err)
+		}
+	}
+	if spec.CrashCollector != nil {
+		if _, err := resource.ParseQuantity(spec.CrashCollector.StorageRequest); err != nil {
+			return fmt.Errorf("invalid crashCollector storageRequest %q: %w", spec.CrashCollector.StorageRequest, err)
+		}
+	}
+	if err := spec.validateDatabaseDevice(); err != nil {
+		return err
+	}
+	if err := validateDiskDiscovery(spec.DiskDiscovery); err != nil {
+		return err
+	}
+	if err := validateExpirer(spec.Expirer, spec.StorageMetrics); err != nil {
+		return err
+	}
+	images := []struct{ field, image string }{
+		{"containerImageAccount", spec.ContainerImageAccount},
+		{"containerImageContainer", spec.ContainerImageContainer},
+		{"containerImageObject", spec.ContainerImageObject},
+		{"containerImageProxy", spec.ContainerImageProxy},
+		{"containerImageMemcached", spec.ContainerImageMemcached},
+	}
+	for _, i := range images {
+		if i.image == "" {
+			return fmt.Errorf("%s must not be empty", i.field)
+		}
+	}
+	return nil
+}
+
+// ConfigOverrideFiles are the config files that can be overridden per pod,
+// rsyncd.conf is not included as it is not an INI file
+var ConfigOverrideFiles = []string{
+	"account-server.conf",
+	"container-server.conf",
+	"object-server.conf",
+	"object-expirer.conf",
+}
+
+// validateConfigOverrides - the ordinals name the override ConfigMaps and
+// are matched against the pod name suffix, so only the canonical form of a
+// non-negative integer is accepted, e.g. "1" but not "01" or "+1"
+func validateConfigOverrides(overrides map[string]map[string]string) error {
+	for ordinal, files := range overrides {
+		if n, err := strconv.Atoi(ordinal); err != nil || n < 0 || strconv.Itoa(n) != ordinal {
+			return fmt.Errorf("invalid pod ordinal %q in configOverrides, expected a non-negative integer without leading zeros", ordinal)
+		}
+		for file := range files {
+			valid := false
+			for _, f := range ConfigOverrideFiles {
+				valid = valid || f == file
+			}
+			if !valid {
+				return fmt.Errorf("invalid file %q in configOverrides of pod %s, expected one of %s",
+					file, ordinal, strings.Join(ConfigOverrideFiles, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// validateDefaultConfigOverwrite - the snippets are merged as INI files,
+// rsyncd.conf is not one
+func validateDefaultConfigOverwrite(overwrite map[string]string) error {
+	for file := range overwrite {
+		if !strings.HasSuffix(file, ".conf") || file == "rsyncd.conf" || strings.Contains(file, "/") {
+			return fmt.Errorf("invalid file %q in defaultConfigOverwrite, expected an INI .conf file name", file)
+		}
+	}
+	return nil
+}
+
+// BackgroundDaemons are the containers paused by pauseBackgroundDaemons,
+// they can be restarted in place with restartDaemons
+var BackgroundDaemons = map[string]bool{
+	"account-replicator":   true,
+	"account-auditor":      true,
+	"account-reaper":       true,
+	"container-replicator": true,
+	"container-auditor":    true,
+	"container-updater":    true,
+	"object-replicator":    true,
+	"object-auditor":       true,
+	"object-updater":       true,
+	"object-expirer":       true,
+	"object-reconstructor": true,
+	"container-sync":       true,
+}
+
+// validateRestartDaemons - only background daemons are restarted and the
+// tokens are rendered on a single line
+func validateRestartDaemons(restarts map[string]string) error {
+	for name, token := range restarts {
+		if !BackgroundDaemons[name] {
+			return fmt.Errorf("invalid container %q in restartDaemons, only background daemons can be restarted", name)
+		}
+		if token == "" || strings.ContainsAny(token, " \t\n") {
+			return fmt.Errorf("invalid token %q of %s in restartDaemons, expected a non-empty token without whitespace", token, name)
+		}
+	}
+	return nil
+}
+
+// validateContainerSync - the realms and the clusters of a realm are
+// sections and keys of container-sync-realms.conf, they must be unique
+func validateContainerSync(cs *SwiftContainerSync) error {
+	if cs == nil {
+		return nil
+	}
+	realms := map[string]bool{}
+	for _, realm := range cs.Realms {
+		if realms[strings.ToLower(realm.Name)] {
+			return fmt.Errorf("container sync realm %s is listed twice", realm.Name)
+		}
+		realms[strings.ToLower(realm.Name)] = true
+		clusters := map[string]bool{}
+		for _, cluster := range realm.Clusters {
+			if clusters[strings.ToLower(cluster.Name)] {
+				return fmt.Errorf("cluster %s is listed twice in container sync realm %s", cluster.Name, realm.Name)
+			}
+			clusters[strings.ToLower(cluster.Name)] = true
+		}
+	}
+	return nil
+}
+
+// validateSingleStorage - only one SwiftStorage is supported per namespace,
+// as they would share the ring ConfigMap and the labels of the pods
+func validateSingleStorage(namespace string, name string) error {
+	if webhookClient == nil {
+		return nil
+	}
+	storages := &SwiftStorageList{}
+	if err := webhookClient.List(context.TODO(), storages, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for _, s := range storages.Items {
+		if s.Name != name {
+			return fmt.Errorf("SwiftStorage %s already exists in namespace %s, only one SwiftStorage per namespace is supported",
+				s.Name, namespace)
+		}
+	}
+	return nil
+}
+
+// validateStorageScaleDown - removing storage replicas drains their devices
+// from the rings, so it is only allowed with the scale down annotation
+func validateStorageScaleDown(oldReplicas int32, replicas int32, annotations map[string]string) error {
+	if replicas >= oldReplicas || annotations[ScaleDownAnnotation] == "true" {
+		return nil
+	}
+	return fmt.Errorf("storage replicas can not be reduced from %d to %d: the devices of the removed pods "+
+		"would be drained from the rings and their PVCs deleted, set the %s annotation to \"true\" to confirm",
+		oldReplicas, replicas, ScaleDownAnnotation)
+}
+
+// validateMetadataTierUpdate - the account and container servers can not be
+// moved between the StatefulSets, their data is not replicated to the other
+// tier. The metadata pods can not be scaled down as their devices are not
+// drained.
+func validateMetadataTierUpdate(oldTier *SwiftStorageMetadataTier, tier *SwiftStorageMetadataTier) error {
+	if (oldTier == nil) != (tier == nil) {
+		return fmt.Errorf("metadataTier can only be set when the SwiftStorage is created")
+	}
+	if tier != nil && tier.Replicas < oldTier.Replicas {
+		return fmt.Errorf("metadataTier replicas can not be reduced from %d to %d", oldTier.Replicas, tier.Replicas)
+	}
+	return nil
+}
+
+// validateDatabaseDevice - the database device needs its own name and a
+// parseable storage request. The metadata tier has its own devices for the
+// databases.
+func (spec *SwiftStorageSpec) validateDatabaseDevice() error {
+	db := spec.DatabaseDevice
+	if db == nil {
+		return nil
+	}
+	if spec.MetadataTier != nil {
+		return fmt.Errorf("databaseDevice can not be used with metadataTier")
+	}
+	if _, err := resource.ParseQuantity(db.StorageRequest); err != nil {
+		return fmt.Errorf("invalid databaseDevice storageRequest %q: %w", db.StorageRequest, err)
+	}
+	// The other devices continue the trailing number of DeviceName
+	prefix := strings.TrimRight(spec.DeviceName, "0123456789")
+	if db.DeviceName == spec.DeviceName || strings.TrimRight(db.DeviceName, "0123456789") == prefix {
+		return fmt.Errorf("databaseDevice deviceName %q must not match the device names %s<n>", db.DeviceName, prefix)
+	}
+	return nil
+}
+
+// validateDiskDiscovery - approved disks are identified by their node and
+// serial, each of them may only be listed once
+func validateDiskDiscovery(dd *SwiftStorageDiskDiscovery) error {
+	if dd == nil {
+		return nil
+	}
+	approved := map[string]bool{}
+	for _, disk := range dd.ApprovedDisks {
+		node, serial, ok := strings.Cut(disk, "/")
+		if !ok || node == "" || serial == "" || strings.Contains(serial, "/") {
+			return fmt.Errorf("approvedDisks %q must be <node>/<serial>", disk)
+		}
+		if approved[disk] {
+			return fmt.Errorf("approvedDisks %s is listed twice", disk)
+		}
+		approved[disk] = true
+	}
+	return nil
+}
+
+// validateExpirer checks the process, the autoscaling and the delayReaping
+// targets of the object expirer, the targets are rendered as
+// delay_reaping_<target> options. The autoscaling changes the number of
+// expirer pods, the processes need a pod each.
+func validateExpirer(expirer SwiftStorageExpirer, storageMetrics bool) error {
+	if expirer.Processes > 0 && expirer.Process >= expirer.Processes {
+		return fmt.Errorf("expirer process must be lower than processes %d, got %d", expirer.Processes, expirer.Process)
+	}
+	if as := expirer.Autoscaling; as != nil {
+		if expirer.Processes > 0 {
+			return fmt.Errorf("expirer autoscaling can not be used with processes")
+		}
+		if !storageMetrics {
+			return fmt.Errorf("expirer autoscaling requires storageMetrics")
+		}
+		if as.MinReplicas > as.MaxReplicas {
+			return fmt.Errorf("expirer autoscaling minReplicas %d exceeds maxReplicas %d", as.MinReplicas, as.MaxReplicas)
+		}
+	}
+	for target, delay := range expirer.DelayReaping {
+		account, container, found := strings.Cut(target, "/")
+		if account == "" || (found && (container == "" || strings.Contains(container, "/"))) ||
+			strings.ContainsAny(target, " \t=:#") {
+			return fmt.Errorf("expirer delayReaping %q must be <account> or <account>/<container>", target)
+		}
+		if delay < 0 {
+			return fmt.Errorf("expirer delayReaping %s must not be negative, got %d", target, delay)
+		}
+	}
+	return nil
+}
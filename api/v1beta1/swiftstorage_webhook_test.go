@@ -0,0 +1,357 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newSwiftStorage(name string, spec SwiftStorageSpec) *SwiftStorage {
+	spec.ContainerImageAccount = "swift-account"
+	spec.ContainerImageContainer = "swift-container"
+	spec.ContainerImageObject = "swift-object"
+	spec.ContainerImageProxy = "swift-proxy"
+	spec.ContainerImageMemcached = "memcached"
+	return &SwiftStorage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+}
+
+var _ = Describe("SwiftStorage webhook", func() {
+	Context("with config overrides", func() {
+		It("accepts the canonical pod ordinals and the INI files", func() {
+			Expect(validateConfigOverrides(nil)).To(Succeed())
+			Expect(validateConfigOverrides(map[string]map[string]string{
+				"0":  {"object-server.conf": "[DEFAULT]\n"},
+				"12": {"object-expirer.conf": ""},
+			})).To(Succeed())
+		})
+
+		It("rejects an ordinal that does not match a pod name", func() {
+			for _, ordinal := range []string{"01", "+1", "-1", "swift-storage-0"} {
+				Expect(validateConfigOverrides(map[string]map[string]string{ordinal: {"object-server.conf": ""}})).To(
+					MatchError(ContainSubstring("invalid pod ordinal %q", ordinal)))
+			}
+		})
+
+		It("rejects rsyncd.conf and the files of other services", func() {
+			for _, file := range []string{"rsyncd.conf", "proxy-server.conf"} {
+				Expect(validateConfigOverrides(map[string]map[string]string{"0": {file: ""}})).To(
+					MatchError(ContainSubstring("invalid file %q in configOverrides of pod 0", file)))
+			}
+		})
+
+		It("rejects a SwiftStorage with a non-canonical ordinal", func() {
+			storage := newSwiftStorage("overridden-storage", SwiftStorageSpec{
+				Replicas:        1,
+				ConfigOverrides: map[string]map[string]string{"01": {"object-server.conf": ""}},
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(MatchError(ContainSubstring(`invalid pod ordinal "01"`)))
+		})
+	})
+
+	Context("with defaultConfigOverwrite", func() {
+		It("accepts the snippets of INI files", func() {
+			Expect(validateDefaultConfigOverwrite(nil)).To(Succeed())
+			Expect(validateDefaultConfigOverwrite(map[string]string{
+				"object-server.conf":   "[object-replicator]\nconcurrency = 4",
+				"internal-client.conf": "",
+			})).To(Succeed())
+		})
+
+		It("rejects rsyncd.conf and paths", func() {
+			for _, file := range []string{"rsyncd.conf", "swift/object-server.conf", "policy.json"} {
+				Expect(validateDefaultConfigOverwrite(map[string]string{file: ""})).To(
+					MatchError(fmt.Sprintf("invalid file %q in defaultConfigOverwrite, expected an INI .conf file name", file)))
+			}
+		})
+
+		It("rejects a SwiftStorage overwriting rsyncd.conf", func() {
+			storage := newSwiftStorage("overwritten-storage", SwiftStorageSpec{
+				Replicas:               1,
+				DefaultConfigOverwrite: map[string]string{"rsyncd.conf": ""},
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(
+				MatchError(ContainSubstring(`invalid file "rsyncd.conf" in defaultConfigOverwrite`)))
+		})
+
+		It("rejects a SwiftProxy overwriting a file that is not an INI file", func() {
+			proxy := newSwiftProxy("overwritten-proxy", SwiftProxySpec{
+				Replicas:               1,
+				DefaultConfigOverwrite: map[string]string{"policy.json": "{}"},
+			})
+			Expect(k8sClient.Create(ctx, proxy)).To(
+				MatchError(ContainSubstring(`invalid file "policy.json" in defaultConfigOverwrite`)))
+		})
+	})
+
+	Context("with restartDaemons", func() {
+		It("accepts a token per background daemon", func() {
+			Expect(validateRestartDaemons(nil)).To(Succeed())
+			Expect(validateRestartDaemons(map[string]string{
+				"object-replicator": "2023-06-20T10:00",
+				"container-sync":    "1",
+			})).To(Succeed())
+		})
+
+		It("rejects the servers and a token with whitespace", func() {
+			Expect(validateRestartDaemons(map[string]string{"object-server": "1"})).To(
+				MatchError(`invalid container "object-server" in restartDaemons, only background daemons can be restarted`))
+			for _, token := range []string{"", "2023-06-20 10:00"} {
+				Expect(validateRestartDaemons(map[string]string{"object-replicator": token})).To(
+					MatchError(ContainSubstring("invalid token %q of object-replicator in restartDaemons", token)))
+			}
+		})
+
+		It("rejects a SwiftStorage restarting a server", func() {
+			storage := newSwiftStorage("restarted-storage", SwiftStorageSpec{
+				Replicas:       1,
+				RestartDaemons: map[string]string{"object-server": "1"},
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(
+				MatchError(ContainSubstring(`invalid container "object-server" in restartDaemons`)))
+		})
+	})
+
+	Context("with a scrub window", func() {
+		window := func(schedule string) *SwiftStorageScrub {
+			return &SwiftStorageScrub{Window: &SwiftStorageScrubWindow{
+				Schedule: schedule,
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+			}}
+		}
+
+		It("parses the cron schedule of the window", func() {
+			s, err := ParseSchedule("0 1-3/2 * * 0,6")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Matches(time.Date(2023, 6, 18, 3, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(s.Matches(time.Date(2023, 6, 18, 2, 0, 0, 0, time.UTC))).To(BeFalse())
+			Expect(s.Matches(time.Date(2023, 6, 19, 3, 0, 0, 0, time.UTC))).To(BeFalse())
+		})
+
+		It("matches either day field if both are restricted", func() {
+			s, err := ParseSchedule("0 0 1 * 7")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Matches(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(s.Matches(time.Date(2023, 6, 18, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(s.Matches(time.Date(2023, 6, 19, 0, 0, 0, 0, time.UTC))).To(BeFalse())
+		})
+
+		It("rejects an invalid schedule", func() {
+			spec := newSwiftStorage("scrubbed-storage", SwiftStorageSpec{Replicas: 1, StorageRequest: "10Gi"}).Spec
+			spec.Scrub = window("0 1 * * *")
+			Expect(spec.Validate()).To(Succeed())
+			for _, schedule := range []string{"0 1 * *", "0 24 * * *", "*/0 * * * *", "0 1-a * * *"} {
+				spec.Scrub = window(schedule)
+				Expect(spec.Validate()).To(MatchError(ContainSubstring("scrub window: invalid schedule %q", schedule)))
+			}
+		})
+
+		It("rejects a SwiftStorage with an invalid schedule", func() {
+			storage := newSwiftStorage("scrubbed-storage", SwiftStorageSpec{
+				Replicas: 1,
+				Scrub:    window("0 1 * *"),
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(
+				MatchError(ContainSubstring(`scrub window: invalid schedule "0 1 * *"`)))
+		})
+	})
+
+	Context("with expirer autoscaling", func() {
+		autoscaling := &SwiftStorageExpirerAutoscaling{MinReplicas: 1, MaxReplicas: 5}
+
+		It("requires the storage metrics the ScaledObject queries", func() {
+			Expect(validateExpirer(SwiftStorageExpirer{Autoscaling: autoscaling}, true)).To(Succeed())
+			Expect(validateExpirer(SwiftStorageExpirer{Autoscaling: autoscaling}, false)).To(
+				MatchError("expirer autoscaling requires storageMetrics"))
+		})
+
+		It("can not be combined with expirer processes", func() {
+			expirer := SwiftStorageExpirer{Processes: 2, Autoscaling: autoscaling}
+			Expect(validateExpirer(expirer, true)).To(
+				MatchError("expirer autoscaling can not be used with processes"))
+		})
+
+		It("rejects minReplicas above maxReplicas", func() {
+			expirer := SwiftStorageExpirer{Autoscaling: &SwiftStorageExpirerAutoscaling{MinReplicas: 6, MaxReplicas: 5}}
+			Expect(validateExpirer(expirer, true)).To(
+				MatchError("expirer autoscaling minReplicas 6 exceeds maxReplicas 5"))
+		})
+
+		It("rejects a SwiftStorage with expirer autoscaling but no storage metrics", func() {
+			storage := newSwiftStorage("autoscaled-expirer", SwiftStorageSpec{
+				Replicas: 1,
+				Expirer:  SwiftStorageExpirer{Replicas: 1, Autoscaling: autoscaling},
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(
+				MatchError(ContainSubstring("expirer autoscaling requires storageMetrics")))
+		})
+	})
+
+	Context("with a scale down", func() {
+		It("requires the scale down annotation", func() {
+			Expect(validateStorageScaleDown(3, 3, nil)).To(Succeed())
+			Expect(validateStorageScaleDown(3, 5, nil)).To(Succeed())
+			Expect(validateStorageScaleDown(3, 2, map[string]string{ScaleDownAnnotation: "true"})).To(Succeed())
+			Expect(validateStorageScaleDown(3, 2, map[string]string{ScaleDownAnnotation: "false"})).To(
+				MatchError(ContainSubstring("storage replicas can not be reduced from 3 to 2")))
+			Expect(validateStorageScaleDown(3, 2, nil)).To(
+				MatchError(ContainSubstring(`set the swift.openstack.org/allow-scale-down annotation to "true" to confirm`)))
+		})
+
+		It("rejects the update of a SwiftStorage with less replicas", func() {
+			storage := newSwiftStorage("scaled-storage", SwiftStorageSpec{Replicas: 3})
+			Expect(k8sClient.Create(ctx, storage)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, storage)).To(Succeed())
+			})
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: storage.Name, Namespace: storage.Namespace}, storage)).To(Succeed())
+			storage.Spec.Replicas = 2
+			Expect(k8sClient.Update(ctx, storage)).To(
+				MatchError(ContainSubstring("storage replicas can not be reduced from 3 to 2")))
+
+			storage.Annotations = map[string]string{ScaleDownAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, storage)).To(Succeed())
+		})
+	})
+
+	Context("with expirer processes", func() {
+		It("requires the process to be lower than the processes", func() {
+			Expect(validateExpirer(SwiftStorageExpirer{}, false)).To(Succeed())
+			Expect(validateExpirer(SwiftStorageExpirer{Processes: 3, Process: 2}, false)).To(Succeed())
+			Expect(validateExpirer(SwiftStorageExpirer{Processes: 3, Process: 3}, false)).To(
+				MatchError("expirer process must be lower than processes 3, got 3"))
+		})
+
+		It("accepts the delayReaping of accounts and containers", func() {
+			Expect(validateExpirer(SwiftStorageExpirer{DelayReaping: map[string]int64{
+				"AUTH_test":         3600,
+				"AUTH_test/backups": 0,
+			}}, false)).To(Succeed())
+		})
+
+		It("rejects an invalid delayReaping target or a negative delay", func() {
+			for _, target := range []string{"", "/backups", "AUTH_test/", "AUTH_test/a/b", "AUTH test", "AUTH_test=1"} {
+				Expect(validateExpirer(SwiftStorageExpirer{DelayReaping: map[string]int64{target: 60}}, false)).To(
+					MatchError(fmt.Sprintf("expirer delayReaping %q must be <account> or <account>/<container>", target)))
+			}
+			Expect(validateExpirer(SwiftStorageExpirer{DelayReaping: map[string]int64{"AUTH_test": -1}}, false)).To(
+				MatchError("expirer delayReaping AUTH_test must not be negative, got -1"))
+		})
+
+		It("rejects a SwiftStorage with the process out of range", func() {
+			storage := newSwiftStorage("split-expirer", SwiftStorageSpec{
+				Replicas: 1,
+				Expirer:  SwiftStorageExpirer{Replicas: 1, Processes: 2, Process: 2},
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(
+				MatchError(ContainSubstring("expirer process must be lower than processes 2, got 2")))
+		})
+	})
+
+	Context("with container sync realms", func() {
+		realm := func(name string, clusters ...string) SwiftContainerSyncRealm {
+			r := SwiftContainerSyncRealm{Name: name, KeySecret: "container-sync-" + name}
+			for _, c := range clusters {
+				r.Clusters = append(r.Clusters, SwiftContainerSyncCluster{Name: c, Endpoint: "https://" + c + ".example.com/v1/"})
+			}
+			return r
+		}
+
+		It("accepts unique realms and clusters", func() {
+			Expect(validateContainerSync(nil)).To(Succeed())
+			Expect(validateContainerSync(&SwiftContainerSync{Realms: []SwiftContainerSyncRealm{
+				realm("east", "east1", "east2"),
+				realm("west", "east1"),
+			}})).To(Succeed())
+		})
+
+		It("rejects a realm or a cluster of a realm listed twice", func() {
+			Expect(validateContainerSync(&SwiftContainerSync{Realms: []SwiftContainerSyncRealm{
+				realm("east", "east1"),
+				realm("EAST", "east2"),
+			}})).To(MatchError("container sync realm EAST is listed twice"))
+			Expect(validateContainerSync(&SwiftContainerSync{Realms: []SwiftContainerSyncRealm{
+				realm("east", "east1", "East1"),
+			}})).To(MatchError("cluster East1 is listed twice in container sync realm east"))
+		})
+
+		It("rejects a SwiftStorage with a realm listed twice", func() {
+			storage := newSwiftStorage("synced-storage", SwiftStorageSpec{
+				Replicas: 1,
+				ContainerSync: &SwiftContainerSync{Realms: []SwiftContainerSyncRealm{
+					realm("east", "east1"),
+					realm("east", "east2"),
+				}},
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(
+				MatchError(ContainSubstring("container sync realm east is listed twice")))
+		})
+	})
+
+	Context("with disk discovery", func() {
+		nodeSelector := map[string]string{"swift-storage": "true"}
+
+		It("accepts the approved disks as <node>/<serial>", func() {
+			Expect(validateDiskDiscovery(nil)).To(Succeed())
+			Expect(validateDiskDiscovery(&SwiftStorageDiskDiscovery{
+				NodeSelector:  nodeSelector,
+				ApprovedDisks: []string{"worker-0/S3Z1NB0K", "worker-1/S3Z1NB0K"},
+			})).To(Succeed())
+		})
+
+		It("rejects a disk without a node or a serial", func() {
+			for _, disk := range []string{"S3Z1NB0K", "/S3Z1NB0K", "worker-0/", "worker-0/dev/sdb"} {
+				Expect(validateDiskDiscovery(&SwiftStorageDiskDiscovery{
+					NodeSelector:  nodeSelector,
+					ApprovedDisks: []string{disk},
+				})).To(MatchError(fmt.Sprintf("approvedDisks %q must be <node>/<serial>", disk)))
+			}
+		})
+
+		It("rejects a disk approved twice", func() {
+			Expect(validateDiskDiscovery(&SwiftStorageDiskDiscovery{
+				NodeSelector:  nodeSelector,
+				ApprovedDisks: []string{"worker-0/S3Z1NB0K", "worker-0/S3Z1NB0K"},
+			})).To(MatchError("approvedDisks worker-0/S3Z1NB0K is listed twice"))
+		})
+
+		It("rejects a SwiftStorage with a disk without a serial", func() {
+			storage := newSwiftStorage("discovered-storage", SwiftStorageSpec{
+				Replicas: 1,
+				DiskDiscovery: &SwiftStorageDiskDiscovery{
+					NodeSelector:  nodeSelector,
+					ApprovedDisks: []string{"worker-0/"},
+				},
+			})
+			Expect(k8sClient.Create(ctx, storage)).To(
+				MatchError(ContainSubstring(`approvedDisks "worker-0/" must be <node>/<serial>`)))
+		})
+	})
+})
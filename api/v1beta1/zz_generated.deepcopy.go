@@ -23,233 +23,262 @@ package v1beta1
 
 import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PasswordSelector) DeepCopyInto(out *PasswordSelector) {
+func (in *ContainerEnv) DeepCopyInto(out *ContainerEnv) {
 	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordSelector.
-func (in *PasswordSelector) DeepCopy() *PasswordSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerEnv.
+func (in *ContainerEnv) DeepCopy() *ContainerEnv {
 	if in == nil {
 		return nil
 	}
-	out := new(PasswordSelector)
+	out := new(ContainerEnv)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Swift) DeepCopyInto(out *Swift) {
+func (in *ContainerResourceRecommendation) DeepCopyInto(out *ContainerResourceRecommendation) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LowerBound != nil {
+		in, out := &in.LowerBound, &out.LowerBound
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.UpperBound != nil {
+		in, out := &in.UpperBound, &out.UpperBound
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Swift.
-func (in *Swift) DeepCopy() *Swift {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResourceRecommendation.
+func (in *ContainerResourceRecommendation) DeepCopy() *ContainerResourceRecommendation {
 	if in == nil {
 		return nil
 	}
-	out := new(Swift)
+	out := new(ContainerResourceRecommendation)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Swift) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftDefaults) DeepCopyInto(out *SwiftDefaults) {
+func (in *ContainerTermination) DeepCopyInto(out *ContainerTermination) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftDefaults.
-func (in *SwiftDefaults) DeepCopy() *SwiftDefaults {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerTermination.
+func (in *ContainerTermination) DeepCopy() *ContainerTermination {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftDefaults)
+	out := new(ContainerTermination)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftList) DeepCopyInto(out *SwiftList) {
+func (in *ExtraVolMounts) DeepCopyInto(out *ExtraVolMounts) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Swift, len(*in))
+	if in.Propagation != nil {
+		in, out := &in.Propagation, &out.Propagation
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]ExtraVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Mounts != nil {
+		in, out := &in.Mounts, &out.Mounts
+		*out = make([]v1.VolumeMount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftList.
-func (in *SwiftList) DeepCopy() *SwiftList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraVolMounts.
+func (in *ExtraVolMounts) DeepCopy() *ExtraVolMounts {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftList)
+	out := new(ExtraVolMounts)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SwiftList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftProxy) DeepCopyInto(out *SwiftProxy) {
+func (in *ExtraVolume) DeepCopyInto(out *ExtraVolume) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(v1.SecretVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(v1.ConfigMapVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(v1.EmptyDirVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPath != nil {
+		in, out := &in.HostPath, &out.HostPath
+		*out = new(v1.HostPathVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(v1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(v1.CSIVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxy.
-func (in *SwiftProxy) DeepCopy() *SwiftProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraVolume.
+func (in *ExtraVolume) DeepCopy() *ExtraVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftProxy)
+	out := new(ExtraVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SwiftProxy) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftProxyList) DeepCopyInto(out *SwiftProxyList) {
+func (in *PasswordSelector) DeepCopyInto(out *PasswordSelector) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]SwiftProxy, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyList.
-func (in *SwiftProxyList) DeepCopy() *SwiftProxyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordSelector.
+func (in *PasswordSelector) DeepCopy() *PasswordSelector {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftProxyList)
+	out := new(PasswordSelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SwiftProxyList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingSyncStatus) DeepCopyInto(out *RingSyncStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingSyncStatus.
+func (in *RingSyncStatus) DeepCopy() *RingSyncStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(RingSyncStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftProxySpec) DeepCopyInto(out *SwiftProxySpec) {
+func (in *SecretStore) DeepCopyInto(out *SecretStore) {
 	*out = *in
-	out.PasswordSelectors = in.PasswordSelectors
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxySpec.
-func (in *SwiftProxySpec) DeepCopy() *SwiftProxySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStore.
+func (in *SecretStore) DeepCopy() *SecretStore {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftProxySpec)
+	out := new(SecretStore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftProxyStatus) DeepCopyInto(out *SwiftProxyStatus) {
+func (in *Swift) DeepCopyInto(out *Swift) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(condition.Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.APIEndpoints != nil {
-		in, out := &in.APIEndpoints, &out.APIEndpoints
-		*out = make(map[string]map[string]string, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(map[string]string, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyStatus.
-func (in *SwiftProxyStatus) DeepCopy() *SwiftProxyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Swift.
+func (in *Swift) DeepCopy() *Swift {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftProxyStatus)
+	out := new(Swift)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Swift) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftRing) DeepCopyInto(out *SwiftRing) {
+func (in *SwiftAccount) DeepCopyInto(out *SwiftAccount) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRing.
-func (in *SwiftRing) DeepCopy() *SwiftRing {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftAccount.
+func (in *SwiftAccount) DeepCopy() *SwiftAccount {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftRing)
+	out := new(SwiftAccount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SwiftRing) DeepCopyObject() runtime.Object {
+func (in *SwiftAccount) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -257,31 +286,61 @@ func (in *SwiftRing) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftRingList) DeepCopyInto(out *SwiftRingList) {
+func (in *SwiftAccountACL) DeepCopyInto(out *SwiftAccountACL) {
+	*out = *in
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadWrite != nil {
+		in, out := &in.ReadWrite, &out.ReadWrite
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Admin != nil {
+		in, out := &in.Admin, &out.Admin
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftAccountACL.
+func (in *SwiftAccountACL) DeepCopy() *SwiftAccountACL {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftAccountACL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftAccountList) DeepCopyInto(out *SwiftAccountList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SwiftRing, len(*in))
+		*out = make([]SwiftAccount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingList.
-func (in *SwiftRingList) DeepCopy() *SwiftRingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftAccountList.
+func (in *SwiftAccountList) DeepCopy() *SwiftAccountList {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftRingList)
+	out := new(SwiftAccountList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SwiftRingList) DeepCopyObject() runtime.Object {
+func (in *SwiftAccountList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -289,69 +348,72 @@ func (in *SwiftRingList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftRingSpec) DeepCopyInto(out *SwiftRingSpec) {
+func (in *SwiftAccountPolicy) DeepCopyInto(out *SwiftAccountPolicy) {
 	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingSpec.
-func (in *SwiftRingSpec) DeepCopy() *SwiftRingSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftAccountPolicy.
+func (in *SwiftAccountPolicy) DeepCopy() *SwiftAccountPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftRingSpec)
+	out := new(SwiftAccountPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftRingStatus) DeepCopyInto(out *SwiftRingStatus) {
+func (in *SwiftAccountS3Credentials) DeepCopyInto(out *SwiftAccountS3Credentials) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(condition.Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Hash != nil {
-		in, out := &in.Hash, &out.Hash
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingStatus.
-func (in *SwiftRingStatus) DeepCopy() *SwiftRingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftAccountS3Credentials.
+func (in *SwiftAccountS3Credentials) DeepCopy() *SwiftAccountS3Credentials {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftRingStatus)
+	out := new(SwiftAccountS3Credentials)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftSpec) DeepCopyInto(out *SwiftSpec) {
+func (in *SwiftAccountSpec) DeepCopyInto(out *SwiftAccountSpec) {
 	*out = *in
-	out.SwiftRing = in.SwiftRing
-	out.SwiftStorage = in.SwiftStorage
-	out.SwiftProxy = in.SwiftProxy
+	if in.QuotaBytes != nil {
+		in, out := &in.QuotaBytes, &out.QuotaBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ACL != nil {
+		in, out := &in.ACL, &out.ACL
+		*out = new(SwiftAccountACL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3Credentials != nil {
+		in, out := &in.S3Credentials, &out.S3Credentials
+		*out = new(SwiftAccountS3Credentials)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSpec.
-func (in *SwiftSpec) DeepCopy() *SwiftSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftAccountSpec.
+func (in *SwiftAccountSpec) DeepCopy() *SwiftAccountSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftSpec)
+	out := new(SwiftAccountSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftStatus) DeepCopyInto(out *SwiftStatus) {
+func (in *SwiftAccountStatus) DeepCopyInto(out *SwiftAccountStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -360,39 +422,46 @@ func (in *SwiftStatus) DeepCopyInto(out *SwiftStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStatus.
-func (in *SwiftStatus) DeepCopy() *SwiftStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftAccountStatus.
+func (in *SwiftAccountStatus) DeepCopy() *SwiftAccountStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftStatus)
+	out := new(SwiftAccountStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftStorage) DeepCopyInto(out *SwiftStorage) {
+func (in *SwiftContainer) DeepCopyInto(out *SwiftContainer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorage.
-func (in *SwiftStorage) DeepCopy() *SwiftStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainer.
+func (in *SwiftContainer) DeepCopy() *SwiftContainer {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftStorage)
+	out := new(SwiftContainer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SwiftStorage) DeepCopyObject() runtime.Object {
+func (in *SwiftContainer) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -400,31 +469,50 @@ func (in *SwiftStorage) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftStorageList) DeepCopyInto(out *SwiftStorageList) {
+func (in *SwiftContainerLifecycle) DeepCopyInto(out *SwiftContainerLifecycle) {
+	*out = *in
+	if in.DeleteAt != nil {
+		in, out := &in.DeleteAt, &out.DeleteAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerLifecycle.
+func (in *SwiftContainerLifecycle) DeepCopy() *SwiftContainerLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftContainerLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftContainerList) DeepCopyInto(out *SwiftContainerList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SwiftStorage, len(*in))
+		*out = make([]SwiftContainer, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageList.
-func (in *SwiftStorageList) DeepCopy() *SwiftStorageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerList.
+func (in *SwiftContainerList) DeepCopy() *SwiftContainerList {
 	if in == nil {
 		return nil
 	}
-	out := new(SwiftStorageList)
+	out := new(SwiftContainerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SwiftStorageList) DeepCopyObject() runtime.Object {
+func (in *SwiftContainerList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -432,12 +520,1958 @@ func (in *SwiftStorageList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SwiftStorageSpec) DeepCopyInto(out *SwiftStorageSpec) {
+func (in *SwiftContainerSpec) DeepCopyInto(out *SwiftContainerSpec) {
 	*out = *in
+	if in.QuotaBytes != nil {
+		in, out := &in.QuotaBytes, &out.QuotaBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.QuotaCount != nil {
+		in, out := &in.QuotaCount, &out.QuotaCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(SwiftContainerLifecycle)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageSpec.
-func (in *SwiftStorageSpec) DeepCopy() *SwiftStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerSpec.
+func (in *SwiftContainerSpec) DeepCopy() *SwiftContainerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftContainerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftContainerStatus) DeepCopyInto(out *SwiftContainerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerStatus.
+func (in *SwiftContainerStatus) DeepCopy() *SwiftContainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftContainerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftContainerSync) DeepCopyInto(out *SwiftContainerSync) {
+	*out = *in
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]SwiftContainerSyncRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerSync.
+func (in *SwiftContainerSync) DeepCopy() *SwiftContainerSync {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftContainerSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftContainerSyncCluster) DeepCopyInto(out *SwiftContainerSyncCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerSyncCluster.
+func (in *SwiftContainerSyncCluster) DeepCopy() *SwiftContainerSyncCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftContainerSyncCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftContainerSyncRealm) DeepCopyInto(out *SwiftContainerSyncRealm) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]SwiftContainerSyncCluster, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftContainerSyncRealm.
+func (in *SwiftContainerSyncRealm) DeepCopy() *SwiftContainerSyncRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftContainerSyncRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftDefaults) DeepCopyInto(out *SwiftDefaults) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftDefaults.
+func (in *SwiftDefaults) DeepCopy() *SwiftDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftDisk) DeepCopyInto(out *SwiftDisk) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftDisk.
+func (in *SwiftDisk) DeepCopy() *SwiftDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftDisk) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftDiskList) DeepCopyInto(out *SwiftDiskList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SwiftDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftDiskList.
+func (in *SwiftDiskList) DeepCopy() *SwiftDiskList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftDiskList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftDiskList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftDiskSpec) DeepCopyInto(out *SwiftDiskSpec) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftDiskSpec.
+func (in *SwiftDiskSpec) DeepCopy() *SwiftDiskSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftDiskSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftDiskStatus) DeepCopyInto(out *SwiftDiskStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftDiskStatus.
+func (in *SwiftDiskStatus) DeepCopy() *SwiftDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftList) DeepCopyInto(out *SwiftList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Swift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftList.
+func (in *SwiftList) DeepCopy() *SwiftList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftOperatorConfig) DeepCopyInto(out *SwiftOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftOperatorConfig.
+func (in *SwiftOperatorConfig) DeepCopy() *SwiftOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftOperatorConfigFeatures) DeepCopyInto(out *SwiftOperatorConfigFeatures) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KeystoneEndpoints != nil {
+		in, out := &in.KeystoneEndpoints, &out.KeystoneEndpoints
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KEDA != nil {
+		in, out := &in.KEDA, &out.KEDA
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftOperatorConfigFeatures.
+func (in *SwiftOperatorConfigFeatures) DeepCopy() *SwiftOperatorConfigFeatures {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftOperatorConfigFeatures)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftOperatorConfigImages) DeepCopyInto(out *SwiftOperatorConfigImages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftOperatorConfigImages.
+func (in *SwiftOperatorConfigImages) DeepCopy() *SwiftOperatorConfigImages {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftOperatorConfigImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftOperatorConfigList) DeepCopyInto(out *SwiftOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SwiftOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftOperatorConfigList.
+func (in *SwiftOperatorConfigList) DeepCopy() *SwiftOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftOperatorConfigMetrics) DeepCopyInto(out *SwiftOperatorConfigMetrics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftOperatorConfigMetrics.
+func (in *SwiftOperatorConfigMetrics) DeepCopy() *SwiftOperatorConfigMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftOperatorConfigMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftOperatorConfigSpec) DeepCopyInto(out *SwiftOperatorConfigSpec) {
+	*out = *in
+	out.DefaultImages = in.DefaultImages
+	in.Features.DeepCopyInto(&out.Features)
+	out.Metrics = in.Metrics
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftOperatorConfigSpec.
+func (in *SwiftOperatorConfigSpec) DeepCopy() *SwiftOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftOperatorConfigStatus) DeepCopyInto(out *SwiftOperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftOperatorConfigStatus.
+func (in *SwiftOperatorConfigStatus) DeepCopy() *SwiftOperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftOperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxy) DeepCopyInto(out *SwiftProxy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxy.
+func (in *SwiftProxy) DeepCopy() *SwiftProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftProxy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyAutoscaling) DeepCopyInto(out *SwiftProxyAutoscaling) {
+	*out = *in
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequestRate != nil {
+		in, out := &in.RequestRate, &out.RequestRate
+		*out = new(SwiftProxyMetricTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(SwiftProxyMetricTarget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyAutoscaling.
+func (in *SwiftProxyAutoscaling) DeepCopy() *SwiftProxyAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyBreakGlass) DeepCopyInto(out *SwiftProxyBreakGlass) {
+	*out = *in
+	in.AllowedClients.DeepCopyInto(&out.AllowedClients)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyBreakGlass.
+func (in *SwiftProxyBreakGlass) DeepCopy() *SwiftProxyBreakGlass {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyBreakGlass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyConsistencyCheck) DeepCopyInto(out *SwiftProxyConsistencyCheck) {
+	*out = *in
+	if in.Accounts != nil {
+		in, out := &in.Accounts, &out.Accounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyConsistencyCheck.
+func (in *SwiftProxyConsistencyCheck) DeepCopy() *SwiftProxyConsistencyCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyConsistencyCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyConsistencyResult) DeepCopyInto(out *SwiftProxyConsistencyResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyConsistencyResult.
+func (in *SwiftProxyConsistencyResult) DeepCopy() *SwiftProxyConsistencyResult {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyConsistencyResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyDataVerification) DeepCopyInto(out *SwiftProxyDataVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyDataVerification.
+func (in *SwiftProxyDataVerification) DeepCopy() *SwiftProxyDataVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyDataVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyKeystoneAuth) DeepCopyInto(out *SwiftProxyKeystoneAuth) {
+	*out = *in
+	if in.OperatorRoles != nil {
+		in, out := &in.OperatorRoles, &out.OperatorRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowOverrides != nil {
+		in, out := &in.AllowOverrides, &out.AllowOverrides
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowNamesInACLs != nil {
+		in, out := &in.AllowNamesInACLs, &out.AllowNamesInACLs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SystemReaderRoles != nil {
+		in, out := &in.SystemReaderRoles, &out.SystemReaderRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProjectReaderRoles != nil {
+		in, out := &in.ProjectReaderRoles, &out.ProjectReaderRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyKeystoneAuth.
+func (in *SwiftProxyKeystoneAuth) DeepCopy() *SwiftProxyKeystoneAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyKeystoneAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyLifecycle) DeepCopyInto(out *SwiftProxyLifecycle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyLifecycle.
+func (in *SwiftProxyLifecycle) DeepCopy() *SwiftProxyLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyList) DeepCopyInto(out *SwiftProxyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SwiftProxy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyList.
+func (in *SwiftProxyList) DeepCopy() *SwiftProxyList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftProxyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyMetricTarget) DeepCopyInto(out *SwiftProxyMetricTarget) {
+	*out = *in
+	out.TargetAverageValue = in.TargetAverageValue.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyMetricTarget.
+func (in *SwiftProxyMetricTarget) DeepCopy() *SwiftProxyMetricTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyMetricTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyPool) DeepCopyInto(out *SwiftProxyPool) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyPool.
+func (in *SwiftProxyPool) DeepCopy() *SwiftProxyPool {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyReadOnly) DeepCopyInto(out *SwiftProxyReadOnly) {
+	*out = *in
+	if in.WritableAccounts != nil {
+		in, out := &in.WritableAccounts, &out.WritableAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyReadOnly.
+func (in *SwiftProxyReadOnly) DeepCopy() *SwiftProxyReadOnly {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyReadOnly)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxySpec) DeepCopyInto(out *SwiftProxySpec) {
+	*out = *in
+	out.PasswordSelectors = in.PasswordSelectors
+	if in.SwiftConfSecretStore != nil {
+		in, out := &in.SwiftConfSecretStore, &out.SwiftConfSecretStore
+		*out = new(SecretStore)
+		**out = **in
+	}
+	if in.CredentialsSecretStore != nil {
+		in, out := &in.CredentialsSecretStore, &out.CredentialsSecretStore
+		*out = new(SecretStore)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(SwiftProxyAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerEnv != nil {
+		in, out := &in.ContainerEnv, &out.ContainerEnv
+		*out = make(map[string]ContainerEnv, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.NofileLimits != nil {
+		in, out := &in.NofileLimits, &out.NofileLimits
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[string]v1.ResourceRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ExtraMounts != nil {
+		in, out := &in.ExtraMounts, &out.ExtraMounts
+		*out = make([]ExtraVolMounts, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KeystoneAuth != nil {
+		in, out := &in.KeystoneAuth, &out.KeystoneAuth
+		*out = new(SwiftProxyKeystoneAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(SwiftProxyTLS)
+		**out = **in
+	}
+	if in.ConsistencyCheck != nil {
+		in, out := &in.ConsistencyCheck, &out.ConsistencyCheck
+		*out = new(SwiftProxyConsistencyCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataVerification != nil {
+		in, out := &in.DataVerification, &out.DataVerification
+		*out = new(SwiftProxyDataVerification)
+		**out = **in
+	}
+	if in.UsageExport != nil {
+		in, out := &in.UsageExport, &out.UsageExport
+		*out = new(SwiftProxyUsageExport)
+		**out = **in
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]SwiftProxyPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AccountPolicies != nil {
+		in, out := &in.AccountPolicies, &out.AccountPolicies
+		*out = make([]SwiftAccountPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PublicContainers != nil {
+		in, out := &in.PublicContainers, &out.PublicContainers
+		*out = make([]SwiftPublicContainer, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(SwiftProxyReadOnly)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BreakGlass != nil {
+		in, out := &in.BreakGlass, &out.BreakGlass
+		*out = new(SwiftProxyBreakGlass)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultConfigOverwrite != nil {
+		in, out := &in.DefaultConfigOverwrite, &out.DefaultConfigOverwrite
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ContainerSync != nil {
+		in, out := &in.ContainerSync, &out.ContainerSync
+		*out = new(SwiftContainerSync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(SwiftProxyLifecycle)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxySpec.
+func (in *SwiftProxySpec) DeepCopy() *SwiftProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyStatus) DeepCopyInto(out *SwiftProxyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.APIEndpoints != nil {
+		in, out := &in.APIEndpoints, &out.APIEndpoints
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RingSync != nil {
+		in, out := &in.RingSync, &out.RingSync
+		*out = make(map[string]RingSyncStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Terminations != nil {
+		in, out := &in.Terminations, &out.Terminations
+		*out = make(map[string]ContainerTermination, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DryRunDiff != nil {
+		in, out := &in.DryRunDiff, &out.DryRunDiff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConsistencyCheck != nil {
+		in, out := &in.ConsistencyCheck, &out.ConsistencyCheck
+		*out = new(SwiftProxyConsistencyResult)
+		**out = **in
+	}
+	if in.WritableAccounts != nil {
+		in, out := &in.WritableAccounts, &out.WritableAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicContainers != nil {
+		in, out := &in.PublicContainers, &out.PublicContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyStatus.
+func (in *SwiftProxyStatus) DeepCopy() *SwiftProxyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyTLS) DeepCopyInto(out *SwiftProxyTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyTLS.
+func (in *SwiftProxyTLS) DeepCopy() *SwiftProxyTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftProxyUsageExport) DeepCopyInto(out *SwiftProxyUsageExport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftProxyUsageExport.
+func (in *SwiftProxyUsageExport) DeepCopy() *SwiftProxyUsageExport {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftProxyUsageExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftPublicContainer) DeepCopyInto(out *SwiftPublicContainer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftPublicContainer.
+func (in *SwiftPublicContainer) DeepCopy() *SwiftPublicContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftPublicContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRing) DeepCopyInto(out *SwiftRing) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRing.
+func (in *SwiftRing) DeepCopy() *SwiftRing {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftRing) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingBackupTarget) DeepCopyInto(out *SwiftRingBackupTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingBackupTarget.
+func (in *SwiftRingBackupTarget) DeepCopy() *SwiftRingBackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingBackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingBarbicanKeys) DeepCopyInto(out *SwiftRingBarbicanKeys) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingBarbicanKeys.
+func (in *SwiftRingBarbicanKeys) DeepCopy() *SwiftRingBarbicanKeys {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingBarbicanKeys)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingEncryption) DeepCopyInto(out *SwiftRingEncryption) {
+	*out = *in
+	if in.Barbican != nil {
+		in, out := &in.Barbican, &out.Barbican
+		*out = new(SwiftRingBarbicanKeys)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingEncryption.
+func (in *SwiftRingEncryption) DeepCopy() *SwiftRingEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingFailedDevice) DeepCopyInto(out *SwiftRingFailedDevice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingFailedDevice.
+func (in *SwiftRingFailedDevice) DeepCopy() *SwiftRingFailedDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingFailedDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingList) DeepCopyInto(out *SwiftRingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SwiftRing, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingList.
+func (in *SwiftRingList) DeepCopy() *SwiftRingList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftRingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingPartPowerIncrease) DeepCopyInto(out *SwiftRingPartPowerIncrease) {
+	*out = *in
+	if in.CompletedPods != nil {
+		in, out := &in.CompletedPods, &out.CompletedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingPartPowerIncrease.
+func (in *SwiftRingPartPowerIncrease) DeepCopy() *SwiftRingPartPowerIncrease {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingPartPowerIncrease)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingSpec) DeepCopyInto(out *SwiftRingSpec) {
+	*out = *in
+	if in.SwiftConfSecretStore != nil {
+		in, out := &in.SwiftConfSecretStore, &out.SwiftConfSecretStore
+		*out = new(SecretStore)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(SwiftRingEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupTarget != nil {
+		in, out := &in.BackupTarget, &out.BackupTarget
+		*out = new(SwiftRingBackupTarget)
+		**out = **in
+	}
+	if in.StoragePolicies != nil {
+		in, out := &in.StoragePolicies, &out.StoragePolicies
+		*out = make([]SwiftStoragePolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeviceWeights != nil {
+		in, out := &in.DeviceWeights, &out.DeviceWeights
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailedDevices != nil {
+		in, out := &in.FailedDevices, &out.FailedDevices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingSpec.
+func (in *SwiftRingSpec) DeepCopy() *SwiftRingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftRingStatus) DeepCopyInto(out *SwiftRingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DryRunDiff != nil {
+		in, out := &in.DryRunDiff, &out.DryRunDiff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PartPowerIncrease != nil {
+		in, out := &in.PartPowerIncrease, &out.PartPowerIncrease
+		*out = new(SwiftRingPartPowerIncrease)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailedDevices != nil {
+		in, out := &in.FailedDevices, &out.FailedDevices
+		*out = make(map[string]SwiftRingFailedDevice, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftRingStatus.
+func (in *SwiftRingStatus) DeepCopy() *SwiftRingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftRingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftScalingSchedule) DeepCopyInto(out *SwiftScalingSchedule) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.ProxyReplicas != nil {
+		in, out := &in.ProxyReplicas, &out.ProxyReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftScalingSchedule.
+func (in *SwiftScalingSchedule) DeepCopy() *SwiftScalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftScalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftSecretGrant) DeepCopyInto(out *SwiftSecretGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSecretGrant.
+func (in *SwiftSecretGrant) DeepCopy() *SwiftSecretGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftSecretGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftSecretGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftSecretGrantFrom) DeepCopyInto(out *SwiftSecretGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSecretGrantFrom.
+func (in *SwiftSecretGrantFrom) DeepCopy() *SwiftSecretGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftSecretGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftSecretGrantList) DeepCopyInto(out *SwiftSecretGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SwiftSecretGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSecretGrantList.
+func (in *SwiftSecretGrantList) DeepCopy() *SwiftSecretGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftSecretGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftSecretGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftSecretGrantSpec) DeepCopyInto(out *SwiftSecretGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]SwiftSecretGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretNames != nil {
+		in, out := &in.SecretNames, &out.SecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSecretGrantSpec.
+func (in *SwiftSecretGrantSpec) DeepCopy() *SwiftSecretGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftSecretGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftSecretGrantStatus) DeepCopyInto(out *SwiftSecretGrantStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Copies != nil {
+		in, out := &in.Copies, &out.Copies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSecretGrantStatus.
+func (in *SwiftSecretGrantStatus) DeepCopy() *SwiftSecretGrantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftSecretGrantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftSpec) DeepCopyInto(out *SwiftSpec) {
+	*out = *in
+	in.SwiftRing.DeepCopyInto(&out.SwiftRing)
+	in.SwiftStorage.DeepCopyInto(&out.SwiftStorage)
+	in.SwiftProxy.DeepCopyInto(&out.SwiftProxy)
+	if in.SwiftConfSecretStore != nil {
+		in, out := &in.SwiftConfSecretStore, &out.SwiftConfSecretStore
+		*out = new(SecretStore)
+		**out = **in
+	}
+	if in.ScalingSchedule != nil {
+		in, out := &in.ScalingSchedule, &out.ScalingSchedule
+		*out = make([]SwiftScalingSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContainerSync != nil {
+		in, out := &in.ContainerSync, &out.ContainerSync
+		*out = new(SwiftContainerSync)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSpec.
+func (in *SwiftSpec) DeepCopy() *SwiftSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStatus) DeepCopyInto(out *SwiftStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(condition.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImageDigests != nil {
+		in, out := &in.ImageDigests, &out.ImageDigests
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PreviousImages != nil {
+		in, out := &in.PreviousImages, &out.PreviousImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RollbackImages != nil {
+		in, out := &in.RollbackImages, &out.RollbackImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UpgradeCheckedImages != nil {
+		in, out := &in.UpgradeCheckedImages, &out.UpgradeCheckedImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ActiveSchedules != nil {
+		in, out := &in.ActiveSchedules, &out.ActiveSchedules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicContainers != nil {
+		in, out := &in.PublicContainers, &out.PublicContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStatus.
+func (in *SwiftStatus) DeepCopy() *SwiftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorage) DeepCopyInto(out *SwiftStorage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorage.
+func (in *SwiftStorage) DeepCopy() *SwiftStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftStorage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageCrashCollector) DeepCopyInto(out *SwiftStorageCrashCollector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageCrashCollector.
+func (in *SwiftStorageCrashCollector) DeepCopy() *SwiftStorageCrashCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageCrashCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageDatabaseDevice) DeepCopyInto(out *SwiftStorageDatabaseDevice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageDatabaseDevice.
+func (in *SwiftStorageDatabaseDevice) DeepCopy() *SwiftStorageDatabaseDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageDatabaseDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageDiskDiscovery) DeepCopyInto(out *SwiftStorageDiskDiscovery) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ApprovedDisks != nil {
+		in, out := &in.ApprovedDisks, &out.ApprovedDisks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageDiskDiscovery.
+func (in *SwiftStorageDiskDiscovery) DeepCopy() *SwiftStorageDiskDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageDiskDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageExpirer) DeepCopyInto(out *SwiftStorageExpirer) {
+	*out = *in
+	if in.DelayReaping != nil {
+		in, out := &in.DelayReaping, &out.DelayReaping
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(SwiftStorageExpirerAutoscaling)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageExpirer.
+func (in *SwiftStorageExpirer) DeepCopy() *SwiftStorageExpirer {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageExpirer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageExpirerAutoscaling) DeepCopyInto(out *SwiftStorageExpirerAutoscaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageExpirerAutoscaling.
+func (in *SwiftStorageExpirerAutoscaling) DeepCopy() *SwiftStorageExpirerAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageExpirerAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageHealth) DeepCopyInto(out *SwiftStorageHealth) {
+	*out = *in
+	if in.ReplicationLag != nil {
+		in, out := &in.ReplicationLag, &out.ReplicationLag
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UnmountedDrives != nil {
+		in, out := &in.UnmountedDrives, &out.UnmountedDrives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnreachableServers != nil {
+		in, out := &in.UnreachableServers, &out.UnreachableServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageHealth.
+func (in *SwiftStorageHealth) DeepCopy() *SwiftStorageHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageHealthCheck) DeepCopyInto(out *SwiftStorageHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageHealthCheck.
+func (in *SwiftStorageHealthCheck) DeepCopy() *SwiftStorageHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageImages) DeepCopyInto(out *SwiftStorageImages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageImages.
+func (in *SwiftStorageImages) DeepCopy() *SwiftStorageImages {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageList) DeepCopyInto(out *SwiftStorageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SwiftStorage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageList.
+func (in *SwiftStorageList) DeepCopy() *SwiftStorageList {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SwiftStorageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageMetadataTier) DeepCopyInto(out *SwiftStorageMetadataTier) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageMetadataTier.
+func (in *SwiftStorageMetadataTier) DeepCopy() *SwiftStorageMetadataTier {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageMetadataTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStoragePolicy) DeepCopyInto(out *SwiftStoragePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStoragePolicy.
+func (in *SwiftStoragePolicy) DeepCopy() *SwiftStoragePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStoragePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageRevisions) DeepCopyInto(out *SwiftStorageRevisions) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageRevisions.
+func (in *SwiftStorageRevisions) DeepCopy() *SwiftStorageRevisions {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageRevisions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageScrub) DeepCopyInto(out *SwiftStorageScrub) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(SwiftStorageScrubWindow)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageScrub.
+func (in *SwiftStorageScrub) DeepCopy() *SwiftStorageScrub {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageScrub)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageScrubWindow) DeepCopyInto(out *SwiftStorageScrubWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageScrubWindow.
+func (in *SwiftStorageScrubWindow) DeepCopy() *SwiftStorageScrubWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageScrubWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageSpec) DeepCopyInto(out *SwiftStorageSpec) {
+	*out = *in
+	if in.SwiftConfSecretStore != nil {
+		in, out := &in.SwiftConfSecretStore, &out.SwiftConfSecretStore
+		*out = new(SecretStore)
+		**out = **in
+	}
+	if in.CrashCollector != nil {
+		in, out := &in.CrashCollector, &out.CrashCollector
+		*out = new(SwiftStorageCrashCollector)
+		**out = **in
+	}
+	if in.ContainerEnv != nil {
+		in, out := &in.ContainerEnv, &out.ContainerEnv
+		*out = make(map[string]ContainerEnv, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.NofileLimits != nil {
+		in, out := &in.NofileLimits, &out.NofileLimits
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[string]v1.ResourceRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ExtraMounts != nil {
+		in, out := &in.ExtraMounts, &out.ExtraMounts
+		*out = make([]ExtraVolMounts, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ArchitectureImages != nil {
+		in, out := &in.ArchitectureImages, &out.ArchitectureImages
+		*out = make(map[string]SwiftStorageImages, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.DefaultConfigOverwrite != nil {
+		in, out := &in.DefaultConfigOverwrite, &out.DefaultConfigOverwrite
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(SwiftStorageTLS)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(SwiftStorageHealthCheck)
+		**out = **in
+	}
+	if in.DiskDiscovery != nil {
+		in, out := &in.DiskDiscovery, &out.DiskDiscovery
+		*out = new(SwiftStorageDiskDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CordonedOrdinals != nil {
+		in, out := &in.CordonedOrdinals, &out.CordonedOrdinals
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.DrainOrdinals != nil {
+		in, out := &in.DrainOrdinals, &out.DrainOrdinals
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Scrub != nil {
+		in, out := &in.Scrub, &out.Scrub
+		*out = new(SwiftStorageScrub)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Expirer.DeepCopyInto(&out.Expirer)
+	if in.DatabaseDevice != nil {
+		in, out := &in.DatabaseDevice, &out.DatabaseDevice
+		*out = new(SwiftStorageDatabaseDevice)
+		**out = **in
+	}
+	if in.MetadataTier != nil {
+		in, out := &in.MetadataTier, &out.MetadataTier
+		*out = new(SwiftStorageMetadataTier)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSync != nil {
+		in, out := &in.ContainerSync, &out.ContainerSync
+		*out = new(SwiftContainerSync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestartDaemons != nil {
+		in, out := &in.RestartDaemons, &out.RestartDaemons
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageSpec.
+func (in *SwiftStorageSpec) DeepCopy() *SwiftStorageSpec {
 	if in == nil {
 		return nil
 	}
@@ -456,6 +2490,53 @@ func (in *SwiftStorageStatus) DeepCopyInto(out *SwiftStorageStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RingSync != nil {
+		in, out := &in.RingSync, &out.RingSync
+		*out = make(map[string]RingSyncStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Terminations != nil {
+		in, out := &in.Terminations, &out.Terminations
+		*out = make(map[string]ContainerTermination, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make(map[string]ContainerResourceRecommendation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.DryRunDiff != nil {
+		in, out := &in.DryRunDiff, &out.DryRunDiff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(SwiftStorageHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = new(SwiftStorageRevisions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RingZones != nil {
+		in, out := &in.RingZones, &out.RingZones
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProgressStartTime != nil {
+		in, out := &in.ProgressStartTime, &out.ProgressStartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageStatus.
@@ -467,3 +2548,18 @@ func (in *SwiftStorageStatus) DeepCopy() *SwiftStorageStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftStorageTLS) DeepCopyInto(out *SwiftStorageTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftStorageTLS.
+func (in *SwiftStorageTLS) DeepCopy() *SwiftStorageTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftStorageTLS)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// swift-operator-cli renders configs, validates CRs and computes the ring
+// plan from a CR YAML file without connecting to a cluster
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
+)
+
+const usage = `Usage: swift-operator-cli <command> -f <cr.yaml>
+
+Commands:
+  validate       decode, default and validate a Swift, SwiftRing, SwiftStorage or SwiftProxy CR
+                 with the checks of its webhooks
+  render-config  render the storage config files of a Swift or SwiftStorage CR
+  ring-plan      print the device list and ring builder commands of a Swift or SwiftStorage CR
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	file := fs.String("f", "", "Path of the CR YAML file")
+	ringReplicas := fs.Int64("ring-replicas", 1, "Number of ring replicas, used if the CR is a SwiftStorage")
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		os.Exit(2)
+	}
+	if *file == "" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// The image defaults of the webhooks, from the same environment
+	// variables as the operator
+	swiftv1beta1.SetupDefaults()
+
+	var err error
+	switch os.Args[1] {
+	case "validate":
+		err = validate(*file)
+	case "render-config":
+		err = renderConfig(*file)
+	case "ring-plan":
+		err = ringPlan(*file, *ringReplicas)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// readCR decodes the CR in the given file. In strict mode unknown fields,
+// which the API server would drop, are reported as errors.
+func readCR(file string, strict bool) (interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	typeMeta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion != swiftv1beta1.GroupVersion.String() {
+		return nil, fmt.Errorf("unsupported apiVersion %q", typeMeta.APIVersion)
+	}
+
+	var obj interface{}
+	switch typeMeta.Kind {
+	case "Swift":
+		obj = &swiftv1beta1.Swift{}
+	case "SwiftRing":
+		obj = &swiftv1beta1.SwiftRing{}
+	case "SwiftStorage":
+		obj = &swiftv1beta1.SwiftStorage{}
+	case "SwiftProxy":
+		obj = &swiftv1beta1.SwiftProxy{}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", typeMeta.Kind)
+	}
+	if strict {
+		err = yaml.UnmarshalStrict(data, obj)
+	} else {
+		err = yaml.Unmarshal(data, obj)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// readStorage returns the SwiftStorage and the SwiftRing spec of a Swift or
+// SwiftStorage CR, defaulted as by the webhooks. The SwiftRing spec of a
+// SwiftStorage CR only has the given number of ring replicas.
+func readStorage(file string, ringReplicas int64) (*swiftv1beta1.SwiftStorage, *swiftv1beta1.SwiftRingSpec, error) {
+	obj, err := readCR(file, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var instance *swiftv1beta1.SwiftStorage
+	var ring *swiftv1beta1.SwiftRingSpec
+	switch cr := obj.(type) {
+	case *swiftv1beta1.Swift:
+		cr.Default()
+		instance = &swiftv1beta1.SwiftStorage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-storage", cr.Name),
+				Namespace: cr.Namespace,
+			},
+			Spec: cr.Spec.SwiftStorage,
+		}
+		ring = &cr.Spec.SwiftRing
+	case *swiftv1beta1.SwiftStorage:
+		cr.Default()
+		instance = cr
+		ring = &swiftv1beta1.SwiftRingSpec{RingReplicas: ringReplicas}
+		ring.Default()
+	default:
+		return nil, nil, errors.New("a Swift or SwiftStorage CR is required")
+	}
+
+	// Mirror the CRD defaults, these are set by the API server otherwise
+	if instance.Spec.DeviceName == "" {
+		instance.Spec.DeviceName = "d1"
+	}
+	if instance.Spec.NodeRoot == "" {
+		instance.Spec.NodeRoot = "/srv/node"
+	}
+	if instance.Spec.DisksPerReplica == 0 {
+		instance.Spec.DisksPerReplica = 1
+	}
+	if instance.Spec.StorageRequest == "" {
+		instance.Spec.StorageRequest = swift.DefaultStorageRequest
+	}
+	if db := instance.Spec.DatabaseDevice; db != nil {
+		if db.DeviceName == "" {
+			db.DeviceName = "db"
+		}
+		if db.StorageRequest == "" {
+			db.StorageRequest = "1Gi"
+		}
+	}
+
+	return instance, ring, nil
+}
+
+// validate runs the defaulting and the create validation of the webhook of
+// the kind of the CR. The checks against other resources of the cluster are
+// skipped offline.
+func validate(file string) error {
+	obj, err := readCR(file, true)
+	if err != nil {
+		return err
+	}
+
+	errs := []string{}
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	switch cr := obj.(type) {
+	case *swiftv1beta1.Swift:
+		cr.Default()
+		check(cr.ValidateCreate())
+		// The SwiftStorage section is validated by the webhook of the
+		// SwiftStorage the controller creates from it
+		check((&swiftv1beta1.SwiftStorage{Spec: cr.Spec.SwiftStorage}).ValidateCreate())
+		// Every disk of a storage pod is a device of the rings
+		disks := cr.Spec.SwiftStorage.DisksPerReplica
+		if disks == 0 {
+			disks = 1
+		}
+		if devices := cr.Spec.SwiftStorage.Replicas * disks; cr.Spec.SwiftRing.RingReplicas > int64(devices) {
+			errs = append(errs, fmt.Sprintf(
+				"ringReplicas (%d) exceeds the number of storage devices (%d)", cr.Spec.SwiftRing.RingReplicas, devices))
+		}
+	case *swiftv1beta1.SwiftRing:
+		cr.Default()
+		check(cr.ValidateCreate())
+	case *swiftv1beta1.SwiftStorage:
+		cr.Default()
+		check(cr.ValidateCreate())
+	case *swiftv1beta1.SwiftProxy:
+		cr.Default()
+		check(cr.ValidateCreate())
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	fmt.Println("valid")
+	return nil
+}
+
+func renderConfig(file string) error {
+	instance, _, err := readStorage(file, 0)
+	if err != nil {
+		return err
+	}
+
+	data, err := util.GetTemplateData(util.Template{
+		Type:          util.TemplateTypeConfig,
+		InstanceType:  "SwiftStorage",
+		ConfigOptions: swift.GetStorageTemplateParameters(instance),
+	})
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("### %s\n%s\n", name, data[name])
+	}
+	return nil
+}
+
+// ringPlan prints the device list of the storage pods and the commands
+// creating the rings from it, with the device weights, the failed devices,
+// the storage policies and the part power of the SwiftRing spec
+func ringPlan(file string, ringReplicas int64) error {
+	instance, ring, err := readStorage(file, ringReplicas)
+	if err != nil {
+		return err
+	}
+
+	// PVC capacities are unknown offline, assume the requested size
+	q, err := resource.ParseQuantity(instance.Spec.StorageRequest)
+	if err != nil {
+		return err
+	}
+
+	devices := []string{}
+	for replica := 0; replica < int(instance.Spec.Replicas); replica++ {
+		for _, device := range swift.GetDeviceNames(instance) {
+			entry := swift.GetDeviceListEntry(instance, replica, device, q.Value(), 0)
+			if instance.Spec.DatabaseDevice != nil {
+				entry = swift.SetDeviceListEntryRings(entry, swift.DeviceRingsObject)
+			}
+			devices = append(devices, strings.TrimSuffix(entry, "\n"))
+		}
+		if db := instance.Spec.DatabaseDevice; db != nil {
+			dbq, err := resource.ParseQuantity(db.StorageRequest)
+			if err != nil {
+				return err
+			}
+			entry := swift.SetDeviceListEntryRings(
+				swift.GetDeviceListEntry(instance, replica, db.DeviceName, dbq.Value(), 0), swift.DeviceRingsMetadata)
+			devices = append(devices, strings.TrimSuffix(entry, "\n"))
+		}
+	}
+
+	fmt.Println("### devices.csv")
+	for _, dev := range devices {
+		fmt.Println(dev)
+	}
+
+	// The devices are added to the rings as by the SwiftRing controller
+	parsed, err := swift.ParseDeviceList(strings.Join(devices, "\n"))
+	if err != nil {
+		return err
+	}
+	parsed = swift.RemoveFailedDevices(parsed, ring.FailedDevices)
+	swift.ApplyDeviceWeights(parsed, ring.DeviceWeights)
+
+	fmt.Println("### ring builder commands")
+	commands := swift.GetRingBuilderCommands(
+		parsed, int(ring.RingReplicas), int(swift.GetPartPower(ring)), ring.StoragePolicies)
+	for _, command := range commands {
+		fmt.Println(command)
+	}
+	return nil
+}
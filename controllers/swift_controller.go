@@ -18,9 +18,18 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/go-logr/logr"
 
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,8 +39,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
 	common_rbac "github.com/openstack-k8s-operators/lib-common/modules/common/rbac"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
@@ -59,6 +70,7 @@ type SwiftReconciler struct {
 // service account permissions that are needed to grant permission to the above
 // +kubebuilder:rbac:groups="security.openshift.io",resourceNames=anyuid;privileged,resources=securitycontextconstraints,verbs=use
 // +kubebuilder:rbac:groups="",resources=pods,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -110,6 +122,14 @@ func (r *SwiftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
+	// The validating webhook rejects an invalid spec, it only gets here while
+	// the webhooks are disabled
+	if err := instance.Spec.Validate(); err != nil {
+		r.Log.Info(fmt.Sprintf("Invalid spec of Swift '%s': %s", instance.Name, err))
+		swift.SetInvalidSpecCondition(&instance.Status.Conditions, err, condition.ReadyCondition)
+		return ctrl.Result{}, r.Status().Update(ctx, instance)
+	}
+
 	// Service account, role, binding
 	rbacRules := []rbacv1.PolicyRule{
 		{
@@ -123,6 +143,11 @@ func (r *SwiftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 			Resources: []string{"pods"},
 			Verbs:     []string{"create", "get", "list", "watch", "update", "patch", "delete"},
 		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods/log"},
+			Verbs:     []string{"get"},
+		},
 		{
 			APIGroups: []string{""},
 			Resources: []string{"configmaps"},
@@ -143,20 +168,88 @@ func (r *SwiftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 
 	labels := swift.GetLabelsSwift()
 
-	// Create a Secret populated with content from templates/
-	_, _, err = secret.GetSecret(ctx, helper, instance.Spec.SwiftConfSecret, instance.Namespace)
-	if err != nil {
-		if apierrors.IsNotFound(err) {
+	// The hash path prefix and suffix place the objects, new ones make all
+	// stored data unreachable. The webhook only covers the Secret created by
+	// the operator, a swift.conf supplied by the user or granted from another
+	// namespace is verified here as well.
+	if instance.Spec.SwiftConfSecretStore == nil {
+		hashPathChanged, err := verifySwiftConfHashPath(ctx, helper, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if hashPathChanged {
+			r.Log.Info(fmt.Sprintf("Hash path of swift.conf Secret %s of %s changed, not rolling it out",
+				instance.Spec.SwiftConfSecret, instance.Name))
+			return ctrl.Result{}, r.Status().Update(ctx, instance)
+		}
+	}
+
+	// Create a Secret populated with content from templates/, unless
+	// swift.conf is provided by a secret store. A Secret created by the
+	// operator is rendered again to keep the storage policies up to date,
+	// the hash path prefix and suffix never change.
+	// A swift.conf of another namespace is copied if it is granted.
+	if instance.Spec.SwiftConfSecretStore == nil && strings.Contains(instance.Spec.SwiftConfSecret, "/") {
+		if err := swift.EnsureGrantedSecret(ctx, helper, instance.Spec.SwiftConfSecret); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		swiftConf, _, err := secret.GetSecret(ctx, helper, instance.Spec.SwiftConfSecret, instance.Namespace)
+		if err != nil && !apierrors.IsNotFound(err) && instance.Spec.SwiftConfSecretStore == nil {
+			return ctrl.Result{}, err
+		}
+		if instance.Spec.SwiftConfSecretStore == nil && (err != nil || metav1.IsControlledBy(swiftConf, instance)) {
 			envVars := make(map[string]env.Setter)
-			tpl := getSwiftSecretTemplates(instance, labels)
+			tpl := getSwiftSecretTemplates(instance, util.MergeStringMaps(labels,
+				map[string]string{swift.SwiftConfSecretLabel: "true"}), swiftConf)
 			err = secret.EnsureSecrets(ctx, helper, instance, tpl, &envVars)
 			if err != nil {
 				return ctrl.Result{}, err
 			}
-		} else {
+		}
+	}
+
+	// Scaling schedule windows in effect, these are applied to the spec of
+	// the services
+	activeSchedules, err := swift.GetActiveSchedules(instance.Spec.ScalingSchedule, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !reflect.DeepEqual(activeSchedules, instance.Status.ActiveSchedules) {
+		r.Log.Info(fmt.Sprintf("Active scaling schedules of %s: %s", instance.Name, strings.Join(activeSchedules, ", ")))
+	}
+	instance.Status.ActiveSchedules = activeSchedules
+
+	// Start a requested rollback with the previous known-good images. The
+	// webhook rejects a rollback without them, a request that got through
+	// anyway is cleared.
+	if instance.Spec.Rollback && len(instance.Status.RollbackImages) == 0 {
+		if len(instance.Status.PreviousImages) == 0 {
+			r.Log.Info(fmt.Sprintf("Rollback of %s requested but no previous known-good images recorded", instance.Name))
+			instance.Spec.Rollback = false
+			return ctrl.Result{}, r.Update(ctx, instance)
+		}
+		instance.Status.RollbackImages = instance.Status.PreviousImages
+		if err := r.Status().Update(ctx, instance); err != nil {
 			return ctrl.Result{}, err
 		}
+		r.Log.Info(fmt.Sprintf("Rolling back %s to the previous known-good images", instance.Name))
+	} else if !instance.Spec.Rollback {
+		instance.Status.RollbackImages = nil
+	}
+	swift.SetRollbackCondition(&instance.Status.Conditions, instance.Status.RollbackImages)
+
+	// Check the cluster is safe to upgrade before applying new images
+	cmVars := make(map[string]env.Setter)
+	err = configmap.EnsureConfigMaps(ctx, helper, instance, getSwiftScriptsTemplates(instance, labels), &cmVars)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	upgradeResult, err := r.reconcilePreUpgradeCheck(ctx, instance, helper, labels)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
+	changed := false
 
 	// create or update Swift rings
 	swiftRing, op, err := r.ringCreateOrUpdate(ctx, instance)
@@ -171,6 +264,7 @@ func (r *SwiftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 	if op != controllerutil.OperationResultNone {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
+		changed = true
 	}
 
 	// Mirror SwiftRing's condition status
@@ -192,6 +286,7 @@ func (r *SwiftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 	if op != controllerutil.OperationResultNone {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
+		changed = true
 	}
 
 	// Mirror SwiftStorage's condition status
@@ -213,6 +308,7 @@ func (r *SwiftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 	if op != controllerutil.OperationResultNone {
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled - operation: %s", instance.Name, string(op)))
+		changed = true
 	}
 
 	// Mirror SwiftProxy's condition status
@@ -220,23 +316,129 @@ func (r *SwiftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	if c != nil {
 		instance.Status.Conditions.Set(c)
 	}
+	instance.Status.PublicContainers = swiftProxy.Status.PublicContainers
+	for _, t := range []condition.Type{
+		swiftv1beta1.ClusterReadOnlyCondition,
+		swiftv1beta1.BreakGlassEnabledCondition,
+		swiftv1beta1.ConsumerReadyCondition,
+		swiftv1beta1.DataVerificationCondition,
+	} {
+		if c := swiftProxy.Status.Conditions.Get(t); c != nil {
+			instance.Status.Conditions.Set(c)
+		} else {
+			instance.Status.Conditions.Remove(t)
+		}
+	}
+
+	// Export the effective configuration decided by the operator
+	effectiveConfig, err := swift.GetEffectiveConfig(ctx, helper, swiftRing, swiftStorage, swiftProxy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	err = configmap.EnsureConfigMaps(ctx, helper, instance, getEffectiveConfigTemplates(instance, labels, effectiveConfig), &cmVars)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
 	if instance.IsReady() {
+		// The status of the services may still be the one of the previous
+		// images if they were just changed or not reconciled yet
+		rollbackDone := false
+		if !changed &&
+			swiftRing.Status.ObservedGeneration == swiftRing.Generation &&
+			swiftStorage.Status.ObservedGeneration == swiftStorage.Generation &&
+			swiftProxy.Status.ObservedGeneration == swiftProxy.Generation {
+			digests, err := swift.GetImageDigests(ctx, helper, instance.Namespace, swift.GetImages(getEffectiveSpec(instance)))
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			rollbackDone = recordImages(instance, digests)
+		}
 		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
 		if err := r.Status().Update(ctx, instance); err != nil {
 			return ctrl.Result{}, err
 		}
 
+		if rollbackDone {
+			swift.SetImages(&instance.Spec, instance.Status.Images)
+			instance.Spec.Rollback = false
+			if err := r.Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Log.Info(fmt.Sprintf("Rollback of %s completed", instance.Name))
+		}
+
 		r.Log.Info(fmt.Sprintf("Deployment %s successfully reconciled", instance.Name))
 	}
 
-	return ctrl.Result{}, nil
+	// Check for the start and end of the scaling schedule windows
+	if (upgradeResult == ctrl.Result{}) && len(instance.Spec.ScalingSchedule) > 0 {
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	return upgradeResult, nil
 }
 
-func getSwiftSecretTemplates(instance *swiftv1beta1.Swift, labels map[string]string) []util.Template {
+// verifySwiftConfHashPath compares the hash path prefix and suffix of the
+// swift.conf Secret with the ones recorded in the status. The first ones seen
+// are recorded, a change only with the HashChangeAnnotation on the Secret.
+// Returns true and sets the HashPathChanged condition if they changed without
+// it.
+func verifySwiftConfHashPath(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.Swift) (bool, error) {
+	namespace, name, found := strings.Cut(instance.Spec.SwiftConfSecret, "/")
+	if !found {
+		namespace, name = instance.Namespace, instance.Spec.SwiftConfSecret
+	}
+	swiftConf, _, err := secret.GetSecret(ctx, h, name, namespace)
+	if apierrors.IsNotFound(err) {
+		// Recorded once the Secret is created or granted
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	conf := swiftConf.Data["swift.conf"]
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(swift.GetSwiftConfValue(conf, "swift_hash_path_prefix")+"\n"+
+		swift.GetSwiftConfValue(conf, "swift_hash_path_suffix"))))
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	recorded, ok := instance.Status.Hash[swiftv1beta1.SwiftConfHashPathHash]
+	if ok && recorded != hash && swiftConf.Annotations[swiftv1beta1.HashChangeAnnotation] != "true" {
+		instance.Status.Conditions.Set(condition.TrueCondition(
+			swiftv1beta1.HashPathChangedCondition,
+			swiftv1beta1.HashPathChangedMessage,
+			instance.Spec.SwiftConfSecret, swiftv1beta1.HashChangeAnnotation))
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityError,
+			swiftv1beta1.HashPathChangedMessage,
+			instance.Spec.SwiftConfSecret, swiftv1beta1.HashChangeAnnotation))
+		return true, nil
+	}
+	instance.Status.Hash[swiftv1beta1.SwiftConfHashPathHash] = hash
+	instance.Status.Conditions.Remove(swiftv1beta1.HashPathChangedCondition)
+	return false, nil
+}
+
+// getSwiftSecretTemplates returns the swift.conf Secret template, the hash
+// path prefix and suffix of the existing Secret are kept
+func getSwiftSecretTemplates(instance *swiftv1beta1.Swift, labels map[string]string, existing *corev1.Secret) []util.Template {
+	prefix := swift.RandomString(16)
+	suffix := swift.RandomString(16)
+	if existing != nil {
+		if v := swift.GetSwiftConfValue(existing.Data["swift.conf"], "swift_hash_path_prefix"); v != "" {
+			prefix = v
+		}
+		if v := swift.GetSwiftConfValue(existing.Data["swift.conf"], "swift_hash_path_suffix"); v != "" {
+			suffix = v
+		}
+	}
+
 	templateParameters := make(map[string]interface{})
-	templateParameters["SwiftHashPathPrefix"] = swift.RandomString(16)
-	templateParameters["SwiftHashPathSuffix"] = swift.RandomString(16)
+	templateParameters["SwiftHashPathPrefix"] = prefix
+	templateParameters["SwiftHashPathSuffix"] = suffix
+	templateParameters["StoragePolicies"] = getEffectiveSpec(instance).SwiftRing.StoragePolicies
 
 	return []util.Template{
 		{
@@ -250,6 +452,31 @@ func getSwiftSecretTemplates(instance *swiftv1beta1.Swift, labels map[string]str
 	}
 }
 
+func getSwiftScriptsTemplates(instance *swiftv1beta1.Swift, labels map[string]string) []util.Template {
+	return []util.Template{
+		{
+			Name:         fmt.Sprintf("%s-scripts", instance.Name),
+			Namespace:    instance.Namespace,
+			Type:         util.TemplateTypeScripts,
+			InstanceType: instance.Kind,
+			Labels:       labels,
+		},
+	}
+}
+
+func getEffectiveConfigTemplates(instance *swiftv1beta1.Swift, labels map[string]string, effectiveConfig string) []util.Template {
+	return []util.Template{
+		{
+			Name:         fmt.Sprintf("%s-effective-config", instance.Name),
+			Namespace:    instance.Namespace,
+			Type:         util.TemplateTypeNone,
+			InstanceType: instance.Kind,
+			Labels:       labels,
+			CustomData:   map[string]string{swift.EffectiveConfigKey: effectiveConfig},
+		},
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SwiftReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -257,15 +484,249 @@ func (r *SwiftReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&swiftv1beta1.SwiftRing{}).
 		Owns(&swiftv1beta1.SwiftStorage{}).
 		Owns(&swiftv1beta1.SwiftProxy{}).
+		Owns(&batchv1.Job{}).
 		Complete(r)
 }
 
+// getDesiredSpec returns the Swift spec with the active scaling schedules
+// applied. The profile is applied to the Swift CR by the defaulting webhook
+// on create. During a rollback the images are the ones being rolled back to.
+func getDesiredSpec(instance *swiftv1beta1.Swift) *swiftv1beta1.SwiftSpec {
+	spec := instance.Spec.DeepCopy()
+	spec.ApplyScalingSchedules(instance.Status.ActiveSchedules)
+	if instance.Spec.Rollback {
+		swift.SetImages(spec, instance.Status.RollbackImages)
+	}
+	return spec
+}
+
+// getEffectiveSpec returns the desired spec, with the known-good images
+// while new images did not pass the pre-upgrade check yet
+func getEffectiveSpec(instance *swiftv1beta1.Swift) *swiftv1beta1.SwiftSpec {
+	spec := getDesiredSpec(instance)
+	if isUpgradePending(instance, swift.GetImages(spec)) {
+		swift.SetImages(spec, instance.Status.Images)
+	}
+	return spec
+}
+
+// isUpgradePending returns true if the images differ from the known-good
+// ones and still need to pass the pre-upgrade check. The first deployment,
+// rollbacks and forced upgrades are not checked.
+func isUpgradePending(instance *swiftv1beta1.Swift, images map[string]string) bool {
+	return !instance.Spec.ForceUpgrade &&
+		len(instance.Status.RollbackImages) == 0 &&
+		len(instance.Status.Images) > 0 &&
+		!reflect.DeepEqual(images, instance.Status.Images) &&
+		!reflect.DeepEqual(images, instance.Status.UpgradeCheckedImages)
+}
+
+// reconcilePreUpgradeCheck runs the pre-upgrade check Job for new images. A
+// failed check is reported in the PreUpgradeCheckReady condition and keeps
+// the known-good images deployed. The failed Job is deleted, so the check
+// runs again after the resync interval.
+func (r *SwiftReconciler) reconcilePreUpgradeCheck(ctx context.Context, instance *swiftv1beta1.Swift, h *helper.Helper, labels map[string]string) (ctrl.Result, error) {
+	images := swift.GetImages(getDesiredSpec(instance))
+	if !isUpgradePending(instance, images) {
+		if !reflect.DeepEqual(images, instance.Status.UpgradeCheckedImages) {
+			instance.Status.Conditions.Remove(swiftv1beta1.PreUpgradeCheckReadyCondition)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	checkJob := getPreUpgradeCheckJob(instance, labels, images)
+	preUpgradeCheckJob := job.NewJob(checkJob, swiftv1beta1.PreUpgradeCheckHash, false, 5*time.Second,
+		instance.Status.Hash[swiftv1beta1.PreUpgradeCheckHash])
+	ctrlResult, err := preUpgradeCheckJob.DoJob(ctx, h)
+	if err != nil {
+		j, getErr := job.GetJobWithName(ctx, h, checkJob.Name, checkJob.Namespace)
+		if getErr != nil || j.Status.Failed == 0 {
+			return ctrl.Result{}, err
+		}
+		// The failures are kept in the condition, the pods are deleted
+		// with the Job
+		terminations, err := swift.GetContainerTerminations(ctx, h, instance.Namespace, swift.GetLabelsPreUpgradeCheck())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		failures := []string{}
+		for _, t := range terminations {
+			failures = append(failures, strings.TrimSpace(t.Message))
+		}
+		if len(failures) == 0 {
+			failures = append(failures, fmt.Sprintf("see the logs of Job %s", checkJob.Name))
+		}
+		sort.Strings(failures)
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.PreUpgradeCheckReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			swiftv1beta1.PreUpgradeCheckReadyErrorMessage,
+			strings.Join(failures, "; ")))
+		if j.DeletionTimestamp.IsZero() {
+			if err := job.DeleteJob(ctx, h, j.Name, j.Namespace); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	} else if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.PreUpgradeCheckReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.PreUpgradeCheckReadyRunningMessage))
+		return ctrlResult, nil
+	}
+
+	if preUpgradeCheckJob.HasChanged() {
+		instance.Status.Hash[swiftv1beta1.PreUpgradeCheckHash] = preUpgradeCheckJob.GetHash()
+		instance.Status.UpgradeCheckedImages = images
+		instance.Status.Conditions.MarkTrue(swiftv1beta1.PreUpgradeCheckReadyCondition, swiftv1beta1.PreUpgradeCheckReadyMessage)
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Log.Info(fmt.Sprintf("New images of %s passed the pre-upgrade check", instance.Name))
+	}
+	return ctrl.Result{}, nil
+}
+
+func getPreUpgradeCheckJob(instance *swiftv1beta1.Swift, labels map[string]string, images map[string]string) *batchv1.Job {
+	securityContext := swift.GetSecurityContext()
+	var scriptsVolumeDefaultMode int32 = 0755
+	var backoffLimit int32 = 0
+
+	newImages := []string{}
+	for component, image := range images {
+		newImages = append(newImages, fmt.Sprintf("%s=%s", component, image))
+	}
+	sort.Strings(newImages)
+
+	envVars := map[string]env.Setter{}
+	envVars["REPLICATION_LAG"] = env.SetValue(strconv.FormatInt(instance.Spec.UpgradeCheckReplicationLag, 10))
+	envVars["DISK_USAGE_LIMIT"] = env.SetValue(strconv.FormatInt(int64(instance.Spec.UpgradeCheckDiskUsage), 10))
+	envVars["NEW_IMAGES"] = env.SetValue(strings.Join(newImages, " "))
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-pre-upgrade-check",
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				// Allowed to query the storage servers by their NetworkPolicy
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: swift.GetLabelsPreUpgradeCheck(),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      "Never",
+					ServiceAccountName: swift.ServiceAccount,
+					SecurityContext: &corev1.PodSecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            instance.Name + "-pre-upgrade-check",
+							Command:         []string{"/usr/local/bin/container-scripts/pre-upgrade-check.sh"},
+							Image:           instance.Status.Images["proxy"],
+							SecurityContext: &securityContext,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "scripts",
+									MountPath: "/usr/local/bin/container-scripts",
+									ReadOnly:  true,
+								},
+								{
+									Name:      "ring-data",
+									MountPath: "/var/lib/config-data/rings",
+									ReadOnly:  true,
+								},
+							},
+							Env: env.MergeEnvs([]corev1.EnvVar{}, envVars),
+
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "scripts",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									DefaultMode: &scriptsVolumeDefaultMode,
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: instance.Name + "-scripts",
+									},
+								},
+							},
+						},
+						{
+							Name: "ring-data",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: swiftv1beta1.RingConfigMapName,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	// The recon queries use the CA certificates of the storage servers
+	swift.AddBackendTLS(&job.Spec.Template.Spec)
+	return job
+}
+
+// recordImages records the deployed images and their digests as known-good
+// once all services are ready. The digests of the images replaced become the
+// previous images used for rollback. Returns true if a rollback in progress
+// is completed.
+func recordImages(instance *swiftv1beta1.Swift, digests map[string]string) bool {
+	images := swift.GetImages(getEffectiveSpec(instance))
+	if len(instance.Status.RollbackImages) > 0 {
+		// The images before the rollback are not known-good
+		instance.Status.Images = images
+		instance.Status.ImageDigests = digests
+		instance.Status.PreviousImages = nil
+		instance.Status.RollbackImages = nil
+		swift.SetRollbackCondition(&instance.Status.Conditions, nil)
+		return true
+	}
+	if !reflect.DeepEqual(images, instance.Status.Images) {
+		instance.Status.PreviousImages = instance.Status.ImageDigests
+		if len(instance.Status.PreviousImages) == 0 {
+			// Recorded before the digests were
+			instance.Status.PreviousImages = instance.Status.Images
+		}
+		instance.Status.Images = images
+	}
+	instance.Status.ImageDigests = digests
+	return false
+}
+
 func (r *SwiftReconciler) ringCreateOrUpdate(ctx context.Context, instance *swiftv1beta1.Swift) (*swiftv1beta1.SwiftRing, controllerutil.OperationResult, error) {
+	spec := getEffectiveSpec(instance)
 
 	swiftRingSpec := swiftv1beta1.SwiftRingSpec{
-		RingReplicas:    instance.Spec.SwiftRing.RingReplicas,
-		ContainerImage:  instance.Spec.SwiftRing.ContainerImage,
-		SwiftConfSecret: instance.Spec.SwiftConfSecret,
+		RingReplicas:         spec.SwiftRing.RingReplicas,
+		ContainerImage:       spec.SwiftRing.ContainerImage,
+		SwiftConfSecret:      swift.GetLocalSecretName(spec.SwiftConfSecret),
+		RingBuilder:          spec.SwiftRing.RingBuilder,
+		SwiftConfSecretStore: spec.SwiftConfSecretStore,
+		Encryption:           spec.SwiftRing.Encryption,
+		BackupTarget:         spec.SwiftRing.BackupTarget,
+		StoragePolicies:      spec.SwiftRing.StoragePolicies,
+		DeviceWeights:        spec.SwiftRing.DeviceWeights,
+		FailedDevices:        spec.SwiftRing.FailedDevices,
+		PartPower:            spec.SwiftRing.PartPower,
 	}
 
 	deployment := &swiftv1beta1.SwiftRing{
@@ -277,6 +738,9 @@ func (r *SwiftReconciler) ringCreateOrUpdate(ctx context.Context, instance *swif
 
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
 		deployment.Spec = swiftRingSpec
+		swift.PropagateMetadata(instance, deployment)
+		// Let the rebalance of the child only be previewed as well
+		swift.SetDryRun(deployment, swift.IsDryRun(instance))
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
 		if err != nil {
 			return err
@@ -289,17 +753,61 @@ func (r *SwiftReconciler) ringCreateOrUpdate(ctx context.Context, instance *swif
 }
 
 func (r *SwiftReconciler) storageCreateOrUpdate(ctx context.Context, instance *swiftv1beta1.Swift) (*swiftv1beta1.SwiftStorage, controllerutil.OperationResult, error) {
+	spec := getEffectiveSpec(instance)
 
 	swiftStorageSpec := swiftv1beta1.SwiftStorageSpec{
-		Replicas:                instance.Spec.SwiftStorage.Replicas,
-		StorageClass:            instance.Spec.SwiftStorage.StorageClass,
-		StorageRequest:          instance.Spec.SwiftStorage.StorageRequest,
-		ContainerImageAccount:   instance.Spec.SwiftStorage.ContainerImageAccount,
-		ContainerImageContainer: instance.Spec.SwiftStorage.ContainerImageContainer,
-		ContainerImageObject:    instance.Spec.SwiftStorage.ContainerImageObject,
-		ContainerImageProxy:     instance.Spec.SwiftStorage.ContainerImageProxy,
-		ContainerImageMemcached: instance.Spec.SwiftStorage.ContainerImageMemcached,
-		SwiftConfSecret:         instance.Spec.SwiftConfSecret,
+		Replicas:                spec.SwiftStorage.Replicas,
+		StorageClass:            spec.SwiftStorage.StorageClass,
+		StorageRequest:          spec.SwiftStorage.StorageRequest,
+		ContainerImageAccount:   spec.SwiftStorage.ContainerImageAccount,
+		ContainerImageContainer: spec.SwiftStorage.ContainerImageContainer,
+		ContainerImageObject:    spec.SwiftStorage.ContainerImageObject,
+		ContainerImageProxy:     spec.SwiftStorage.ContainerImageProxy,
+		ContainerImageMemcached: spec.SwiftStorage.ContainerImageMemcached,
+		SwiftConfSecret:         swift.GetLocalSecretName(spec.SwiftConfSecret),
+		DeviceName:              spec.SwiftStorage.DeviceName,
+		DisksPerReplica:         spec.SwiftStorage.DisksPerReplica,
+		NodeRoot:                spec.SwiftStorage.NodeRoot,
+		ResourceRecommendations: spec.SwiftStorage.ResourceRecommendations,
+		CrashCollector:          spec.SwiftStorage.CrashCollector,
+		ContainerEnv:            spec.SwiftStorage.ContainerEnv,
+		NofileLimits:            spec.SwiftStorage.NofileLimits,
+		Resources:               spec.SwiftStorage.Resources,
+		ClockSkewThreshold:      spec.SwiftStorage.ClockSkewThreshold,
+		DriftPolicy:             spec.SwiftStorage.DriftPolicy,
+		Architectures:           spec.SwiftStorage.Architectures,
+		ArchitectureImages:      spec.SwiftStorage.ArchitectureImages,
+		ConfigOverrides:         spec.SwiftStorage.ConfigOverrides,
+		DefaultConfigOverwrite:  spec.SwiftStorage.DefaultConfigOverwrite,
+		ExtraMounts:             spec.SwiftStorage.ExtraMounts,
+		RsyncMetrics:            spec.SwiftStorage.RsyncMetrics,
+		StorageMetrics:          spec.SwiftStorage.StorageMetrics,
+		MinimalContainers:       spec.SwiftStorage.MinimalContainers || spec.Mode == swiftv1beta1.SwiftModeAIO,
+		SwiftConfSecretStore:    spec.SwiftConfSecretStore,
+		ObjectReconstructor:     len(spec.SwiftRing.StoragePolicies) > 0,
+		ScaleUpBatchSize:        spec.SwiftStorage.ScaleUpBatchSize,
+		TLS:                     spec.SwiftStorage.TLS,
+		PauseBackgroundDaemons:  spec.SwiftStorage.PauseBackgroundDaemons,
+		HealthCheck:             spec.SwiftStorage.HealthCheck,
+		RolloutTimeout:          spec.SwiftStorage.RolloutTimeout,
+		AutoRollback:            spec.SwiftStorage.AutoRollback,
+		ProgressDeadlineSeconds: spec.SwiftStorage.ProgressDeadlineSeconds,
+		CordonedOrdinals:        spec.SwiftStorage.CordonedOrdinals,
+		DrainOrdinals:           spec.SwiftStorage.DrainOrdinals,
+		Scrub:                   spec.SwiftStorage.Scrub,
+		Expirer:                 spec.SwiftStorage.Expirer,
+		DeviceInventory:         spec.SwiftStorage.DeviceInventory,
+		ContainerSync:           spec.ContainerSync,
+		MemcachedInstance:       spec.MemcachedInstance,
+		RestartDaemons:          spec.SwiftStorage.RestartDaemons,
+		ZoneAwareRings:          spec.SwiftStorage.ZoneAwareRings,
+		Region:                  spec.SwiftStorage.Region,
+		DatabaseDevice:          spec.SwiftStorage.DatabaseDevice,
+		MetadataTier:            spec.SwiftStorage.MetadataTier,
+		DiskDiscovery:           spec.SwiftStorage.DiskDiscovery,
+		Affinity:                spec.SwiftStorage.Affinity,
+		NodeSelector:            spec.SwiftStorage.NodeSelector,
+		Tolerations:             spec.SwiftStorage.Tolerations,
 	}
 
 	deployment := &swiftv1beta1.SwiftStorage{
@@ -311,6 +819,10 @@ func (r *SwiftReconciler) storageCreateOrUpdate(ctx context.Context, instance *s
 
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
 		deployment.Spec = swiftStorageSpec
+		swift.PropagateMetadata(instance, deployment)
+		// Let the sub-resources of the child only be previewed as well
+		swift.SetDryRun(deployment, swift.IsDryRun(instance))
+		propagateScaleDown(instance, deployment)
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
 		if err != nil {
 			return err
@@ -322,16 +834,67 @@ func (r *SwiftReconciler) storageCreateOrUpdate(ctx context.Context, instance *s
 	return deployment, op, err
 }
 
+// propagateScaleDown sets the scale down annotation of the Swift on its
+// SwiftStorage, which is updated together with the reduced replicas. The
+// SwiftStorage webhook rejects the update without it.
+func propagateScaleDown(instance *swiftv1beta1.Swift, storage *swiftv1beta1.SwiftStorage) {
+	if instance.Annotations[swiftv1beta1.ScaleDownAnnotation] != "true" {
+		delete(storage.Annotations, swiftv1beta1.ScaleDownAnnotation)
+		return
+	}
+	if storage.Annotations == nil {
+		storage.Annotations = map[string]string{}
+	}
+	storage.Annotations[swiftv1beta1.ScaleDownAnnotation] = "true"
+}
+
 func (r *SwiftReconciler) proxyCreateOrUpdate(ctx context.Context, instance *swiftv1beta1.Swift) (*swiftv1beta1.SwiftProxy, controllerutil.OperationResult, error) {
+	spec := getEffectiveSpec(instance)
 
 	swiftProxySpec := swiftv1beta1.SwiftProxySpec{
-		Replicas:                instance.Spec.SwiftProxy.Replicas,
-		ContainerImageProxy:     instance.Spec.SwiftProxy.ContainerImageProxy,
-		ContainerImageMemcached: instance.Spec.SwiftProxy.ContainerImageMemcached,
-		Secret:                  instance.Spec.SwiftProxy.Secret,
-		ServiceUser:             instance.Spec.SwiftProxy.ServiceUser,
-		PasswordSelectors:       instance.Spec.SwiftProxy.PasswordSelectors,
-		SwiftConfSecret:         instance.Spec.SwiftConfSecret,
+		Replicas:                 spec.SwiftProxy.Replicas,
+		ContainerImageProxy:      spec.SwiftProxy.ContainerImageProxy,
+		ContainerImageMemcached:  spec.SwiftProxy.ContainerImageMemcached,
+		Secret:                   spec.SwiftProxy.Secret,
+		ServiceUser:              spec.SwiftProxy.ServiceUser,
+		PasswordSelectors:        spec.SwiftProxy.PasswordSelectors,
+		SwiftConfSecret:          swift.GetLocalSecretName(spec.SwiftConfSecret),
+		ContainerSync:            spec.ContainerSync,
+		MemcachedInstance:        spec.MemcachedInstance,
+		Autoscaling:              spec.SwiftProxy.Autoscaling,
+		ContainerEnv:             spec.SwiftProxy.ContainerEnv,
+		NofileLimits:             spec.SwiftProxy.NofileLimits,
+		Resources:                spec.SwiftProxy.Resources,
+		ClockSkewThreshold:       spec.SwiftProxy.ClockSkewThreshold,
+		Pools:                    spec.SwiftProxy.Pools,
+		AccountPolicies:          spec.SwiftProxy.AccountPolicies,
+		TopologyAwareRouting:     spec.SwiftProxy.TopologyAwareRouting,
+		InternalTrafficPolicy:    spec.SwiftProxy.InternalTrafficPolicy,
+		Workers:                  spec.SwiftProxy.Workers,
+		ErrorSuppressionInterval: spec.SwiftProxy.ErrorSuppressionInterval,
+		ErrorSuppressionLimit:    spec.SwiftProxy.ErrorSuppressionLimit,
+		ConcurrentGets:           spec.SwiftProxy.ConcurrentGets,
+		ConcurrencyTimeout:       spec.SwiftProxy.ConcurrencyTimeout,
+		S3API:                    spec.SwiftProxy.S3API,
+		SortingMethod:            spec.SwiftProxy.SortingMethod,
+		TimingExpiry:             spec.SwiftProxy.TimingExpiry,
+		ReadAffinity:             spec.SwiftProxy.ReadAffinity,
+		KeystoneAuth:             spec.SwiftProxy.KeystoneAuth,
+		TLS:                      spec.SwiftProxy.TLS,
+		ConsistencyCheck:         spec.SwiftProxy.ConsistencyCheck,
+		DataVerification:         spec.SwiftProxy.DataVerification,
+		UsageExport:              spec.SwiftProxy.UsageExport,
+		SwiftConfSecretStore:     spec.SwiftConfSecretStore,
+		CredentialsSecretStore:   spec.SwiftProxy.CredentialsSecretStore,
+		FlushCacheOnRingChange:   spec.SwiftProxy.FlushCacheOnRingChange,
+		NodeSelector:             spec.SwiftProxy.NodeSelector,
+		Tolerations:              spec.SwiftProxy.Tolerations,
+		ReadOnly:                 spec.SwiftProxy.ReadOnly,
+		BreakGlass:               spec.SwiftProxy.BreakGlass,
+		DefaultConfigOverwrite:   spec.SwiftProxy.DefaultConfigOverwrite,
+		ExtraMounts:              spec.SwiftProxy.ExtraMounts,
+		PublicContainers:         spec.SwiftProxy.PublicContainers,
+		Lifecycle:                spec.SwiftProxy.Lifecycle,
 	}
 
 	deployment := &swiftv1beta1.SwiftProxy{
@@ -343,6 +906,9 @@ func (r *SwiftReconciler) proxyCreateOrUpdate(ctx context.Context, instance *swi
 
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
 		deployment.Spec = swiftProxySpec
+		swift.PropagateMetadata(instance, deployment)
+		// Let the sub-resources of the child only be previewed as well
+		swift.SetDryRun(deployment, swift.IsDryRun(instance))
 		err := controllerutil.SetControllerReference(instance, deployment, r.Scheme)
 		if err != nil {
 			return err
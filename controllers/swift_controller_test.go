@@ -0,0 +1,163 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+var _ = Describe("Swift controller", func() {
+	Context("with an invalid scaling schedule", func() {
+		It("sets a False Ready condition instead of failing the reconcile", func() {
+			instance := &swiftv1beta1.Swift{
+				ObjectMeta: metav1.ObjectMeta{Name: "swift", Namespace: "default"},
+				Spec: swiftv1beta1.SwiftSpec{
+					ScalingSchedule: []swiftv1beta1.SwiftScalingSchedule{{
+						Name:     "nightly",
+						Schedule: "0 1 * *",
+						Duration: metav1.Duration{Duration: time.Hour},
+					}},
+				},
+			}
+			c, s := newFakeClient(instance)
+			r := &SwiftReconciler{
+				Client:  c,
+				Scheme:  s,
+				Log:     ctrl.Log.WithName("controllers").WithName("Swift"),
+				Kclient: kfake.NewSimpleClientset(),
+			}
+			key := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			updated := &swiftv1beta1.Swift{}
+			Expect(c.Get(context.TODO(), key, updated)).To(Succeed())
+			Expect(updated.Status.Conditions.IsFalse(condition.ReadyCondition)).To(BeTrue())
+			Expect(updated.Status.Conditions.Get(condition.ReadyCondition).Message).To(
+				ContainSubstring(`scaling schedule nightly: invalid schedule "0 1 * *"`))
+		})
+	})
+
+	Context("with a storage scale down", func() {
+		allowed := map[string]string{swiftv1beta1.ScaleDownAnnotation: "true"}
+
+		It("passes the scale down annotation on to the SwiftStorage", func() {
+			instance := &swiftv1beta1.Swift{ObjectMeta: metav1.ObjectMeta{Annotations: allowed}}
+			storage := &swiftv1beta1.SwiftStorage{}
+			propagateScaleDown(instance, storage)
+			Expect(storage.Annotations).To(Equal(allowed))
+		})
+
+		It("removes the annotation from the SwiftStorage once it is removed from the Swift", func() {
+			instance := &swiftv1beta1.Swift{}
+			storage := &swiftv1beta1.SwiftStorage{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				swiftv1beta1.ScaleDownAnnotation: "true",
+				"example.com/owner":              "storage-team",
+			}}}
+			propagateScaleDown(instance, storage)
+			Expect(storage.Annotations).To(Equal(map[string]string{"example.com/owner": "storage-team"}))
+
+			instance.Annotations = map[string]string{swiftv1beta1.ScaleDownAnnotation: "false"}
+			storage.Annotations[swiftv1beta1.ScaleDownAnnotation] = "true"
+			propagateScaleDown(instance, storage)
+			Expect(storage.Annotations).NotTo(HaveKey(swiftv1beta1.ScaleDownAnnotation))
+		})
+	})
+
+	Context("with a swift.conf supplied by the user", func() {
+		var instance *swiftv1beta1.Swift
+
+		BeforeEach(func() {
+			instance = &swiftv1beta1.Swift{
+				ObjectMeta: metav1.ObjectMeta{Name: "swift", Namespace: "default"},
+				Spec:       swiftv1beta1.SwiftSpec{SwiftConfSecret: "user-swift-conf"},
+			}
+		})
+
+		// verify verifies the hash path of a swift.conf Secret with the given
+		// prefix and annotations
+		verify := func(namespace string, prefix string, annotations map[string]string) bool {
+			swiftConf := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "user-swift-conf", Namespace: namespace, Annotations: annotations},
+				Data: map[string][]byte{"swift.conf": []byte(
+					"[swift-hash]\nswift_hash_path_prefix = " + prefix + "\nswift_hash_path_suffix = suffix\n")},
+			}
+			c, s := newFakeClient(instance, swiftConf)
+			h, err := helper.NewHelper(instance, c, kfake.NewSimpleClientset(), s, ctrl.Log.WithName("controllers").WithName("Swift"))
+			Expect(err).NotTo(HaveOccurred())
+			changed, err := verifySwiftConfHashPath(context.TODO(), h, instance)
+			Expect(err).NotTo(HaveOccurred())
+			return changed
+		}
+
+		It("records the hash path and accepts it unchanged", func() {
+			Expect(verify("default", "prefix", nil)).To(BeFalse())
+			recorded := instance.Status.Hash[swiftv1beta1.SwiftConfHashPathHash]
+			Expect(recorded).NotTo(BeEmpty())
+			Expect(recorded).NotTo(ContainSubstring("prefix"))
+			Expect(verify("default", "prefix", nil)).To(BeFalse())
+			Expect(instance.Status.Hash).To(HaveKeyWithValue(swiftv1beta1.SwiftConfHashPathHash, recorded))
+		})
+
+		It("sets a blocking condition if the hash path changed", func() {
+			Expect(verify("default", "prefix", nil)).To(BeFalse())
+			recorded := instance.Status.Hash[swiftv1beta1.SwiftConfHashPathHash]
+			Expect(verify("default", "new-prefix", nil)).To(BeTrue())
+			Expect(instance.Status.Hash).To(HaveKeyWithValue(swiftv1beta1.SwiftConfHashPathHash, recorded))
+			Expect(instance.Status.Conditions.IsTrue(swiftv1beta1.HashPathChangedCondition)).To(BeTrue())
+			Expect(instance.Status.Conditions.IsFalse(condition.ReadyCondition)).To(BeTrue())
+			Expect(instance.Status.Conditions.Get(condition.ReadyCondition).Message).To(
+				ContainSubstring("The hash path prefix or suffix of Secret user-swift-conf changed"))
+		})
+
+		It("records a hash path change allowed by the annotation", func() {
+			Expect(verify("default", "prefix", nil)).To(BeFalse())
+			Expect(verify("default", "new-prefix", nil)).To(BeTrue())
+			allowed := map[string]string{swiftv1beta1.HashChangeAnnotation: "true"}
+			Expect(verify("default", "new-prefix", allowed)).To(BeFalse())
+			Expect(instance.Status.Conditions.Has(swiftv1beta1.HashPathChangedCondition)).To(BeFalse())
+			Expect(verify("default", "new-prefix", nil)).To(BeFalse())
+		})
+
+		It("verifies a Secret granted from another namespace", func() {
+			instance.Spec.SwiftConfSecret = "shared/user-swift-conf"
+			Expect(verify("shared", "prefix", nil)).To(BeFalse())
+			Expect(verify("shared", "new-prefix", nil)).To(BeTrue())
+		})
+
+		It("waits for the Secret to be created", func() {
+			Expect(verify("other", "prefix", nil)).To(BeFalse())
+			Expect(instance.Status.Hash).NotTo(HaveKey(swiftv1beta1.SwiftConfHashPathHash))
+		})
+	})
+})
@@ -0,0 +1,313 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
+
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
+)
+
+// SwiftAccountReconciler reconciles a SwiftAccount object
+type SwiftAccountReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Log     logr.Logger
+	Kclient kubernetes.Interface
+}
+
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftaccounts,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftaccounts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftaccounts/finalizers,verbs=update
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftproxies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile applies the account settings with a Job running the internal
+// client of the SwiftProxy. The generated keys are kept in Secrets owned by
+// the SwiftAccount, the account itself is kept when it is deleted.
+func (r *SwiftAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("swiftaccount", req.NamespacedName)
+
+	instance := &swiftv1beta1.SwiftAccount{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.Info("SwiftAccount resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get SwiftAccount")
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+		cl := condition.CreateList(
+			condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
+		)
+		instance.Status.Conditions.Init(&cl)
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+
+	helper, err := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, r.Log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	proxy, err := getReadySwiftProxy(ctx, r.Client, instance.Namespace, instance.Spec.SwiftProxy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if proxy == nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.SwiftProxyWaitingMessage,
+			instance.Spec.SwiftProxy))
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
+	// The keys are generated once and kept until the SwiftAccount is
+	// deleted
+	if instance.Spec.TempURLKeys {
+		err = r.ensureKeySecret(ctx, instance, instance.Name+"-temp-url-keys", []string{"key", "key2"})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	authURL := ""
+	if instance.Spec.S3Credentials != nil {
+		err = r.ensureKeySecret(ctx, instance, instance.Name+"-s3-credentials", []string{"access", "secret"})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, helper, instance.Namespace, map[string]string{})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		authURL, err = keystoneAPI.GetEndpoint(endpoint.EndpointPublic)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	accountJob := job.NewJob(
+		getSwiftAccountJob(instance, proxy, authURL),
+		swiftv1beta1.SwiftAccountHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.SwiftAccountHash])
+	ctrlResult, err := accountJob.DoJob(ctx, helper)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.SwiftAccountRunningMessage))
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrlResult, nil
+	}
+
+	account := swift.GetAccountName(instance)
+	if instance.Status.Hash[swiftv1beta1.SwiftAccountHash] != accountJob.GetHash() {
+		r.Log.Info(fmt.Sprintf("Applied the settings of account %s", account))
+	}
+	instance.Status.Hash[swiftv1beta1.SwiftAccountHash] = accountJob.GetHash()
+	instance.Status.Account = account
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, swiftv1beta1.SwiftAccountReadyMessage, account)
+	return ctrl.Result{}, r.Status().Update(ctx, instance)
+}
+
+// ensureKeySecret creates the Secret with a random value for each key
+// missing in it
+func (r *SwiftAccountReconciler) ensureKeySecret(ctx context.Context, instance *swiftv1beta1.SwiftAccount, name string, keys []string) error {
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+		},
+	}
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, keySecret, func() error {
+		if keySecret.Data == nil {
+			keySecret.Data = map[string][]byte{}
+		}
+		for _, key := range keys {
+			if _, ok := keySecret.Data[key]; !ok {
+				keySecret.Data[key] = []byte(swift.RandomString(32))
+			}
+		}
+		return controllerutil.SetControllerReference(instance, keySecret, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("Secret %s successfully reconciled - operation: %s", keySecret.Name, string(op)))
+	}
+	return nil
+}
+
+// getSwiftAccountJob returns the Job applying the account settings
+func getSwiftAccountJob(instance *swiftv1beta1.SwiftAccount, proxy *swiftv1beta1.SwiftProxy, authURL string) *batchv1.Job {
+	envVars := map[string]env.Setter{}
+	envVars["ACCOUNT"] = env.SetValue(swift.GetAccountName(instance))
+	envVars["ACL"] = env.SetValue(swift.GetAccountACL(instance.Spec.ACL))
+	if instance.Spec.QuotaBytes != nil {
+		envVars["QUOTA_BYTES"] = env.SetValue(fmt.Sprintf("%d", *instance.Spec.QuotaBytes))
+	}
+	envs := env.MergeEnvs([]corev1.EnvVar{}, envVars)
+
+	secretEnv := func(name string, secretName string, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+	if instance.Spec.TempURLKeys {
+		envs = append(envs,
+			secretEnv("TEMP_URL_KEY", instance.Name+"-temp-url-keys", "key"),
+			secretEnv("TEMP_URL_KEY_2", instance.Name+"-temp-url-keys", "key2"))
+	}
+	if instance.Spec.S3Credentials != nil {
+		envs = append(envs,
+			corev1.EnvVar{Name: "S3_USER_ID", Value: instance.Spec.S3Credentials.UserID},
+			corev1.EnvVar{Name: "PROJECT_ID", Value: instance.Spec.ProjectID},
+			corev1.EnvVar{Name: "OS_AUTH_URL", Value: authURL},
+			corev1.EnvVar{Name: "OS_USERNAME", Value: proxy.Spec.ServiceUser},
+			secretEnv("OS_PASSWORD", proxy.Spec.Secret, proxy.Spec.PasswordSelectors.Service),
+			secretEnv("S3_ACCESS", instance.Name+"-s3-credentials", "access"),
+			secretEnv("S3_SECRET", instance.Name+"-s3-credentials", "secret"))
+	}
+
+	accountJob := getInternalClientJob(proxy, instance.Name+"-swift-account", instance.Namespace, swift.GetLabelsAccount(), "swift-account", envs)
+	// The credentials volume is part of the proxy volumes
+	if instance.Spec.S3Credentials != nil {
+		podSpec := &accountJob.Spec.Template.Spec
+		podSpec.Containers, _ = applyCredentialsSecretStore(proxy, podSpec.Containers, nil)
+	}
+	return accountJob
+}
+
+// getReadySwiftProxy returns the SwiftProxy with the given name if it is
+// ready, nil otherwise
+func getReadySwiftProxy(ctx context.Context, c client.Client, namespace string, name string) (*swiftv1beta1.SwiftProxy, error) {
+	proxy := &swiftv1beta1.SwiftProxy{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, proxy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !proxy.Status.Conditions.IsTrue(condition.ReadyCondition) {
+		return nil, nil
+	}
+	return proxy, nil
+}
+
+// getInternalClientJob returns a Job running one of the proxy scripts with
+// the config and rings of the proxy, like the read-only accounts Job, e.g.
+// to set metadata reserved to the reseller admin
+func getInternalClientJob(proxy *swiftv1beta1.SwiftProxy, name string, namespace string, labels map[string]string, script string, envs []corev1.EnvVar) *batchv1.Job {
+	securityContext := swift.GetSecurityContext()
+
+	internalClientJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      "OnFailure",
+					ServiceAccountName: swift.ServiceAccount,
+					SecurityContext: &corev1.PodSecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					InitContainers: getInitContainers(proxy),
+					Containers: []corev1.Container{
+						{
+							Name:            script,
+							Command:         []string{"/usr/local/bin/container-scripts/" + script + ".sh"},
+							Image:           proxy.Spec.ContainerImageProxy,
+							SecurityContext: &securityContext,
+							VolumeMounts:    getProxyVolumeMounts(),
+							Env:             envs,
+						},
+					},
+					Volumes: getProxyVolumes(proxy, proxy.Name),
+				},
+			},
+		},
+	}
+	swift.SetTerminationMessagePolicy(&internalClientJob.Spec.Template.Spec)
+	return internalClientJob
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwiftAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swiftv1beta1.SwiftAccount{}).
+		Owns(&batchv1.Job{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
+)
+
+// SwiftContainerReconciler reconciles a SwiftContainer object
+type SwiftContainerReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Log     logr.Logger
+	Kclient kubernetes.Interface
+}
+
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftcontainers,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftcontainers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftcontainers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftproxies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates the container and applies its settings with a Job
+// running the internal client of the SwiftProxy. The container is kept when
+// the SwiftContainer is deleted.
+func (r *SwiftContainerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("swiftcontainer", req.NamespacedName)
+
+	instance := &swiftv1beta1.SwiftContainer{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.Info("SwiftContainer resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get SwiftContainer")
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+		cl := condition.CreateList(
+			condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
+		)
+		instance.Status.Conditions.Init(&cl)
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+
+	helper, err := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, r.Log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	proxy, err := getReadySwiftProxy(ctx, r.Client, instance.Namespace, instance.Spec.SwiftProxy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if proxy == nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.SwiftProxyWaitingMessage,
+			instance.Spec.SwiftProxy))
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
+	if instance.Spec.Lifecycle != nil && proxy.Spec.Lifecycle == nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			swiftv1beta1.SwiftContainerLifecycleMessage,
+			proxy.Name))
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+
+	containerJob := job.NewJob(
+		getSwiftContainerJob(instance, proxy),
+		swiftv1beta1.SwiftContainerHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.SwiftContainerHash])
+	ctrlResult, err := containerJob.DoJob(ctx, helper)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.SwiftContainerRunningMessage))
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrlResult, nil
+	}
+
+	if instance.Status.Hash[swiftv1beta1.SwiftContainerHash] != containerJob.GetHash() {
+		r.Log.Info(fmt.Sprintf("Applied the settings of container %s/%s", instance.Spec.Account, instance.Spec.ContainerName))
+	}
+	instance.Status.Hash[swiftv1beta1.SwiftContainerHash] = containerJob.GetHash()
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, swiftv1beta1.SwiftContainerReadyMessage,
+		instance.Spec.Account, instance.Spec.ContainerName)
+	return ctrl.Result{}, r.Status().Update(ctx, instance)
+}
+
+// getSwiftContainerJob returns the Job creating the container and applying
+// its settings
+func getSwiftContainerJob(instance *swiftv1beta1.SwiftContainer, proxy *swiftv1beta1.SwiftProxy) *batchv1.Job {
+	envVars := map[string]env.Setter{}
+	envVars["ACCOUNT"] = env.SetValue(instance.Spec.Account)
+	envVars["CONTAINER"] = env.SetValue(instance.Spec.ContainerName)
+	envVars["STORAGE_POLICY"] = env.SetValue(instance.Spec.StoragePolicy)
+	envVars["READ_ACL"] = env.SetValue(instance.Spec.ReadACL)
+	envVars["WRITE_ACL"] = env.SetValue(instance.Spec.WriteACL)
+	envVars["VERSIONS_CONTAINER"] = env.SetValue(instance.Spec.VersionsContainer)
+	if instance.Spec.QuotaBytes != nil {
+		envVars["QUOTA_BYTES"] = env.SetValue(fmt.Sprintf("%d", *instance.Spec.QuotaBytes))
+	}
+	if instance.Spec.QuotaCount != nil {
+		envVars["QUOTA_COUNT"] = env.SetValue(fmt.Sprintf("%d", *instance.Spec.QuotaCount))
+	}
+	if lc := instance.Spec.Lifecycle; lc != nil {
+		if lc.ExpireAfterDays > 0 {
+			envVars["LIFECYCLE_EXPIRE_AFTER"] = env.SetValue(fmt.Sprintf("%d", int64(lc.ExpireAfterDays)*24*60*60))
+		}
+		if lc.DeleteAt != nil {
+			envVars["LIFECYCLE_DELETE_AT"] = env.SetValue(fmt.Sprintf("%d", lc.DeleteAt.Unix()))
+		}
+	}
+
+	return getInternalClientJob(proxy, instance.Name+"-swift-container", instance.Namespace,
+		swift.GetLabelsContainer(), "swift-container", env.MergeEnvs([]corev1.EnvVar{}, envVars))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwiftContainerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swiftv1beta1.SwiftContainer{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
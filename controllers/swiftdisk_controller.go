@@ -0,0 +1,164 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
+)
+
+// SwiftDiskReconciler reconciles a SwiftDisk object
+type SwiftDiskReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Log     logr.Logger
+	Kclient kubernetes.Interface
+}
+
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftdisks,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftdisks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftdisks/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile offers the disk as a local PersistentVolume on its node. The
+// PersistentVolume is cluster-scoped, so it is deleted with a finalizer
+// instead of an owner reference.
+func (r *SwiftDiskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("swiftdisk", req.NamespacedName)
+
+	instance := &swiftv1beta1.SwiftDisk{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.Info("SwiftDisk resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get SwiftDisk")
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+		cl := condition.CreateList(
+			condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
+		)
+		instance.Status.Conditions.Init(&cl)
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	helper, err := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, r.Log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: swift.GetSwiftDiskVolumeName(instance),
+		},
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		// A claimed PersistentVolume is only removed once the claim is
+		// deleted, e.g. by a SwiftStorage scale down
+		err := r.Client.Delete(ctx, pv)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(instance, helper.GetFinalizer())
+		if err := r.Update(ctx, instance); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.Log.Info(fmt.Sprintf("Reconciled SwiftDisk '%s' delete successfully", instance.Name))
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(instance, helper.GetFinalizer()) {
+		if err := r.Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, pv, func() error {
+		desired := swift.GetSwiftDiskVolume(instance)
+		pv.Labels = desired.Labels
+		// The volume source and node affinity of a PersistentVolume are
+		// immutable
+		if pv.CreationTimestamp.IsZero() {
+			pv.Spec = desired.Spec
+		}
+		pv.Spec.Capacity = desired.Spec.Capacity
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("PersistentVolume %s successfully reconciled - operation: %s", pv.Name, string(op)))
+	}
+
+	instance.Status.VolumeName = pv.Name
+	instance.Status.ClaimName = ""
+	if pv.Spec.ClaimRef != nil {
+		instance.Status.ClaimName = pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name
+		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, swiftv1beta1.SwiftDiskBoundMessage, pv.Name, instance.Status.ClaimName)
+	} else {
+		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, swiftv1beta1.SwiftDiskAvailableMessage, pv.Name)
+	}
+	return ctrl.Result{}, r.Status().Update(ctx, instance)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwiftDiskReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The PersistentVolumes are reconciled through the labels with the
+	// SwiftDisk, e.g. when they are claimed
+	volumeFilter := func(o client.Object) []reconcile.Request {
+		name, ok := o.GetLabels()[swiftv1beta1.SwiftDiskLabel]
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: client.ObjectKey{
+			Namespace: o.GetLabels()[swiftv1beta1.SwiftDiskNamespaceLabel],
+			Name:      name,
+		}}}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swiftv1beta1.SwiftDisk{}).
+		Watches(&source.Kind{Type: &corev1.PersistentVolume{}}, handler.EnqueueRequestsFromMapFunc(volumeFilter)).
+		Complete(r)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
+)
+
+// SwiftOperatorConfigReconciler reconciles a SwiftOperatorConfig object
+type SwiftOperatorConfigReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+}
+
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftoperatorconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftoperatorconfigs/status,verbs=get;update;patch
+
+// Reconcile applies the SwiftOperatorConfig named cluster to the controllers
+// and the defaulting webhooks. Without it the operator uses its flags and
+// environment.
+func (r *SwiftOperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("swiftoperatorconfig", req.NamespacedName)
+
+	instance := &swiftv1beta1.SwiftOperatorConfig{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			if req.Name == swiftv1beta1.SwiftOperatorConfigName {
+				r.Log.Info("SwiftOperatorConfig not found, using the operator defaults")
+				swift.SetOperatorConfig(nil)
+				swiftv1beta1.SetupDefaults()
+			}
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get SwiftOperatorConfig")
+		return ctrl.Result{}, err
+	}
+
+	if instance.Name != swiftv1beta1.SwiftOperatorConfigName {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			swiftv1beta1.SwiftOperatorConfigIgnoredMessage,
+			swiftv1beta1.SwiftOperatorConfigName))
+		return ctrl.Result{}, r.Status().Update(ctx, instance)
+	}
+
+	swift.SetOperatorConfig(&instance.Spec)
+	swiftv1beta1.SetupSwiftDefaults(instance.Spec.GetSwiftDefaults())
+	r.Log.Info(fmt.Sprintf("Applied SwiftOperatorConfig generation %d", instance.Generation))
+
+	instance.Status.Conditions.Set(condition.TrueCondition(
+		condition.ReadyCondition,
+		swiftv1beta1.SwiftOperatorConfigAppliedMessage))
+	instance.Status.ObservedGeneration = instance.Generation
+	return ctrl.Result{}, r.Status().Update(ctx, instance)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwiftOperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swiftv1beta1.SwiftOperatorConfig{}).
+		Complete(r)
+}
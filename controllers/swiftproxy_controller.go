@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-logr/logr"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -27,10 +30,16 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,7 +50,10 @@ import (
 	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/route"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 
 	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
@@ -52,20 +64,30 @@ import (
 // SwiftProxyReconciler reconciles a SwiftProxy object
 type SwiftProxyReconciler struct {
 	client.Client
-	Scheme  *runtime.Scheme
-	Log     logr.Logger
-	Kclient kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Kclient  kubernetes.Interface
+	Features swift.Features
+}
+
+// features returns the features enabled with the operator flags and not
+// disabled in the SwiftOperatorConfig
+func (r *SwiftProxyReconciler) features() swift.Features {
+	return r.Features.WithOperatorConfig()
 }
 
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftproxies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftproxies/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftproxies/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete;
 //+kubebuilder:rbac:groups=keystone.openstack.org,resources=keystoneapis,verbs=get;list;watch
-//+kubebuilder:rbac:groups=keystone.openstack.org,resources=keystoneendpoints,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=keystone.openstack.org,resources=keystoneservices,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete;
+//+kubebuilder:rbac:groups=memcached.openstack.org,resources=memcacheds,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftstorages,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -88,172 +110,1666 @@ func (r *SwiftProxyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			r.Log.Info("SwiftProxy resource not found. Ignoring since object must be deleted")
 			return ctrl.Result{}, nil
 		}
-		// Error reading the object - requeue the request.
-		r.Log.Error(err, "Failed to get SwiftProxy")
-		return ctrl.Result{}, err
+		// Error reading the object - requeue the request.
+		r.Log.Error(err, "Failed to get SwiftProxy")
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Conditions == nil {
+		instance.Status.Conditions = condition.Conditions{}
+		cl := condition.CreateList(
+			condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
+			condition.UnknownCondition(swiftv1beta1.SwiftProxyReadyCondition, condition.InitReason, swiftv1beta1.SwiftProxyReadyInitMessage),
+		)
+
+		instance.Status.Conditions.Init(&cl)
+
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	helper, err := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, r.Log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.AddFinalizer(instance, helper.GetFinalizer())
+	if err := r.Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, instance, helper)
+	}
+
+	// Check if there is a ConfigMap for the Swift rings
+	cm, ctrlResult, err := configmap.GetConfigMap(
+		ctx, helper, instance, swiftv1beta1.RingConfigMapName, 5*time.Second)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	// This will only exist after SwiftStorage instances are up and rings
+	// are set up properly
+	_, ok := cm.BinaryData["swiftrings.tar.gz"]
+	if !ok {
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
+	labels := swift.GetLabelsProxy()
+
+	// The validating webhook rejects an invalid spec, it only gets here while
+	// the webhooks are disabled
+	if err := instance.Spec.Validate(); err != nil {
+		r.Log.Info(fmt.Sprintf("Invalid spec of SwiftProxy '%s': %s", instance.Name, err))
+		swift.SetInvalidSpecCondition(&instance.Status.Conditions, err,
+			condition.ReadyCondition, swiftv1beta1.SwiftProxyReadyCondition)
+		return ctrl.Result{}, r.Status().Update(ctx, instance)
+	}
+
+	// Only report the changes to the sub-resources in dry-run mode
+	if swift.IsDryRun(instance) {
+		return r.reconcileDryRun(ctx, instance, helper, labels)
+	}
+	instance.Status.DryRunDiff = nil
+
+	// Request the certificate of the proxy from cert-manager
+	if instance.Spec.TLS != nil && instance.Spec.TLS.Issuer != "" {
+		err = swift.CreateOrPatchProxyCertificate(ctx, helper, instance, labels)
+	} else {
+		err = swift.DeleteCertificate(ctx, helper, instance.Name, instance.Namespace)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// The Route certificate is requested once the Route has a hostname
+	if instance.Spec.TLS == nil || instance.Spec.TLS.RouteIssuer == "" {
+		err = swift.DeleteCertificate(ctx, helper, instance.Name+"-route", instance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Certificates of other namespaces are copied if they are granted
+	if instance.Spec.TLS != nil {
+		for _, ref := range []string{instance.Spec.TLS.SecretName, instance.Spec.TLS.RouteSecretName} {
+			if err := swift.EnsureGrantedSecret(ctx, helper, ref); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// The proxy pods are rolled when the certificate is renewed
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	if instance.Spec.TLS != nil {
+		_, hash, err := secret.GetSecret(ctx, helper, swift.GetProxyTLSSecretName(instance), instance.Namespace)
+		if apierrors.IsNotFound(err) {
+			r.Log.Info(fmt.Sprintf("Waiting for the proxy certificate Secret %s", swift.GetProxyTLSSecretName(instance)))
+			return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+		instance.Status.Hash[swiftv1beta1.CertificateHash] = hash
+	} else {
+		delete(instance.Status.Hash, swiftv1beta1.CertificateHash)
+	}
+
+	// Create a Service and endpoints for the proxy
+	var swiftPorts = map[endpoint.Endpoint]endpoint.Data{
+		endpoint.EndpointAdmin: endpoint.Data{
+			Port: swift.ProxyPort,
+			Path: "",
+		},
+		endpoint.EndpointPublic: endpoint.Data{
+			Port: swift.ProxyPort,
+			Path: "/v1/AUTH_%(tenant_id)s",
+		},
+		endpoint.EndpointInternal: endpoint.Data{
+			Port: swift.ProxyPort,
+			Path: "/v1/AUTH_%(tenant_id)s",
+		},
+	}
+
+	// Endpoints claimed by a pool are served by the pods of that pool
+	selectors := map[string]map[string]string{"": labels}
+	ports := map[string]map[endpoint.Endpoint]endpoint.Data{"": {}}
+	for endpointType, data := range swiftPorts {
+		pool := getProxyPoolForEndpoint(instance.Spec.Pools, endpointType)
+		if _, ok := ports[pool]; !ok {
+			selectors[pool] = swift.GetLabelsProxyPool(pool)
+			ports[pool] = map[endpoint.Endpoint]endpoint.Data{}
+		}
+		ports[pool][endpointType] = data
+	}
+
+	// Optional features requested by the instance that are disabled in the
+	// operator or miss permissions
+	unavailable := map[string]string{}
+
+	apiEndpoints := map[string]string{}
+	for pool, poolPorts := range ports {
+		if len(poolPorts) == 0 {
+			continue
+		}
+		poolEndpoints, ctrlResult, err := r.exposeEndpoints(ctx, helper, instance, selectors[pool], poolPorts, unavailable)
+		if err != nil {
+			r.Log.Error(err, "Failed to expose endpoints for Swift Proxy")
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+		for k, v := range poolEndpoints {
+			apiEndpoints[k] = v
+		}
+	}
+	for endpointType := range swiftPorts {
+		err := swift.SetTopologyAwareRouting(ctx, helper, fmt.Sprintf("%s-%s", swift.ServiceName, endpointType),
+			instance.Namespace, instance.Spec.TopologyAwareRouting, instance.Spec.InternalTrafficPolicy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if instance.Status.APIEndpoints == nil {
+		instance.Status.APIEndpoints = map[string]map[string]string{}
+	}
+	instance.Status.APIEndpoints[swift.ServiceName] = apiEndpoints
+
+	// Register the service and its endpoints in the Keystone catalog
+	if r.features().KeystoneEndpoints {
+		ctrlResult, err = r.registerKeystoneEndpoints(ctx, instance, helper, labels)
+		if swift.IsPermissionError(err) {
+			r.Log.Info(fmt.Sprintf("Not registering SwiftProxy '%s' in Keystone: %s", instance.Name, err))
+			unavailable[swift.FeatureKeystoneEndpoints] = swift.FeatureForbidden
+		} else if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			// Not Ready until the service is in the Keystone catalog
+			if err := r.Status().Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrlResult, nil
+		}
+	} else {
+		unavailable[swift.FeatureKeystoneEndpoints] = swift.FeatureDisabled
+	}
+
+	authURL, password, memcachedServers, err := getProxyConfigData(ctx, helper, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if memcachedServers == "" {
+		r.Log.Info(fmt.Sprintf("Waiting for Memcached %s to provide its servers", instance.Spec.MemcachedInstance))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
+	// Create a Secret populated with content from templates/
+	envVars := make(map[string]env.Setter)
+	tpl := getProxySecretTemplates(instance, labels, authURL, password, memcachedServers)
+	err = secret.EnsureSecrets(ctx, helper, instance, tpl, &envVars)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The container sync realms are shared by the proxy and its pools
+	err = swift.EnsureContainerSyncSecret(ctx, helper, instance, instance.Kind, instance.Spec.ContainerSync, labels)
+	if apierrors.IsNotFound(err) {
+		r.Log.Info(fmt.Sprintf("Waiting for the container sync key Secrets of SwiftProxy '%s'", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The ring zones of the nodes the proxy pods prefer with read and write
+	// affinity, looked up by swift-init.sh when a pod starts
+	err = swift.EnsureZoneAffinityConfigMap(ctx, helper, instance, instance.Kind, instance.Spec.TopologyAwareRouting, labels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	replicas, err := getProxyReplicas(ctx, helper, instance, r.features().Autoscaling)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Create Deployment
+	depl := deployment.NewDeployment(getProxyDeployment(instance, instance.Name, labels, replicas, nil), 5*time.Second)
+	ctrlResult, err = depl.CreateOrPatch(ctx, helper)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	// Create the Deployment, Service and config of each proxy pool
+	ctrlResult, err = r.reconcileProxyPools(ctx, instance, helper, authURL, password, memcachedServers)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+	if instance.Spec.BreakGlass != nil {
+		r.Log.Info(fmt.Sprintf("WARNING: SwiftProxy '%s' serves the unauthenticated break-glass pool", instance.Name))
+	}
+	swift.SetBreakGlassCondition(&instance.Status.Conditions, instance.Spec.BreakGlass,
+		getProxyPoolName(instance, swift.BreakGlassPoolName))
+
+	// Create or delete the HorizontalPodAutoscaler
+	hpa := swift.NewHorizontalPodAutoscaler(getProxyHorizontalPodAutoscaler(instance, labels), 5*time.Second)
+	if !r.features().Autoscaling {
+		if instance.Spec.Autoscaling != nil {
+			unavailable[swift.FeatureAutoscaling] = swift.FeatureDisabled
+		}
+	} else if instance.Spec.Autoscaling != nil {
+		ctrlResult, err = hpa.CreateOrPatch(ctx, helper)
+		if swift.IsPermissionError(err) {
+			r.Log.Info(fmt.Sprintf("Not autoscaling SwiftProxy '%s': %s", instance.Name, err))
+			unavailable[swift.FeatureAutoscaling] = swift.FeatureForbidden
+		} else if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	} else if err := hpa.Delete(ctx, helper); err != nil && !swift.IsPermissionError(err) {
+		return ctrl.Result{}, err
+	}
+	swift.SetPermissionsCondition(&instance.Status.Conditions, unavailable)
+
+	// Create the containers of the managed accounts with their policy
+	if len(instance.Spec.AccountPolicies) > 0 && depl.GetDeployment().Status.ReadyReplicas > 0 {
+		ctrlResult, err = r.reconcileAccountPolicies(ctx, instance, helper, labels, authURL)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	}
+
+	// Publish the public containers once the proxy serves requests
+	if depl.GetDeployment().Status.ReadyReplicas > 0 {
+		ctrlResult, err = r.reconcilePublicContainers(ctx, instance, helper, labels, authURL)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	}
+
+	// Only report Swift ready to its consumers after an end-to-end write
+	if depl.GetDeployment().Status.ReadyReplicas > 0 {
+		ctrlResult, err = r.reconcileCanary(ctx, instance, helper, labels, authURL, swift.GetRingMd5(cm))
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	} else {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.ConsumerReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.ConsumerReadyWaitingMessage))
+	}
+
+	// Verify the objects written before a ring change once rebalanced
+	if instance.Spec.DataVerification == nil {
+		instance.Status.Conditions.Remove(swiftv1beta1.DataVerificationCondition)
+	} else if depl.GetDeployment().Status.ReadyReplicas > 0 {
+		ctrlResult, err = r.reconcileDataVerification(ctx, instance, helper, labels, authURL, swift.GetRingMd5(cm))
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	}
+
+	// Mark the accounts that stay writable in read-only mode
+	if swift.WritableAccountsChanged(instance.Spec.ReadOnly, instance.Status.WritableAccounts) &&
+		depl.GetDeployment().Status.ReadyReplicas > 0 {
+		ctrlResult, err = r.reconcileReadOnlyAccounts(ctx, instance, helper, labels)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+	}
+	swift.SetReadOnlyCondition(&instance.Status.Conditions, instance.Spec.ReadOnly)
+
+	// Periodically compare container listings with the object servers
+	ctrlResult, err = r.reconcileConsistencyCheck(ctx, instance, helper, authURL)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	// Periodically export the usage per account from the access logs
+	err = r.reconcileUsageExport(ctx, instance, authURL)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if depl.GetDeployment().Status.ReadyReplicas > 0 {
+		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+		instance.Status.Conditions.MarkTrue(swiftv1beta1.SwiftProxyReadyCondition, condition.ReadyMessage)
+		instance.Status.ObservedGeneration = instance.Generation
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Report the ring version synced by each proxy pod, including the pools
+	instance.Status.RingSync, err = swift.GetRingSyncStatus(ctx, helper, instance.Namespace, labels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	poolRingSync, err := swift.GetRingSyncStatus(ctx, helper, instance.Namespace, swift.GetLabelsProxyPools())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for pod, status := range poolRingSync {
+		instance.Status.RingSync[pod] = status
+	}
+
+	// Report the crashed containers of the proxy pods, including the pools
+	instance.Status.Terminations, err = swift.GetContainerTerminations(ctx, helper, instance.Namespace, labels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	poolTerminations, err := swift.GetContainerTerminations(ctx, helper, instance.Namespace, swift.GetLabelsProxyPools())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for c, t := range poolTerminations {
+		instance.Status.Terminations[c] = t
+	}
+
+	// Compare the clocks of the proxy pods to the API server
+	skewed, err := swift.GetClockSkewedPods(ctx, helper, instance.Namespace, labels, instance.Spec.ClockSkewThreshold)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	poolSkewed, err := swift.GetClockSkewedPods(ctx, helper, instance.Namespace, swift.GetLabelsProxyPools(), instance.Spec.ClockSkewThreshold)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	skewed = append(skewed, poolSkewed...)
+	swift.SetClockSkewCondition(&instance.Status.Conditions, skewed, instance.Spec.ClockSkewThreshold)
+
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	pods := int(replicas)
+	for _, pool := range getProxyPools(instance) {
+		pods += int(pool.Replicas)
+	}
+	if !swift.IsRingSynced(instance.Status.RingSync, swift.GetRingMd5(cm), pods) {
+		r.Log.Info(fmt.Sprintf("Waiting for all SwiftProxy '%s' pods to sync the rings", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	if len(skewed) > 0 {
+		r.Log.Info(fmt.Sprintf("Clock skew detected on SwiftProxy '%s' pods: %s", instance.Name, strings.Join(skewed, ", ")))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+
+	r.Log.Info(fmt.Sprintf("Reconciled SwiftProxy '%s' successfully", instance.Name))
+	if instance.Spec.MemcachedInstance != "" {
+		// The Memcached is not watched, pick up changes of its servers
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// getProxyReplicas returns the number of proxy replicas. The
+// HorizontalPodAutoscaler owns the number of replicas if enabled, keep the
+// current one instead of resetting it on every reconcile.
+func getProxyReplicas(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.SwiftProxy, autoscaling bool) (int32, error) {
+	if instance.Spec.Autoscaling == nil || !autoscaling {
+		return instance.Spec.Replicas, nil
+	}
+	found, err := deployment.GetDeploymentWithName(ctx, h, instance.Name, instance.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	} else if err == nil && found.Spec.Replicas != nil {
+		return *found.Spec.Replicas, nil
+	}
+	return instance.Spec.Autoscaling.MinReplicas, nil
+}
+
+// getProxyConfigData returns the Keystone authURL, the service password and
+// the memcache servers the config of the proxy is rendered with. The memcache
+// servers are empty until the shared Memcached provides them.
+func getProxyConfigData(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.SwiftProxy) (string, string, string, error) {
+	// Get the Keystone authURL
+	keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, h, instance.Namespace, map[string]string{})
+	if err != nil {
+		return "", "", "", err
+	}
+	authURL, err := keystoneAPI.GetEndpoint(endpoint.EndpointPublic)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Get the service password, swift-init.sh fills it in if it is mounted
+	// from a secret store
+	password := swift.ServicePasswordPlaceholder
+	if instance.Spec.CredentialsSecretStore == nil {
+		sps, _, err := secret.GetSecret(ctx, h, instance.Spec.Secret, instance.Namespace)
+		if err != nil {
+			return "", "", "", err
+		}
+		password = string(sps.Data[instance.Spec.PasswordSelectors.Service])
+	}
+
+	// The memcache servers of a shared Memcached replace the memcached
+	// container of the pods
+	memcachedServers, err := swift.GetMemcachedServers(ctx, h, instance.Namespace, instance.Spec.MemcachedInstance)
+	if err != nil {
+		return "", "", "", err
+	}
+	return authURL, password, memcachedServers, nil
+}
+
+// reconcileDryRun computes and reports the changes a reconcile would make to
+// the config Secrets, the Deployments and the HorizontalPodAutoscaler
+// without applying them
+func (r *SwiftProxyReconciler) reconcileDryRun(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, labels map[string]string) (ctrl.Result, error) {
+	authURL, password, memcachedServers, err := getProxyConfigData(ctx, h, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if memcachedServers == "" {
+		r.Log.Info(fmt.Sprintf("Waiting for Memcached %s to provide its servers", instance.Spec.MemcachedInstance))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
+	replicas, err := getProxyReplicas(ctx, h, instance, r.features().Autoscaling)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	secretTpls := getProxySecretTemplates(instance, labels, authURL, password, memcachedServers)
+	syncTpls, err := swift.GetContainerSyncTemplates(ctx, h, instance, instance.Kind, instance.Spec.ContainerSync, labels)
+	if apierrors.IsNotFound(err) {
+		r.Log.Info(fmt.Sprintf("Waiting for the container sync key Secrets of SwiftProxy '%s'", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+	secretTpls = append(secretTpls, syncTpls...)
+	for _, pool := range getProxyPools(instance) {
+		secretTpls = append(secretTpls, getProxyPoolSecretTemplates(instance, pool, authURL, password, memcachedServers)...)
+	}
+	objs, err := swift.GetTemplateObjects(secretTpls, true)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	cmTpls, err := swift.GetZoneAffinityTemplates(ctx, h, instance, instance.Kind, instance.Spec.TopologyAwareRouting, labels)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	cms, err := swift.GetTemplateObjects(cmTpls, false)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	objs = append(objs, cms...)
+
+	objs = append(objs, getProxyDeployment(instance, instance.Name, labels, replicas, nil))
+	for _, pool := range getProxyPools(instance) {
+		objs = append(objs, getProxyDeployment(instance, getProxyPoolName(instance, pool.Name),
+			swift.GetLabelsProxyPool(pool.Name), pool.Replicas, pool.NodeSelector))
+	}
+	if instance.Spec.Autoscaling != nil && r.features().Autoscaling {
+		objs = append(objs, getProxyHorizontalPodAutoscaler(instance, labels))
+	}
+
+	diff := []string{}
+	for _, obj := range objs {
+		d, err := swift.GetDryRunDiff(ctx, h, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		diff = append(diff, d...)
+	}
+
+	for _, d := range diff {
+		r.Log.Info(fmt.Sprintf("Dry-run SwiftProxy '%s' - %s", instance.Name, d))
+	}
+	instance.Status.DryRunDiff = diff
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileAccountPolicies runs a Job creating the containers of the
+// managed accounts with their storage policy. The Job is run again whenever
+// the account policies change.
+func (r *SwiftProxyReconciler) reconcileAccountPolicies(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, labels map[string]string, authURL string) (ctrl.Result, error) {
+	internalURL, err := url.Parse(instance.Status.APIEndpoints[swift.ServiceName][string(endpoint.EndpointInternal)])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	accountPoliciesJob := job.NewJob(
+		getAccountPoliciesJob(instance, labels, authURL, fmt.Sprintf("%s://%s", internalURL.Scheme, internalURL.Host)),
+		swiftv1beta1.AccountPoliciesHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.AccountPoliciesHash])
+	ctrlResult, err := accountPoliciesJob.DoJob(ctx, h)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	if accountPoliciesJob.HasChanged() {
+		instance.Status.Hash[swiftv1beta1.AccountPoliciesHash] = accountPoliciesJob.GetHash()
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Log.Info(fmt.Sprintf("Applied the account policies of SwiftProxy '%s'", instance.Name))
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcilePublicContainers runs a Job creating the public containers with
+// their read ACL and staticweb settings and reports their URLs once done.
+// The Job is run again whenever the public containers change.
+func (r *SwiftProxyReconciler) reconcilePublicContainers(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, labels map[string]string, authURL string) (ctrl.Result, error) {
+	if len(instance.Spec.PublicContainers) == 0 {
+		instance.Status.PublicContainers = nil
+		delete(instance.Status.Hash, swiftv1beta1.PublicContainersHash)
+		return ctrl.Result{}, nil
+	}
+	internalURL, err := url.Parse(instance.Status.APIEndpoints[swift.ServiceName][string(endpoint.EndpointInternal)])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	publicURL, err := url.Parse(instance.Status.APIEndpoints[swift.ServiceName][string(endpoint.EndpointPublic)])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	publicContainersJob := job.NewJob(
+		getPublicContainersJob(instance, labels, authURL, fmt.Sprintf("%s://%s", internalURL.Scheme, internalURL.Host)),
+		swiftv1beta1.PublicContainersHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.PublicContainersHash])
+	ctrlResult, err := publicContainersJob.DoJob(ctx, h)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	if publicContainersJob.HasChanged() {
+		instance.Status.Hash[swiftv1beta1.PublicContainersHash] = publicContainersJob.GetHash()
+		r.Log.Info(fmt.Sprintf("Published the public containers of SwiftProxy '%s'", instance.Name))
+	}
+	instance.Status.PublicContainers = swift.GetPublicContainerURLs(instance.Spec.PublicContainers,
+		fmt.Sprintf("%s://%s", publicURL.Scheme, publicURL.Host))
+	return ctrl.Result{}, nil
+}
+
+// reconcileCanary runs a Job writing, reading back and deleting a canary
+// object and reports the result in the ConsumerReady condition. The Job is
+// run again for new proxy images and rings.
+func (r *SwiftProxyReconciler) reconcileCanary(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, labels map[string]string, authURL string, ringMd5 string) (ctrl.Result, error) {
+	internalURL, err := url.Parse(instance.Status.APIEndpoints[swift.ServiceName][string(endpoint.EndpointInternal)])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	envVars := map[string]env.Setter{}
+	envVars["CANARY_CONTAINER"] = env.SetValue(swift.CanaryContainer)
+	envVars["RING_MD5"] = env.SetValue(ringMd5)
+	canaryJob := getServiceUserJob(instance, labels, authURL,
+		fmt.Sprintf("%s://%s", internalURL.Scheme, internalURL.Host), "canary", envVars)
+	canary := job.NewJob(canaryJob, swiftv1beta1.CanaryHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.CanaryHash])
+	ctrlResult, err := canary.DoJob(ctx, h)
+	if err != nil {
+		j, getErr := job.GetJobWithName(ctx, h, canaryJob.Name, canaryJob.Namespace)
+		if getErr != nil || j.Status.Failed == 0 {
+			return ctrl.Result{}, err
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.ConsumerReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			swiftv1beta1.ConsumerReadyErrorMessage,
+			canaryJob.Name))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	} else if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.ConsumerReadyCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.ConsumerReadyRunningMessage))
+		return ctrlResult, nil
+	}
+
+	if canary.HasChanged() {
+		instance.Status.Hash[swiftv1beta1.CanaryHash] = canary.GetHash()
+		r.Log.Info(fmt.Sprintf("Canary write of SwiftProxy '%s' succeeded", instance.Name))
+	}
+	instance.Status.Conditions.MarkTrue(swiftv1beta1.ConsumerReadyCondition, swiftv1beta1.ConsumerReadyMessage)
+	return ctrl.Result{}, nil
+}
+
+// reconcileDataVerification runs a Job checking a random sample of the
+// verification objects and writing the missing ones, and reports the result
+// in the DataVerification condition. The Job is run again for new rings, so
+// the objects written before a rebalance are checked after it.
+func (r *SwiftProxyReconciler) reconcileDataVerification(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, labels map[string]string, authURL string, ringMd5 string) (ctrl.Result, error) {
+	internalURL, err := url.Parse(instance.Status.APIEndpoints[swift.ServiceName][string(endpoint.EndpointInternal)])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	envVars := map[string]env.Setter{}
+	envVars["VERIFICATION_CONTAINER"] = env.SetValue(swift.VerificationContainer)
+	envVars["VERIFICATION_OBJECTS"] = env.SetValue(fmt.Sprint(instance.Spec.DataVerification.Objects))
+	envVars["VERIFICATION_SAMPLE"] = env.SetValue(fmt.Sprint(instance.Spec.DataVerification.Sample))
+	envVars["RING_MD5"] = env.SetValue(ringMd5)
+	verificationJob := getServiceUserJob(instance, labels, authURL,
+		fmt.Sprintf("%s://%s", internalURL.Scheme, internalURL.Host), "data-verification", envVars)
+	verification := job.NewJob(verificationJob, swiftv1beta1.DataVerificationHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.DataVerificationHash])
+	ctrlResult, err := verification.DoJob(ctx, h)
+	if err != nil {
+		j, getErr := job.GetJobWithName(ctx, h, verificationJob.Name, verificationJob.Namespace)
+		if getErr != nil || j.Status.Failed == 0 {
+			return ctrl.Result{}, err
+		}
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.DataVerificationCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			swiftv1beta1.DataVerificationErrorMessage,
+			verificationJob.Name))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	} else if (ctrlResult != ctrl.Result{}) {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.DataVerificationCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			swiftv1beta1.DataVerificationRunningMessage))
+		return ctrlResult, nil
+	}
+
+	if verification.HasChanged() {
+		instance.Status.Hash[swiftv1beta1.DataVerificationHash] = verification.GetHash()
+		r.Log.Info(fmt.Sprintf("Data verification of SwiftProxy '%s' succeeded", instance.Name))
+	}
+	instance.Status.Conditions.MarkTrue(swiftv1beta1.DataVerificationCondition, swiftv1beta1.DataVerificationMessage)
+	return ctrl.Result{}, nil
+}
+
+// reconcileReadOnlyAccounts runs a Job marking the writable accounts of the
+// read-only mode and clearing the mark of the accounts removed from them
+func (r *SwiftProxyReconciler) reconcileReadOnlyAccounts(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, labels map[string]string) (ctrl.Result, error) {
+	if instance.Status.Hash == nil {
+		instance.Status.Hash = map[string]string{}
+	}
+	writable := swift.GetWritableAccounts(instance.Spec.ReadOnly)
+	readOnlyAccountsJob := job.NewJob(
+		getReadOnlyAccountsJob(instance, labels, writable),
+		swiftv1beta1.ReadOnlyAccountsHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.ReadOnlyAccountsHash])
+	ctrlResult, err := readOnlyAccountsJob.DoJob(ctx, h)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	instance.Status.Hash[swiftv1beta1.ReadOnlyAccountsHash] = readOnlyAccountsJob.GetHash()
+	instance.Status.WritableAccounts = writable
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Log.Info(fmt.Sprintf("Applied the writable accounts of SwiftProxy '%s': %s", instance.Name, strings.Join(writable, ", ")))
+	return ctrl.Result{}, nil
+}
+
+// reconcileConsistencyCheck creates or deletes the consistency check CronJob
+// and reports the result of its last run
+func (r *SwiftProxyReconciler) reconcileConsistencyCheck(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, authURL string) (ctrl.Result, error) {
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-consistency-check",
+			Namespace: instance.Namespace,
+		},
+	}
+	if instance.Spec.ConsistencyCheck == nil {
+		err := r.Client.Delete(ctx, cronJob)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		instance.Status.ConsistencyCheck = nil
+		swift.SetConsistencyCondition(&instance.Status.Conditions, nil)
+		return ctrl.Result{}, nil
+	}
+
+	internalURL, err := url.Parse(instance.Status.APIEndpoints[swift.ServiceName][string(endpoint.EndpointInternal)])
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	desired := getConsistencyCheckCronJob(instance, authURL, fmt.Sprintf("%s://%s", internalURL.Scheme, internalURL.Host))
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, cronJob, func() error {
+		cronJob.Labels = desired.Labels
+		cronJob.Spec = desired.Spec
+		return controllerutil.SetControllerReference(instance, cronJob, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("CronJob %s successfully reconciled - operation: %s", cronJob.Name, string(op)))
+	}
+
+	instance.Status.ConsistencyCheck, err = swift.GetConsistencyResult(ctx, h, instance.Namespace, swift.GetLabelsConsistencyCheck())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	swift.SetConsistencyCondition(&instance.Status.Conditions, instance.Status.ConsistencyCheck)
+	return ctrl.Result{}, nil
+}
+
+func getConsistencyCheckCronJob(instance *swiftv1beta1.SwiftProxy, authURL string, swiftURL string) *batchv1.CronJob {
+	securityContext := swift.GetSecurityContext()
+	var scriptsVolumeDefaultMode int32 = 0755
+	var backoffLimit int32 = 0
+	check := instance.Spec.ConsistencyCheck
+
+	envVars := map[string]env.Setter{}
+	envVars["OS_AUTH_URL"] = env.SetValue(authURL)
+	envVars["OS_USERNAME"] = env.SetValue(instance.Spec.ServiceUser)
+	envVars["SWIFT_URL"] = env.SetValue(swiftURL)
+	envVars["ACCOUNTS"] = env.SetValue(strings.Join(check.Accounts, " "))
+	envVars["CONTAINERS"] = env.SetValue(fmt.Sprintf("%d", check.Containers))
+	envVars["OBJECTS"] = env.SetValue(fmt.Sprintf("%d", check.Objects))
+	envs := env.MergeEnvs(swift.GetRingSyncEnvVars(), envVars)
+	envs = append(envs, corev1.EnvVar{
+		Name: "OS_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: instance.Spec.Secret},
+				Key:                  instance.Spec.PasswordSelectors.Service,
+			},
+		},
+	})
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-consistency-check",
+			Namespace: instance.Namespace,
+			Labels:    swift.GetLabelsConsistencyCheck(),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          check.Schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: swift.GetLabelsConsistencyCheck(),
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:      "Never",
+							ServiceAccountName: swift.ServiceAccount,
+							SecurityContext: &corev1.PodSecurityContext{
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
+							},
+							Containers: []corev1.Container{
+								{
+									Name:            "consistency-check",
+									Command:         []string{"/usr/local/bin/container-scripts/consistency-check.sh"},
+									Image:           instance.Spec.ContainerImageProxy,
+									SecurityContext: &securityContext,
+									VolumeMounts: []corev1.VolumeMount{
+										{
+											Name:      "scripts",
+											MountPath: "/usr/local/bin/container-scripts",
+											ReadOnly:  true,
+										},
+										{
+											Name:      "swiftconf",
+											MountPath: "/var/lib/config-data/swiftconf",
+											ReadOnly:  true,
+										},
+										{
+											Name:      "ring-data",
+											MountPath: "/var/lib/config-data/rings",
+											ReadOnly:  true,
+										},
+										{
+											Name:      "etc-swift",
+											MountPath: "/etc/swift",
+										},
+									},
+									Env: envs,
+								},
+							},
+							Volumes: []corev1.Volume{
+								{
+									Name: "scripts",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											DefaultMode: &scriptsVolumeDefaultMode,
+											SecretName:  instance.Name + "-scripts",
+										},
+									},
+								},
+								swift.GetSwiftConfVolume(instance.Spec.SwiftConfSecret, instance.Spec.SwiftConfSecretStore),
+								{
+									Name: "ring-data",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: swiftv1beta1.RingConfigMapName,
+											},
+										},
+									},
+								},
+								{
+									Name: "etc-swift",
+									VolumeSource: corev1.VolumeSource{
+										EmptyDir: &corev1.EmptyDirVolumeSource{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	podSpec.Containers, podSpec.Volumes = applyCredentialsSecretStore(instance, podSpec.Containers, podSpec.Volumes)
+	podSpec.Containers, podSpec.Volumes = applyProxyTLS(instance, podSpec.Containers, podSpec.Volumes)
+	// The direct HEAD requests use the CA certificates of the storage servers
+	swift.AddBackendTLS(podSpec)
+	swift.SetTerminationMessagePolicy(podSpec)
+	return cronJob
+}
+
+// reconcileUsageExport creates or deletes the usage export CronJob
+func (r *SwiftProxyReconciler) reconcileUsageExport(ctx context.Context, instance *swiftv1beta1.SwiftProxy, authURL string) error {
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-usage-export",
+			Namespace: instance.Namespace,
+		},
+	}
+	if instance.Spec.UsageExport == nil {
+		err := r.Client.Delete(ctx, cronJob)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	internalURL, err := url.Parse(instance.Status.APIEndpoints[swift.ServiceName][string(endpoint.EndpointInternal)])
+	if err != nil {
+		return err
+	}
+	desired := getUsageExportCronJob(instance, authURL, fmt.Sprintf("%s://%s", internalURL.Scheme, internalURL.Host))
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, cronJob, func() error {
+		cronJob.Labels = desired.Labels
+		cronJob.Spec = desired.Spec
+		return controllerutil.SetControllerReference(instance, cronJob, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("CronJob %s successfully reconciled - operation: %s", cronJob.Name, string(op)))
+	}
+	return nil
+}
+
+// getUsageExportCronJob returns the CronJob reading the access logs of the
+// proxy pods, it runs with the service account allowed to read pod logs
+func getUsageExportCronJob(instance *swiftv1beta1.SwiftProxy, authURL string, swiftURL string) *batchv1.CronJob {
+	securityContext := swift.GetSecurityContext()
+	var scriptsVolumeDefaultMode int32 = 0755
+	var backoffLimit int32 = 0
+	export := instance.Spec.UsageExport
+
+	envVars := map[string]env.Setter{}
+	envVars["OS_AUTH_URL"] = env.SetValue(authURL)
+	envVars["OS_USERNAME"] = env.SetValue(instance.Spec.ServiceUser)
+	envVars["SWIFT_URL"] = env.SetValue(swiftURL)
+	envVars["CONTAINER"] = env.SetValue(export.Container)
+	envVars["FORMAT"] = env.SetValue(export.Format)
+	envVars["PROXY_SELECTOR"] = env.SetValue(swift.UsageExportProxySelector)
+	envVars["NAMESPACE"] = env.DownwardAPI("metadata.namespace")
+	envs := env.MergeEnvs([]corev1.EnvVar{}, envVars)
+	envs = append(envs, corev1.EnvVar{
+		Name: "OS_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: instance.Spec.Secret},
+				Key:                  instance.Spec.PasswordSelectors.Service,
+			},
+		},
+	})
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-usage-export",
+			Namespace: instance.Namespace,
+			Labels:    swift.GetLabelsUsageExport(),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          export.Schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: swift.GetLabelsUsageExport(),
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:      "Never",
+							ServiceAccountName: swift.ServiceAccount,
+							SecurityContext: &corev1.PodSecurityContext{
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
+							},
+							Containers: []corev1.Container{
+								{
+									Name:            "usage-export",
+									Command:         []string{"/usr/local/bin/container-scripts/usage-export.sh"},
+									Image:           instance.Spec.ContainerImageProxy,
+									SecurityContext: &securityContext,
+									VolumeMounts: []corev1.VolumeMount{
+										{
+											Name:      "scripts",
+											MountPath: "/usr/local/bin/container-scripts",
+											ReadOnly:  true,
+										},
+									},
+									Env: envs,
+								},
+							},
+							Volumes: []corev1.Volume{
+								{
+									Name: "scripts",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											DefaultMode: &scriptsVolumeDefaultMode,
+											SecretName:  instance.Name + "-scripts",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	podSpec.Containers, podSpec.Volumes = applyCredentialsSecretStore(instance, podSpec.Containers, podSpec.Volumes)
+	podSpec.Containers, podSpec.Volumes = applyProxyTLS(instance, podSpec.Containers, podSpec.Volumes)
+	swift.SetTerminationMessagePolicy(podSpec)
+	return cronJob
+}
+
+func getAccountPoliciesJob(instance *swiftv1beta1.SwiftProxy, labels map[string]string, authURL string, swiftURL string) *batchv1.Job {
+	entries := []string{}
+	for _, p := range instance.Spec.AccountPolicies {
+		for _, c := range p.Containers {
+			entries = append(entries, fmt.Sprintf("%s %s %s", p.Account, c, p.Policy))
+		}
+	}
+
+	envVars := map[string]env.Setter{}
+	envVars["ACCOUNT_POLICIES"] = env.SetValue(strings.Join(entries, "\n"))
+	return getServiceUserJob(instance, labels, authURL, swiftURL, "account-policies", envVars)
+}
+
+func getPublicContainersJob(instance *swiftv1beta1.SwiftProxy, labels map[string]string, authURL string, swiftURL string) *batchv1.Job {
+	entries := []string{}
+	for _, c := range instance.Spec.PublicContainers {
+		index := c.WebIndex
+		if index == "" {
+			index = "-"
+		}
+		entries = append(entries, fmt.Sprintf("%s %s %s %t", c.Account, c.Container, index, c.WebListings))
+	}
+
+	envVars := map[string]env.Setter{}
+	envVars["PUBLIC_CONTAINERS"] = env.SetValue(strings.Join(entries, "\n"))
+	return getServiceUserJob(instance, labels, authURL, swiftURL, "public-containers", envVars)
+}
+
+// getServiceUserJob returns a Job running the script of the given name with
+// the credentials of the service user and the proxy URL
+func getServiceUserJob(instance *swiftv1beta1.SwiftProxy, labels map[string]string, authURL string, swiftURL string, name string, envVars map[string]env.Setter) *batchv1.Job {
+	securityContext := swift.GetSecurityContext()
+	var scriptsVolumeDefaultMode int32 = 0755
+
+	envVars["OS_AUTH_URL"] = env.SetValue(authURL)
+	envVars["OS_USERNAME"] = env.SetValue(instance.Spec.ServiceUser)
+	envVars["SWIFT_URL"] = env.SetValue(swiftURL)
+	envs := env.MergeEnvs([]corev1.EnvVar{}, envVars)
+	envs = append(envs, corev1.EnvVar{
+		Name: "OS_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: instance.Spec.Secret},
+				Key:                  instance.Spec.PasswordSelectors.Service,
+			},
+		},
+	})
+
+	serviceUserJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-" + name,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      "OnFailure",
+					ServiceAccountName: swift.ServiceAccount,
+					SecurityContext: &corev1.PodSecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            instance.Name + "-" + name,
+							Command:         []string{"/usr/local/bin/container-scripts/" + name + ".sh"},
+							Image:           instance.Spec.ContainerImageProxy,
+							SecurityContext: &securityContext,
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      "scripts",
+								MountPath: "/usr/local/bin/container-scripts",
+								ReadOnly:  true,
+							}},
+							Env: envs,
+						},
+					},
+					Volumes: []corev1.Volume{{
+						Name: "scripts",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{
+								DefaultMode: &scriptsVolumeDefaultMode,
+								SecretName:  instance.Name + "-scripts",
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	podSpec := &serviceUserJob.Spec.Template.Spec
+	podSpec.Containers, podSpec.Volumes = applyCredentialsSecretStore(instance, podSpec.Containers, podSpec.Volumes)
+	podSpec.Containers, podSpec.Volumes = applyProxyTLS(instance, podSpec.Containers, podSpec.Volumes)
+	swift.SetTerminationMessagePolicy(podSpec)
+	return serviceUserJob
+}
+
+// getReadOnlyAccountsJob returns the Job setting the read-only system
+// metadata of the accounts. It runs with the proxy config and rings, as
+// system metadata can not be set through the proxy.
+func getReadOnlyAccountsJob(instance *swiftv1beta1.SwiftProxy, labels map[string]string, writable []string) *batchv1.Job {
+	securityContext := swift.GetSecurityContext()
+
+	keep := map[string]bool{}
+	for _, a := range writable {
+		keep[a] = true
+	}
+	reset := []string{}
+	for _, a := range instance.Status.WritableAccounts {
+		if !keep[a] {
+			reset = append(reset, a)
+		}
+	}
+
+	envVars := map[string]env.Setter{}
+	envVars["WRITABLE_ACCOUNTS"] = env.SetValue(strings.Join(writable, "\n"))
+	envVars["RESET_ACCOUNTS"] = env.SetValue(strings.Join(reset, "\n"))
+
+	readOnlyAccountsJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-read-only-accounts",
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      "OnFailure",
+					ServiceAccountName: swift.ServiceAccount,
+					SecurityContext: &corev1.PodSecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					InitContainers: getInitContainers(instance),
+					Containers: []corev1.Container{
+						{
+							Name:            instance.Name + "-read-only-accounts",
+							Command:         []string{"/usr/local/bin/container-scripts/read-only-accounts.sh"},
+							Image:           instance.Spec.ContainerImageProxy,
+							SecurityContext: &securityContext,
+							VolumeMounts:    getProxyVolumeMounts(),
+							Env:             env.MergeEnvs([]corev1.EnvVar{}, envVars),
+						},
+					},
+					Volumes: getProxyVolumes(instance, instance.Name),
+				},
+			},
+		},
+	}
+	podSpec := &readOnlyAccountsJob.Spec.Template.Spec
+	podSpec.Containers, podSpec.Volumes = applyProxyTLS(instance, podSpec.Containers, podSpec.Volumes)
+	swift.SetTerminationMessagePolicy(podSpec)
+	return readOnlyAccountsJob
+}
+
+// applyCredentialsSecretStore mounts the service password from the secret
+// store, if any, into the containers instead of taking it from the Secret.
+// The scripts read it from SERVICE_PASSWORD_FILE.
+func applyCredentialsSecretStore(instance *swiftv1beta1.SwiftProxy, containers []corev1.Container, volumes []corev1.Volume) ([]corev1.Container, []corev1.Volume) {
+	if instance.Spec.CredentialsSecretStore == nil {
+		return containers, volumes
+	}
+	for i := range containers {
+		envs := []corev1.EnvVar{}
+		for _, e := range containers[i].Env {
+			if e.Name != "OS_PASSWORD" {
+				envs = append(envs, e)
+			}
+		}
+		containers[i].Env = append(envs, corev1.EnvVar{
+			Name:  "SERVICE_PASSWORD_FILE",
+			Value: swift.CredentialsMountPath + "/" + instance.Spec.PasswordSelectors.Service,
+		})
+		containers[i].VolumeMounts = append(containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      "credentials",
+			MountPath: swift.CredentialsMountPath,
+			ReadOnly:  true,
+		})
+	}
+	volumes = append(volumes, corev1.Volume{
+		Name:         "credentials",
+		VolumeSource: swift.GetSecretStoreVolumeSource(instance.Spec.CredentialsSecretStore),
+	})
+	return containers, volumes
+}
+
+// applyProxyTLS mounts the proxy certificate, if any, into the containers.
+// The scripts calling the proxy trust the CA in SWIFT_CACERT.
+func applyProxyTLS(instance *swiftv1beta1.SwiftProxy, containers []corev1.Container, volumes []corev1.Volume) ([]corev1.Container, []corev1.Volume) {
+	if instance.Spec.TLS == nil {
+		return containers, volumes
+	}
+	for i := range containers {
+		containers[i].Env = append(containers[i].Env, corev1.EnvVar{
+			Name:  "SWIFT_CACERT",
+			Value: swift.TLSMountPath + "/ca.crt",
+		})
+		containers[i].VolumeMounts = append(containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      "tls",
+			MountPath: swift.TLSMountPath,
+			ReadOnly:  true,
+		})
+	}
+	volumes = append(volumes, corev1.Volume{
+		Name: "tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: swift.GetProxyTLSSecretName(instance),
+			},
+		},
+	})
+	return containers, volumes
+}
+
+// getProxyPools returns the proxy pools of the spec and the break-glass
+// pool, if enabled
+func getProxyPools(instance *swiftv1beta1.SwiftProxy) []swiftv1beta1.SwiftProxyPool {
+	pools := append([]swiftv1beta1.SwiftProxyPool{}, instance.Spec.Pools...)
+	if instance.Spec.BreakGlass != nil {
+		pools = append(pools, swiftv1beta1.SwiftProxyPool{
+			Name:     swift.BreakGlassPoolName,
+			Replicas: instance.Spec.BreakGlass.Replicas,
+			Pipeline: swift.ProxyPipelineNoAuth,
+		})
+	}
+	return pools
+}
+
+// getBreakGlassNetworkPolicy only allows the clients of the break-glass pool
+// to connect to it
+func getBreakGlassNetworkPolicy(instance *swiftv1beta1.SwiftProxy, name string, labels map[string]string) *networkingv1.NetworkPolicy {
+	port := intstr.FromInt(int(swift.ProxyPort))
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "np-" + name,
+			Namespace: instance.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{{Port: &port}},
+					From: []networkingv1.NetworkPolicyPeer{{
+						PodSelector: instance.Spec.BreakGlass.AllowedClients.DeepCopy(),
+					}},
+				},
+			},
+		},
+	}
+}
+
+// getProxyPoolForEndpoint returns the name of the pool serving the endpoint
+// type, or an empty string if the main proxy serves it
+func getProxyPoolForEndpoint(pools []swiftv1beta1.SwiftProxyPool, endpointType endpoint.Endpoint) string {
+	for _, pool := range pools {
+		for _, e := range pool.Endpoints {
+			if endpoint.Endpoint(e) == endpointType {
+				return pool.Name
+			}
+		}
 	}
+	return ""
+}
 
-	if instance.Status.Conditions == nil {
-		instance.Status.Conditions = condition.Conditions{}
-		cl := condition.CreateList(
-			condition.UnknownCondition(condition.ReadyCondition, condition.InitReason, condition.ReadyInitMessage),
-			condition.UnknownCondition(swiftv1beta1.SwiftProxyReadyCondition, condition.InitReason, swiftv1beta1.SwiftProxyReadyInitMessage),
-		)
+func getProxyPoolName(instance *swiftv1beta1.SwiftProxy, pool string) string {
+	return fmt.Sprintf("%s-%s", instance.Name, pool)
+}
 
-		instance.Status.Conditions.Init(&cl)
+// reconcileProxyPools creates the config, Deployment and Service of each
+// proxy pool and deletes the ones of pools removed from the spec
+func (r *SwiftProxyReconciler) reconcileProxyPools(ctx context.Context, instance *swiftv1beta1.SwiftProxy, h *helper.Helper, authURL string, password string, memcachedServers string) (ctrl.Result, error) {
+	pools := map[string]bool{}
+	for _, pool := range getProxyPools(instance) {
+		pools[pool.Name] = true
+		name := getProxyPoolName(instance, pool.Name)
+		labels := swift.GetLabelsProxyPool(pool.Name)
 
-		if err := r.Status().Update(ctx, instance); err != nil {
+		envVars := make(map[string]env.Setter)
+		tpl := getProxyPoolSecretTemplates(instance, pool, authURL, password, memcachedServers)
+		if err := secret.EnsureSecrets(ctx, h, instance, tpl, &envVars); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		depl := deployment.NewDeployment(
+			getProxyDeployment(instance, name, labels, pool.Replicas, pool.NodeSelector), 5*time.Second)
+		ctrlResult, err := depl.CreateOrPatch(ctx, h)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+
+		poolService := service.GenericService(&service.GenericServiceDetails{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+			Selector:  labels,
+			Port: service.GenericServicePort{
+				Name:     "proxy-server",
+				Port:     swift.ProxyPort,
+				Protocol: corev1.ProtocolTCP,
+			}})
+		swift.AddPropagatedMetadata(instance, &poolService.ObjectMeta)
+		svc := service.NewService(poolService, labels, 5*time.Second)
+		ctrlResult, err = svc.CreateOrPatch(ctx, h)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+		if err := swift.SetTopologyAwareRouting(ctx, h, name, instance.Namespace, instance.Spec.TopologyAwareRouting, instance.Spec.InternalTrafficPolicy); err != nil {
 			return ctrl.Result{}, err
 		}
+
+		if pool.Name == swift.BreakGlassPoolName {
+			np := swift.NewNetworkPolicy(getBreakGlassNetworkPolicy(instance, name, labels), labels, 5*time.Second)
+			ctrlResult, err = np.CreateOrPatch(ctx, h)
+			if err != nil {
+				return ctrlResult, err
+			} else if (ctrlResult != ctrl.Result{}) {
+				return ctrlResult, nil
+			}
+		}
 	}
 
-	helper, err := helper.NewHelper(instance, r.Client, r.Kclient, r.Scheme, r.Log)
+	// Delete the resources of removed pools
+	deployments := &appsv1.DeploymentList{}
+	err := h.GetClient().List(ctx, deployments,
+		client.InNamespace(instance.Namespace), client.MatchingLabels(swift.GetLabelsProxyPools()))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	for _, d := range deployments.Items {
+		pool := d.Spec.Template.Labels[swift.ProxyPoolLabel]
+		if pools[pool] || !metav1.IsControlledBy(&d, instance) {
+			continue
+		}
+		name := getProxyPoolName(instance, pool)
+		objs := []client.Object{
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name + "-config-data", Namespace: instance.Namespace}},
+			&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "np-" + name, Namespace: instance.Namespace}},
+		}
+		for _, obj := range objs {
+			if err := h.GetClient().Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		r.Log.Info(fmt.Sprintf("Deleted proxy pool '%s' of SwiftProxy '%s'", pool, instance.Name))
+	}
 
-	controllerutil.AddFinalizer(instance, helper.GetFinalizer())
-	if err := r.Update(ctx, instance); err != nil {
-		return ctrl.Result{}, err
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// The optional resources are only watched if their feature is enabled, the
+// operator may not have the permissions to list them otherwise.
+func (r *SwiftProxyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&swiftv1beta1.SwiftProxy{}).
+		Owns(&corev1.Secret{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{})
+	if r.Features.KeystoneEndpoints {
+		b = b.Owns(&keystonev1.KeystoneService{}).
+			Owns(&keystonev1.KeystoneEndpoint{})
+	}
+	if r.Features.Routes {
+		b = b.Owns(&routev1.Route{})
+	}
+	if r.Features.Autoscaling {
+		b = b.Owns(&autoscalingv2.HorizontalPodAutoscaler{})
 	}
 
-	if !instance.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, instance, helper)
+	// The certificate Secrets are created by cert-manager or the user, map
+	// them to the SwiftProxy by name to roll the pods and update the Route
+	// when they are renewed
+	certificateFilter := func(o client.Object) []reconcile.Request {
+		result := []reconcile.Request{}
+		swiftProxies := &swiftv1beta1.SwiftProxyList{}
+		listOpts := []client.ListOption{client.InNamespace(o.GetNamespace())}
+		if err := r.Client.List(context.Background(), swiftProxies, listOpts...); err != nil {
+			r.Log.Error(err, "Unable to list SwiftProxies")
+			return result
+		}
+
+		for i := range swiftProxies.Items {
+			instance := &swiftProxies.Items[i]
+			if instance.Spec.TLS == nil {
+				continue
+			}
+			if o.GetName() == swift.GetProxyTLSSecretName(instance) || o.GetName() == swift.GetProxyRouteTLSSecretName(instance) {
+				name := client.ObjectKey{
+					Namespace: o.GetNamespace(),
+					Name:      instance.Name,
+				}
+				result = append(result, reconcile.Request{NamespacedName: name})
+			}
+		}
+		return result
 	}
+	b = b.Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(certificateFilter))
 
-	// Check if there is a ConfigMap for the Swift rings
-	cm, ctrlResult, err := configmap.GetConfigMap(
-		ctx, helper, instance, swiftv1beta1.RingConfigMapName, 5*time.Second)
-	if err != nil {
-		return ctrlResult, err
-	} else if (ctrlResult != ctrl.Result{}) {
-		return ctrlResult, nil
+	return b.Complete(r)
+}
+
+// exposeEndpoints creates the Services of the endpoints and returns their
+// URLs. The public endpoint is exposed with a Route if routes are enabled
+// and permitted, with its Service otherwise. With TLS the Route re-encrypts
+// the requests to the proxy.
+func (r *SwiftProxyReconciler) exposeEndpoints(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftProxy,
+	selector map[string]string,
+	ports map[endpoint.Endpoint]endpoint.Data,
+	unavailable map[string]string,
+) (map[string]string, ctrl.Result, error) {
+	routes := r.features().Routes
+	if routes && instance.Spec.TLS == nil {
+		apiEndpoints, ctrlResult, err := endpoint.ExposeEndpoints(
+			ctx,
+			h,
+			swift.ServiceName,
+			selector,
+			ports,
+			time.Duration(5)*time.Second,
+		)
+		if !swift.IsPermissionError(err) {
+			return apiEndpoints, ctrlResult, err
+		}
+		r.Log.Info(fmt.Sprintf("Not exposing the public endpoint with a Route: %s", err))
+		unavailable[swift.FeatureRoutes] = swift.FeatureForbidden
+		routes = false
 	}
 
-	// This will only exist after SwiftStorage instances are up and rings
-	// are set up properly
-	_, ok := cm.BinaryData["swiftrings.tar.gz"]
-	if !ok {
-		return ctrl.Result{RequeueAfter: time.Duration(10) * time.Second}, nil
+	scheme := "http"
+	if instance.Spec.TLS != nil {
+		scheme = "https"
 	}
+	apiEndpoints := map[string]string{}
+	for endpointType, data := range ports {
+		name := fmt.Sprintf("%s-%s", swift.ServiceName, endpointType)
+		exportLabels := util.MergeStringMaps(selector, map[string]string{string(endpointType): "true"})
+		endpointService := service.GenericService(&service.GenericServiceDetails{
+			Name:      name,
+			Namespace: h.GetBeforeObject().GetNamespace(),
+			Labels:    exportLabels,
+			Selector:  selector,
+			Port: service.GenericServicePort{
+				Name:     name,
+				Port:     data.Port,
+				Protocol: corev1.ProtocolTCP,
+			}})
+		swift.AddPropagatedMetadata(instance, &endpointService.ObjectMeta)
+		svc := service.NewService(
+			endpointService,
+			exportLabels,
+			time.Duration(5)*time.Second,
+		)
+		ctrlResult, err := svc.CreateOrPatch(ctx, h)
+		if err != nil {
+			return nil, ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return nil, ctrlResult, nil
+		}
+		apiEndpoints[string(endpointType)] = fmt.Sprintf("%s://%s%s", scheme, svc.GetServiceHostnamePort(), data.Path)
 
-	labels := swift.GetLabelsProxy()
+		if routes && endpointType == endpoint.EndpointPublic {
+			hostname, ctrlResult, err := r.exposeReencryptRoute(ctx, h, instance, name, exportLabels)
+			if swift.IsPermissionError(err) {
+				r.Log.Info(fmt.Sprintf("Not exposing the public endpoint with a Route: %s", err))
+				unavailable[swift.FeatureRoutes] = swift.FeatureForbidden
+				continue
+			} else if err != nil {
+				return nil, ctrlResult, err
+			} else if (ctrlResult != ctrl.Result{}) {
+				return nil, ctrlResult, nil
+			}
+			apiEndpoints[string(endpointType)] = fmt.Sprintf("https://%s%s", hostname, data.Path)
+		}
+	}
+	return apiEndpoints, ctrl.Result{}, nil
+}
 
-	// Create a Service and endpoints for the proxy
-	var swiftPorts = map[endpoint.Endpoint]endpoint.Data{
-		endpoint.EndpointAdmin: endpoint.Data{
-			Port: swift.ProxyPort,
-			Path: "",
-		},
-		endpoint.EndpointPublic: endpoint.Data{
-			Port: swift.ProxyPort,
-			Path: "/v1/AUTH_%(tenant_id)s",
-		},
-		endpoint.EndpointInternal: endpoint.Data{
-			Port: swift.ProxyPort,
-			Path: "/v1/AUTH_%(tenant_id)s",
-		},
+// exposeReencryptRoute exposes the Service with a re-encrypt Route and
+// returns its hostname. The Route trusts the ca.crt of the proxy certificate
+// and serves the certificate of the route Secret, if any.
+func (r *SwiftProxyReconciler) exposeReencryptRoute(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftProxy,
+	name string,
+	labels map[string]string,
+) (string, ctrl.Result, error) {
+	proxySecret, _, err := secret.GetSecret(ctx, h, swift.GetProxyTLSSecretName(instance), instance.Namespace)
+	if apierrors.IsNotFound(err) {
+		r.Log.Info(fmt.Sprintf("Waiting for the proxy certificate Secret %s", swift.GetProxyTLSSecretName(instance)))
+		return "", ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	} else if err != nil {
+		return "", ctrl.Result{}, err
+	}
+	tls := &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationReencrypt,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		DestinationCACertificate:      string(proxySecret.Data["ca.crt"]),
+	}
+	// The Route serves the router certificate until the certificate of its
+	// issuer is ready
+	routeIssued := false
+	if name := swift.GetProxyRouteTLSSecretName(instance); name != "" {
+		routeSecret, _, err := secret.GetSecret(ctx, h, name, instance.Namespace)
+		if apierrors.IsNotFound(err) && instance.Spec.TLS.RouteIssuer == "" {
+			r.Log.Info(fmt.Sprintf("Waiting for the route certificate Secret %s", name))
+			return "", ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+		} else if err != nil && !apierrors.IsNotFound(err) {
+			return "", ctrl.Result{}, err
+		}
+		if routeSecret != nil {
+			tls.Certificate = string(routeSecret.Data["tls.crt"])
+			tls.Key = string(routeSecret.Data["tls.key"])
+			tls.CACertificate = string(routeSecret.Data["ca.crt"])
+			routeIssued = true
+		}
 	}
 
-	apiEndpoints, ctrlResult, err := endpoint.ExposeEndpoints(
-		ctx,
-		helper,
-		swift.ServiceName,
-		labels,
-		swiftPorts,
-		time.Duration(5)*time.Second,
-	)
-	if err != nil {
-		r.Log.Error(err, "Failed to expose endpoints for Swift Proxy")
-		return ctrlResult, err
-	} else if (ctrlResult != ctrl.Result{}) {
-		return ctrlResult, nil
+	desired := route.GenericRoute(&route.GenericRouteDetails{
+		Name:           name,
+		Namespace:      instance.Namespace,
+		Labels:         labels,
+		ServiceName:    name,
+		TargetPortName: name,
+	})
+	desired.Spec.TLS = tls
+	rt := route.NewRoute(desired, labels, time.Duration(5)*time.Second)
+	ctrlResult, err := rt.CreateOrPatch(ctx, h)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return "", ctrlResult, err
 	}
 
-	if instance.Status.APIEndpoints == nil {
-		instance.Status.APIEndpoints = map[string]map[string]string{}
+	if instance.Spec.TLS.RouteIssuer != "" && rt.GetHostname() != "" {
+		err = swift.CreateOrPatchProxyRouteCertificate(ctx, h, instance, labels, rt.GetHostname())
+		if err != nil {
+			return "", ctrl.Result{}, err
+		}
+		if !routeIssued {
+			r.Log.Info(fmt.Sprintf("Waiting for the route certificate Secret %s", swift.GetProxyRouteTLSSecretName(instance)))
+			return "", ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+		}
 	}
-	instance.Status.APIEndpoints[swift.ServiceName] = apiEndpoints
+	return rt.GetHostname(), ctrl.Result{}, nil
+}
 
-	// Create Keystone Service
+// registerKeystoneEndpoints creates the KeystoneService and KeystoneEndpoint
+// of the proxy and mirrors their readiness in the conditions of the proxy. It
+// requeues until both are Ready.
+func (r *SwiftProxyReconciler) registerKeystoneEndpoints(
+	ctx context.Context,
+	instance *swiftv1beta1.SwiftProxy,
+	h *helper.Helper,
+	labels map[string]string,
+) (ctrl.Result, error) {
 	ksh := getKeystoneServiceHelper(instance, labels)
-	ctrlResult, err = ksh.CreateOrPatch(ctx, helper)
+	ctrlResult, err := ksh.CreateOrPatch(ctx, h)
 	if err != nil {
 		return ctrlResult, err
 	}
-
-	// Create Keystone endpoints
-	eph := getKeystoneEndpointHelper(instance, labels)
-	ctrlResult, err = eph.CreateOrPatch(ctx, helper)
-	if err != nil {
-		return ctrlResult, err
+	if c := ksh.GetConditions().Mirror(condition.KeystoneServiceReadyCondition); c != nil {
+		instance.Status.Conditions.Set(c)
 	}
-
-	// Get the Keystone authURL
-	keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, helper, instance.Namespace, map[string]string{})
-	if err != nil {
-		return ctrlResult, err
+	if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
 	}
-	authURL, err := keystoneAPI.GetEndpoint(endpoint.EndpointPublic)
-	if err != nil {
-		return ctrlResult, err
+	if !instance.Status.Conditions.IsTrue(condition.KeystoneServiceReadyCondition) {
+		r.Log.Info(fmt.Sprintf("Waiting for KeystoneService %s to be ready", swift.ServiceName))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
 	}
 
-	// Get the service password
-	sps, _, err := secret.GetSecret(ctx, helper, instance.Spec.Secret, instance.Namespace)
+	eph := getKeystoneEndpointHelper(instance, labels)
+	ctrlResult, err = eph.CreateOrPatch(ctx, h)
 	if err != nil {
 		return ctrlResult, err
 	}
-	password := string(sps.Data[instance.Spec.PasswordSelectors.Service])
-
-	// Create a Secret populated with content from templates/
-	envVars := make(map[string]env.Setter)
-	tpl := getProxySecretTemplates(instance, labels, authURL, password)
-	err = secret.EnsureSecrets(ctx, helper, instance, tpl, &envVars)
-	if err != nil {
-		return ctrl.Result{}, err
+	if c := eph.GetConditions().Mirror(condition.KeystoneEndpointReadyCondition); c != nil {
+		instance.Status.Conditions.Set(c)
 	}
-
-	// Create Deployment
-	depl := deployment.NewDeployment(getProxyDeployment(instance, labels), 5*time.Second)
-	ctrlResult, err = depl.CreateOrPatch(ctx, helper)
-	if err != nil {
-		return ctrlResult, err
-	} else if (ctrlResult != ctrl.Result{}) {
+	if (ctrlResult != ctrl.Result{}) {
 		return ctrlResult, nil
 	}
-
-	if depl.GetDeployment().Status.ReadyReplicas > 0 {
-		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
-		instance.Status.Conditions.MarkTrue(swiftv1beta1.SwiftProxyReadyCondition, condition.ReadyMessage)
-		if err := r.Status().Update(ctx, instance); err != nil {
-			return ctrl.Result{}, err
-		}
+	if !instance.Status.Conditions.IsTrue(condition.KeystoneEndpointReadyCondition) {
+		r.Log.Info(fmt.Sprintf("Waiting for KeystoneEndpoint %s to be ready", swift.ServiceName))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
 	}
-
-	r.Log.Info(fmt.Sprintf("Reconciled SwiftProxy '%s' successfully", instance.Name))
 	return ctrl.Result{}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *SwiftProxyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&swiftv1beta1.SwiftProxy{}).
-		Owns(&corev1.Secret{}).
-		Owns(&keystonev1.KeystoneService{}).
-		Owns(&keystonev1.KeystoneEndpoint{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&corev1.Service{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&routev1.Route{}).
-		Complete(r)
-}
-
-func getProxySecretTemplates(instance *swiftv1beta1.SwiftProxy, labels map[string]string, authURL string, password string) []util.Template {
+func getProxySecretTemplates(instance *swiftv1beta1.SwiftProxy, labels map[string]string, authURL string, password string, memcachedServers string) []util.Template {
 	templateParameters := make(map[string]interface{})
 	templateParameters["ServiceUser"] = instance.Spec.ServiceUser
 	templateParameters["ServicePassword"] = password
 	templateParameters["KeystonePublicURL"] = authURL
+	templateParameters["Pipeline"] = swift.ProxyPipeline
+	if instance.Spec.S3API {
+		templateParameters["Pipeline"] = swift.ProxyPipelineS3API
+	}
+	templateParameters["Pipeline"] = swift.GetStaticWebPipeline(templateParameters["Pipeline"].(string), instance.Spec.PublicContainers)
+	templateParameters["Pipeline"] = swift.GetLifecyclePipeline(templateParameters["Pipeline"].(string), instance.Spec.Lifecycle)
+	templateParameters["Pipeline"] = swift.GetReadOnlyPipeline(templateParameters["Pipeline"].(string), instance.Spec.ReadOnly)
+	templateParameters["LifecycleExpireAfter"] = swift.GetLifecycleExpireAfter(instance.Spec.Lifecycle)
+	templateParameters["ReadOnlyAllowDeletes"] = instance.Spec.ReadOnly != nil && instance.Spec.ReadOnly.AllowDeletes
+	templateParameters["Workers"] = instance.Spec.Workers
+	templateParameters["ErrorSuppressionInterval"] = instance.Spec.ErrorSuppressionInterval
+	templateParameters["ErrorSuppressionLimit"] = instance.Spec.ErrorSuppressionLimit
+	templateParameters["ConcurrentGets"] = instance.Spec.ConcurrentGets
+	templateParameters["ConcurrencyTimeout"] = instance.Spec.ConcurrencyTimeout
+	templateParameters["SortingMethod"] = instance.Spec.SortingMethod
+	templateParameters["TimingExpiry"] = instance.Spec.TimingExpiry
+	templateParameters["ReadAffinity"] = instance.Spec.ReadAffinity
+	if swift.HasProxyCustomMetrics(instance) {
+		templateParameters["StatsdPort"] = swift.ProxyStatsdPort
+	}
+	templateParameters["MemcachedServers"] = memcachedServers
+	templateParameters["TLS"] = instance.Spec.TLS != nil
+	templateParameters["TLSPath"] = swift.TLSMountPath
+	for k, v := range getKeystoneAuthParameters(instance.Spec.KeystoneAuth) {
+		templateParameters[k] = v
+	}
 
 	return []util.Template{
 		{
@@ -262,38 +1778,71 @@ func getProxySecretTemplates(instance *swiftv1beta1.SwiftProxy, labels map[strin
 			Type:          util.TemplateTypeConfig,
 			InstanceType:  instance.Kind,
 			ConfigOptions: templateParameters,
+			CustomData:    swift.GetConfigOverwriteData(instance.Spec.DefaultConfigOverwrite),
 			Labels:        labels,
 		},
 		{
 			Name:               fmt.Sprintf("%s-scripts", instance.Name),
 			Namespace:          instance.Namespace,
 			Type:               util.TemplateTypeScripts,
-			AdditionalTemplate: map[string]string{"swift-init.sh": "/common/swift-init.sh", "ring-sync.sh": "/common/ring-sync.sh"},
+			AdditionalTemplate: map[string]string{"swift-init.sh": "/common/swift-init.sh", "ring-sync.sh": "/common/ring-sync.sh", "nofile-exec.sh": "/common/nofile-exec.sh"},
 			InstanceType:       instance.Kind,
 			Labels:             labels,
 		},
 	}
 }
 
-func getProxyVolumes(instance *swiftv1beta1.SwiftProxy) []corev1.Volume {
+// getKeystoneAuthParameters returns the keystoneauth settings rendered in the
+// proxy config, unset values are empty and keep the Swift defaults
+func getKeystoneAuthParameters(ka *swiftv1beta1.SwiftProxyKeystoneAuth) map[string]string {
+	params := map[string]string{
+		"OperatorRoles":      "admin, SwiftOperator",
+		"AllowOverrides":     "",
+		"AllowNamesInACLs":   "",
+		"SystemReaderRoles":  "",
+		"ProjectReaderRoles": "",
+	}
+	if ka == nil {
+		return params
+	}
+	if len(ka.OperatorRoles) > 0 {
+		params["OperatorRoles"] = strings.Join(ka.OperatorRoles, ", ")
+	}
+	if ka.AllowOverrides != nil {
+		params["AllowOverrides"] = strconv.FormatBool(*ka.AllowOverrides)
+	}
+	if ka.AllowNamesInACLs != nil {
+		params["AllowNamesInACLs"] = strconv.FormatBool(*ka.AllowNamesInACLs)
+	}
+	params["SystemReaderRoles"] = strings.Join(ka.SystemReaderRoles, ", ")
+	params["ProjectReaderRoles"] = strings.Join(ka.ProjectReaderRoles, ", ")
+	return params
+}
+
+// getProxyPoolSecretTemplates returns the config of a proxy pool, which only
+// differs from the main proxy in the pipeline
+func getProxyPoolSecretTemplates(instance *swiftv1beta1.SwiftProxy, pool swiftv1beta1.SwiftProxyPool, authURL string, password string, memcachedServers string) []util.Template {
+	tpl := getProxySecretTemplates(instance, swift.GetLabelsProxyPool(pool.Name), authURL, password, memcachedServers)[:1]
+	tpl[0].Name = fmt.Sprintf("%s-config-data", getProxyPoolName(instance, pool.Name))
+	if pool.Pipeline != "" {
+		tpl[0].ConfigOptions["Pipeline"] = swift.GetReadOnlyPipeline(
+			swift.GetLifecyclePipeline(pool.Pipeline, instance.Spec.Lifecycle), instance.Spec.ReadOnly)
+	}
+	return tpl
+}
+
+func getProxyVolumes(instance *swiftv1beta1.SwiftProxy, name string) []corev1.Volume {
 	var scriptsVolumeDefaultMode int32 = 0755
-	return []corev1.Volume{
+	volumes := []corev1.Volume{
 		{
 			Name: "config-data",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: instance.Name + "-config-data",
-				},
-			},
-		},
-		{
-			Name: "swiftconf",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: instance.Spec.SwiftConfSecret,
+					SecretName: name + "-config-data",
 				},
 			},
 		},
+		swift.GetSwiftConfVolume(instance.Spec.SwiftConfSecret, instance.Spec.SwiftConfSecretStore),
 		{
 			Name: "ring-data",
 			VolumeSource: corev1.VolumeSource{
@@ -320,6 +1869,14 @@ func getProxyVolumes(instance *swiftv1beta1.SwiftProxy) []corev1.Volume {
 			},
 		},
 	}
+	if instance.Spec.ContainerSync != nil {
+		volumes = append(volumes, swift.GetContainerSyncVolume(instance.Name))
+	}
+	if instance.Spec.TopologyAwareRouting {
+		volumes = append(volumes, swift.GetZoneAffinityVolume(instance.Name))
+	}
+	_, volumes = applyCredentialsSecretStore(instance, nil, volumes)
+	return volumes
 }
 
 func getProxyVolumeMounts() []corev1.VolumeMount {
@@ -354,7 +1911,7 @@ func getProxyVolumeMounts() []corev1.VolumeMount {
 
 func getInitContainers(swiftproxy *swiftv1beta1.SwiftProxy) []corev1.Container {
 	securityContext := swift.GetSecurityContext()
-	return []corev1.Container{
+	initContainers := []corev1.Container{
 		{
 			Name:            "swift-init",
 			Image:           swiftproxy.Spec.ContainerImageProxy,
@@ -364,13 +1921,30 @@ func getInitContainers(swiftproxy *swiftv1beta1.SwiftProxy) []corev1.Container {
 			Command:         []string{"/usr/local/bin/container-scripts/swift-init.sh"},
 		},
 	}
+	if swiftproxy.Spec.ContainerSync != nil {
+		initContainers[0].VolumeMounts = append(initContainers[0].VolumeMounts, swift.GetContainerSyncVolumeMount())
+	}
+	if swiftproxy.Spec.TopologyAwareRouting {
+		initContainers[0].VolumeMounts = append(initContainers[0].VolumeMounts, swift.GetZoneAffinityVolumeMount())
+		initContainers[0].Env = append(initContainers[0].Env, corev1.EnvVar{
+			Name: "NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+			},
+		})
+	}
+	initContainers, _ = applyCredentialsSecretStore(swiftproxy, initContainers, nil)
+	return initContainers
 }
 
 func getProxyDeployment(
-	instance *swiftv1beta1.SwiftProxy, labels map[string]string) *appsv1.Deployment {
+	instance *swiftv1beta1.SwiftProxy, name string, labels map[string]string, replicas int32, nodeSelector map[string]string) *appsv1.Deployment {
 
 	trueVal := true
 	securityContext := swift.GetSecurityContext()
+	if len(nodeSelector) == 0 {
+		nodeSelector = instance.Spec.NodeSelector
+	}
 
 	livenessProbe := &corev1.Probe{
 		TimeoutSeconds:      5,
@@ -383,28 +1957,101 @@ func getProxyDeployment(
 		InitialDelaySeconds: 5,
 	}
 
+	scheme := corev1.URISchemeHTTP
+	if instance.Spec.TLS != nil {
+		scheme = corev1.URISchemeHTTPS
+	}
 	livenessProbe.HTTPGet = &corev1.HTTPGetAction{
-		Path: "/healthcheck",
-		Port: intstr.FromInt(int(swift.ProxyPort)),
+		Path:   "/healthcheck",
+		Port:   intstr.FromInt(int(swift.ProxyPort)),
+		Scheme: scheme,
 	}
 	readinessProbe.HTTPGet = &corev1.HTTPGetAction{
-		Path: "/healthcheck",
-		Port: intstr.FromInt(int(swift.ProxyPort)),
+		Path:   "/healthcheck",
+		Port:   intstr.FromInt(int(swift.ProxyPort)),
+		Scheme: scheme,
+	}
+
+	containers := []corev1.Container{
+		{
+			Image:           instance.Spec.ContainerImageProxy,
+			Name:            "proxy-server",
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			Ports: []corev1.ContainerPort{{
+				ContainerPort: swift.ProxyPort,
+				Name:          "proxy-server",
+			}},
+			ReadinessProbe: readinessProbe,
+			LivenessProbe:  livenessProbe,
+			VolumeMounts:   getProxyVolumeMounts(),
+			Command:        []string{"/usr/bin/swift-proxy-server", "/etc/swift/proxy-server.conf", "-v"},
+		},
+		{
+			Image:           instance.Spec.ContainerImageMemcached,
+			Name:            "memcached",
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			Ports: []corev1.ContainerPort{{
+				ContainerPort: swift.MemcachedPort,
+				Name:          "memcached",
+			}},
+			VolumeMounts: getProxyVolumeMounts(),
+			Command:      []string{"/usr/bin/memcached", "-p", "11211", "-u", "memcached"},
+		},
+		{
+			Name:            "ring-sync",
+			Image:           instance.Spec.ContainerImageProxy,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			ReadinessProbe:  readinessProbe,
+			LivenessProbe:   livenessProbe,
+			VolumeMounts:    getProxyVolumeMounts(),
+			Env: append(swift.GetRingSyncEnvVars(), corev1.EnvVar{
+				Name:  "FLUSH_MEMCACHED",
+				Value: strconv.FormatBool(instance.Spec.FlushCacheOnRingChange && instance.Spec.MemcachedInstance == ""),
+			}),
+			Command: []string{"/usr/local/bin/container-scripts/ring-sync.sh"},
+		},
+	}
+	if instance.Spec.MemcachedInstance != "" {
+		containers = removeMemcachedContainer(containers)
+	}
+	if swift.HasProxyCustomMetrics(instance) {
+		containers = append(containers, getProxyExporterContainer(instance))
+	}
+	// The lifecycle middleware is loaded from the scripts Secret
+	if instance.Spec.Lifecycle != nil {
+		containers[0].Env = append(containers[0].Env, corev1.EnvVar{
+			Name:  "PYTHONPATH",
+			Value: swift.LifecycleMiddlewarePath,
+		})
+	}
+	containers = swift.ApplyNofileLimits(containers, instance.Spec.NofileLimits)
+	containers = swift.ApplyContainerEnv(containers, instance.Spec.ContainerEnv)
+	containers = swift.ApplyResources(containers, instance.Spec.Resources)
+	containers, volumes := applyProxyTLS(instance, containers, getProxyVolumes(instance, name))
+	containers, volumes = swift.ApplyExtraMounts(containers, volumes, instance.Spec.ExtraMounts)
+	initContainers, _ := swift.ApplyExtraMounts(getInitContainers(instance), nil, instance.Spec.ExtraMounts)
+	annotations := map[string]string{}
+	if hash := instance.Status.Hash[swiftv1beta1.CertificateHash]; hash != "" {
+		annotations[swift.CertificateHashAnnotation] = hash
 	}
 
-	return &appsv1.Deployment{
+	depl := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.Name,
+			Name:      name,
 			Namespace: instance.Namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Replicas: &instance.Spec.Replicas,
+			Replicas: &replicas,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      labels,
+					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: swift.ServiceAccount,
@@ -414,48 +2061,119 @@ func getProxyDeployment(
 							Type: corev1.SeccompProfileTypeRuntimeDefault,
 						},
 					},
-					Volumes:        getProxyVolumes(instance),
-					InitContainers: getInitContainers(instance),
-					Containers: []corev1.Container{
-						{
-							Image:           instance.Spec.ContainerImageProxy,
-							Name:            "proxy-server",
-							ImagePullPolicy: corev1.PullIfNotPresent,
-							SecurityContext: &securityContext,
-							Ports: []corev1.ContainerPort{{
-								ContainerPort: swift.ProxyPort,
-								Name:          "proxy-server",
-							}},
-							ReadinessProbe: readinessProbe,
-							LivenessProbe:  livenessProbe,
-							VolumeMounts:   getProxyVolumeMounts(),
-							Command:        []string{"/usr/bin/swift-proxy-server", "/etc/swift/proxy-server.conf", "-v"},
-						},
-						{
-							Image:           instance.Spec.ContainerImageMemcached,
-							Name:            "memcached",
-							ImagePullPolicy: corev1.PullIfNotPresent,
-							SecurityContext: &securityContext,
-							Ports: []corev1.ContainerPort{{
-								ContainerPort: swift.MemcachedPort,
-								Name:          "memcached",
-							}},
-							VolumeMounts: getProxyVolumeMounts(),
-							Command:      []string{"/usr/bin/memcached", "-p", "11211", "-u", "memcached"},
-						},
-						{
-							Name:            "ring-sync",
-							Image:           instance.Spec.ContainerImageProxy,
-							ImagePullPolicy: corev1.PullIfNotPresent,
-							SecurityContext: &securityContext,
-							ReadinessProbe:  readinessProbe,
-							LivenessProbe:   livenessProbe,
-							VolumeMounts:    getProxyVolumeMounts(),
-							Command:         []string{"/usr/local/bin/container-scripts/ring-sync.sh"},
-						},
-					},
+					Volumes:        volumes,
+					InitContainers: initContainers,
+					Containers:     containers,
+					NodeSelector:   nodeSelector,
+					Tolerations:    instance.Spec.Tolerations,
+				},
+			},
+		},
+	}
+	swift.AddBackendTLS(&depl.Spec.Template.Spec)
+	swift.SetTerminationMessagePolicy(&depl.Spec.Template.Spec)
+	swift.AddPropagatedMetadata(instance, &depl.ObjectMeta)
+	swift.AddPropagatedMetadata(instance, &depl.Spec.Template.ObjectMeta)
+	return depl
+}
+
+// getProxyExporterContainer returns a sidecar receiving the statsd timings of
+// the proxy server and exposing the request rate and latency the
+// HorizontalPodAutoscaler scales on
+func getProxyExporterContainer(instance *swiftv1beta1.SwiftProxy) corev1.Container {
+	securityContext := swift.GetSecurityContext()
+
+	return corev1.Container{
+		Name:            "proxy-exporter",
+		Image:           instance.Spec.ContainerImageProxy,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		SecurityContext: &securityContext,
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: swift.ProxyMetricsPort,
+			Name:          "proxy-metrics",
+		}},
+		VolumeMounts: getProxyVolumeMounts(),
+		Env: []corev1.EnvVar{
+			{
+				Name:  "METRICS_PORT",
+				Value: strconv.Itoa(int(swift.ProxyMetricsPort)),
+			},
+			{
+				Name:  "STATSD_PORT",
+				Value: strconv.Itoa(int(swift.ProxyStatsdPort)),
+			},
+		},
+		Command: []string{"/usr/local/bin/container-scripts/proxy-exporter.sh"},
+	}
+}
+
+func getProxyHorizontalPodAutoscaler(
+	instance *swiftv1beta1.SwiftProxy, labels map[string]string) *autoscalingv2.HorizontalPodAutoscaler {
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+	}
+
+	as := instance.Spec.Autoscaling
+	if as == nil {
+		return hpa
+	}
+
+	metrics := []autoscalingv2.MetricSpec{}
+	if as.TargetCPUUtilization != nil {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: as.TargetCPUUtilization,
 				},
 			},
+		})
+	}
+	if as.RequestRate != nil {
+		metrics = append(metrics, getProxyPodsMetric(as.RequestRate, swift.ProxyRequestRateMetric))
+	}
+	if as.Latency != nil {
+		metrics = append(metrics, getProxyPodsMetric(as.Latency, swift.ProxyLatencyMetric))
+	}
+
+	hpa.Spec = autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       instance.Name,
+		},
+		MinReplicas: &as.MinReplicas,
+		MaxReplicas: as.MaxReplicas,
+		Metrics:     metrics,
+	}
+
+	return hpa
+}
+
+func getProxyPodsMetric(target *swiftv1beta1.SwiftProxyMetricTarget, defaultName string) autoscalingv2.MetricSpec {
+	name := target.MetricName
+	if name == "" {
+		name = defaultName
+	}
+	value := target.TargetAverageValue
+
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name: name,
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &value,
+			},
 		},
 	}
 }
@@ -496,11 +2214,11 @@ func (r *SwiftProxyReconciler) reconcileDelete(ctx context.Context, instance *sw
 	r.Log.Info(fmt.Sprintf("Reconciling Service '%s' delete", instance.Name))
 
 	// It's possible to get here before the endpoints have been set in the status, so check for this
-	if instance.Status.APIEndpoints != nil {
+	if instance.Status.APIEndpoints != nil && r.features().KeystoneEndpoints {
 
 		// Remove the finalizer from our KeystoneEndpoint CR
 		keystoneEndpoint, err := keystonev1.GetKeystoneEndpointWithName(ctx, helper, swift.ServiceName, instance.Namespace)
-		if err != nil && !apierrors.IsNotFound(err) {
+		if err != nil && !apierrors.IsNotFound(err) && !swift.IsPermissionError(err) {
 			return ctrl.Result{}, err
 		}
 
@@ -514,7 +2232,7 @@ func (r *SwiftProxyReconciler) reconcileDelete(ctx context.Context, instance *sw
 
 		// Remove the finalizer from our KeystoneService CR
 		keystoneService, err := keystonev1.GetKeystoneServiceWithName(ctx, helper, swift.ServiceName, instance.Namespace)
-		if err != nil && !apierrors.IsNotFound(err) {
+		if err != nil && !apierrors.IsNotFound(err) && !swift.IsPermissionError(err) {
 			return ctrl.Result{}, err
 		}
 
@@ -0,0 +1,37 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+var _ = Describe("SwiftProxy controller", func() {
+	Context("with proxy pools", func() {
+		It("registers the endpoint types of a pool with its Service", func() {
+			pools := []swiftv1beta1.SwiftProxyPool{{Name: "internal", Endpoints: []string{"internal", "admin"}}}
+			Expect(getProxyPoolForEndpoint(pools, endpoint.EndpointInternal)).To(Equal("internal"))
+			Expect(getProxyPoolForEndpoint(pools, endpoint.EndpointAdmin)).To(Equal("internal"))
+			Expect(getProxyPoolForEndpoint(pools, endpoint.EndpointPublic)).To(BeEmpty())
+		})
+	})
+})
@@ -18,24 +18,30 @@ package controllers
 
 import (
 	"context"
+	"crypto/md5"
 	"fmt"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strings"
 	"time"
 
 	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/endpoint"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/job"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 
+	keystonev1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
 	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
 	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
 	batchv1 "k8s.io/api/batch/v1"
@@ -43,6 +49,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -58,7 +65,12 @@ type SwiftRingReconciler struct {
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftrings/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftrings/finalizers,verbs=update
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=*,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftstorages,verbs=get;list;watch
+//+kubebuilder:rbac:groups=keystone.openstack.org,resources=keystoneapis,verbs=get;list;watch
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
 
@@ -119,6 +131,13 @@ func (r *SwiftRingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	ls := swift.GetLabelsRing()
 
+	// Only report the changes to the rings and the sub-resources in dry-run
+	// mode, the rebalance Job is not run
+	if swift.IsDryRun(instance) {
+		return r.reconcileDryRun(ctx, instance, helper, ls)
+	}
+	instance.Status.DryRunDiff = nil
+
 	// Create a Secret populated with content from templates/
 	envVars := make(map[string]env.Setter)
 	tpl := getRingSecretTemplates(instance, ls)
@@ -127,6 +146,12 @@ func (r *SwiftRingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Restore the rings and builder files from the backup if the ConfigMap
+	// was lost
+	if _, err := swift.RestoreRings(ctx, helper, instance, ls); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Create a ConfigMap for the Swift rings
 	tpl = getRingTemplates(instance, ls)
 	err = configmap.EnsureConfigMaps(ctx, helper, instance, tpl, &envVars)
@@ -140,26 +165,90 @@ func (r *SwiftRingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 	ringCreateHash := instance.Status.Hash[swiftv1beta1.RingCreateHash]
 
+	// Increase the part power of the object rings, device list changes are
+	// applied once the increase finished
+	ctrlResult, err := r.reconcilePartPower(ctx, instance, helper, ls)
+	if err != nil {
+		return ctrlResult, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	// Replace the failed devices, they are removed from the rings until
+	// their new PVC is bound
+	failedDevicesPending, err := r.reconcileFailedDevices(ctx, instance, helper)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Check if the device list ConfigMap did change and if so, delete the
 	// rebalance Job. This will result in a new Job that rebalances with
 	// the updated device list
-	_, deviceListHash, err := configmap.GetConfigMapAndHashWithName(ctx, helper, swiftv1beta1.DeviceConfigMapName, instance.Namespace)
+	deviceList, deviceListHash, err := configmap.GetConfigMapAndHashWithName(ctx, helper, swiftv1beta1.DeviceConfigMapName, instance.Namespace)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if instance.Status.Hash[swiftv1beta1.DeviceListHash] != deviceListHash {
-		if err := job.DeleteJob(ctx, helper, instance.Name+"-rebalance", instance.Namespace); err != nil {
+
+	// The rings are rebuilt as well if storage policies are added, device
+	// weights change or failed devices are removed or added back
+	storagePoliciesHash := getStoragePoliciesHash(instance)
+	deviceWeightsHash := getDeviceWeightsHash(instance)
+	failedDevicesHash := getFailedDevicesHash(instance)
+
+	// Build the rings in-process instead of running the rebalance Job
+	if instance.Spec.RingBuilder == swiftv1beta1.RingBuilderNative {
+		ctrlResult, err := r.reconcileNativeRings(ctx, instance, helper, deviceList, deviceListHash, storagePoliciesHash, deviceWeightsHash, failedDevicesHash)
+		if err == nil && (ctrlResult == ctrl.Result{}) && failedDevicesPending {
+			return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+		}
+		return ctrlResult, err
+	}
+	if instance.Status.Hash[swiftv1beta1.DeviceListHash] != deviceListHash ||
+		instance.Status.Hash[swiftv1beta1.StoragePoliciesHash] != storagePoliciesHash ||
+		instance.Status.Hash[swiftv1beta1.DeviceWeightsHash] != deviceWeightsHash ||
+		instance.Status.Hash[swiftv1beta1.FailedDevicesHash] != failedDevicesHash {
+		// Wait for the previous Job to be gone, it would be taken for the
+		// rebalance of the new device list otherwise
+		previous, err := job.GetJobWithName(ctx, helper, instance.Name+"-rebalance", instance.Namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
 			return ctrl.Result{}, err
+		} else if err == nil {
+			if previous.DeletionTimestamp.IsZero() {
+				if err := job.DeleteJob(ctx, helper, previous.Name, instance.Namespace); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			r.Log.Info(fmt.Sprintf("Device list of SwiftRing '%s' changed, waiting for the previous rebalance Job to be deleted", instance.Name))
+			return ctrl.Result{RequeueAfter: time.Duration(5) * time.Second}, nil
 		}
+		r.Log.Info(fmt.Sprintf("Device list of SwiftRing '%s' changed, rebalancing the rings", instance.Name))
 		instance.Status.Hash[swiftv1beta1.RingCreateHash] = ""
 		instance.Status.Hash[swiftv1beta1.DeviceListHash] = deviceListHash
+		instance.Status.Hash[swiftv1beta1.StoragePoliciesHash] = storagePoliciesHash
+		instance.Status.Hash[swiftv1beta1.DeviceWeightsHash] = deviceWeightsHash
+		instance.Status.Hash[swiftv1beta1.FailedDevicesHash] = failedDevicesHash
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The Job encrypts the builder files before storing them, the keys must
+	// be available before it runs
+	authURL, err := r.checkRingKeys(ctx, instance, helper)
+	if err != nil {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			condition.ReadyCondition,
+			condition.ErrorReason,
+			condition.SeverityWarning,
+			err.Error()))
 		if err := r.Status().Update(ctx, instance); err != nil {
 			return ctrl.Result{}, err
 		}
+		return ctrl.Result{}, err
 	}
 
-	ringCreateJob := job.NewJob(getRingJob(instance, ls), swiftv1beta1.RingCreateHash, false, 5*time.Second, ringCreateHash)
-	ctrlResult, err := ringCreateJob.DoJob(ctx, helper)
+	ringCreateJob := job.NewJob(getRingJob(instance, ls, deviceListHash, authURL), swiftv1beta1.RingCreateHash, false, 5*time.Second, ringCreateHash)
+	ctrlResult, err = ringCreateJob.DoJob(ctx, helper)
 	if (ctrlResult != ctrl.Result{}) {
 		instance.Status.Conditions.Set(condition.FalseCondition(
 			condition.ReadyCondition,
@@ -186,23 +275,688 @@ func (r *SwiftRingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if ringCreateJob.HasChanged() {
 		instance.Status.Hash[swiftv1beta1.RingCreateHash] = ringCreateJob.GetHash()
 		instance.Status.Hash[swiftv1beta1.DeviceListHash] = deviceListHash
+		instance.Status.Hash[swiftv1beta1.StoragePoliciesHash] = storagePoliciesHash
+		instance.Status.Hash[swiftv1beta1.DeviceWeightsHash] = deviceWeightsHash
+		instance.Status.Hash[swiftv1beta1.FailedDevicesHash] = failedDevicesHash
 		if err := r.Status().Update(ctx, instance); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	// Record the key the builder files are encrypted with
+	if err := r.reconcileRingEncryption(ctx, instance, helper); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Back up the rings and builder files after every rebalance
+	if err := swift.BackupRings(ctx, helper, instance, ls); err != nil {
+		return ctrl.Result{}, err
+	}
+	ctrlResult, err = r.reconcileBackupTarget(ctx, instance, helper, ls)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	// Record the ring change in the audit history
+	if err := swift.RecordRingAudit(ctx, helper, instance.Namespace, instance.Spec.RingBuilder, ls); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
 	instance.Status.Conditions.MarkTrue(swiftv1beta1.SwiftRingReadyCondition, condition.ReadyMessage)
+	instance.Status.ObservedGeneration = instance.Generation
 	if err := r.Status().Update(ctx, instance); err != nil {
 		return ctrl.Result{}, err
 	}
 	// Swift ring init job - end
 
+	r.Log.Info(fmt.Sprintf("Reconciled SwiftRing '%s' successfully", instance.Name))
+	if failedDevicesPending {
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileDryRun computes and reports the changes a reconcile would make to
+// the scripts Secret and the ring ConfigMap, and whether the rings would be
+// rebalanced, without applying them or running any Job
+func (r *SwiftRingReconciler) reconcileDryRun(ctx context.Context, instance *swiftv1beta1.SwiftRing, h *helper.Helper, labels map[string]string) (ctrl.Result, error) {
+	objs, err := swift.GetTemplateObjects(getRingSecretTemplates(instance, labels), true)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	cms, err := swift.GetTemplateObjects(getRingTemplates(instance, labels), false)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	objs = append(objs, cms...)
+
+	diff := []string{}
+	for _, obj := range objs {
+		d, err := swift.GetDryRunDiff(ctx, h, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		diff = append(diff, d...)
+	}
+
+	if instance.Spec.RingBuilder != swiftv1beta1.RingBuilderNative &&
+		instance.Status.PartPowerIncrease == nil &&
+		instance.Status.PartPower != 0 && getPartPower(instance) > instance.Status.PartPower {
+		diff = append(diff, fmt.Sprintf("rings: part power would be increased from %d to %d",
+			instance.Status.PartPower, instance.Status.PartPower+1))
+	}
+	for _, name := range instance.Spec.FailedDevices {
+		if _, ok := instance.Status.FailedDevices[name]; !ok {
+			diff = append(diff, fmt.Sprintf("rings: failed device %s would be removed", name))
+		}
+	}
+
+	_, deviceListHash, err := configmap.GetConfigMapAndHashWithName(ctx, h, swiftv1beta1.DeviceConfigMapName, instance.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	rebalance := instance.Status.Hash[swiftv1beta1.DeviceListHash] != deviceListHash ||
+		instance.Status.Hash[swiftv1beta1.StoragePoliciesHash] != getStoragePoliciesHash(instance) ||
+		instance.Status.Hash[swiftv1beta1.DeviceWeightsHash] != getDeviceWeightsHash(instance) ||
+		instance.Status.Hash[swiftv1beta1.FailedDevicesHash] != getFailedDevicesHash(instance)
+	if instance.Spec.RingBuilder == swiftv1beta1.RingBuilderNative {
+		if rebalance || instance.Status.Hash[swiftv1beta1.RingCreateHash] == "" {
+			diff = append(diff, "rings: would be rebuilt by the Native ring builder")
+		}
+	} else {
+		authURL, err := r.checkRingKeys(ctx, instance, h)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		jobHash, err := util.ObjectHash(getRingJob(instance, labels, deviceListHash, authURL).Spec.Template)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if rebalance || instance.Status.Hash[swiftv1beta1.RingCreateHash] != jobHash {
+			diff = append(diff, fmt.Sprintf("Job %s-rebalance: would rebalance the rings", instance.Name))
+		}
+	}
+
+	for _, d := range diff {
+		r.Log.Info(fmt.Sprintf("Dry-run SwiftRing '%s' - %s", instance.Name, d))
+	}
+	instance.Status.DryRunDiff = diff
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileNativeRings builds the rings with the in-process ring builder
+// whenever the device list changes and stores them in the ring ConfigMap
+func (r *SwiftRingReconciler) reconcileNativeRings(ctx context.Context, instance *swiftv1beta1.SwiftRing, h *helper.Helper, deviceList *corev1.ConfigMap, deviceListHash string, storagePoliciesHash string, deviceWeightsHash string, failedDevicesHash string) (ctrl.Result, error) {
+	if instance.Status.Hash[swiftv1beta1.DeviceListHash] != deviceListHash || instance.Status.Hash[swiftv1beta1.RingCreateHash] == "" ||
+		instance.Status.Hash[swiftv1beta1.StoragePoliciesHash] != storagePoliciesHash ||
+		instance.Status.Hash[swiftv1beta1.DeviceWeightsHash] != deviceWeightsHash ||
+		instance.Status.Hash[swiftv1beta1.FailedDevicesHash] != failedDevicesHash {
+		devices, err := swift.ParseDeviceList(deviceList.Data["devices.csv"])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		devices = swift.RemoveFailedDevices(devices, getRemovedDevices(instance))
+		swift.ApplyDeviceWeights(devices, instance.Spec.DeviceWeights)
+		if len(devices) == 0 {
+			r.Log.Info(fmt.Sprintf("Waiting for the storage devices of SwiftRing '%s'", instance.Name))
+			return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+		}
+
+		cm := &corev1.ConfigMap{}
+		err = h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: instance.Namespace}, cm)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		rings, err := swift.BuildRings(devices, int(instance.Spec.RingReplicas), int(instance.Status.PartPower), instance.Spec.StoragePolicies, cm.BinaryData["swiftrings.tar.gz"])
+		if err != nil {
+			instance.Status.Conditions.Set(condition.FalseCondition(
+				condition.ReadyCondition,
+				condition.ErrorReason,
+				condition.SeverityWarning,
+				err.Error()))
+			if err := r.Status().Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, err
+		}
+
+		if cm.BinaryData == nil {
+			cm.BinaryData = map[string][]byte{}
+		}
+		cm.BinaryData["swiftrings.tar.gz"] = rings
+		if err := h.GetClient().Update(ctx, cm); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		instance.Status.Hash[swiftv1beta1.RingCreateHash] = fmt.Sprintf("%x", md5.Sum(rings))
+		instance.Status.Hash[swiftv1beta1.DeviceListHash] = deviceListHash
+		instance.Status.Hash[swiftv1beta1.StoragePoliciesHash] = storagePoliciesHash
+		instance.Status.Hash[swiftv1beta1.DeviceWeightsHash] = deviceWeightsHash
+		instance.Status.Hash[swiftv1beta1.FailedDevicesHash] = failedDevicesHash
+		r.Log.Info(fmt.Sprintf("Built the rings of SwiftRing '%s' with %d devices", instance.Name, len(devices)))
+	}
+
+	// Record the key of the builder files of a previous rebalance Job
+	if err := r.reconcileRingEncryption(ctx, instance, h); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Back up the rings and builder files after every rebalance
+	if err := swift.BackupRings(ctx, h, instance, swift.GetLabelsRing()); err != nil {
+		return ctrl.Result{}, err
+	}
+	ctrlResult, err := r.reconcileBackupTarget(ctx, instance, h, swift.GetLabelsRing())
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, nil
+	}
+
+	// Record the ring change in the audit history
+	if err := swift.RecordRingAudit(ctx, h, instance.Namespace, instance.Spec.RingBuilder, swift.GetLabelsRing()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+	instance.Status.Conditions.MarkTrue(swiftv1beta1.SwiftRingReadyCondition, condition.ReadyMessage)
+	instance.Status.ObservedGeneration = instance.Generation
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	r.Log.Info(fmt.Sprintf("Reconciled SwiftRing '%s' successfully", instance.Name))
 	return ctrl.Result{}, nil
 }
 
-func getRingJob(instance *swiftv1beta1.SwiftRing, labels map[string]string) *batchv1.Job {
+// checkRingKeys checks that the active key is in the key Secret. With
+// Barbican keys it returns the Keystone URL the rebalance Job fetches them
+// with instead.
+func (r *SwiftRingReconciler) checkRingKeys(ctx context.Context, instance *swiftv1beta1.SwiftRing, h *helper.Helper) (string, error) {
+	encryption := instance.Spec.Encryption
+	if encryption == nil {
+		return "", nil
+	}
+
+	if encryption.Barbican != nil {
+		keystoneAPI, err := keystonev1.GetKeystoneAPI(ctx, h, instance.Namespace, map[string]string{})
+		if err != nil {
+			return "", err
+		}
+		return keystoneAPI.GetEndpoint(endpoint.EndpointInternal)
+	}
+
+	keySecret, _, err := secret.GetSecret(ctx, h, encryption.KeySecret, instance.Namespace)
+	if err != nil {
+		return "", err
+	}
+	for name, key := range keySecret.Data {
+		if len(key) != 32 {
+			return "", fmt.Errorf("key %s in Secret %s must be 32 bytes long", name, keySecret.Name)
+		}
+	}
+	if _, ok := keySecret.Data[encryption.ActiveKey]; !ok {
+		return "", fmt.Errorf("active key %s not found in Secret %s", encryption.ActiveKey, keySecret.Name)
+	}
+	return "", nil
+}
+
+// reconcileRingEncryption records the key encryption key of the builder
+// files the rebalance Job stored in the ring ConfigMap
+func (r *SwiftRingReconciler) reconcileRingEncryption(ctx context.Context, instance *swiftv1beta1.SwiftRing, h *helper.Helper) error {
+	if instance.Spec.Encryption == nil {
+		instance.Status.EncryptionKey = ""
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: instance.Namespace}, cm)
+	if err != nil {
+		return err
+	}
+	blob := cm.BinaryData[swift.RingBuildersKey]
+	if len(blob) == 0 {
+		instance.Status.EncryptionKey = ""
+		return nil
+	}
+	keyID, err := swift.GetBuildersKeyID(blob)
+	if err != nil {
+		return err
+	}
+	instance.Status.EncryptionKey = keyID
+	return nil
+}
+
+// getStoragePoliciesHash returns the hash of the storage policy rings, empty
+// without storage policies
+func getStoragePoliciesHash(instance *swiftv1beta1.SwiftRing) string {
+	policies := swift.GetStoragePoliciesEnv(instance.Spec.StoragePolicies)
+	if policies == "" {
+		return ""
+	}
+	return fmt.Sprintf("%x", md5.Sum([]byte(policies)))
+}
+
+// getPartPower returns the part power of the object rings set in the spec
+func getPartPower(instance *swiftv1beta1.SwiftRing) int32 {
+	return swift.GetPartPower(&instance.Spec)
+}
+
+// setPartPowerNotApplied sets the PartPowerNotApplied condition with the
+// reason the partPower of the spec is not applied
+func setPartPowerNotApplied(instance *swiftv1beta1.SwiftRing, reason string) {
+	instance.Status.Conditions.Set(condition.TrueCondition(
+		swiftv1beta1.PartPowerNotAppliedCondition,
+		fmt.Sprintf(swiftv1beta1.PartPowerNotAppliedMessage, getPartPower(instance), instance.Status.PartPower, reason)))
+}
+
+// partPowerActions are the swift-ring-builder commands of the phases of a
+// part power increase run by the ring Job
+var partPowerActions = map[string]string{
+	swiftv1beta1.PartPowerPhasePrepare:  "prepare_increase_partition_power",
+	swiftv1beta1.PartPowerPhaseIncrease: "increase_partition_power",
+	swiftv1beta1.PartPowerPhaseFinish:   "finish_increase_partition_power",
+}
+
+// partPowerNextPhase is the phase following each phase of a part power
+// increase, the increase is done after Finish
+var partPowerNextPhase = map[string]string{
+	swiftv1beta1.PartPowerPhasePrepare:  swiftv1beta1.PartPowerPhaseRelink,
+	swiftv1beta1.PartPowerPhaseRelink:   swiftv1beta1.PartPowerPhaseIncrease,
+	swiftv1beta1.PartPowerPhaseIncrease: swiftv1beta1.PartPowerPhaseCleanup,
+	swiftv1beta1.PartPowerPhaseCleanup:  swiftv1beta1.PartPowerPhaseFinish,
+}
+
+// reconcilePartPower increases the part power of the object rings one step
+// at a time up to the one of the spec. The rings are prepared and
+// distributed, the objects of every storage pod are relinked, the rings are
+// switched and distributed, the old links are cleaned up and the increase is
+// finished. Returns a non-empty result while an increase is in progress.
+// The Native ring builder keeps the part power of the rings, a different
+// partPower is reported in the PartPowerNotApplied condition.
+func (r *SwiftRingReconciler) reconcilePartPower(ctx context.Context, instance *swiftv1beta1.SwiftRing, h *helper.Helper, labels map[string]string) (ctrl.Result, error) {
+	// Rings built before the part power was configurable use the default
+	if instance.Status.PartPower == 0 {
+		cm := &corev1.ConfigMap{}
+		err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: instance.Namespace}, cm)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if _, ok := cm.BinaryData["swiftrings.tar.gz"]; ok {
+			instance.Status.PartPower = swift.RingPartPower
+		} else {
+			instance.Status.PartPower = getPartPower(instance)
+		}
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	increase := instance.Status.PartPowerIncrease
+	if instance.Spec.RingBuilder == swiftv1beta1.RingBuilderNative {
+		if increase != nil {
+			setPartPowerNotApplied(instance, "the part power increase in progress requires the Job ring builder")
+		} else if getPartPower(instance) != instance.Status.PartPower {
+			setPartPowerNotApplied(instance, "the Native ring builder can not change the part power")
+		} else {
+			instance.Status.Conditions.Remove(swiftv1beta1.PartPowerNotAppliedCondition)
+		}
+		return ctrl.Result{}, nil
+	}
+	if increase == nil {
+		if getPartPower(instance) < instance.Status.PartPower {
+			r.Log.Info(fmt.Sprintf("Part power of SwiftRing '%s' can not be decreased from %d to %d",
+				instance.Name, instance.Status.PartPower, getPartPower(instance)))
+			setPartPowerNotApplied(instance, "the part power can not be decreased")
+		} else {
+			instance.Status.Conditions.Remove(swiftv1beta1.PartPowerNotAppliedCondition)
+		}
+		if getPartPower(instance) <= instance.Status.PartPower {
+			return ctrl.Result{}, nil
+		}
+		increase = &swiftv1beta1.SwiftRingPartPowerIncrease{
+			PartPower: instance.Status.PartPower + 1,
+			Phase:     swiftv1beta1.PartPowerPhasePrepare,
+		}
+		instance.Status.PartPowerIncrease = increase
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Log.Info(fmt.Sprintf("Increasing the part power of SwiftRing '%s' to %d", instance.Name, increase.PartPower))
+	}
+
+	switch increase.Phase {
+	case swiftv1beta1.PartPowerPhaseRelink, swiftv1beta1.PartPowerPhaseCleanup:
+		podList, err := pod.GetPodListWithLabel(ctx, h, instance.Namespace, swift.GetLabelsStorage())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		completed := map[string]bool{}
+		for _, name := range increase.CompletedPods {
+			completed[name] = true
+		}
+		pending := false
+		for i := range podList.Items {
+			p := &podList.Items[i]
+			if completed[p.Name] {
+				continue
+			}
+			if p.Spec.NodeName == "" {
+				pending = true
+				continue
+			}
+			relinkerJob, err := getRelinkerJob(instance, p, labels, increase)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			ctrlResult, err := job.NewJob(relinkerJob, "relinker", false, 5*time.Second, "").DoJob(ctx, h)
+			if err != nil {
+				return ctrl.Result{}, err
+			} else if (ctrlResult != ctrl.Result{}) {
+				pending = true
+				continue
+			}
+			increase.CompletedPods = append(increase.CompletedPods, p.Name)
+			if err := r.Status().Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if pending {
+			r.Log.Info(fmt.Sprintf("Part power increase of SwiftRing '%s': %s of %d of %d storage pods completed",
+				instance.Name, strings.ToLower(increase.Phase), len(increase.CompletedPods), len(podList.Items)))
+			return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+		}
+	default:
+		authURL, err := r.checkRingKeys(ctx, instance, h)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		ringJob := getRingJob(instance, labels, instance.Status.Hash[swiftv1beta1.DeviceListHash], authURL)
+		ringJob.Name = fmt.Sprintf("%s-part-power-%d-%s", instance.Name, increase.PartPower, strings.ToLower(increase.Phase))
+		ringJob.Spec.Template.Spec.Containers[0].Env = append(ringJob.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{Name: "PART_POWER_ACTION", Value: partPowerActions[increase.Phase]})
+		ctrlResult, err := job.NewJob(ringJob, "partpower", false, 5*time.Second, "").DoJob(ctx, h)
+		if err != nil || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+
+		// The objects are relinked or cleaned up once all storage pods use
+		// the new rings
+		if increase.Phase != swiftv1beta1.PartPowerPhaseFinish {
+			cm := &corev1.ConfigMap{}
+			err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: instance.Namespace}, cm)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			podList, err := pod.GetPodListWithLabel(ctx, h, instance.Namespace, swift.GetLabelsStorage())
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			ringSync, err := swift.GetRingSyncStatus(ctx, h, instance.Namespace, swift.GetLabelsStorage())
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !swift.IsRingSynced(ringSync, swift.GetRingMd5(cm), len(podList.Items)) {
+				r.Log.Info(fmt.Sprintf("Part power increase of SwiftRing '%s': waiting for the storage pods to sync the rings", instance.Name))
+				return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+			}
+		}
+	}
+
+	if increase.Phase == swiftv1beta1.PartPowerPhaseFinish {
+		instance.Status.PartPower = increase.PartPower
+		instance.Status.PartPowerIncrease = nil
+		r.Log.Info(fmt.Sprintf("Increased the part power of SwiftRing '%s' to %d", instance.Name, increase.PartPower))
+	} else {
+		increase.Phase = partPowerNextPhase[increase.Phase]
+		increase.CompletedPods = nil
+	}
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+}
+
+// getRelinkerJob returns the Job relinking or cleaning up the objects of a
+// storage pod. It runs next to the pod with its volumes and config, the
+// device claims can be mounted by both as they are on the same node.
+func getRelinkerJob(instance *swiftv1beta1.SwiftRing, p *corev1.Pod, labels map[string]string, increase *swiftv1beta1.SwiftRingPartPowerIncrease) (*batchv1.Job, error) {
+	action := "relink"
+	if increase.Phase == swiftv1beta1.PartPowerPhaseCleanup {
+		action = "cleanup"
+	}
+
+	// The service account token is mounted again in the Job pod
+	isTokenVolume := func(name string) bool {
+		return strings.HasPrefix(name, "kube-api-access-")
+	}
+	getVolumeMounts := func(c corev1.Container) []corev1.VolumeMount {
+		mounts := []corev1.VolumeMount{}
+		for _, m := range c.VolumeMounts {
+			if !isTokenVolume(m.Name) {
+				mounts = append(mounts, m)
+			}
+		}
+		return mounts
+	}
+
+	volumes := []corev1.Volume{}
+	for _, v := range p.Spec.Volumes {
+		if !isTokenVolume(v.Name) {
+			volumes = append(volumes, v)
+		}
+	}
+	// The init containers render the config of the storage pod
+	initContainers := []corev1.Container{}
+	for _, c := range p.Spec.InitContainers {
+		c.VolumeMounts = getVolumeMounts(c)
+		for i := range c.Env {
+			if c.Env[i].Name == "POD_NAME" {
+				c.Env[i] = corev1.EnvVar{Name: "POD_NAME", Value: p.Name}
+			}
+		}
+		initContainers = append(initContainers, c)
+	}
+	var objectServer *corev1.Container
+	for i := range p.Spec.Containers {
+		if p.Spec.Containers[i].Name == "object-server" {
+			objectServer = &p.Spec.Containers[i]
+		}
+	}
+	if objectServer == nil {
+		return nil, fmt.Errorf("storage pod %s has no object-server container", p.Name)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-%d-%s", instance.Name, action, increase.PartPower, p.Name),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      "OnFailure",
+					ServiceAccountName: p.Spec.ServiceAccountName,
+					SecurityContext:    p.Spec.SecurityContext,
+					NodeName:           p.Spec.NodeName,
+					Tolerations:        p.Spec.Tolerations,
+					Volumes:            volumes,
+					InitContainers:     initContainers,
+					Containers: []corev1.Container{
+						{
+							Name:            "object-" + action,
+							Command:         []string{"/usr/bin/swift-object-relinker", action, "/etc/swift/object-server.conf"},
+							Image:           objectServer.Image,
+							SecurityContext: objectServer.SecurityContext,
+							VolumeMounts:    getVolumeMounts(*objectServer),
+							Env:             objectServer.Env,
+
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// getDeviceWeightsHash returns the hash of the device weights, empty without
+// any so the rings of existing SwiftRings are not rebuilt
+func getDeviceWeightsHash(instance *swiftv1beta1.SwiftRing) string {
+	weights := swift.GetDeviceWeightsEnv(instance.Spec.DeviceWeights)
+	if weights == "" {
+		return ""
+	}
+	return fmt.Sprintf("%x", md5.Sum([]byte(weights)))
+}
+
+// getRemovedDevices returns the failed devices removed from the rings, the
+// replaced ones are added back
+func getRemovedDevices(instance *swiftv1beta1.SwiftRing) []string {
+	removed := []string{}
+	for _, name := range instance.Spec.FailedDevices {
+		if instance.Status.FailedDevices[name].Phase != swiftv1beta1.FailedDevicePhaseReplaced {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// getFailedDevicesHash returns the hash of the devices removed from the
+// rings, empty without any so the rings of existing SwiftRings are not
+// rebuilt
+func getFailedDevicesHash(instance *swiftv1beta1.SwiftRing) string {
+	removed := swift.GetFailedDevicesEnv(getRemovedDevices(instance))
+	if removed == "" {
+		return ""
+	}
+	return fmt.Sprintf("%x", md5.Sum([]byte(removed)))
+}
+
+// reconcileFailedDevices advances the replacement of the failed devices and
+// returns true while any is in progress. A failed device is removed from the
+// rings first, then its PVC and pod are deleted so the StatefulSet creates a
+// new PVC. The device is added back to the rings once the new PVC is bound.
+func (r *SwiftRingReconciler) reconcileFailedDevices(ctx context.Context, instance *swiftv1beta1.SwiftRing, h *helper.Helper) (bool, error) {
+	if len(instance.Spec.FailedDevices) == 0 && len(instance.Status.FailedDevices) == 0 {
+		return false, nil
+	}
+
+	ringCM := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: instance.Namespace}, ringCM)
+	if err != nil {
+		return false, err
+	}
+	_, hasRings := ringCM.BinaryData["swiftrings.tar.gz"]
+
+	// Devices no longer failed are forgotten
+	failed := map[string]swiftv1beta1.SwiftRingFailedDevice{}
+	pending := false
+	for _, name := range instance.Spec.FailedDevices {
+		state, ok := instance.Status.FailedDevices[name]
+		if !ok {
+			r.Log.Info(fmt.Sprintf("Removing failed device %s from the rings of SwiftRing '%s'", name, instance.Name))
+			state = swiftv1beta1.SwiftRingFailedDevice{Phase: swiftv1beta1.FailedDevicePhaseRemoving}
+		}
+
+		switch state.Phase {
+		case swiftv1beta1.FailedDevicePhaseRemoving:
+			pending = true
+			if !hasRings {
+				break
+			}
+			host, device, _ := strings.Cut(name, "/")
+			inRings, err := swift.RingsHaveDevice(ringCM, host, device)
+			if err != nil {
+				return false, err
+			}
+			if inRings {
+				break
+			}
+			claim, podName, err := swift.GetFailedDeviceClaim(ctx, h, instance.Namespace, name)
+			if err != nil {
+				return false, err
+			}
+			if claim != nil {
+				state.ClaimUID = string(claim.UID)
+				if err := h.GetClient().Delete(ctx, claim); err != nil && !apierrors.IsNotFound(err) {
+					return false, err
+				}
+			}
+			// The PVC is only deleted once the pod is gone, the StatefulSet
+			// creates a new one with the new pod
+			p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: instance.Namespace}}
+			if err := h.GetClient().Delete(ctx, p); err != nil && !apierrors.IsNotFound(err) {
+				return false, err
+			}
+			r.Log.Info(fmt.Sprintf("Removed failed device %s from the rings, replacing its PVC", name))
+			state.Phase = swiftv1beta1.FailedDevicePhaseReplacing
+		case swiftv1beta1.FailedDevicePhaseReplacing:
+			pending = true
+			claim, podName, err := swift.GetFailedDeviceClaim(ctx, h, instance.Namespace, name)
+			if err != nil {
+				return false, err
+			}
+			// The new pod can be created before the old PVC is gone, it then
+			// stays Pending on the terminating or missing claim. Deleting it
+			// again lets the StatefulSet create the new PVC.
+			if claim == nil || (string(claim.UID) == state.ClaimUID && claim.DeletionTimestamp != nil) {
+				if err := r.deletePendingPod(ctx, h, instance.Namespace, podName); err != nil {
+					return false, err
+				}
+				break
+			}
+			if string(claim.UID) == state.ClaimUID || claim.Status.Phase != corev1.ClaimBound {
+				break
+			}
+			r.Log.Info(fmt.Sprintf("Replaced the PVC of failed device %s, adding it back to the rings", name))
+			state.Phase = swiftv1beta1.FailedDevicePhaseReplaced
+		}
+		failed[name] = state
+	}
+
+	if len(failed) == 0 {
+		failed = nil
+	}
+	if !reflect.DeepEqual(failed, instance.Status.FailedDevices) {
+		instance.Status.FailedDevices = failed
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return false, err
+		}
+	}
+	return pending, nil
+}
+
+// deletePendingPod deletes the pod if it exists and is still Pending
+func (r *SwiftRingReconciler) deletePendingPod(ctx context.Context, h *helper.Helper, namespace string, name string) error {
+	p := &corev1.Pod{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, p)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if p.Status.Phase != corev1.PodPending || p.DeletionTimestamp != nil {
+		return nil
+	}
+	r.Log.Info(fmt.Sprintf("Deleting pod %s pending on the replaced PVC", name))
+	if err := h.GetClient().Delete(ctx, p); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// getRingJob returns the rebalance Job. The device list hash is part of the
+// pod template, every change of the devices results in a new Job. The Job
+// fetches Barbican keys from the Keystone catalog of authURL.
+func getRingJob(instance *swiftv1beta1.SwiftRing, labels map[string]string, deviceListHash string, authURL string) *batchv1.Job {
 	securityContext := swift.GetSecurityContext()
 
 	envVars := map[string]env.Setter{}
@@ -213,6 +967,52 @@ func getRingJob(instance *swiftv1beta1.SwiftRing, labels map[string]string) *bat
 	envVars["OWNER_KIND"] = env.SetValue(instance.Kind)
 	envVars["OWNER_UID"] = env.SetValue(string(instance.ObjectMeta.UID))
 	envVars["OWNER_NAME"] = env.SetValue(instance.ObjectMeta.Name)
+	envVars["DEVICE_LIST_HASH"] = env.SetValue(deviceListHash)
+	envVars["STORAGE_POLICIES"] = env.SetValue(swift.GetStoragePoliciesEnv(instance.Spec.StoragePolicies))
+	envVars["DEVICE_WEIGHTS"] = env.SetValue(swift.GetDeviceWeightsEnv(instance.Spec.DeviceWeights))
+	envVars["FAILED_DEVICES"] = env.SetValue(swift.GetFailedDevicesEnv(getRemovedDevices(instance)))
+	if instance.Status.PartPower > 0 && instance.Status.PartPower != swift.RingPartPower {
+		envVars["PART_POWER"] = env.SetValue(fmt.Sprint(instance.Status.PartPower))
+	}
+
+	volumes := getRingVolumes(instance)
+	volumeMounts := getRingVolumeMounts()
+	extraEnvs := []corev1.EnvVar{}
+	if encryption := instance.Spec.Encryption; encryption != nil {
+		envVars["ACTIVE_KEY"] = env.SetValue(encryption.ActiveKey)
+		keysVolume := corev1.Volume{
+			Name: "ring-keys",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: encryption.KeySecret,
+				},
+			},
+		}
+		if encryption.Barbican != nil {
+			// The keys fetched from Barbican are only kept in memory
+			keysVolume.VolumeSource = corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+			}
+			envVars["BARBICAN_KEYS"] = env.SetValue(swift.GetBarbicanKeysEnv(encryption.Barbican.Keys))
+			envVars["OS_AUTH_URL"] = env.SetValue(authURL)
+			envVars["OS_USERNAME"] = env.SetValue(encryption.Barbican.ServiceUser)
+			extraEnvs = append(extraEnvs, corev1.EnvVar{
+				Name: "OS_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: encryption.Barbican.Secret},
+						Key:                  encryption.Barbican.PasswordSelector,
+					},
+				},
+			})
+		}
+		volumes = append(volumes, keysVolume)
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "ring-keys",
+			MountPath: "/var/lib/config-data/ring-keys",
+			ReadOnly:  encryption.Barbican == nil,
+		})
+	}
 
 	return &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -236,11 +1036,130 @@ func getRingJob(instance *swiftv1beta1.SwiftRing, labels map[string]string) *bat
 							Command:         []string{"/usr/local/bin/container-scripts/swift-ring-rebalance.sh"},
 							Image:           instance.Spec.ContainerImage,
 							SecurityContext: &securityContext,
-							VolumeMounts:    getRingVolumeMounts(),
+							VolumeMounts:    volumeMounts,
+							Env:             env.MergeEnvs(extraEnvs, envVars),
+
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// reconcileBackupTarget copies the rings to the S3-compatible backup target
+// once per ring version. The Job of the previous version is deleted first.
+func (r *SwiftRingReconciler) reconcileBackupTarget(ctx context.Context, instance *swiftv1beta1.SwiftRing, h *helper.Helper, labels map[string]string) (ctrl.Result, error) {
+	if instance.Spec.BackupTarget == nil {
+		return ctrl.Result{}, nil
+	}
+
+	ringCM := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: instance.Namespace}, ringCM)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if _, ok := ringCM.BinaryData["swiftrings.tar.gz"]; !ok {
+		return ctrl.Result{}, nil
+	}
+	md5 := swift.GetRingMd5(ringCM)
+
+	backupJob := getRingBackupJob(instance, labels, md5)
+	previous, err := job.GetJobWithName(ctx, h, backupJob.Name, instance.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	} else if err == nil && previous.Annotations[swift.RingMd5Annotation] != md5 {
+		if previous.DeletionTimestamp.IsZero() {
+			if err := job.DeleteJob(ctx, h, previous.Name, instance.Namespace); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: time.Duration(5) * time.Second}, nil
+	}
+
+	j := job.NewJob(backupJob, swiftv1beta1.RingBackupHash, false, 5*time.Second, instance.Status.Hash[swiftv1beta1.RingBackupHash])
+	ctrlResult, err := j.DoJob(ctx, h)
+	if err != nil || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+	if j.HasChanged() {
+		instance.Status.Hash[swiftv1beta1.RingBackupHash] = j.GetHash()
+		r.Log.Info(fmt.Sprintf("Backed up ring %s of SwiftRing '%s' to bucket %s", md5, instance.Name, instance.Spec.BackupTarget.Bucket))
+	}
+	return ctrl.Result{}, nil
+}
+
+// getRingBackupJob returns the Job copying the files of the ring ConfigMap
+// to the backup target. The credentials are requested with a projected
+// service account token for the audience of the STS.
+func getRingBackupJob(instance *swiftv1beta1.SwiftRing, labels map[string]string, md5 string) *batchv1.Job {
+	securityContext := swift.GetSecurityContext()
+	target := instance.Spec.BackupTarget
+	var tokenExpiration int64 = 3600
+
+	envVars := map[string]env.Setter{}
+	envVars["RING_MD5"] = env.SetValue(md5)
+	envVars["S3_ENDPOINT"] = env.SetValue(target.Endpoint)
+	envVars["S3_BUCKET"] = env.SetValue(target.Bucket)
+	envVars["S3_PREFIX"] = env.SetValue(target.Prefix)
+	envVars["STS_ENDPOINT"] = env.SetValue(target.STSEndpoint)
+	envVars["AWS_REGION"] = env.SetValue(target.Region)
+	envVars["AWS_ROLE_ARN"] = env.SetValue(target.RoleARN)
+	envVars["AWS_WEB_IDENTITY_TOKEN_FILE"] = env.SetValue("/var/run/secrets/backup-target/token")
+
+	volumes := getRingVolumes(instance)
+	volumes = append(volumes, corev1.Volume{
+		Name: "backup-token",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          target.Audience,
+						ExpirationSeconds: &tokenExpiration,
+						Path:              "token",
+					},
+				}},
+			},
+		},
+	})
+	volumeMounts := append(getRingVolumeMounts(), corev1.VolumeMount{
+		Name:      "backup-token",
+		MountPath: "/var/run/secrets/backup-target",
+		ReadOnly:  true,
+	})
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instance.Name + "-backup",
+			Namespace:   instance.Namespace,
+			Labels:      labels,
+			Annotations: map[string]string{swift.RingMd5Annotation: md5},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      "OnFailure",
+					ServiceAccountName: swift.ServiceAccount,
+					SecurityContext: &corev1.PodSecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            instance.Name + "-backup",
+							Command:         []string{"/usr/local/bin/container-scripts/swift-ring-backup.sh"},
+							Image:           instance.Spec.ContainerImage,
+							SecurityContext: &securityContext,
+							VolumeMounts:    volumeMounts,
 							Env:             env.MergeEnvs([]corev1.EnvVar{}, envVars),
+
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
 					},
-					Volumes: getRingVolumes(instance),
+					Volumes: volumes,
 				},
 			},
 		},
@@ -259,14 +1178,7 @@ func getRingVolumes(instance *swiftv1beta1.SwiftRing) []corev1.Volume {
 				},
 			},
 		},
-		{
-			Name: "swiftconf",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: instance.Spec.SwiftConfSecret,
-				},
-			},
-		},
+		swift.GetSwiftConfVolume(instance.Spec.SwiftConfSecret, instance.Spec.SwiftConfSecretStore),
 		{
 			Name: "etc-swift",
 			VolumeSource: corev1.VolumeSource{
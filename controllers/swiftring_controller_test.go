@@ -0,0 +1,103 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/swift-operator/pkg/swift"
+)
+
+var _ = Describe("SwiftRing controller", func() {
+	Context("with a ring backup", func() {
+		var ring *swiftv1beta1.SwiftRing
+
+		BeforeEach(func() {
+			controller := true
+			ring = &swiftv1beta1.SwiftRing{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "swift-ring",
+					Namespace: "default",
+					UID:       "ring-uid",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: swiftv1beta1.GroupVersion.String(),
+						Kind:       "Swift",
+						Name:       "swift",
+						UID:        "swift-uid",
+						Controller: &controller,
+					}},
+				},
+			}
+		})
+
+		// restore restores the rings from a backup of the given owner and
+		// returns the client to look up the ring ConfigMap
+		restore := func(owner string) (bool, client.Client) {
+			backup := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        swiftv1beta1.RingBackupSecretName,
+					Namespace:   ring.Namespace,
+					Annotations: map[string]string{swiftv1beta1.RingBackupOwnerAnnotation: owner},
+				},
+				Data: map[string][]byte{"swiftrings.tar.gz": []byte("rings")},
+			}
+			c, s := newFakeClient(ring, backup)
+			h, err := helper.NewHelper(ring, c, kfake.NewSimpleClientset(), s, ctrl.Log.WithName("controllers").WithName("SwiftRing"))
+			Expect(err).NotTo(HaveOccurred())
+			restored, err := swift.RestoreRings(context.TODO(), h, ring, swift.GetLabelsRing())
+			Expect(err).NotTo(HaveOccurred())
+			return restored, c
+		}
+
+		It("restores the rings backed up for the same Swift", func() {
+			restored, c := restore("swift-uid")
+			Expect(restored).To(BeTrue())
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(context.TODO(), types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: ring.Namespace}, cm)).To(Succeed())
+			Expect(cm.BinaryData).To(HaveKeyWithValue("swiftrings.tar.gz", []byte("rings")))
+		})
+
+		It("does not restore the rings of another Swift or an unowned backup", func() {
+			for _, owner := range []string{"other-swift-uid", ""} {
+				restored, c := restore(owner)
+				Expect(restored).To(BeFalse())
+				err := c.Get(context.TODO(), types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: ring.Namespace}, &corev1.ConfigMap{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
+		It("uses the UID of a SwiftRing without a Swift", func() {
+			ring.OwnerReferences = nil
+			restored, _ := restore("ring-uid")
+			Expect(restored).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,164 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
+)
+
+// SwiftSecretGrantReconciler reconciles a SwiftSecretGrant object
+type SwiftSecretGrantReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+}
+
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftsecretgrants,verbs=get;list;watch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftsecretgrants/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile keeps the copies of the Secrets of the namespace of the grant in
+// sync with the Secrets. The copies are created by the controllers of the
+// resources referencing the Secrets, copies no longer granted by any
+// SwiftSecretGrant of the namespace are deleted.
+func (r *SwiftSecretGrantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("swiftsecretgrant", req.NamespacedName)
+
+	grants := &swiftv1beta1.SwiftSecretGrantList{}
+	if err := r.List(ctx, grants, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	copies := &corev1.SecretList{}
+	if err := r.List(ctx, copies, client.MatchingLabels{swift.GrantedSecretNamespaceLabel: req.Namespace}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	granted := map[string][]string{}
+	for i := range copies.Items {
+		c := &copies.Items[i]
+		_, secretName, _ := strings.Cut(c.Annotations[swift.GrantedSecretAnnotation], "/")
+		grantedBy := []string{}
+		for _, g := range grants.Items {
+			if g.DeletionTimestamp.IsZero() && g.Grants(c.Namespace, secretName) {
+				grantedBy = append(grantedBy, g.Name)
+			}
+		}
+		if len(grantedBy) == 0 {
+			r.Log.Info(fmt.Sprintf("Secret %s/%s is no longer granted to namespace %s, deleting its copy %s", req.Namespace, secretName, c.Namespace, c.Name))
+			if err := r.Delete(ctx, c); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		for _, g := range grantedBy {
+			granted[g] = append(granted[g], fmt.Sprintf("%s/%s", c.Namespace, c.Name))
+		}
+
+		// The copy is kept if the Secret is gone, the referencing resources
+		// still need it
+		src := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: req.Namespace}, src)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !reflect.DeepEqual(src.Data, c.Data) {
+			c.Data = src.Data
+			if err := r.Update(ctx, c); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Log.Info(fmt.Sprintf("Updated the copy %s/%s of Secret %s/%s", c.Namespace, c.Name, req.Namespace, secretName))
+		}
+	}
+
+	for i := range grants.Items {
+		g := &grants.Items[i]
+		if !g.DeletionTimestamp.IsZero() {
+			continue
+		}
+		sort.Strings(granted[g.Name])
+		namespaces := map[string]bool{}
+		for _, c := range granted[g.Name] {
+			namespace, _, _ := strings.Cut(c, "/")
+			namespaces[namespace] = true
+		}
+		if g.Status.Conditions == nil {
+			g.Status.Conditions = condition.Conditions{}
+		}
+		g.Status.Copies = granted[g.Name]
+		g.Status.Conditions.Set(condition.TrueCondition(
+			condition.ReadyCondition,
+			swiftv1beta1.SwiftSecretGrantReadyMessage,
+			len(namespaces)))
+		if err := r.Status().Update(ctx, g); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwiftSecretGrantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Changes of the Secrets of a grant and of their copies reconcile the
+	// grants of the namespace of the Secrets
+	secretFn := func(o client.Object) []reconcile.Request {
+		namespaces := []string{o.GetNamespace()}
+		if namespace, ok := o.GetLabels()[swift.GrantedSecretNamespaceLabel]; ok {
+			namespaces = append(namespaces, namespace)
+		}
+		result := []reconcile.Request{}
+		for _, namespace := range namespaces {
+			grants := &swiftv1beta1.SwiftSecretGrantList{}
+			if err := r.List(context.Background(), grants, client.InNamespace(namespace)); err != nil {
+				r.Log.Error(err, "Unable to list SwiftSecretGrants")
+				continue
+			}
+			for _, g := range grants.Items {
+				result = append(result, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: g.Name, Namespace: g.Namespace},
+				})
+			}
+		}
+		return result
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swiftv1beta1.SwiftSecretGrant{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(secretFn)).
+		Complete(r)
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-logr/logr"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,14 +31,24 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	affinity "github.com/openstack-k8s-operators/lib-common/modules/common/affinity"
+	daemonset "github.com/openstack-k8s-operators/lib-common/modules/common/daemonset"
+	deployment "github.com/openstack-k8s-operators/lib-common/modules/common/deployment"
 	helper "github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	pod "github.com/openstack-k8s-operators/lib-common/modules/common/pod"
 	service "github.com/openstack-k8s-operators/lib-common/modules/common/service"
 	statefulset "github.com/openstack-k8s-operators/lib-common/modules/common/statefulset"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -53,17 +66,31 @@ import (
 // SwiftStorageReconciler reconciles a SwiftStorage object
 type SwiftStorageReconciler struct {
 	client.Client
-	Scheme  *runtime.Scheme
-	Log     logr.Logger
-	Kclient kubernetes.Interface
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Kclient  kubernetes.Interface
+	Features swift.Features
+}
+
+// features returns the features enabled with the operator flags and not
+// disabled in the SwiftOperatorConfig
+func (r *SwiftStorageReconciler) features() swift.Features {
+	return r.Features.WithOperatorConfig()
 }
 
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftstorages,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftstorages/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftstorages/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods/ephemeralcontainers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=memcached.openstack.org,resources=memcacheds,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -122,29 +149,109 @@ func (r *SwiftStorageReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	ls := swift.GetLabelsStorage()
 
+	// Only report the changes to the sub-resources in dry-run mode
+	if swift.IsDryRun(instance) {
+		return r.reconcileDryRun(ctx, instance, helper, ls)
+	}
+	instance.Status.DryRunDiff = nil
+
+	// Report waits beyond the progress deadline, the reconcile below may
+	// return early while waiting
+	if err := r.reconcileStalled(ctx, instance, helper, ls); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The validating webhook rejects an invalid spec, it only gets here while
+	// the webhooks are disabled
+	if err := instance.Spec.Validate(); err != nil {
+		r.Log.Info(fmt.Sprintf("Invalid spec of SwiftStorage '%s': %s", instance.Name, err))
+		swift.SetInvalidSpecCondition(&instance.Status.Conditions, err,
+			condition.ReadyCondition, swiftv1beta1.SwiftStorageReadyCondition)
+		return ctrl.Result{}, r.Status().Update(ctx, instance)
+	}
+
+	// The memcache servers of a shared Memcached replace the memcached
+	// container of the pods
+	memcachedServers, err := swift.GetMemcachedServers(ctx, helper, instance.Namespace, instance.Spec.MemcachedInstance)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if memcachedServers == "" {
+		r.Log.Info(fmt.Sprintf("Waiting for Memcached %s to provide its servers", instance.Spec.MemcachedInstance))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
 	// Create a ConfigMap populated with content from templates/
 	envVars := make(map[string]env.Setter)
-	tpl := getStorageConfigMapTemplates(instance, ls)
+	tpl := getStorageConfigMapTemplates(instance, ls, memcachedServers)
 	err = configmap.EnsureConfigMaps(ctx, helper, instance, tpl, &envVars)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if err := r.deleteStaleConfigOverrides(ctx, instance, helper, ls); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The container sync realms include the keys, they are kept in a Secret
+	err = swift.EnsureContainerSyncSecret(ctx, helper, instance, instance.Kind, instance.Spec.ContainerSync, ls)
+	if apierrors.IsNotFound(err) {
+		r.Log.Info(fmt.Sprintf("Waiting for the container sync key Secrets of SwiftStorage '%s'", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Check if there is a ConfigMap for the Swift rings
-	_, ctrlResult, err := configmap.GetConfigMap(ctx, helper, instance, swiftv1beta1.RingConfigMapName, 5*time.Second)
+	ringConfigMap, ctrlResult, err := configmap.GetConfigMap(ctx, helper, instance, swiftv1beta1.RingConfigMapName, 5*time.Second)
 	if err != nil {
 		return ctrlResult, err
 	} else if (ctrlResult != ctrl.Result{}) {
 		return ctrlResult, nil
 	}
 
-	// Headless Service
-	svc := service.NewService(getStorageService(instance), ls, 5*time.Second)
-	ctrlResult, err = svc.CreateOrPatch(ctx, helper)
+	// Take over a Service and StatefulSet created by hand before the
+	// SwiftStorage, e.g. when migrating from manually deployed Swift
+	if err := r.adoptResources(ctx, instance, helper, ls); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Detect out-of-band changes to the Service and StatefulSet. These are
+	// reverted below unless the drift policy is Report, which leaves the
+	// changed object as is and reconciles everything else.
+	serviceDrift, ssetDrift, err := r.getDrift(ctx, instance, helper, ls)
 	if err != nil {
-		return ctrlResult, err
-	} else if (ctrlResult != ctrl.Result{}) {
-		return ctrlResult, nil
+		return ctrl.Result{}, err
+	}
+	drift := []string{}
+	for _, d := range []string{serviceDrift, ssetDrift} {
+		if d != "" {
+			drift = append(drift, d)
+		}
+	}
+	reportDrift := instance.Spec.DriftPolicy == swiftv1beta1.DriftPolicyReport
+	if reportDrift {
+		if len(drift) > 0 {
+			r.Log.Info(fmt.Sprintf("Drift detected on SwiftStorage '%s', not reverting: %s", instance.Name, strings.Join(drift, "; ")))
+		}
+		swift.SetDriftCondition(&instance.Status.Conditions, drift)
+	} else {
+		for _, d := range drift {
+			r.Log.Info(fmt.Sprintf("Reverting out-of-band change on SwiftStorage '%s': %s", instance.Name, d))
+		}
+		swift.SetDriftCondition(&instance.Status.Conditions, nil)
+	}
+
+	// Headless Service, it selects the pods by their tier label
+	if err := labelStorageTierPods(ctx, helper, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	if !reportDrift || serviceDrift == "" {
+		svc := service.NewService(getStorageService(instance), ls, 5*time.Second)
+		ctrlResult, err = svc.CreateOrPatch(ctx, helper)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
 	}
 
 	// Limit internal storage traffic to Swift services
@@ -156,57 +263,473 @@ func (r *SwiftStorageReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrlResult, nil
 	}
 
-	// Ensure the StatefulSet is not resized after initial deployment
-	found, err := statefulset.GetStatefulSetWithName(ctx, helper, instance.Name, instance.Namespace)
-	if err != nil && !apierrors.IsNotFound(err) {
-		return ctrlResult, err
-	} else if err == nil {
-		if *found.Spec.Replicas > instance.Spec.Replicas {
-			r.Log.Info(fmt.Sprintf(
-				"Downsizing (%d -> %d) number of replicas not supported",
-				*found.Spec.Replicas, instance.Spec.Replicas))
-			instance.Spec.Replicas = *found.Spec.Replicas
-			if err := r.Client.Update(ctx, instance); err != nil {
-				return ctrl.Result{}, err
-			}
+	// Certificate of the storage servers, unless provided in a Secret
+	if instance.Spec.TLS != nil && instance.Spec.TLS.Issuer != "" {
+		err = swift.CreateOrPatchStorageCertificate(ctx, helper, instance, ls)
+	} else {
+		err = swift.DeleteCertificate(ctx, helper, instance.Name, instance.Namespace)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// A certificate of another namespace is copied if it is granted
+	if instance.Spec.TLS != nil {
+		if err := swift.EnsureGrantedSecret(ctx, helper, instance.Spec.TLS.SecretName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The clients of the storage servers verify them with the CA of the
+	// certificate. They switch to TLS once the kubelet updated the CA in
+	// their pods, requests to servers not restarted yet fail over to the
+	// other replicas until the rollout finished.
+	caReady, err := swift.EnsureBackendCA(ctx, helper, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !caReady {
+		r.Log.Info(fmt.Sprintf("SwiftStorage '%s' waiting for the certificate Secret %s", instance.Name, swift.GetStorageTLSSecretName(instance)))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
+	// Pods removed by a scale down are kept until they are drained from
+	// the rings, pods of a scale up are added in batches
+	replicas, err := getStorageReplicas(ctx, helper, instance, ringConfigMap)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	swift.SetScaleUpCondition(&instance.Status.Conditions, instance, replicas)
+
+	// Statefulset with all backend containers, unless its rollout of this
+	// spec was rolled back or its drift is only reported
+	var sset *appsv1.StatefulSet
+	if instance.Status.RolledBackGeneration != instance.Generation && (!reportDrift || ssetDrift == "") {
+		desired, err := getStorageStatefulSet(instance, ls, replicas)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		ss := statefulset.NewStatefulSet(desired, 5*time.Second)
+		ctrlResult, err = ss.CreateOrPatch(ctx, helper)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+		s := ss.GetStatefulSet()
+		sset = &s
+	} else {
+		sset, err = statefulset.GetStatefulSetWithName(ctx, helper, instance.Name, instance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The account and container servers run in their own StatefulSet if
+	// the metadata tier is enabled
+	metadataReady := true
+	if instance.Spec.MetadataTier != nil {
+		metadataLabels := swift.GetLabelsStorageMetadata()
+		svc := service.NewService(getMetadataService(instance), metadataLabels, 5*time.Second)
+		ctrlResult, err = svc.CreateOrPatch(ctx, helper)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+		desired, err := getMetadataStatefulSet(instance, metadataLabels)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
+		ss := statefulset.NewStatefulSet(desired, 5*time.Second)
+		ctrlResult, err = ss.CreateOrPatch(ctx, helper)
+		if err != nil {
+			return ctrlResult, err
+		} else if (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, nil
+		}
+		metadataReady = ss.GetStatefulSet().Status.ReadyReplicas >= instance.Spec.MetadataTier.Replicas
 	}
 
-	// Statefulset with all backend containers
-	sset := statefulset.NewStatefulSet(getStorageStatefulSet(instance, ls), 5*time.Second)
-	ctrlResult, err = sset.CreateOrPatch(ctx, helper)
+	// The object expirer runs in its own StatefulSet, a single queue is
+	// shared by all storage pods. The pod ordinals select the parts of the
+	// expiration tasks if they are divided into processes.
+	if err := r.deleteExpirerDeployment(ctx, instance, helper); err != nil {
+		return ctrl.Result{}, err
+	}
+	expirerReplicas, err := getExpirerReplicas(ctx, helper, instance, r.features().KEDA)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	expirer := statefulset.NewStatefulSet(getExpirerStatefulSet(instance, swift.GetLabelsExpirer(), expirerReplicas), 5*time.Second)
+	ctrlResult, err = expirer.CreateOrPatch(ctx, helper)
 	if err != nil {
 		return ctrlResult, err
 	} else if (ctrlResult != ctrl.Result{}) {
 		return ctrlResult, nil
 	}
 
-	if sset.GetStatefulSet().Status.ReadyReplicas == instance.Spec.Replicas {
-		envVars := make(map[string]env.Setter)
-		devices, err := getDeviceList(ctx, helper, instance)
+	// Create or delete the KEDA ScaledObject of the object expirer
+	unavailable := map[string]string{}
+	expirerName := instance.Name + "-object-expirer"
+	if !r.features().KEDA {
+		if isExpirerAutoscaled(instance) {
+			unavailable[swift.FeatureKEDA] = swift.FeatureDisabled
+		}
+	} else if isExpirerAutoscaled(instance) {
+		err = swift.CreateOrPatchExpirerScaledObject(ctx, helper, expirerName, instance.Namespace,
+			swift.GetLabelsExpirer(), instance.Spec.Expirer.Autoscaling)
+		if swift.IsPermissionError(err) {
+			r.Log.Info(fmt.Sprintf("Not autoscaling the object expirer of SwiftStorage '%s': %s", instance.Name, err))
+			unavailable[swift.FeatureKEDA] = swift.FeatureForbidden
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if err := swift.DeleteScaledObject(ctx, helper, expirerName, instance.Namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	swift.SetPermissionsCondition(&instance.Status.Conditions, unavailable)
+
+	// Inventory the local disks of the storage nodes and offer the approved
+	// ones as SwiftDisks
+	if err := r.reconcileDiskDiscovery(ctx, instance, helper); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report rollouts stuck on crash-looping pods and roll them back if
+	// requested
+	if err := r.reconcileStuckRollout(ctx, instance, helper, sset); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report the revisions of the StatefulSet, so partial rollouts are
+	// visible in the status
+	instance.Status.Revisions, err = swift.GetStatefulSetRevisions(ctx, helper, sset)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Inject an ephemeral debug container if requested
+	if err := r.reconcileDebugContainer(ctx, instance, helper); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Resource recommendations for the storage pods
+	if instance.Spec.ResourceRecommendations {
+		err = swift.CreateOrPatchRecommendationVPA(ctx, helper, instance.Name, instance.Namespace, ls)
+		if err == nil {
+			instance.Status.ResourceRecommendations, err = swift.GetVPARecommendations(ctx, helper, instance.Name, instance.Namespace)
+		}
+		if swift.IsVPANotInstalled(err) {
+			r.Log.Info("VerticalPodAutoscaler CRD not installed, no resource recommendations available")
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		instance.Status.ResourceRecommendations = nil
+		if err := swift.DeleteVPA(ctx, helper, instance.Name, instance.Namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Cordoned pods are not required to be ready
+	if sset.Status.ReadyReplicas >= replicas-getCordonedCount(instance, replicas) && metadataReady {
+		devices, err := getDeviceList(ctx, helper, instance, replicas)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		devices, queued, err := r.reconcileDeviceList(ctx, instance, helper, ringConfigMap, devices, getScaleDownList(instance, replicas))
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if queued {
+			return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+		}
+
+		// Ready only once the rings were rebuilt with the device list
+		deviceList, err := swift.ParseDeviceList(devices)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		tpl = getDeviceConfigMapTemplates(instance, devices)
-		err = configmap.EnsureConfigMaps(ctx, helper, instance, tpl, &envVars)
+		diff, err := swift.GetRingDeviceDiff(ringConfigMap, deviceList)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
-		instance.Status.Conditions.MarkTrue(swiftv1beta1.SwiftStorageReadyCondition, condition.ReadyMessage)
+		swift.SetRingUpdatePendingCondition(&instance.Status.Conditions, diff)
+		if len(diff) == 0 {
+			swift.GetRingBuildQueue().Release(swift.GetRingBuildQueueKey(instance), instance.Name)
+			instance.Status.RingBuildQueuePosition = 0
+		}
+		if len(diff) > 0 {
+			for _, t := range []condition.Type{condition.ReadyCondition, swiftv1beta1.SwiftStorageReadyCondition} {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					t,
+					condition.RequestedReason,
+					condition.SeverityInfo,
+					swiftv1beta1.RingUpdatePendingMessage,
+					strings.Join(diff, ", ")))
+			}
+		} else {
+			instance.Status.Conditions.MarkTrue(condition.ReadyCondition, condition.ReadyMessage)
+			instance.Status.Conditions.MarkTrue(swiftv1beta1.SwiftStorageReadyCondition, condition.ReadyMessage)
+			instance.Status.ObservedGeneration = instance.Generation
+		}
 		if err := r.Status().Update(ctx, instance); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	// Report the ring version synced by each storage pod
+	instance.Status.RingSync, err = swift.GetRingSyncStatus(ctx, helper, instance.Namespace, ls)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report the crashed containers of the storage and expirer pods
+	instance.Status.Terminations, err = swift.GetContainerTerminations(ctx, helper, instance.Namespace, ls)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	expirerTerminations, err := swift.GetContainerTerminations(ctx, helper, instance.Namespace, swift.GetLabelsExpirer())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for c, t := range expirerTerminations {
+		instance.Status.Terminations[c] = t
+	}
+
+	// Compare the clocks of the storage pods to the API server
+	skewed, err := swift.GetClockSkewedPods(ctx, helper, instance.Namespace, ls, instance.Spec.ClockSkewThreshold)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	swift.SetClockSkewCondition(&instance.Status.Conditions, skewed, instance.Spec.ClockSkewThreshold)
+
+	// Periodic recon health check of the storage servers
+	if err := r.reconcileHealthCheck(ctx, instance, helper); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	syncedPods := replicas - getCordonedCount(instance, replicas)
+	if instance.Spec.MetadataTier != nil {
+		syncedPods += instance.Spec.MetadataTier.Replicas
+	}
+	if !swift.IsRingSynced(instance.Status.RingSync, swift.GetRingMd5(ringConfigMap), int(syncedPods)) {
+		r.Log.Info(fmt.Sprintf("Waiting for all SwiftStorage '%s' pods to sync the rings", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	if replicas > instance.Spec.Replicas {
+		return r.reconcileScaleDown(ctx, instance, helper, ringConfigMap, replicas, ls)
+	}
+	if err := r.deleteRemovedClaims(ctx, instance, helper); err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(skewed) > 0 {
+		r.Log.Info(fmt.Sprintf("Clock skew detected on SwiftStorage '%s' pods: %s", instance.Name, strings.Join(skewed, ", ")))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	if instance.Status.Conditions.Has(swiftv1beta1.RingUpdatePendingCondition) {
+		r.Log.Info(fmt.Sprintf("Waiting for the rings to include the devices of SwiftStorage '%s'", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+	if replicas < instance.Spec.Replicas {
+		r.Log.Info(fmt.Sprintf("Waiting for replication before adding more SwiftStorage '%s' pods", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+
+	// Report the drain of the pods of DrainOrdinals
+	drained, err := r.reconcileDrain(ctx, instance, helper, ringConfigMap, ls)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !drained {
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+
 	r.Log.Info(fmt.Sprintf("Reconciled SwiftStorage '%s' successfully", instance.Name))
+	if instance.Spec.Scrub != nil && instance.Spec.Scrub.Window != nil {
+		// Move the object auditors to the next scrub slot
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	if instance.Spec.MemcachedInstance != "" || instance.Spec.DiskDiscovery != nil {
+		// The Memcached and the discovery pods are not watched, pick up
+		// changes of the memcache servers and the discovered disks
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileStalled starts the progress deadline when the SwiftStorage is
+// not ready or its spec changed and sets the Stalled condition with a
+// diagnosis once it passed. The deadline is cleared when it is ready.
+func (r *SwiftStorageReconciler) reconcileStalled(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper, labels map[string]string) error {
+	if instance.Status.Conditions.IsTrue(condition.ReadyCondition) || instance.Spec.ProgressDeadlineSeconds == 0 {
+		if instance.Status.ProgressStartTime == nil && !instance.Status.Conditions.Has(swiftv1beta1.StalledCondition) {
+			return nil
+		}
+		instance.Status.ProgressStartTime = nil
+		instance.Status.Conditions.Remove(swiftv1beta1.StalledCondition)
+		return r.Status().Update(ctx, instance)
+	}
+
+	if instance.Status.ProgressStartTime == nil || instance.Status.ProgressGeneration != instance.Generation {
+		now := metav1.Now()
+		instance.Status.ProgressStartTime = &now
+		instance.Status.ProgressGeneration = instance.Generation
+		instance.Status.Conditions.Remove(swiftv1beta1.StalledCondition)
+		return r.Status().Update(ctx, instance)
+	}
+	deadline := time.Duration(instance.Spec.ProgressDeadlineSeconds) * time.Second
+	if time.Since(instance.Status.ProgressStartTime.Time) < deadline {
+		return nil
+	}
+
+	diagnosis, err := swift.GetStallDiagnosis(ctx, h, instance, labels)
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("SwiftStorage '%s' not ready after %d seconds: %s",
+		instance.Name, instance.Spec.ProgressDeadlineSeconds, strings.Join(diagnosis, "; ")))
+	swift.SetStalledCondition(&instance.Status.Conditions, instance.Spec.ProgressDeadlineSeconds, diagnosis)
+	return r.Status().Update(ctx, instance)
+}
+
+// adoptResources adopts a pre-existing storage Service and StatefulSet with
+// the expected names and labels
+func (r *SwiftStorageReconciler) adoptResources(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper, labels map[string]string) error {
+	svc, err := service.GetServiceWithName(ctx, h, instance.Name, instance.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		svc.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+		if err := swift.AdoptObject(ctx, h, svc, labels); err != nil {
+			return err
+		}
+	}
+
+	sset, err := statefulset.GetStatefulSetWithName(ctx, h, instance.Name, instance.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		// The selector is immutable, a StatefulSet selecting other pods can
+		// not be reconciled and needs to be recreated
+		if metav1.GetControllerOf(sset) == nil &&
+			(sset.Spec.Selector == nil || !equality.Semantic.DeepEqual(sset.Spec.Selector.MatchLabels, labels)) {
+			return fmt.Errorf("StatefulSet %s needs the selector %v to be adopted", sset.Name, labels)
+		}
+		sset.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+		if err := swift.AdoptObject(ctx, h, sset, labels); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getDrift returns the out-of-band changes to the fields of the storage
+// Service and StatefulSet set by the operator, based on their managed fields
+func (r *SwiftStorageReconciler) getDrift(
+	ctx context.Context,
+	instance *swiftv1beta1.SwiftStorage,
+	h *helper.Helper,
+	labels map[string]string,
+) (string, string, error) {
+	serviceDrift := ""
+	svc, err := service.GetServiceWithName(ctx, h, instance.Name, instance.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", "", err
+	} else if err == nil {
+		managers, err := swift.GetDriftManagers(svc, instance.UID, getStorageService(instance).Spec)
+		if err != nil {
+			return "", "", err
+		}
+		if len(managers) > 0 {
+			serviceDrift = swift.FormatDrift("Service", svc.Name, managers)
+		}
+	}
+
+	// Only the paths of the fields are compared, the replicas of the scale
+	// up and down do not matter
+	ssetDrift := ""
+	sset, err := statefulset.GetStatefulSetWithName(ctx, h, instance.Name, instance.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", "", err
+	} else if err == nil {
+		desired, err := getStorageStatefulSet(instance, labels, instance.Spec.Replicas)
+		if err != nil {
+			return "", "", err
+		}
+		managers, err := swift.GetDriftManagers(sset, instance.UID, desired.Spec)
+		if err != nil {
+			return "", "", err
+		}
+		if len(managers) > 0 {
+			ssetDrift = swift.FormatDrift("StatefulSet", sset.Name, managers)
+		}
+	}
+
+	return serviceDrift, ssetDrift, nil
+}
+
+// reconcileDryRun computes and reports the changes a reconcile would make to
+// the config ConfigMaps and Secret, the Service, NetworkPolicy and
+// StatefulSet without applying them
+func (r *SwiftStorageReconciler) reconcileDryRun(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper, labels map[string]string) (ctrl.Result, error) {
+	memcachedServers, err := swift.GetMemcachedServers(ctx, h, instance.Namespace, instance.Spec.MemcachedInstance)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if memcachedServers == "" {
+		r.Log.Info(fmt.Sprintf("Waiting for Memcached %s to provide its servers", instance.Spec.MemcachedInstance))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	}
+
+	objs, err := swift.GetTemplateObjects(getStorageConfigMapTemplates(instance, labels, memcachedServers), false)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	syncTpls, err := swift.GetContainerSyncTemplates(ctx, h, instance, instance.Kind, instance.Spec.ContainerSync, labels)
+	if apierrors.IsNotFound(err) {
+		r.Log.Info(fmt.Sprintf("Waiting for the container sync key Secrets of SwiftStorage '%s'", instance.Name))
+		return ctrl.Result{RequeueAfter: swift.RequeueInterval()}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+	secrets, err := swift.GetTemplateObjects(syncTpls, true)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	objs = append(objs, secrets...)
+
+	sset, err := getStorageStatefulSet(instance, labels, instance.Spec.Replicas)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	objs = append(objs, getStorageService(instance), getStorageNetworkPolicy(instance), sset)
+
+	diff := []string{}
+	for _, obj := range objs {
+		d, err := swift.GetDryRunDiff(ctx, h, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		diff = append(diff, d...)
+	}
+
+	for _, d := range diff {
+		r.Log.Info(fmt.Sprintf("Dry-run SwiftStorage '%s' - %s", instance.Name, d))
+	}
+	instance.Status.DryRunDiff = diff
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
-func getStorageConfigMapTemplates(instance *swiftv1beta1.SwiftStorage, labels map[string]string) []util.Template {
-	templateParameters := make(map[string]interface{})
+func getStorageConfigMapTemplates(instance *swiftv1beta1.SwiftStorage, labels map[string]string, memcachedServers string) []util.Template {
+	templateParameters := swift.GetStorageTemplateParameters(instance)
+	templateParameters["MemcachedServers"] = memcachedServers
 
-	return []util.Template{
+	templates := []util.Template{
 		{
 			Name:          fmt.Sprintf("%s-config-data", instance.Name),
 			Namespace:     instance.Namespace,
@@ -214,6 +737,7 @@ func getStorageConfigMapTemplates(instance *swiftv1beta1.SwiftStorage, labels ma
 			InstanceType:  instance.Kind,
 			Labels:        labels,
 			ConfigOptions: templateParameters,
+			CustomData:    swift.GetConfigOverwriteData(instance.Spec.DefaultConfigOverwrite),
 		},
 		{
 			Name:               fmt.Sprintf("%s-scripts", instance.Name),
@@ -221,14 +745,54 @@ func getStorageConfigMapTemplates(instance *swiftv1beta1.SwiftStorage, labels ma
 			Type:               util.TemplateTypeScripts,
 			InstanceType:       instance.Kind,
 			Labels:             labels,
-			AdditionalTemplate: map[string]string{"swift-init.sh": "/common/swift-init.sh", "ring-sync.sh": "/common/ring-sync.sh"},
+			AdditionalTemplate: map[string]string{"swift-init.sh": "/common/swift-init.sh", "ring-sync.sh": "/common/ring-sync.sh", "nofile-exec.sh": "/common/nofile-exec.sh"},
 		},
 	}
+	for ordinal, files := range instance.Spec.ConfigOverrides {
+		templates = append(templates, util.Template{
+			Name:         getConfigOverrideName(instance, ordinal),
+			Namespace:    instance.Namespace,
+			Type:         util.TemplateTypeNone,
+			InstanceType: instance.Kind,
+			Labels:       labels,
+			CustomData:   files,
+		})
+	}
+	return templates
+}
+
+func getConfigOverrideName(instance *swiftv1beta1.SwiftStorage, ordinal string) string {
+	return fmt.Sprintf("%s-config-override-%s", instance.Name, ordinal)
+}
+
+// deleteStaleConfigOverrides deletes the override ConfigMaps of pods removed
+// from the configOverrides
+func (r *SwiftStorageReconciler) deleteStaleConfigOverrides(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper, labels map[string]string) error {
+	cms := &corev1.ConfigMapList{}
+	err := h.GetClient().List(ctx, cms, client.InNamespace(instance.Namespace), client.MatchingLabels(labels))
+	if err != nil {
+		return err
+	}
+	prefix := getConfigOverrideName(instance, "")
+	for _, cm := range cms.Items {
+		ordinal := strings.TrimPrefix(cm.Name, prefix)
+		if ordinal == cm.Name || !metav1.IsControlledBy(&cm, instance) {
+			continue
+		}
+		if _, ok := instance.Spec.ConfigOverrides[ordinal]; ok {
+			continue
+		}
+		if err := h.GetClient().Delete(ctx, &cm); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		r.Log.Info(fmt.Sprintf("Deleted config override ConfigMap %s", cm.Name))
+	}
+	return nil
 }
 
 func getStorageVolumes(instance *swiftv1beta1.SwiftStorage) []corev1.Volume {
 	var scriptsVolumeDefaultMode int32 = 0755
-	return []corev1.Volume{
+	volumes := []corev1.Volume{
 		{
 			Name: swift.ClaimName,
 			VolumeSource: corev1.VolumeSource{
@@ -247,14 +811,7 @@ func getStorageVolumes(instance *swiftv1beta1.SwiftStorage) []corev1.Volume {
 				},
 			},
 		},
-		{
-			Name: "swiftconf",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: instance.Spec.SwiftConfSecret,
-				},
-			},
-		},
+		swift.GetSwiftConfVolume(instance.Spec.SwiftConfSecret, instance.Spec.SwiftConfSecretStore),
 		{
 			Name: "ring-data",
 			VolumeSource: corev1.VolumeSource{
@@ -289,25 +846,94 @@ func getStorageVolumes(instance *swiftv1beta1.SwiftStorage) []corev1.Volume {
 			},
 		},
 	}
-}
 
-func getStorageVolumeMounts() []corev1.VolumeMount {
-	return []corev1.VolumeMount{
-		{
-			Name:      swift.ClaimName,
-			MountPath: "/srv/node/d1",
-			ReadOnly:  false,
-		},
-		{
-			Name:      "config-data",
-			MountPath: "/var/lib/config-data/default",
-			ReadOnly:  true,
-		},
-		{
-			Name:      "swiftconf",
-			MountPath: "/var/lib/config-data/swiftconf",
-			ReadOnly:  true,
-		},
+	if instance.Spec.TLS != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: swift.GetStorageTLSSecretName(instance),
+				},
+			},
+		})
+	}
+
+	if instance.Spec.RsyncMetrics {
+		volumes = append(volumes, corev1.Volume{
+			Name: "rsync-log",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: ""},
+			},
+		})
+	}
+
+	if instance.Spec.ContainerSync != nil {
+		volumes = append(volumes, swift.GetContainerSyncVolume(instance.Name))
+	}
+
+	// The override files of all pods are mounted in a directory per ordinal,
+	// swift-init selects the ones of its own pod
+	if len(instance.Spec.ConfigOverrides) > 0 {
+		ordinals := make([]string, 0, len(instance.Spec.ConfigOverrides))
+		for ordinal := range instance.Spec.ConfigOverrides {
+			ordinals = append(ordinals, ordinal)
+		}
+		sort.Strings(ordinals)
+
+		sources := []corev1.VolumeProjection{}
+		for _, ordinal := range ordinals {
+			items := []corev1.KeyToPath{}
+			for _, file := range swiftv1beta1.ConfigOverrideFiles {
+				if _, ok := instance.Spec.ConfigOverrides[ordinal][file]; ok {
+					items = append(items, corev1.KeyToPath{Key: file, Path: path.Join(ordinal, file)})
+				}
+			}
+			sources = append(sources, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: getConfigOverrideName(instance, ordinal),
+					},
+					Items: items,
+				},
+			})
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: "config-overrides",
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+			},
+		})
+	}
+	return volumes
+}
+
+func getStorageVolumeMounts(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{}
+	if swiftstorage.Spec.TLS != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "tls",
+			MountPath: swift.TLSMountPath,
+			ReadOnly:  true,
+		})
+	}
+	for _, device := range getStorageDevices(swiftstorage) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      device.claim,
+			MountPath: path.Join(swiftstorage.Spec.NodeRoot, device.name),
+			ReadOnly:  false,
+		})
+	}
+	volumeMounts = append(volumeMounts, []corev1.VolumeMount{
+		{
+			Name:      "config-data",
+			MountPath: "/var/lib/config-data/default",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "swiftconf",
+			MountPath: "/var/lib/config-data/swiftconf",
+			ReadOnly:  true,
+		},
 		{
 			Name:      "ring-data",
 			MountPath: "/var/lib/config-data/rings",
@@ -328,7 +954,21 @@ func getStorageVolumeMounts() []corev1.VolumeMount {
 			MountPath: "/usr/local/bin/container-scripts",
 			ReadOnly:  true,
 		},
+	}...)
+
+	// The background daemons re-run swift-init when they are restarted in
+	// place, the config sources of swift-init are mounted in all containers
+	if len(swiftstorage.Spec.ConfigOverrides) > 0 {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "config-overrides",
+			MountPath: "/var/lib/config-data/overrides",
+			ReadOnly:  true,
+		})
+	}
+	if swiftstorage.Spec.ContainerSync != nil {
+		volumeMounts = append(volumeMounts, swift.GetContainerSyncVolumeMount())
 	}
+	return volumeMounts
 }
 
 func getPorts(port int32, name string) []corev1.ContainerPort {
@@ -340,6 +980,60 @@ func getPorts(port int32, name string) []corev1.ContainerPort {
 	}
 }
 
+const (
+	// livenessPeriod and readinessPeriod are the probe periods in seconds
+	livenessPeriod  int32 = 10
+	readinessPeriod int32 = 5
+)
+
+// getStorageServerProbe returns a probe of the healthcheck middleware of the
+// account, container or object server listening on port
+func getStorageServerProbe(swiftstorage *swiftv1beta1.SwiftStorage, port int32, period int32) *corev1.Probe {
+	scheme := corev1.URISchemeHTTP
+	if swiftstorage.Spec.TLS != nil {
+		scheme = corev1.URISchemeHTTPS
+	}
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   "/healthcheck",
+				Port:   intstr.FromInt(int(port)),
+				Scheme: scheme,
+			},
+		},
+		TimeoutSeconds:      5,
+		PeriodSeconds:       period,
+		InitialDelaySeconds: 5,
+	}
+}
+
+// getTCPProbe returns a probe connecting to port, used for the services
+// without a health check endpoint
+func getTCPProbe(port int32, period int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(int(port)),
+			},
+		},
+		TimeoutSeconds:      5,
+		PeriodSeconds:       period,
+		InitialDelaySeconds: 5,
+	}
+}
+
+// removeMemcachedContainer drops the memcached container of a pod using the
+// servers of a shared Memcached instead
+func removeMemcachedContainer(containers []corev1.Container) []corev1.Container {
+	result := []corev1.Container{}
+	for _, c := range containers {
+		if c.Name != "memcached" {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
 func getStorageInitContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Container {
 	securityContext := swift.GetSecurityContext()
 
@@ -349,23 +1043,41 @@ func getStorageInitContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.
 			Image:           swiftstorage.Spec.ContainerImageAccount,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
-			Command:         []string{"/usr/local/bin/container-scripts/swift-init.sh"},
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+			Env: []corev1.EnvVar{{
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			}},
+			Command: []string{"/usr/local/bin/container-scripts/swift-init.sh"},
 		},
 	}
 }
 
+// minimalStorageContainers are the containers deployed with MinimalContainers
+var minimalStorageContainers = map[string]bool{
+	"account-server":    true,
+	"container-server":  true,
+	"container-updater": true,
+	"object-server":     true,
+	"memcached":         true,
+	"ring-sync":         true,
+}
+
 func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Container {
 	securityContext := swift.GetSecurityContext()
 
-	return []corev1.Container{
+	containers := []corev1.Container{
 		{
 			Name:            "account-server",
 			Image:           swiftstorage.Spec.ContainerImageAccount,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
 			Ports:           getPorts(swift.AccountServerPort, "account"),
-			VolumeMounts:    getStorageVolumeMounts(),
+			LivenessProbe:   getStorageServerProbe(swiftstorage, swift.AccountServerPort, livenessPeriod),
+			ReadinessProbe:  getStorageServerProbe(swiftstorage, swift.AccountServerPort, readinessPeriod),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-account-server", "/etc/swift/account-server.conf", "-v"},
 		},
 		{
@@ -373,7 +1085,7 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageAccount,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-account-replicator", "/etc/swift/account-server.conf", "-v"},
 		},
 		{
@@ -381,7 +1093,7 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageAccount,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-account-auditor", "/etc/swift/account-server.conf", "-v"},
 		},
 		{
@@ -389,7 +1101,7 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageAccount,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-account-reaper", "/etc/swift/account-server.conf", "-v"},
 		},
 		{
@@ -398,7 +1110,9 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
 			Ports:           getPorts(swift.ContainerServerPort, "container"),
-			VolumeMounts:    getStorageVolumeMounts(),
+			LivenessProbe:   getStorageServerProbe(swiftstorage, swift.ContainerServerPort, livenessPeriod),
+			ReadinessProbe:  getStorageServerProbe(swiftstorage, swift.ContainerServerPort, readinessPeriod),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-container-server", "/etc/swift/container-server.conf", "-v"},
 		},
 		{
@@ -406,7 +1120,7 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageContainer,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-container-replicator", "/etc/swift/container-server.conf", "-v"},
 		},
 		{
@@ -414,7 +1128,7 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageContainer,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-container-replicator", "/etc/swift/container-server.conf", "-v"},
 		},
 		{
@@ -422,7 +1136,7 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageContainer,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-container-replicator", "/etc/swift/container-server.conf", "-v"},
 		},
 		{
@@ -431,7 +1145,9 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
 			Ports:           getPorts(swift.ObjectServerPort, "object"),
-			VolumeMounts:    getStorageVolumeMounts(),
+			LivenessProbe:   getStorageServerProbe(swiftstorage, swift.ObjectServerPort, livenessPeriod),
+			ReadinessProbe:  getStorageServerProbe(swiftstorage, swift.ObjectServerPort, readinessPeriod),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-object-server", "/etc/swift/object-server.conf", "-v"},
 		},
 		{
@@ -439,7 +1155,7 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageObject,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-object-replicator", "/etc/swift/object-server.conf", "-v"},
 		},
 		{
@@ -447,40 +1163,26 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageObject,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
-			Command:         []string{"/usr/bin/swift-object-replicator", "/etc/swift/object-server.conf", "-v"},
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+			Command:         append([]string{"/usr/bin/swift-object-auditor", "/etc/swift/object-server.conf", "-v"}, swift.GetObjectAuditorArgs(swiftstorage.Spec.Scrub)...),
 		},
 		{
 			Name:            "object-updater",
 			Image:           swiftstorage.Spec.ContainerImageObject,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
 			Command:         []string{"/usr/bin/swift-object-replicator", "/etc/swift/object-server.conf", "-v"},
 		},
-		{
-			Name:            "object-expirer",
-			Image:           swiftstorage.Spec.ContainerImageProxy,
-			ImagePullPolicy: corev1.PullIfNotPresent,
-			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
-			Command:         []string{"/usr/bin/swift-object-expirer", "/etc/swift/object-expirer.conf", "-v"},
-		},
-		{
-			Name:            "rsync",
-			Image:           swiftstorage.Spec.ContainerImageObject,
-			ImagePullPolicy: corev1.PullIfNotPresent,
-			SecurityContext: &securityContext,
-			Ports:           getPorts(swift.RsyncPort, "rsync"),
-			VolumeMounts:    getStorageVolumeMounts(),
-			Command:         []string{"/usr/bin/rsync", "--daemon", "--no-detach", "--config=/etc/swift/rsyncd.conf", "--log-file=/dev/stdout"},
-		},
+		getStorageRsyncContainer(swiftstorage),
 		{
 			Name:            "memcached",
 			Image:           swiftstorage.Spec.ContainerImageMemcached,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
 			Ports:           getPorts(swift.MemcachedPort, "memcached"),
+			LivenessProbe:   getTCPProbe(swift.MemcachedPort, livenessPeriod),
+			ReadinessProbe:  getTCPProbe(swift.MemcachedPort, readinessPeriod),
 			Command:         []string{"/usr/bin/memcached", "-p", "11211", "-u", "memcached"},
 		},
 		{
@@ -488,18 +1190,309 @@ func getStorageContainers(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Cont
 			Image:           swiftstorage.Spec.ContainerImageProxy,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			SecurityContext: &securityContext,
-			VolumeMounts:    getStorageVolumeMounts(),
-			Command:         []string{"/usr/local/bin/container-scripts/ring-sync.sh"},
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+			Env: append(swift.GetRingSyncEnvVars(), corev1.EnvVar{
+				Name:  "NODE_ROOT",
+				Value: swiftstorage.Spec.NodeRoot,
+			}),
+			Command: []string{"/usr/local/bin/container-scripts/ring-sync.sh"},
+		},
+	}
+
+	if swiftstorage.Spec.MinimalContainers {
+		minimal := []corev1.Container{}
+		for _, c := range containers {
+			if minimalStorageContainers[c.Name] {
+				minimal = append(minimal, c)
+			}
+		}
+		containers = minimal
+	}
+	if swiftstorage.Spec.MemcachedInstance != "" {
+		containers = removeMemcachedContainer(containers)
+	}
+
+	if swiftstorage.Spec.CrashCollector != nil {
+		containers = append(containers, getStorageCrashCollectorContainer(swiftstorage))
+	}
+	if swiftstorage.Spec.RsyncMetrics && !swiftstorage.Spec.MinimalContainers {
+		containers = append(containers, getStorageRsyncExporterContainer(swiftstorage))
+	}
+	if swiftstorage.Spec.StorageMetrics {
+		containers = append(containers, getStorageExporterContainer(swiftstorage))
+	}
+	if swiftstorage.Spec.ContainerSync != nil && !swiftstorage.Spec.MinimalContainers {
+		containers = append(containers, corev1.Container{
+			Name:            "container-sync",
+			Image:           swiftstorage.Spec.ContainerImageContainer,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+			Command:         []string{"/usr/bin/swift-container-sync", "/etc/swift/container-server.conf", "-v"},
+		})
+	}
+	// Erasure coding storage policies rebuild missing fragments with the
+	// reconstructor instead of the replicator
+	if swiftstorage.Spec.ObjectReconstructor && !swiftstorage.Spec.MinimalContainers {
+		containers = append(containers, corev1.Container{
+			Name:            "object-reconstructor",
+			Image:           swiftstorage.Spec.ContainerImageObject,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+			Command:         []string{"/usr/bin/swift-object-reconstructor", "/etc/swift/object-server.conf", "-v"},
+		})
+	}
+
+	// The background daemons always run with the wrapper, a scaling
+	// schedule pauses them without restarting the pods
+	for i := range containers {
+		if swiftv1beta1.BackgroundDaemons[containers[i].Name] {
+			containers[i].Command = append([]string{"/usr/local/bin/container-scripts/background-daemon.sh"}, containers[i].Command...)
+		}
+	}
+
+	containers = swift.ApplyNofileLimits(containers, swiftstorage.Spec.NofileLimits)
+	containers = swift.ApplyResources(containers, swiftstorage.Spec.Resources)
+	return swift.ApplyContainerEnv(containers, swiftstorage.Spec.ContainerEnv)
+}
+
+// getStorageRsyncContainer returns the rsync daemon container. With rsync
+// metrics enabled it logs to a file followed by the exporter sidecar, which
+// prints the log to stdout.
+func getStorageRsyncContainer(swiftstorage *swiftv1beta1.SwiftStorage) corev1.Container {
+	securityContext := swift.GetSecurityContext()
+
+	container := corev1.Container{
+		Name:            "rsync",
+		Image:           swiftstorage.Spec.ContainerImageObject,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		SecurityContext: &securityContext,
+		Ports:           getPorts(swift.RsyncPort, "rsync"),
+		LivenessProbe:   getTCPProbe(swift.RsyncPort, livenessPeriod),
+		ReadinessProbe:  getTCPProbe(swift.RsyncPort, readinessPeriod),
+		VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+		Command:         []string{"/usr/bin/rsync", "--daemon", "--no-detach", "--config=/etc/swift/rsyncd.conf", "--log-file=/dev/stdout"},
+	}
+	if swiftstorage.Spec.RsyncMetrics {
+		container.VolumeMounts = append(container.VolumeMounts, getRsyncLogVolumeMount())
+		container.Command[len(container.Command)-1] = "--log-file=/var/log/rsync/rsyncd.log"
+	}
+	return container
+}
+
+// getStorageExporterContainer returns a sidecar exposing the state of the
+// devices and the replication lag from the recon cache as metrics
+func getStorageExporterContainer(swiftstorage *swiftv1beta1.SwiftStorage) corev1.Container {
+	securityContext := swift.GetSecurityContext()
+
+	return corev1.Container{
+		Name:            "storage-exporter",
+		Image:           swiftstorage.Spec.ContainerImageObject,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		SecurityContext: &securityContext,
+		Ports:           getPorts(swift.StorageMetricsPort, "storage-metrics"),
+		VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+		Env: []corev1.EnvVar{
+			{
+				Name:  "METRICS_PORT",
+				Value: strconv.Itoa(int(swift.StorageMetricsPort)),
+			},
+			{
+				Name:  "NODE_ROOT",
+				Value: swiftstorage.Spec.NodeRoot,
+			},
+		},
+		Command: []string{"/usr/local/bin/container-scripts/storage-exporter.sh"},
+	}
+}
+
+func getStorageRsyncExporterContainer(swiftstorage *swiftv1beta1.SwiftStorage) corev1.Container {
+	securityContext := swift.GetSecurityContext()
+
+	return corev1.Container{
+		Name:            "rsync-exporter",
+		Image:           swiftstorage.Spec.ContainerImageObject,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		SecurityContext: &securityContext,
+		Ports:           getPorts(swift.RsyncMetricsPort, "rsync-metrics"),
+		VolumeMounts:    append(getStorageVolumeMounts(swiftstorage), getRsyncLogVolumeMount()),
+		Env: []corev1.EnvVar{{
+			Name:  "METRICS_PORT",
+			Value: strconv.Itoa(int(swift.RsyncMetricsPort)),
+		}},
+		Command: []string{"/usr/local/bin/container-scripts/rsync-exporter.sh"},
+	}
+}
+
+func getRsyncLogVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "rsync-log",
+		MountPath: "/var/log/rsync",
+		ReadOnly:  false,
+	}
+}
+
+// getStorageCrashCollectorContainer returns a sidecar that saves the previous
+// log of every restarted container, including Python tracebacks, to the
+// crash PVC and removes them once the retention period is over
+func getStorageCrashCollectorContainer(swiftstorage *swiftv1beta1.SwiftStorage) corev1.Container {
+	securityContext := swift.GetSecurityContext()
+
+	envVars := swift.GetRingSyncEnvVars()
+	envVars = append(envVars, corev1.EnvVar{
+		Name:  "RETENTION_DAYS",
+		Value: strconv.Itoa(int(swiftstorage.Spec.CrashCollector.RetentionDays)),
+	})
+
+	return corev1.Container{
+		Name:            "crash-collector",
+		Image:           swiftstorage.Spec.ContainerImageProxy,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		SecurityContext: &securityContext,
+		VolumeMounts: append(getStorageVolumeMounts(swiftstorage), corev1.VolumeMount{
+			Name:      swift.CrashClaimName,
+			MountPath: "/var/crash",
+		}),
+		Env:     envVars,
+		Command: []string{"/usr/local/bin/container-scripts/crash-collector.sh"},
+	}
+}
+
+// reconcileStuckRollout sets the RolloutStuck condition if a pod of the
+// StatefulSet rollout is crash-looping for longer than the rollout timeout.
+// With AutoRollback the StatefulSet is rolled back to its current revision.
+func (r *SwiftStorageReconciler) reconcileStuckRollout(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper, sset *appsv1.StatefulSet) error {
+	stuck, err := swift.GetStuckRollout(ctx, h, sset, time.Duration(instance.Spec.RolloutTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
+	if stuck == nil {
+		// Keep reporting the rollback until the spec is changed
+		if instance.Status.RolledBackGeneration != instance.Generation {
+			swift.SetRolloutStuckCondition(&instance.Status.Conditions, nil, false)
+		}
+		return nil
+	}
+	r.Log.Info(fmt.Sprintf("Rollout of SwiftStorage '%s' stuck on pod %s container %s", instance.Name, stuck.Pod, stuck.Container))
+	if instance.Spec.AutoRollback {
+		if err := swift.RollbackStatefulSet(ctx, h, sset, stuck); err != nil {
+			return err
+		}
+		instance.Status.RolledBackGeneration = instance.Generation
+	}
+	swift.SetRolloutStuckCondition(&instance.Status.Conditions, stuck, instance.Spec.AutoRollback)
+	return nil
+}
+
+// reconcileDebugContainer injects an ephemeral debug container into the
+// storage pod named in the DebugPodAnnotation. Ephemeral containers can not
+// be removed, the pod needs to be deleted to get rid of it.
+func (r *SwiftStorageReconciler) reconcileDebugContainer(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper) error {
+	podName, ok := instance.Annotations[swiftv1beta1.DebugPodAnnotation]
+	if !ok || podName == "" {
+		return nil
+	}
+
+	pod, err := h.GetKClient().CoreV1().Pods(instance.Namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.Info(fmt.Sprintf("Debug pod %s not found", podName))
+			return nil
+		}
+		return err
+	}
+
+	// Only storage pods of this instance can be debugged
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "StatefulSet" || owner.Name != instance.Name {
+		r.Log.Info(fmt.Sprintf("Pod %s is not a storage pod of %s, not injecting debug container", podName, instance.Name))
+		return nil
+	}
+
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == swift.DebugContainerName {
+			return nil
+		}
+	}
+
+	image := instance.Spec.ContainerImageProxy
+	if i, ok := instance.Annotations[swiftv1beta1.DebugImageAnnotation]; ok && i != "" {
+		image = i
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, getStorageDebugContainer(instance, image))
+
+	_, err = h.GetKClient().CoreV1().Pods(instance.Namespace).UpdateEphemeralContainers(ctx, podName, pod, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("Injected debug container into pod %s", podName))
+	return nil
+}
+
+func getStorageDebugContainer(swiftstorage *swiftv1beta1.SwiftStorage, image string) corev1.EphemeralContainer {
+	securityContext := swift.GetSecurityContext()
+
+	return corev1.EphemeralContainer{
+		TargetContainerName: "object-server",
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:            swift.DebugContainerName,
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			VolumeMounts:    getStorageVolumeMounts(swiftstorage),
+			Command:         []string{"/bin/sh"},
+			Stdin:           true,
+			TTY:             true,
+
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		},
 	}
 }
 
+// labelStorageTierPods adds the tier label to the pods of the storage
+// StatefulSet created before it was part of the pod template, they would
+// lose their host names until they are rolled out otherwise
+func labelStorageTierPods(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.SwiftStorage) error {
+	sset, err := statefulset.GetStatefulSetWithName(ctx, h, instance.Name, instance.Namespace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	podList, err := pod.GetPodListWithLabel(ctx, h, instance.Namespace, swift.GetLabelsStorage())
+	if err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		p := &podList.Items[i]
+		if _, ok := p.Labels[swift.StorageTierLabel]; ok || !metav1.IsControlledBy(p, sset) {
+			continue
+		}
+		patch := client.MergeFrom(p.DeepCopy())
+		p.Labels[swift.StorageTierLabel] = swift.StorageTier
+		if err := h.GetClient().Patch(ctx, p, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getStorageService returns the headless Service of the storage pods, the
+// metadata tier pods have their own Service
 func getStorageService(
 	swiftstorage *swiftv1beta1.SwiftStorage) *corev1.Service {
 
-	selector := swift.GetLabelsStorage()
+	selector := swift.GetLabelsStorageTier()
+
+	// The servers only accept TLS connections with a certificate
+	var serverProtocol *string
+	if swiftstorage.Spec.TLS != nil {
+		https := "https"
+		serverProtocol = &https
+	}
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      swiftstorage.Name,
 			Namespace: swiftstorage.Namespace,
@@ -509,19 +1502,22 @@ func getStorageService(
 			Selector: selector,
 			Ports: []corev1.ServicePort{
 				{
-					Name:     "account",
-					Port:     swift.AccountServerPort,
-					Protocol: corev1.ProtocolTCP,
+					Name:        "account",
+					Port:        swift.AccountServerPort,
+					Protocol:    corev1.ProtocolTCP,
+					AppProtocol: serverProtocol,
 				},
 				{
-					Name:     "container",
-					Port:     swift.ContainerServerPort,
-					Protocol: corev1.ProtocolTCP,
+					Name:        "container",
+					Port:        swift.ContainerServerPort,
+					Protocol:    corev1.ProtocolTCP,
+					AppProtocol: serverProtocol,
 				},
 				{
-					Name:     "object",
-					Port:     swift.ObjectServerPort,
-					Protocol: corev1.ProtocolTCP,
+					Name:        "object",
+					Port:        swift.ObjectServerPort,
+					Protocol:    corev1.ProtocolTCP,
+					AppProtocol: serverProtocol,
 				},
 				{
 					Name:     "rsync",
@@ -532,16 +1528,94 @@ func getStorageService(
 			ClusterIP: "None", // headless service
 		},
 	}
+	swift.AddPropagatedMetadata(swiftstorage, &svc.ObjectMeta)
+	return svc
+}
+
+// getArchitectureStorage returns a copy of the SwiftStorage with the images
+// of its only architecture applied, if there are overrides for it
+func getArchitectureStorage(swiftstorage *swiftv1beta1.SwiftStorage) *swiftv1beta1.SwiftStorage {
+	if len(swiftstorage.Spec.Architectures) != 1 {
+		return swiftstorage
+	}
+	images, ok := swiftstorage.Spec.ArchitectureImages[swiftstorage.Spec.Architectures[0]]
+	if !ok {
+		return swiftstorage
+	}
+
+	instance := swiftstorage.DeepCopy()
+	for _, o := range []struct {
+		image    *string
+		override string
+	}{
+		{&instance.Spec.ContainerImageAccount, images.ContainerImageAccount},
+		{&instance.Spec.ContainerImageContainer, images.ContainerImageContainer},
+		{&instance.Spec.ContainerImageObject, images.ContainerImageObject},
+		{&instance.Spec.ContainerImageProxy, images.ContainerImageProxy},
+		{&instance.Spec.ContainerImageMemcached, images.ContainerImageMemcached},
+	} {
+		if o.override != "" {
+			*o.image = o.override
+		}
+	}
+	return instance
+}
+
+// getStorageAffinity returns the affinity of the spec, or a preferred pod
+// anti-affinity spreading the storage pods across the nodes. The storage
+// pods are restricted to the requested CPU architectures in every node
+// selector term.
+func getStorageAffinity(swiftstorage *swiftv1beta1.SwiftStorage) *corev1.Affinity {
+	result := swiftstorage.Spec.Affinity.DeepCopy()
+	if result == nil {
+		result = affinity.DistributePods(
+			"app.kubernetes.io/name",
+			[]string{swift.GetLabelsStorage()["app.kubernetes.io/name"]},
+			corev1.LabelHostname,
+		)
+	}
+	if len(swiftstorage.Spec.Architectures) == 0 {
+		return result
+	}
+
+	if result.NodeAffinity == nil {
+		result.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil {
+		required = &corev1.NodeSelector{}
+		result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	}
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions,
+			corev1.NodeSelectorRequirement{
+				Key:      corev1.LabelArchStable,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   swiftstorage.Spec.Architectures,
+			})
+	}
+	return result
 }
 
 func getStorageStatefulSet(
-	swiftstorage *swiftv1beta1.SwiftStorage, labels map[string]string) *appsv1.StatefulSet {
+	swiftstorage *swiftv1beta1.SwiftStorage, labels map[string]string, replicas int32) (*appsv1.StatefulSet, error) {
 
+	swiftstorage = getArchitectureStorage(swiftstorage)
 	trueVal := true
 	OnRootMismatch := corev1.FSGroupChangeOnRootMismatch
 	user := int64(swift.RunAsUser)
+	containers, volumes := swift.ApplyExtraMounts(
+		getStorageContainers(swiftstorage), getStorageVolumes(swiftstorage), swiftstorage.Spec.ExtraMounts)
+	initContainers, _ := swift.ApplyExtraMounts(getStorageInitContainers(swiftstorage), nil, swiftstorage.Spec.ExtraMounts)
+	claims, err := getStorageVolumeClaimTemplates(swiftstorage)
+	if err != nil {
+		return nil, err
+	}
 
-	return &appsv1.StatefulSet{
+	sset := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      swiftstorage.Name,
 			Namespace: swiftstorage.Namespace,
@@ -552,10 +1626,11 @@ func getStorageStatefulSet(
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Replicas: &swiftstorage.Spec.Replicas,
+			Replicas: &replicas,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					// The metadata tier labels keep their tier
+					Labels: util.MergeStringMaps(labels, map[string]string{swift.StorageTierLabel: swift.StorageTier}),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: swift.ServiceAccount,
@@ -571,80 +1646,580 @@ func getStorageStatefulSet(
 							Type: corev1.SeccompProfileTypeRuntimeDefault,
 						},
 					},
-					Volumes:        getStorageVolumes(swiftstorage),
-					InitContainers: getStorageInitContainers(swiftstorage),
-					Containers:     getStorageContainers(swiftstorage),
+					Affinity:       getStorageAffinity(swiftstorage),
+					NodeSelector:   swiftstorage.Spec.NodeSelector,
+					Tolerations:    swiftstorage.Spec.Tolerations,
+					Volumes:        volumes,
+					InitContainers: initContainers,
+					Containers:     containers,
 				},
 			},
-			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: swift.ClaimName,
-				},
-				Spec: corev1.PersistentVolumeClaimSpec{
-					StorageClassName: &swiftstorage.Spec.StorageClass,
-					AccessModes: []corev1.PersistentVolumeAccessMode{
-						corev1.ReadWriteOnce,
-					},
-					Resources: corev1.ResourceRequirements{
-						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(swiftstorage.Spec.StorageRequest),
-						},
-					},
-				},
-			}},
+			VolumeClaimTemplates: claims,
 		},
 	}
+	// The account and container servers run in the metadata tier
+	if swiftstorage.Spec.MetadataTier != nil {
+		sset.Spec.Template.Spec.Containers = getTierContainers(sset.Spec.Template.Spec.Containers, isMetadataContainer)
+	}
+	swift.AddBackendTLS(&sset.Spec.Template.Spec)
+	swift.SetTerminationMessagePolicy(&sset.Spec.Template.Spec)
+	swift.AddPropagatedMetadata(swiftstorage, &sset.ObjectMeta)
+	swift.AddPropagatedMetadata(swiftstorage, &sset.Spec.Template.ObjectMeta)
+	return sset, nil
 }
 
-//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// isMetadataContainer returns true for the containers of the account and
+// container servers and their daemons
+func isMetadataContainer(name string) bool {
+	return strings.HasPrefix(name, "account-") || strings.HasPrefix(name, "container-")
+}
 
-func getStorageNetworkPolicy(
-	swiftstorage *swiftv1beta1.SwiftStorage) *networkingv1.NetworkPolicy {
+// isObjectContainer returns true for the containers of the object servers
+// and their daemons
+func isObjectContainer(name string) bool {
+	return strings.HasPrefix(name, "object-")
+}
 
-	portAccountServer := intstr.FromInt(int(swift.AccountServerPort))
-	portContainerServer := intstr.FromInt(int(swift.ContainerServerPort))
-	portObjectServer := intstr.FromInt(int(swift.ObjectServerPort))
-	portRsync := intstr.FromInt(int(swift.RsyncPort))
+// getTierContainers returns the containers without the ones of the other
+// storage tier
+func getTierContainers(containers []corev1.Container, otherTier func(string) bool) []corev1.Container {
+	result := []corev1.Container{}
+	for _, c := range containers {
+		if !otherTier(c.Name) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
 
-	storageLabels := swift.GetLabelsStorage()
-	proxyLabels := swift.GetLabelsProxy()
+// getMetadataStatefulSet returns the StatefulSet of the account and container
+// servers. Its pods share the config of the storage pods and have a single
+// device each, the per-pod config overrides only apply to the storage pods.
+func getMetadataStatefulSet(swiftstorage *swiftv1beta1.SwiftStorage, labels map[string]string) (*appsv1.StatefulSet, error) {
+	tier := swiftstorage.Spec.MetadataTier
+	metadata := swiftstorage.DeepCopy()
+	metadata.Spec.MetadataTier = nil
+	metadata.Spec.DisksPerReplica = 1
+	metadata.Spec.ConfigOverrides = nil
+	if tier.StorageClass != "" {
+		metadata.Spec.StorageClass = tier.StorageClass
+	}
+	if tier.StorageRequest != "" {
+		metadata.Spec.StorageRequest = tier.StorageRequest
+	}
+	if tier.NodeSelector != nil {
+		metadata.Spec.NodeSelector = tier.NodeSelector
+	}
+	if tier.Tolerations != nil {
+		metadata.Spec.Tolerations = tier.Tolerations
+	}
 
-	return &networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "np-" + swiftstorage.Name,
-			Namespace: swiftstorage.Namespace,
-		},
-		Spec: networkingv1.NetworkPolicySpec{
-			PodSelector: metav1.LabelSelector{
-				MatchLabels: storageLabels,
-			},
-			Ingress: []networkingv1.NetworkPolicyIngressRule{
-				{
-					Ports: []networkingv1.NetworkPolicyPort{
-						{
-							Port: &portAccountServer,
-						},
-						{
-							Port: &portContainerServer,
-						},
-						{
-							Port: &portObjectServer,
-						},
-						{
-							Port: &portRsync,
-						},
-					},
-					From: []networkingv1.NetworkPolicyPeer{
-						{
-							PodSelector: &metav1.LabelSelector{
-								MatchLabels: storageLabels,
-							},
-						},
-					},
-				},
-				{
-					Ports: []networkingv1.NetworkPolicyPort{
-						{
+	sset, err := getStorageStatefulSet(metadata, labels, tier.Replicas)
+	if err != nil {
+		return nil, err
+	}
+	sset.Name = swift.GetMetadataTierName(swiftstorage.Name)
+	sset.Spec.ServiceName = sset.Name
+	containers := getTierContainers(sset.Spec.Template.Spec.Containers, isObjectContainer)
+	for i := range containers {
+		if strings.HasPrefix(containers[i].Name, "account-") && tier.ContainerImageAccount != "" {
+			containers[i].Image = tier.ContainerImageAccount
+		}
+		if strings.HasPrefix(containers[i].Name, "container-") && tier.ContainerImageContainer != "" {
+			containers[i].Image = tier.ContainerImageContainer
+		}
+	}
+	sset.Spec.Template.Spec.Containers = containers
+	return sset, nil
+}
+
+// getMetadataService returns the headless Service of the metadata tier, the
+// host names of its pods are used in the account and container rings
+func getMetadataService(swiftstorage *swiftv1beta1.SwiftStorage) *corev1.Service {
+	labels := swift.GetLabelsStorageMetadata()
+	svc := getStorageService(swiftstorage)
+	svc.Name = swift.GetMetadataTierName(swiftstorage.Name)
+	svc.Labels = labels
+	svc.Spec.Selector = labels
+	ports := []corev1.ServicePort{}
+	for _, p := range svc.Spec.Ports {
+		if p.Name != "object" {
+			ports = append(ports, p)
+		}
+	}
+	svc.Spec.Ports = ports
+	return svc
+}
+
+// expirerVolumes are the storage pod volumes used by the object expirer, it
+// does not access the devices
+var expirerVolumes = map[string]bool{
+	"config-data":        true,
+	"swiftconf":          true,
+	"ring-data":          true,
+	"config-data-merged": true,
+	"cache":              true,
+	"scripts":            true,
+}
+
+func getExpirerVolumes(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.Volume {
+	volumes := []corev1.Volume{}
+	for _, v := range getStorageVolumes(swiftstorage) {
+		if expirerVolumes[v.Name] {
+			volumes = append(volumes, v)
+		}
+	}
+	return volumes
+}
+
+func getExpirerVolumeMounts(swiftstorage *swiftv1beta1.SwiftStorage) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{}
+	for _, m := range getStorageVolumeMounts(swiftstorage) {
+		if expirerVolumes[m.Name] {
+			volumeMounts = append(volumeMounts, m)
+		}
+	}
+	return volumeMounts
+}
+
+// deleteExpirerDeployment deletes the object expirer Deployment of earlier
+// versions, it is replaced by the StatefulSet of the same name
+func (r *SwiftStorageReconciler) deleteExpirerDeployment(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper) error {
+	depl, err := deployment.GetDeploymentWithName(ctx, h, instance.Name+"-object-expirer", instance.Namespace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("Deleting the object expirer Deployment %s", depl.Name))
+	return deployment.NewDeployment(depl, 5*time.Second).Delete(ctx, h)
+}
+
+// isExpirerAutoscaled returns true if the object expirer is enabled and
+// scaled by a KEDA ScaledObject
+func isExpirerAutoscaled(swiftstorage *swiftv1beta1.SwiftStorage) bool {
+	return swiftstorage.Spec.Expirer.Replicas > 0 && swiftstorage.Spec.Expirer.Autoscaling != nil
+}
+
+// getExpirerReplicas returns the number of object expirer pods, one per
+// process if the expiration tasks are divided into processes. The current
+// replicas are kept if the ScaledObject scales the expirer.
+func getExpirerReplicas(ctx context.Context, h *helper.Helper, swiftstorage *swiftv1beta1.SwiftStorage, keda bool) (int32, error) {
+	if isExpirerAutoscaled(swiftstorage) && keda {
+		found, err := statefulset.GetStatefulSetWithName(ctx, h, swiftstorage.Name+"-object-expirer", swiftstorage.Namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return 0, err
+		} else if err == nil && found.Spec.Replicas != nil {
+			return *found.Spec.Replicas, nil
+		}
+		return swiftstorage.Spec.Expirer.Autoscaling.MinReplicas, nil
+	}
+	if swiftstorage.Spec.Expirer.Replicas > 0 && swiftstorage.Spec.Expirer.Processes > 0 {
+		return swiftstorage.Spec.Expirer.Processes, nil
+	}
+	return swiftstorage.Spec.Expirer.Replicas, nil
+}
+
+// getExpirerStatefulSet returns the StatefulSet of the object expirer. It
+// reads the expiring objects queue through its internal client, so it does
+// not need to run next to the devices. Every pod processes the part of the
+// expiration tasks of its ordinal if they are divided into processes.
+func getExpirerStatefulSet(swiftstorage *swiftv1beta1.SwiftStorage, labels map[string]string, replicas int32) *appsv1.StatefulSet {
+	trueVal := true
+	securityContext := swift.GetSecurityContext()
+
+	env := []corev1.EnvVar{{
+		Name: "POD_NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+		},
+	}}
+	if swiftstorage.Spec.Expirer.Processes > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "EXPIRER_PROCESSES",
+			Value: fmt.Sprintf("%d", swiftstorage.Spec.Expirer.Processes),
+		})
+	}
+
+	initContainers := []corev1.Container{
+		{
+			Name:            "swift-init",
+			Image:           swiftstorage.Spec.ContainerImageProxy,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			VolumeMounts:    getExpirerVolumeMounts(swiftstorage),
+			Env:             env,
+			Command:         []string{"/usr/local/bin/container-scripts/swift-init.sh"},
+		},
+	}
+	containers := []corev1.Container{
+		{
+			Name:            "object-expirer",
+			Image:           swiftstorage.Spec.ContainerImageProxy,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			VolumeMounts:    getExpirerVolumeMounts(swiftstorage),
+			Command: []string{
+				"/usr/local/bin/container-scripts/background-daemon.sh",
+				"/usr/bin/swift-object-expirer", "/etc/swift/object-expirer.conf", "-v",
+			},
+		},
+		{
+			Name:            "ring-sync",
+			Image:           swiftstorage.Spec.ContainerImageProxy,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			SecurityContext: &securityContext,
+			VolumeMounts:    getExpirerVolumeMounts(swiftstorage),
+			Env:             swift.GetRingSyncEnvVars(),
+			Command:         []string{"/usr/local/bin/container-scripts/ring-sync.sh"},
+		},
+	}
+	containers = swift.ApplyNofileLimits(containers, swiftstorage.Spec.NofileLimits)
+	containers = swift.ApplyResources(containers, swiftstorage.Spec.Resources)
+	containers = swift.ApplyContainerEnv(containers, swiftstorage.Spec.ContainerEnv)
+	containers, volumes := swift.ApplyExtraMounts(containers, getExpirerVolumes(swiftstorage), swiftstorage.Spec.ExtraMounts)
+	initContainers, _ = swift.ApplyExtraMounts(initContainers, nil, swiftstorage.Spec.ExtraMounts)
+
+	sset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      swiftstorage.Name + "-object-expirer",
+			Namespace: swiftstorage.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName: swiftstorage.Name + "-object-expirer",
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Replicas:            &replicas,
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: swift.ServiceAccount,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: &trueVal,
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					NodeSelector:   swiftstorage.Spec.NodeSelector,
+					Tolerations:    swiftstorage.Spec.Tolerations,
+					Volumes:        volumes,
+					InitContainers: initContainers,
+					Containers:     containers,
+				},
+			},
+		},
+	}
+	swift.AddBackendTLS(&sset.Spec.Template.Spec)
+	swift.SetTerminationMessagePolicy(&sset.Spec.Template.Spec)
+	swift.AddPropagatedMetadata(swiftstorage, &sset.ObjectMeta)
+	swift.AddPropagatedMetadata(swiftstorage, &sset.Spec.Template.ObjectMeta)
+	return sset
+}
+
+// reconcileDiskDiscovery runs the disk discovery DaemonSet, writes the
+// discovered disks to the disk inventory ConfigMap and creates a SwiftDisk
+// for every approved disk once it is mounted. Existing SwiftDisks are not
+// changed, e.g. their zone or weight may be edited. Without disk discovery
+// the DaemonSet and the inventory are deleted, the SwiftDisks are kept.
+func (r *SwiftStorageReconciler) reconcileDiskDiscovery(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper) error {
+	dd := instance.Spec.DiskDiscovery
+	if dd == nil {
+		for _, obj := range []client.Object{
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: swift.GetDiskDiscoveryName(instance), Namespace: instance.Namespace}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: swift.GetDiskInventoryName(instance), Namespace: instance.Namespace}},
+		} {
+			if err := h.GetClient().Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ds := daemonset.NewDaemonSet(getDiskDiscoveryDaemonSet(instance, swift.GetLabelsDiskDiscovery()), 5*time.Second)
+	if _, err := ds.CreateOrPatch(ctx, h); err != nil {
+		return err
+	}
+
+	disks, err := swift.GetDiscoveredDisks(ctx, h, instance.Namespace, swift.GetLabelsDiskDiscovery())
+	if err != nil {
+		return err
+	}
+	envVars := make(map[string]env.Setter)
+	tpl := []util.Template{
+		{
+			Name:         swift.GetDiskInventoryName(instance),
+			Namespace:    instance.Namespace,
+			Type:         util.TemplateTypeNone,
+			InstanceType: instance.Kind,
+			Labels:       swift.GetLabelsDiskDiscovery(),
+			CustomData:   map[string]string{"disks.csv": swift.GetDiskInventory(dd, disks)},
+		},
+	}
+	if err := configmap.EnsureConfigMaps(ctx, h, instance, tpl, &envVars); err != nil {
+		return err
+	}
+
+	for _, d := range disks {
+		if d.State != swift.DiskStateMounted || !swift.IsDiskApproved(dd, d) {
+			continue
+		}
+		disk := &swiftv1beta1.SwiftDisk{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      swift.GetDiscoveredSwiftDiskName(d),
+				Namespace: instance.Namespace,
+			},
+		}
+		op, err := controllerutil.CreateOrPatch(ctx, h.GetClient(), disk, func() error {
+			if disk.CreationTimestamp.IsZero() {
+				disk.Spec = swift.GetDiscoveredSwiftDiskSpec(instance, d)
+			}
+			return controllerutil.SetControllerReference(instance, disk, r.Scheme)
+		})
+		if err != nil {
+			return err
+		}
+		if op == controllerutil.OperationResultCreated {
+			r.Log.Info(fmt.Sprintf("Created SwiftDisk %s for the approved disk %s/%s", disk.Name, d.Node, d.Serial))
+		}
+	}
+	return nil
+}
+
+// getDiskDiscoveryDaemonSet returns the DaemonSet inventorying the disks of
+// the selected nodes. It runs privileged to format and mount the approved
+// disks, the mounts are propagated to the host.
+func getDiskDiscoveryDaemonSet(swiftstorage *swiftv1beta1.SwiftStorage, labels map[string]string) *appsv1.DaemonSet {
+	dd := swiftstorage.Spec.DiskDiscovery
+	trueVal := true
+	rootUser := int64(0)
+	bidirectional := corev1.MountPropagationBidirectional
+	directoryOrCreate := corev1.HostPathDirectoryOrCreate
+	image := dd.ContainerImage
+	if image == "" {
+		image = swiftstorage.Spec.ContainerImageObject
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name:         "dev",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/dev"}},
+		},
+		{
+			Name:         "udev",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/run/udev"}},
+		},
+		{
+			Name:         "mount-root",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: dd.MountRoot, Type: &directoryOrCreate}},
+		},
+	}
+	for _, v := range getStorageVolumes(swiftstorage) {
+		if v.Name == "scripts" {
+			volumes = append(volumes, v)
+		}
+	}
+
+	envVars := append(swift.GetRingSyncEnvVars(),
+		corev1.EnvVar{
+			Name: "NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+			},
+		},
+		corev1.EnvVar{Name: "MOUNT_ROOT", Value: dd.MountRoot},
+		corev1.EnvVar{Name: "APPROVED_DISKS", Value: strings.Join(dd.ApprovedDisks, " ")},
+		corev1.EnvVar{Name: "SWIFT_UID", Value: strconv.FormatInt(swift.RunAsUser, 10)},
+	)
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      swift.GetDiskDiscoveryName(swiftstorage),
+			Namespace: swiftstorage.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: swift.ServiceAccount,
+					NodeSelector:       dd.NodeSelector,
+					Tolerations:        dd.Tolerations,
+					Volumes:            volumes,
+					Containers: []corev1.Container{
+						{
+							Name:            "disk-discovery",
+							Image:           image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: &trueVal,
+								RunAsUser:  &rootUser,
+							},
+							Env: envVars,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "dev", MountPath: "/dev"},
+								{Name: "udev", MountPath: "/run/udev", ReadOnly: true},
+								{Name: "mount-root", MountPath: dd.MountRoot, MountPropagation: &bidirectional},
+								{Name: "scripts", MountPath: "/usr/local/bin/container-scripts", ReadOnly: true},
+							},
+							Command: []string{"/usr/local/bin/container-scripts/disk-discovery.sh"},
+						},
+					},
+				},
+			},
+		},
+	}
+	swift.SetTerminationMessagePolicy(&ds.Spec.Template.Spec)
+	swift.AddPropagatedMetadata(swiftstorage, &ds.ObjectMeta)
+	swift.AddPropagatedMetadata(swiftstorage, &ds.Spec.Template.ObjectMeta)
+	return ds
+}
+
+// getStorageVolumeClaimTemplates returns the claims of the devices, the
+// database device and the crash artifacts. The storage requests are validated
+// by the webhook, a SwiftStorage created without it gets an error instead of
+// crashing the operator.
+func getStorageVolumeClaimTemplates(swiftstorage *swiftv1beta1.SwiftStorage) ([]corev1.PersistentVolumeClaim, error) {
+	storageRequest := swiftstorage.Spec.StorageRequest
+	if storageRequest == "" {
+		storageRequest = swift.DefaultStorageRequest
+	}
+	deviceRequest, err := resource.ParseQuantity(storageRequest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storageRequest %q: %w", storageRequest, err)
+	}
+	claims := []corev1.PersistentVolumeClaim{}
+	for i, device := range swift.GetDeviceNames(swiftstorage) {
+		claims = append(claims, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: swift.GetDeviceClaimName(i, device),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &swiftstorage.Spec.StorageClass,
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: deviceRequest,
+					},
+				},
+			},
+		})
+	}
+
+	if db := swiftstorage.Spec.DatabaseDevice; db != nil {
+		dbRequest, err := resource.ParseQuantity(db.StorageRequest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid databaseDevice storageRequest %q: %w", db.StorageRequest, err)
+		}
+		storageClass := db.StorageClass
+		if storageClass == "" {
+			storageClass = swiftstorage.Spec.StorageClass
+		}
+		claims = append(claims, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: swift.GetDatabaseDeviceClaimName(db.DeviceName),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: dbRequest,
+					},
+				},
+			},
+		})
+	}
+
+	if swiftstorage.Spec.CrashCollector != nil {
+		crashRequest, err := resource.ParseQuantity(swiftstorage.Spec.CrashCollector.StorageRequest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid crashCollector storageRequest %q: %w",
+				swiftstorage.Spec.CrashCollector.StorageRequest, err)
+		}
+		claims = append(claims, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: swift.CrashClaimName,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &swiftstorage.Spec.StorageClass,
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: crashRequest,
+					},
+				},
+			},
+		})
+	}
+
+	return claims, nil
+}
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+func getStorageNetworkPolicy(
+	swiftstorage *swiftv1beta1.SwiftStorage) *networkingv1.NetworkPolicy {
+
+	portAccountServer := intstr.FromInt(int(swift.AccountServerPort))
+	portContainerServer := intstr.FromInt(int(swift.ContainerServerPort))
+	portObjectServer := intstr.FromInt(int(swift.ObjectServerPort))
+	portRsync := intstr.FromInt(int(swift.RsyncPort))
+
+	storageLabels := swift.GetLabelsStorage()
+	proxyLabels := swift.GetLabelsProxy()
+
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "np-" + swiftstorage.Name,
+			Namespace: swiftstorage.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: storageLabels,
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Port: &portAccountServer,
+						},
+						{
+							Port: &portContainerServer,
+						},
+						{
+							Port: &portObjectServer,
+						},
+						{
+							Port: &portRsync,
+						},
+					},
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: storageLabels,
+							},
+						},
+					},
+				},
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
 							Port: &portAccountServer,
 						},
 						{
@@ -660,57 +2235,764 @@ func getStorageNetworkPolicy(
 								MatchLabels: proxyLabels,
 							},
 						},
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: swift.GetLabelsProxyPools(),
+							},
+						},
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: swift.GetLabelsHealthCheck(),
+							},
+						},
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: swift.GetLabelsExpirer(),
+							},
+						},
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: swift.GetLabelsPreUpgradeCheck(),
+							},
+						},
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: swift.GetLabelsConsistencyCheck(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Allow scraping the rsync metrics from any namespace
+	if swiftstorage.Spec.RsyncMetrics {
+		portRsyncMetrics := intstr.FromInt(int(swift.RsyncMetricsPort))
+		np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: []networkingv1.NetworkPolicyPort{{Port: &portRsyncMetrics}},
+		})
+	}
+	if swiftstorage.Spec.StorageMetrics {
+		portStorageMetrics := intstr.FromInt(int(swift.StorageMetricsPort))
+		np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: []networkingv1.NetworkPolicyPort{{Port: &portStorageMetrics}},
+		})
+	}
+	return np
+}
+
+// reconcileHealthCheck creates or deletes the health check CronJob and
+// reports the result of its last run
+func (r *SwiftStorageReconciler) reconcileHealthCheck(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper) error {
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-health-check",
+			Namespace: instance.Namespace,
+		},
+	}
+	if instance.Spec.HealthCheck == nil {
+		err := r.Client.Delete(ctx, cronJob)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		instance.Status.Health = nil
+		swift.SetHealthCondition(&instance.Status.Conditions, nil, 0)
+		return nil
+	}
+
+	desired := getHealthCheckCronJob(instance)
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, cronJob, func() error {
+		cronJob.Labels = desired.Labels
+		cronJob.Spec = desired.Spec
+		return controllerutil.SetControllerReference(instance, cronJob, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.Log.Info(fmt.Sprintf("CronJob %s successfully reconciled - operation: %s", cronJob.Name, string(op)))
+	}
+
+	instance.Status.Health, err = swift.GetStorageHealth(ctx, h, instance.Namespace, swift.GetLabelsHealthCheck())
+	if err != nil {
+		return err
+	}
+	swift.SetHealthCondition(&instance.Status.Conditions, instance.Status.Health, instance.Spec.HealthCheck.ReplicationLagThreshold)
+	return nil
+}
+
+func getHealthCheckCronJob(instance *swiftv1beta1.SwiftStorage) *batchv1.CronJob {
+	securityContext := swift.GetSecurityContext()
+	var scriptsVolumeDefaultMode int32 = 0755
+	var backoffLimit int32 = 0
+
+	envVars := map[string]env.Setter{}
+	envVars["RECON_PORT"] = env.SetValue(strconv.Itoa(int(swift.ObjectServerPort)))
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + "-health-check",
+			Namespace: instance.Namespace,
+			Labels:    swift.GetLabelsHealthCheck(),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          instance.Spec.HealthCheck.Schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: swift.GetLabelsHealthCheck(),
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:      "Never",
+							ServiceAccountName: swift.ServiceAccount,
+							SecurityContext: &corev1.PodSecurityContext{
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
+							},
+							Containers: []corev1.Container{
+								{
+									Name:            "health-check",
+									Command:         []string{"/usr/local/bin/container-scripts/health-check.sh"},
+									Image:           instance.Spec.ContainerImageProxy,
+									SecurityContext: &securityContext,
+									VolumeMounts: []corev1.VolumeMount{
+										{
+											Name:      "scripts",
+											MountPath: "/usr/local/bin/container-scripts",
+											ReadOnly:  true,
+										},
+										{
+											Name:      "ring-data",
+											MountPath: "/var/lib/config-data/rings",
+											ReadOnly:  true,
+										},
+									},
+									Env: env.MergeEnvs(swift.GetRingSyncEnvVars(), envVars),
+
+									TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+								},
+							},
+							Volumes: []corev1.Volume{
+								{
+									Name: "scripts",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											DefaultMode: &scriptsVolumeDefaultMode,
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: instance.Name + "-scripts",
+											},
+										},
+									},
+								},
+								{
+									Name: "ring-data",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: swiftv1beta1.RingConfigMapName,
+											},
+										},
+									},
+								},
+							},
+						},
 					},
 				},
 			},
 		},
 	}
+	// The recon queries use the CA certificates of the storage servers
+	swift.AddBackendTLS(&cronJob.Spec.JobTemplate.Spec.Template.Spec)
+	return cronJob
 }
 
-//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=swift.openstack.org,resources=swiftdisks,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 
-func getDeviceList(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.SwiftStorage) (string, error) {
+// getDeviceList returns the devices.csv content for the given number of pods.
+// The devices of pods removed by a scale down are kept with a weight of zero
+// to drain them from the rings. With ZoneAwareRings the ring zones of new
+// topology zones are added to the status. Devices on a SwiftDisk use its
+// region, zone and weight. The devices of the drained pods get a weight of
+// zero too. With the metadata tier the devices of the storage pods and the
+// inventory are only added to the object rings, the ones of the metadata
+// pods are in zone 1 of the account and container rings. The database
+// device of a storage pod is only added to the account and container rings.
+func getDeviceList(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.SwiftStorage, replicas int32) (string, error) {
 	var devices strings.Builder
+	if instance.Spec.MetadataTier != nil {
+		objectDevices, err := getDeviceList(ctx, h, withoutMetadataTier(instance), replicas)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range strings.SplitAfter(objectDevices, "\n") {
+			if strings.TrimSpace(entry) != "" {
+				devices.WriteString(swift.SetDeviceListEntryRings(entry, swift.DeviceRingsObject))
+			}
+		}
+		metadataDevices, err := getMetadataDeviceList(ctx, h, instance)
+		if err != nil {
+			return "", err
+		}
+		devices.WriteString(metadataDevices)
+		return devices.String(), nil
+	}
+
+	foundClaim := &corev1.PersistentVolumeClaim{}
+	for replica := 0; replica < int(replicas); replica++ {
+		// The devices of cordoned pods are removed from the rings
+		if isCordoned(instance, int32(replica)) {
+			continue
+		}
+		drain := replica >= int(instance.Spec.Replicas) || isDraining(instance, int32(replica))
+		for _, d := range getStorageDevices(instance) {
+			device := d.name
+			cn := fmt.Sprintf("%s-%s-%d", d.claim, instance.Name, replica)
+			err := h.GetClient().Get(ctx, types.NamespacedName{Name: cn, Namespace: instance.Namespace}, foundClaim)
+			if err == nil {
+				disk, err := swift.GetClaimSwiftDisk(ctx, h, foundClaim)
+				if err != nil {
+					return "", err
+				}
+				if disk != nil {
+					devices.WriteString(d.withRings(swift.GetSwiftDiskDeviceListEntry(instance, replica, device, disk, drain)))
+					continue
+				}
+				var c int64
+				if !drain {
+					fsc := foundClaim.Status.Capacity["storage"]
+					c, _ = (&fsc).AsInt64()
+				}
+				var zone int32
+				if instance.Spec.ZoneAwareRings {
+					podName := fmt.Sprintf("%s-%d", instance.Name, replica)
+					nodeZone, err := swift.GetNodeZone(ctx, h, instance.Namespace, podName, foundClaim)
+					if err != nil {
+						return "", err
+					}
+					if instance.Status.RingZones == nil {
+						instance.Status.RingZones = map[string]int32{}
+					}
+					zone = swift.GetRingZone(instance.Status.RingZones, nodeZone)
+				}
+				devices.WriteString(d.withRings(swift.GetDeviceListEntry(instance, replica, device, c, zone)))
+			} else {
+				return "", err
+			}
+		}
+	}
+
+	// The devices of the inventory are added as they are, e.g. the devices
+	// of storage nodes migrated from an existing cluster
+	if instance.Spec.DeviceInventory != "" {
+		cm := &corev1.ConfigMap{}
+		err := h.GetClient().Get(ctx, types.NamespacedName{Name: instance.Spec.DeviceInventory, Namespace: instance.Namespace}, cm)
+		if err != nil {
+			return "", err
+		}
+		inventory, err := swiftv1beta1.ParseDeviceInventory(cm.Data[swiftv1beta1.DeviceInventoryKey])
+		if err != nil {
+			return "", fmt.Errorf("invalid device inventory ConfigMap %s: %w", cm.Name, err)
+		}
+		for _, d := range inventory {
+			devices.WriteString(swift.GetInventoryDeviceListEntry(d))
+		}
+	}
+	return devices.String(), nil
+}
+
+// storageDevice is a device of each storage pod
+type storageDevice struct {
+	name  string
+	claim string
+	// rings the device is added to, all if empty
+	rings string
+}
+
+// withRings returns the devices.csv line restricted to the rings of the
+// device
+func (d storageDevice) withRings(entry string) string {
+	if d.rings == "" {
+		return entry
+	}
+	return swift.SetDeviceListEntryRings(entry, d.rings)
+}
+
+// getStorageDevices returns the devices of each storage pod. With a database
+// device the other devices are only added to the object rings.
+func getStorageDevices(instance *swiftv1beta1.SwiftStorage) []storageDevice {
+	devices := []storageDevice{}
+	for i, name := range swift.GetDeviceNames(instance) {
+		devices = append(devices, storageDevice{name: name, claim: swift.GetDeviceClaimName(i, name)})
+	}
+	if db := instance.Spec.DatabaseDevice; db != nil {
+		for i := range devices {
+			devices[i].rings = swift.DeviceRingsObject
+		}
+		devices = append(devices, storageDevice{
+			name:  db.DeviceName,
+			claim: swift.GetDatabaseDeviceClaimName(db.DeviceName),
+			rings: swift.DeviceRingsMetadata,
+		})
+	}
+	return devices
+}
 
+// getScaleDownList returns the "<host>,<device>" lines of the devices of the
+// pods removed by a scale down. Only the builders with one of these devices
+// skip min_part_hours on the rebalance, devices drained for other reasons
+// move one replica per min_part_hours.
+func getScaleDownList(instance *swiftv1beta1.SwiftStorage, replicas int32) string {
+	var devices strings.Builder
+	for replica := instance.Spec.Replicas; replica < replicas; replica++ {
+		if isCordoned(instance, replica) {
+			continue
+		}
+		host := fmt.Sprintf("%s-%d.%s", instance.Name, replica, instance.Name)
+		for _, d := range getStorageDevices(instance) {
+			devices.WriteString(fmt.Sprintf("%s,%s\n", host, d.name))
+		}
+	}
+	return devices.String()
+}
+
+// withoutMetadataTier returns the SwiftStorage without its metadata tier.
+// The status is shared, the ring zones are added to it.
+func withoutMetadataTier(instance *swiftv1beta1.SwiftStorage) *swiftv1beta1.SwiftStorage {
+	storage := *instance
+	storage.Spec.MetadataTier = nil
+	return &storage
+}
+
+// getMetadataDeviceList returns the devices.csv lines of the metadata pods
+func getMetadataDeviceList(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.SwiftStorage) (string, error) {
+	var devices strings.Builder
+	name := swift.GetMetadataTierName(instance.Name)
 	foundClaim := &corev1.PersistentVolumeClaim{}
-	for replica := 0; replica < int(instance.Spec.Replicas); replica++ {
-		cn := fmt.Sprintf("%s-%s-%d", swift.ClaimName, instance.Name, replica)
+	for replica := 0; replica < int(instance.Spec.MetadataTier.Replicas); replica++ {
+		cn := fmt.Sprintf("%s-%s-%d", swift.ClaimName, name, replica)
 		err := h.GetClient().Get(ctx, types.NamespacedName{Name: cn, Namespace: instance.Namespace}, foundClaim)
-		if err == nil {
-			fsc := foundClaim.Status.Capacity["storage"]
-			c, _ := (&fsc).AsInt64()
-			c = c / (1000 * 1000 * 1000)
-			host := fmt.Sprintf("%s-%d.%s", instance.Name, replica, instance.Name)
-			devices.WriteString(fmt.Sprintf("%s,%s,%d\n", host, "d1", c))
-		} else {
+		if err != nil {
 			return "", err
 		}
+		fsc := foundClaim.Status.Capacity["storage"]
+		c, _ := (&fsc).AsInt64()
+		devices.WriteString(swift.GetMetadataDeviceListEntry(instance, replica, c))
 	}
 	return devices.String(), nil
 }
 
-func getDeviceConfigMapTemplates(instance *swiftv1beta1.SwiftStorage, devices string) []util.Template {
-	data := make(map[string]string)
-	data["devices.csv"] = devices
+// isCordoned returns true if the storage pod with the given ordinal is
+// cordoned
+func isCordoned(instance *swiftv1beta1.SwiftStorage, ordinal int32) bool {
+	for _, o := range instance.Spec.CordonedOrdinals {
+		if o == ordinal {
+			return true
+		}
+	}
+	return false
+}
 
-	return []util.Template{
-		{
-			Name:         swiftv1beta1.DeviceConfigMapName,
-			Namespace:    instance.Namespace,
-			Type:         util.TemplateTypeNone,
-			InstanceType: instance.Kind,
-			CustomData:   data,
-		},
+// isDraining returns true if the storage pod with the given ordinal is
+// drained
+func isDraining(instance *swiftv1beta1.SwiftStorage, ordinal int32) bool {
+	for _, o := range instance.Spec.DrainOrdinals {
+		if o == ordinal {
+			return true
+		}
+	}
+	return false
+}
+
+// getCordonedCount returns the number of cordoned pods out of the first
+// replicas pods
+func getCordonedCount(instance *swiftv1beta1.SwiftStorage, replicas int32) int32 {
+	count := int32(0)
+	for replica := int32(0); replica < replicas; replica++ {
+		if isCordoned(instance, replica) {
+			count++
+		}
+	}
+	return count
+}
+
+// getStorageReplicas returns the number of storage pods to run. This is the
+// current size of the StatefulSet while scaling down, at most
+// ScaleUpBatchSize more pods while scaling up and the replicas in the spec
+// otherwise.
+func getStorageReplicas(ctx context.Context, h *helper.Helper, instance *swiftv1beta1.SwiftStorage, ringConfigMap *corev1.ConfigMap) (int32, error) {
+	found, err := statefulset.GetStatefulSetWithName(ctx, h, instance.Name, instance.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	} else if err != nil || found.Spec.Replicas == nil || *found.Spec.Replicas == 0 {
+		return instance.Spec.Replicas, nil
+	}
+	current := *found.Spec.Replicas
+	if current > instance.Spec.Replicas {
+		return current, nil
+	}
+	batch := instance.Spec.ScaleUpBatchSize
+	if batch == 0 || instance.Spec.Replicas-current <= batch {
+		return instance.Spec.Replicas, nil
+	}
+
+	// The next batch is added once the current pods are ready, the rings
+	// include their devices and all pods replicated with these rings
+	if found.Status.ReadyReplicas != current {
+		return current, nil
+	}
+	devices, err := getDeviceList(ctx, h, instance, current)
+	if err != nil {
+		return 0, err
+	}
+	deviceList, err := swift.ParseDeviceList(devices)
+	if err != nil {
+		return 0, err
+	}
+	diff, err := swift.GetRingDeviceDiff(ringConfigMap, deviceList)
+	if err != nil {
+		return 0, err
+	} else if len(diff) > 0 {
+		return current, nil
+	}
+	ringSync, err := swift.GetRingSyncStatus(ctx, h, instance.Namespace, swift.GetLabelsStorage())
+	if err != nil {
+		return 0, err
+	}
+	if !swift.IsReplicationComplete(ringSync, swift.GetRingMd5(ringConfigMap), int(current)) {
+		return current, nil
+	}
+	h.GetLogger().Info(fmt.Sprintf("Replication of SwiftStorage '%s' completed with %d pods, adding %d more", instance.Name, current, batch))
+	return current + batch, nil
+}
+
+// reconcileScaleDown removes the storage pods above Spec.Replicas once their
+// devices are drained. The devices got a weight of zero in the device list,
+// the pods are kept until the rings assign no partitions to them and the
+// replicators moved all partitions off their devices.
+func (r *SwiftStorageReconciler) reconcileScaleDown(
+	ctx context.Context,
+	instance *swiftv1beta1.SwiftStorage,
+	h *helper.Helper,
+	ringConfigMap *corev1.ConfigMap,
+	replicas int32,
+	labels map[string]string,
+) (ctrl.Result, error) {
+	hosts := []string{}
+	pods := []string{}
+	for replica := instance.Spec.Replicas; replica < replicas; replica++ {
+		hosts = append(hosts, fmt.Sprintf("%s-%d.%s", instance.Name, replica, instance.Name))
+		pods = append(pods, fmt.Sprintf("%s-%d", instance.Name, replica))
+	}
+
+	pending, err := swift.GetRingDrainPending(ringConfigMap, hosts)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if pending != 0 {
+		swift.SetScaleDownCondition(&instance.Status.Conditions, fmt.Sprintf(
+			swiftv1beta1.ScaleDownInProgressRingMessage, replicas, instance.Spec.Replicas, strings.Join(pods, ", ")))
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Log.Info(fmt.Sprintf("Waiting for the rings to drain SwiftStorage '%s' pods %s", instance.Name, strings.Join(pods, ", ")))
+		return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+	}
+
+	partitions, err := swift.GetDevicePartitions(ctx, h, instance.Namespace, labels, pods)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, count := range partitions {
+		if count != 0 {
+			progress := swift.FormatDevicePartitions(partitions)
+			swift.SetScaleDownCondition(&instance.Status.Conditions, fmt.Sprintf(
+				swiftv1beta1.ScaleDownInProgressReplicationMessage, replicas, instance.Spec.Replicas, progress))
+			if err := r.Status().Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Log.Info(fmt.Sprintf("Waiting for replication to drain SwiftStorage '%s' pods: %s", instance.Name, progress))
+			return ctrl.Result{RequeueAfter: swift.ResyncInterval()}, nil
+		}
+	}
+
+	// All devices are drained, shrink the StatefulSet. The device list is
+	// updated without the removed devices once the remaining pods are
+	// ready, the claims are deleted in the next reconcile.
+	desired, err := getStorageStatefulSet(instance, labels, instance.Spec.Replicas)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	sset := statefulset.NewStatefulSet(desired, 5*time.Second)
+	ctrlResult, err := sset.CreateOrPatch(ctx, h)
+	if err != nil {
+		return ctrlResult, err
+	}
+	r.Log.Info(fmt.Sprintf("Scaled down SwiftStorage '%s' from %d to %d replicas", instance.Name, replicas, instance.Spec.Replicas))
+	return ctrl.Result{RequeueAfter: time.Duration(5) * time.Second}, nil
+}
+
+// reconcileDrain sets the Drained condition once the rings assign no
+// partitions to the pods of DrainOrdinals and the replicators moved all
+// partitions off their devices, like for a scale down. Returns false while
+// the pods are still draining.
+func (r *SwiftStorageReconciler) reconcileDrain(
+	ctx context.Context,
+	instance *swiftv1beta1.SwiftStorage,
+	h *helper.Helper,
+	ringConfigMap *corev1.ConfigMap,
+	labels map[string]string,
+) (bool, error) {
+	if len(instance.Spec.DrainOrdinals) == 0 {
+		if instance.Status.Conditions.Has(swiftv1beta1.DrainedCondition) {
+			instance.Status.Conditions.Remove(swiftv1beta1.DrainedCondition)
+			return true, r.Status().Update(ctx, instance)
+		}
+		return true, nil
+	}
+
+	hosts := []string{}
+	pods := []string{}
+	for _, o := range instance.Spec.DrainOrdinals {
+		hosts = append(hosts, fmt.Sprintf("%s-%d.%s", instance.Name, o, instance.Name))
+		pods = append(pods, fmt.Sprintf("%s-%d", instance.Name, o))
+	}
+
+	message := ""
+	pending, err := swift.GetRingDrainPending(ringConfigMap, hosts)
+	if err != nil {
+		return false, err
+	}
+	if pending != 0 {
+		message = fmt.Sprintf(swiftv1beta1.DrainedRingMessage, strings.Join(pods, ", "))
+	} else {
+		partitions, err := swift.GetDevicePartitions(ctx, h, instance.Namespace, labels, pods)
+		if err != nil {
+			return false, err
+		}
+		for _, count := range partitions {
+			if count != 0 {
+				message = fmt.Sprintf(swiftv1beta1.DrainedReplicationMessage, swift.FormatDevicePartitions(partitions))
+				break
+			}
+		}
+	}
+
+	if message != "" {
+		instance.Status.Conditions.Set(condition.FalseCondition(
+			swiftv1beta1.DrainedCondition,
+			condition.RequestedReason,
+			condition.SeverityInfo,
+			message))
+		r.Log.Info(fmt.Sprintf("SwiftStorage '%s': %s", instance.Name, message))
+		return false, r.Status().Update(ctx, instance)
+	}
+	if !instance.Status.Conditions.IsTrue(swiftv1beta1.DrainedCondition) {
+		r.Log.Info(fmt.Sprintf("SwiftStorage '%s' pods %s are drained", instance.Name, strings.Join(pods, ", ")))
+	}
+	instance.Status.Conditions.MarkTrue(swiftv1beta1.DrainedCondition, swiftv1beta1.DrainedMessage, strings.Join(pods, ", "))
+	return true, r.Status().Update(ctx, instance)
+}
+
+// deleteRemovedClaims deletes the claims of the storage pods removed by a
+// scale down and clears the ScaleDownInProgress condition
+func (r *SwiftStorageReconciler) deleteRemovedClaims(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper) error {
+	if !instance.Status.Conditions.Has(swiftv1beta1.ScaleDownInProgressCondition) {
+		return nil
+	}
+
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := h.GetClient().List(ctx, claims, client.InNamespace(instance.Namespace)); err != nil {
+		return err
+	}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		ordinal, ok := getClaimOrdinal(instance, claim.Name)
+		if !ok || ordinal < int(instance.Spec.Replicas) || claim.DeletionTimestamp != nil {
+			continue
+		}
+		if err := h.GetClient().Delete(ctx, claim); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		r.Log.Info(fmt.Sprintf("Deleted PersistentVolumeClaim %s of removed SwiftStorage pod", claim.Name))
+	}
+
+	swift.SetScaleDownCondition(&instance.Status.Conditions, "")
+	return r.Status().Update(ctx, instance)
+}
+
+// reconcileDeviceList updates the devices of the SwiftStorage in the device
+// list shared with the other SwiftStorages and returns the whole device
+// list. Changes are only applied at the head of the ring build queue, the
+// SwiftStorage keeps its position until the rings include the device list.
+// Scale, drain and device changes of the SwiftStorage at the head wait for
+// the rings to include its previous change as well, so one rebalance runs at
+// a time. It returns true while the SwiftStorage waits for its turn.
+func (r *SwiftStorageReconciler) reconcileDeviceList(ctx context.Context, instance *swiftv1beta1.SwiftStorage, h *helper.Helper, ringConfigMap *corev1.ConfigMap, devices string, scaleDown string) (string, bool, error) {
+	queue := swift.GetRingBuildQueue()
+	key := swift.GetRingBuildQueueKey(instance)
+
+	cm := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.DeviceConfigMapName, Namespace: instance.Namespace}, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", false, err
+	}
+	current, ok := swift.GetDeviceListPool(cm, instance.Name)
+	if ok && current == devices && swift.GetScaleDownListPool(cm, instance.Name) == scaleDown && queue.Position(key, instance.Name) == 0 {
+		return cm.Data["devices.csv"], false, nil
+	}
+
+	// A new change of the SwiftStorage at the head of the queue waits for
+	// the rebalance of its previous change
+	if ok && current != devices && queue.Position(key, instance.Name) == 1 {
+		deviceList, err := swift.ParseDeviceList(cm.Data["devices.csv"])
+		if err != nil {
+			return "", false, err
+		}
+		diff, err := swift.GetRingDeviceDiff(ringConfigMap, deviceList)
+		if err != nil {
+			return "", false, err
+		} else if len(diff) > 0 {
+			r.Log.Info(fmt.Sprintf("SwiftStorage '%s' waiting for the rings to include its previous device list change: %s", instance.Name, strings.Join(diff, ", ")))
+			return "", true, nil
+		}
+	}
+
+	swiftStorages := &swiftv1beta1.SwiftStorageList{}
+	if err := h.GetClient().List(ctx, swiftStorages, client.InNamespace(instance.Namespace)); err != nil {
+		return "", false, err
+	}
+	existing := map[string]bool{}
+	for _, s := range swiftStorages.Items {
+		existing[s.Name] = s.DeletionTimestamp.IsZero()
+	}
+	queue.Prune(key, existing)
+
+	position := queue.Enqueue(key, instance.Name)
+	if instance.Status.RingBuildQueuePosition != position {
+		instance.Status.RingBuildQueuePosition = position
+		if position > 1 {
+			r.Log.Info(fmt.Sprintf("SwiftStorage '%s' waiting at position %d of the ring build queue of %s", instance.Name, position, key))
+			for _, t := range []condition.Type{condition.ReadyCondition, swiftv1beta1.SwiftStorageReadyCondition} {
+				instance.Status.Conditions.Set(condition.FalseCondition(
+					t,
+					condition.RequestedReason,
+					condition.SeverityInfo,
+					swiftv1beta1.RingUpdateQueuedMessage,
+					position,
+					key))
+			}
+		}
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return "", false, err
+		}
+	}
+	if position > 1 {
+		return "", true, nil
 	}
+
+	merged, err := swift.EnsureDeviceList(ctx, h, instance, devices, scaleDown)
+	return merged, false, err
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SwiftStorageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	// The claims of the StatefulSet are not owned by it, map them to the
+	// SwiftStorage by name to update the device list when they are bound
+	// or expanded
+	claimFilter := func(o client.Object) []reconcile.Request {
+		result := []reconcile.Request{}
+		swiftStorages := &swiftv1beta1.SwiftStorageList{}
+		listOpts := []client.ListOption{client.InNamespace(o.GetNamespace())}
+		if err := r.Client.List(context.Background(), swiftStorages, listOpts...); err != nil {
+			r.Log.Error(err, "Unable to list SwiftStorages")
+			return result
+		}
+
+		for i := range swiftStorages.Items {
+			if _, ok := getClaimOrdinal(&swiftStorages.Items[i], o.GetName()); ok {
+				name := client.ObjectKey{
+					Namespace: o.GetNamespace(),
+					Name:      swiftStorages.Items[i].Name,
+				}
+				result = append(result, reconcile.Request{NamespacedName: name})
+			}
+		}
+		return result
+	}
+
+	// The device inventory is not owned by the SwiftStorage, update the
+	// device list when it changes
+	inventoryFilter := func(o client.Object) []reconcile.Request {
+		result := []reconcile.Request{}
+		swiftStorages := &swiftv1beta1.SwiftStorageList{}
+		listOpts := []client.ListOption{client.InNamespace(o.GetNamespace())}
+		if err := r.Client.List(context.Background(), swiftStorages, listOpts...); err != nil {
+			r.Log.Error(err, "Unable to list SwiftStorages")
+			return result
+		}
+
+		for _, s := range swiftStorages.Items {
+			if s.Spec.DeviceInventory == o.GetName() {
+				name := client.ObjectKey{
+					Namespace: o.GetNamespace(),
+					Name:      s.Name,
+				}
+				result = append(result, reconcile.Request{NamespacedName: name})
+			}
+		}
+		return result
+	}
+
+	// The SwiftDisks are not owned by the SwiftStorage, update the device
+	// list of the SwiftStorage claiming a disk when it changes
+	diskFilter := func(o client.Object) []reconcile.Request {
+		disk, ok := o.(*swiftv1beta1.SwiftDisk)
+		if !ok || disk.Status.ClaimName == "" {
+			return nil
+		}
+		namespace, claim, _ := strings.Cut(disk.Status.ClaimName, "/")
+		return claimFilter(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: claim, Namespace: namespace}})
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&swiftv1beta1.SwiftStorage{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&batchv1.CronJob{}).
+		Watches(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, handler.EnqueueRequestsFromMapFunc(claimFilter)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(inventoryFilter)).
+		Watches(&source.Kind{Type: &swiftv1beta1.SwiftDisk{}}, handler.EnqueueRequestsFromMapFunc(diskFilter)).
 		Complete(r)
 }
+
+// getClaimOrdinal returns the ordinal of the storage pod using the claim
+// with the given name, if it is one of the claims of the SwiftStorage
+func getClaimOrdinal(instance *swiftv1beta1.SwiftStorage, name string) (int, bool) {
+	prefixes := []string{swift.CrashClaimName}
+	for _, device := range getStorageDevices(instance) {
+		prefixes = append(prefixes, device.claim)
+	}
+	for _, prefix := range prefixes {
+		suffix := strings.TrimPrefix(name, fmt.Sprintf("%s-%s-", prefix, instance.Name))
+		if suffix == name {
+			continue
+		}
+		if ordinal, err := strconv.Atoi(suffix); err == nil {
+			return ordinal, true
+		}
+	}
+	return 0, false
+}
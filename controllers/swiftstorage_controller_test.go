@@ -0,0 +1,130 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// newFakeClient returns a client of the given objects without an API server,
+// the reconcile steps run before the sub-resources are created
+func newFakeClient(objs ...client.Object) (client.Client, *runtime.Scheme) {
+	s := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	Expect(swiftv1beta1.AddToScheme(s)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(), s
+}
+
+func newStorage(spec swiftv1beta1.SwiftStorageSpec) *swiftv1beta1.SwiftStorage {
+	spec.Replicas = 1
+	spec.StorageRequest = "10Gi"
+	spec.ContainerImageAccount = "swift-account"
+	spec.ContainerImageContainer = "swift-container"
+	spec.ContainerImageObject = "swift-object"
+	spec.ContainerImageProxy = "swift-proxy"
+	spec.ContainerImageMemcached = "memcached"
+	return &swiftv1beta1.SwiftStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: "swift-storage", Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+// reconcileStorage reconciles the SwiftStorage once and returns it with the
+// updated status
+func reconcileStorage(storage *swiftv1beta1.SwiftStorage) (ctrl.Result, *swiftv1beta1.SwiftStorage) {
+	c, s := newFakeClient(storage)
+	r := &SwiftStorageReconciler{
+		Client:  c,
+		Scheme:  s,
+		Log:     ctrl.Log.WithName("controllers").WithName("SwiftStorage"),
+		Kclient: kfake.NewSimpleClientset(),
+	}
+	key := types.NamespacedName{Name: storage.Name, Namespace: storage.Namespace}
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	Expect(err).NotTo(HaveOccurred())
+
+	updated := &swiftv1beta1.SwiftStorage{}
+	Expect(c.Get(context.TODO(), key, updated)).To(Succeed())
+	return result, updated
+}
+
+var _ = Describe("SwiftStorage controller", func() {
+	Context("with an invalid defaultConfigOverwrite", func() {
+		It("sets a False Ready condition instead of failing the reconcile", func() {
+			storage := newStorage(swiftv1beta1.SwiftStorageSpec{
+				DefaultConfigOverwrite: map[string]string{"rsyncd.conf": "[account]\n"},
+			})
+			result, updated := reconcileStorage(storage)
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			for _, t := range []condition.Type{condition.ReadyCondition, swiftv1beta1.SwiftStorageReadyCondition} {
+				c := updated.Status.Conditions.Get(t)
+				Expect(c).NotTo(BeNil())
+				Expect(c.Status).To(Equal(corev1.ConditionFalse))
+				Expect(c.Reason).To(Equal(condition.Reason(condition.ErrorReason)))
+				Expect(c.Message).To(ContainSubstring(`invalid file "rsyncd.conf" in defaultConfigOverwrite`))
+			}
+		})
+	})
+
+	Context("with an invalid restartDaemons", func() {
+		It("sets a False Ready condition instead of failing the reconcile", func() {
+			storage := newStorage(swiftv1beta1.SwiftStorageSpec{
+				RestartDaemons: map[string]string{"object-server": "1"},
+			})
+			result, updated := reconcileStorage(storage)
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(updated.Status.Conditions.IsFalse(condition.ReadyCondition)).To(BeTrue())
+			Expect(updated.Status.Conditions.Get(condition.ReadyCondition).Message).To(
+				ContainSubstring(`invalid container "object-server" in restartDaemons`))
+		})
+	})
+
+	Context("with an invalid scrub window", func() {
+		It("sets a False Ready condition instead of failing the reconcile", func() {
+			storage := newStorage(swiftv1beta1.SwiftStorageSpec{
+				Scrub: &swiftv1beta1.SwiftStorageScrub{Window: &swiftv1beta1.SwiftStorageScrubWindow{
+					Schedule: "0 25 * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+				}},
+			})
+			result, updated := reconcileStorage(storage)
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(updated.Status.Conditions.IsFalse(swiftv1beta1.SwiftStorageReadyCondition)).To(BeTrue())
+			Expect(updated.Status.Conditions.Get(swiftv1beta1.SwiftStorageReadyCondition).Message).To(
+				ContainSubstring(`scrub window: invalid schedule "0 25 * * *"`))
+		})
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -35,12 +36,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	keystonev1beta1 "github.com/openstack-k8s-operators/keystone-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
 
 	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
 	"github.com/openstack-k8s-operators/swift-operator/controllers"
+	swift "github.com/openstack-k8s-operators/swift-operator/pkg/swift"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -61,8 +65,21 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var clusterInfoAddr string
+	var features swift.Features
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&clusterInfoAddr, "cluster-info-bind-address", "0",
+		"The address the read-only Swift cluster info endpoint binds to. Set to 0 to disable it.")
+	flag.BoolVar(&features.Routes, "enable-routes", getEnvBool("ENABLE_ROUTES", true),
+		"Expose the public endpoint with an OpenShift Route. Needs the routes RBAC rules. Defaults to $ENABLE_ROUTES.")
+	flag.BoolVar(&features.Autoscaling, "enable-autoscaling", getEnvBool("ENABLE_AUTOSCALING", true),
+		"Create HorizontalPodAutoscalers for the proxy. Needs the autoscaling RBAC rules. Defaults to $ENABLE_AUTOSCALING.")
+	flag.BoolVar(&features.KeystoneEndpoints, "enable-keystone-endpoints", getEnvBool("ENABLE_KEYSTONE_ENDPOINTS", true),
+		"Register the service and its endpoints in Keystone. Needs the keystone-endpoints RBAC rules. "+
+			"Defaults to $ENABLE_KEYSTONE_ENDPOINTS.")
+	flag.BoolVar(&features.KEDA, "enable-keda", getEnvBool("ENABLE_KEDA", true),
+		"Scale the object expirers with KEDA ScaledObjects. Needs the keda RBAC rules. Defaults to $ENABLE_KEDA.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -110,43 +127,94 @@ func main() {
 	}
 
 	if err = (&controllers.SwiftProxyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      mgr.GetLogger(),
+		Kclient:  kclient,
+		Features: features,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftProxy")
+		os.Exit(1)
+	}
+	if err = (&controllers.SwiftStorageReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      mgr.GetLogger(),
+		Kclient:  kclient,
+		Features: features,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftStorage")
+		os.Exit(1)
+	}
+	if err = (&controllers.SwiftRingReconciler{
 		Client:  mgr.GetClient(),
 		Scheme:  mgr.GetScheme(),
 		Log:     mgr.GetLogger(),
 		Kclient: kclient,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "SwiftProxy")
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftRing")
 		os.Exit(1)
 	}
-	if err = (&controllers.SwiftStorageReconciler{
+	if err = (&controllers.SwiftReconciler{
 		Client:  mgr.GetClient(),
 		Scheme:  mgr.GetScheme(),
 		Log:     mgr.GetLogger(),
 		Kclient: kclient,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "SwiftStorage")
+		setupLog.Error(err, "unable to create controller", "controller", "Swift")
 		os.Exit(1)
 	}
-	if err = (&controllers.SwiftRingReconciler{
+
+	if err = (&controllers.SwiftDiskReconciler{
 		Client:  mgr.GetClient(),
 		Scheme:  mgr.GetScheme(),
 		Log:     mgr.GetLogger(),
 		Kclient: kclient,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "SwiftRing")
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftDisk")
 		os.Exit(1)
 	}
-	if err = (&controllers.SwiftReconciler{
+
+	if err = (&controllers.SwiftAccountReconciler{
 		Client:  mgr.GetClient(),
 		Scheme:  mgr.GetScheme(),
 		Log:     mgr.GetLogger(),
 		Kclient: kclient,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Swift")
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftAccount")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.SwiftContainerReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Log:     mgr.GetLogger(),
+		Kclient: kclient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftContainer")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.SwiftSecretGrantReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Log:    mgr.GetLogger(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftSecretGrant")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.SwiftOperatorConfigReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Log:    mgr.GetLogger(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwiftOperatorConfig")
 		os.Exit(1)
 	}
 
-	// Acquire environmental defaults and initialize operator defaults with them
+	// Acquire environmental defaults and initialize operator defaults with
+	// them, the SwiftOperatorConfig overrides them once it is reconciled
 	swiftv1beta1.SetupDefaults()
 
 	// Setup webhooks if requested
@@ -155,10 +223,42 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Swift")
 			os.Exit(1)
 		}
+		if err = (&swiftv1beta1.SwiftRing{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwiftRing")
+			os.Exit(1)
+		}
+		if err = (&swiftv1beta1.SwiftStorage{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwiftStorage")
+			os.Exit(1)
+		}
+		if err = (&swiftv1beta1.SwiftProxy{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwiftProxy")
+			os.Exit(1)
+		}
+
+		decoder, err := admission.NewDecoder(mgr.GetScheme())
+		if err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwiftConfSecret")
+			os.Exit(1)
+		}
+		mgr.GetWebhookServer().Register(swift.SwiftConfSecretValidatorPath, &webhook.Admission{
+			Handler: swift.NewSwiftConfSecretValidator(decoder),
+		})
 	}
 
 	//+kubebuilder:scaffold:builder
 
+	if clusterInfoAddr != "0" {
+		if err := mgr.Add(swift.NewClusterInfoServer(
+			mgr.GetClient(),
+			clusterInfoAddr,
+			mgr.GetLogger().WithName("cluster-info"),
+		)); err != nil {
+			setupLog.Error(err, "unable to set up cluster info endpoint")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -177,3 +277,18 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// getEnvBool returns the boolean value of the environment variable, or def if
+// it is not set or can not be parsed. It allows toggling the feature flags in
+// the config.env of an OLM Subscription.
+func getEnvBool(name string, def bool) bool {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
@@ -0,0 +1,495 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ringbuilder computes Swift rings in-process and reads and writes
+// them in the *.ring.gz format used by Swift. Only the ring files are
+// produced, the Python *.builder files are not.
+package ringbuilder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+const (
+	ringMagic   = "R1NG"
+	ringVersion = 1
+
+	// maxPartPower limits the partitions of a ring, a replica of a ring of
+	// the maximum part power takes 16 MiB
+	maxPartPower = 23
+	// maxReplicas limits the replicas of a ring read from a file
+	maxReplicas = 16
+	// maxHeaderLen limits the JSON header of a ring read from a file
+	maxHeaderLen = 64 << 20
+)
+
+// Device is a device of a ring
+type Device struct {
+	ID              int     `json:"id"`
+	Region          int     `json:"region"`
+	Zone            int     `json:"zone"`
+	IP              string  `json:"ip"`
+	Port            int32   `json:"port"`
+	ReplicationIP   string  `json:"replication_ip"`
+	ReplicationPort int32   `json:"replication_port"`
+	Device          string  `json:"device"`
+	Weight          float64 `json:"weight"`
+	Meta            string  `json:"meta"`
+	// Rings restricts the device to the rings of these kinds, e.g. account
+	// and container. It is not part of the ring format.
+	Rings []string `json:"-"`
+}
+
+// key identifies a device across rebuilds, the IDs may change
+func (d *Device) key() string {
+	return fmt.Sprintf("%s:%d/%s", d.IP, d.Port, d.Device)
+}
+
+// Ring is a Swift ring
+type Ring struct {
+	PartPower int
+	// Devices indexed by ID, removed devices leave a nil entry
+	Devices []*Device
+	// Replica2Part2Dev holds the device ID of each replica of each partition
+	Replica2Part2Dev [][]uint16
+}
+
+type ringHeader struct {
+	Devs         []*Device `json:"devs"`
+	PartShift    int       `json:"part_shift"`
+	ReplicaCount int       `json:"replica_count"`
+	ByteOrder    string    `json:"byteorder"`
+}
+
+// Build assigns the partitions of a ring with the given part power and number
+// of replicas to the devices, proportionally to their weight. The result only
+// depends on the arguments. If a previous ring is given, the assignments to
+// devices still present are kept as long as the device is not overloaded, to
+// minimize the data moved. Like min_part_hours of swift-ring-builder, at most
+// one replica of a partition is moved per build unless its device is gone, so
+// two replicas remain readable while the data is moved. A device may stay
+// over its quota until the following builds.
+func Build(partPower int, replicas int, devices []Device, previous *Ring) (*Ring, error) {
+	if partPower < 1 || partPower > maxPartPower {
+		return nil, fmt.Errorf("invalid part power %d", partPower)
+	}
+	if replicas < 1 {
+		return nil, fmt.Errorf("invalid number of replicas %d", replicas)
+	}
+
+	ring := &Ring{PartPower: partPower}
+	parts := 1 << partPower
+
+	// Keep the IDs of known devices, new ones get the lowest free ID
+	previousIDs := map[string]int{}
+	if previous != nil {
+		for _, d := range previous.Devices {
+			if d != nil {
+				previousIDs[d.key()] = d.ID
+			}
+		}
+	}
+	byKey := map[string]*Device{}
+	used := map[int]bool{}
+	pending := []*Device{}
+	for i := range devices {
+		d := devices[i]
+		if d.ReplicationIP == "" {
+			d.ReplicationIP = d.IP
+		}
+		if d.ReplicationPort == 0 {
+			d.ReplicationPort = d.Port
+		}
+		if _, ok := byKey[d.key()]; ok {
+			return nil, fmt.Errorf("duplicate device %s", d.key())
+		}
+		byKey[d.key()] = &d
+		if id, ok := previousIDs[d.key()]; ok {
+			d.ID = id
+			used[id] = true
+		} else {
+			pending = append(pending, &d)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].key() < pending[j].key() })
+	nextID := 0
+	for _, d := range pending {
+		for used[nextID] {
+			nextID++
+		}
+		d.ID = nextID
+		used[nextID] = true
+	}
+	for _, d := range byKey {
+		for len(ring.Devices) <= d.ID {
+			ring.Devices = append(ring.Devices, nil)
+		}
+		ring.Devices[d.ID] = d
+	}
+	if len(ring.Devices) > math.MaxUint16 {
+		return nil, errors.New("too many devices")
+	}
+
+	quota, err := getQuota(ring.Devices, parts*replicas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the previous assignments that are still valid
+	const unassigned = -1
+	assignment := make([][]int, replicas)
+	assigned := make([]int, len(ring.Devices))
+	for r := range assignment {
+		assignment[r] = make([]int, parts)
+		for p := range assignment[r] {
+			assignment[r][p] = unassigned
+		}
+	}
+	// moved records the partitions with a replica moved by this build
+	moved := make([]bool, parts)
+	if previous != nil && previous.PartPower == partPower {
+		for r := 0; r < replicas && r < len(previous.Replica2Part2Dev); r++ {
+			for p, oldID := range previous.Replica2Part2Dev[r] {
+				if int(oldID) >= len(previous.Devices) || previous.Devices[oldID] == nil {
+					moved[p] = true
+					continue
+				}
+				d, ok := byKey[previous.Devices[oldID].key()]
+				if !ok || quota[d.ID] == 0 || partitionHas(assignment, p, d.ID) {
+					moved[p] = true
+					continue
+				}
+				assignment[r][p] = d.ID
+				assigned[d.ID]++
+			}
+		}
+	}
+
+	// Unassign the replicas over the quota of a device, at most one per
+	// partition so the freed replicas are spread over the partitions and the
+	// other replicas stay in place
+	for dropped := true; dropped; {
+		dropped = false
+		for p := 0; p < parts; p++ {
+			if moved[p] {
+				continue
+			}
+			drop := unassigned
+			for r := 0; r < replicas; r++ {
+				id := assignment[r][p]
+				if id == unassigned || assigned[id] <= quota[id] {
+					continue
+				}
+				if drop == unassigned || assigned[id]-quota[id] > assigned[assignment[drop][p]]-quota[assignment[drop][p]] {
+					drop = r
+				}
+			}
+			if drop != unassigned {
+				assigned[assignment[drop][p]]--
+				assignment[drop][p] = unassigned
+				moved[p] = true
+				dropped = true
+			}
+		}
+	}
+
+	// Assign the remaining replicas, spreading the replicas of a partition
+	// over different devices, IPs, zones and regions where possible
+	for p := 0; p < parts; p++ {
+		for r := 0; r < replicas; r++ {
+			if assignment[r][p] != unassigned {
+				continue
+			}
+			best := unassigned
+			var bestScore [5]int
+			for _, d := range ring.Devices {
+				if d == nil || quota[d.ID] == 0 {
+					continue
+				}
+				score := [5]int{
+					countInPartition(ring.Devices, assignment, p, func(o *Device) bool { return o.ID == d.ID }),
+					countInPartition(ring.Devices, assignment, p, func(o *Device) bool { return o.Region == d.Region && o.Zone == d.Zone && o.IP == d.IP }),
+					countInPartition(ring.Devices, assignment, p, func(o *Device) bool { return o.Region == d.Region && o.Zone == d.Zone }),
+					countInPartition(ring.Devices, assignment, p, func(o *Device) bool { return o.Region == d.Region }),
+					assigned[d.ID] - quota[d.ID],
+				}
+				if best == unassigned || lessScore(score, bestScore) {
+					best = d.ID
+					bestScore = score
+				}
+			}
+			assignment[r][p] = best
+			assigned[best]++
+		}
+	}
+
+	ring.Replica2Part2Dev = make([][]uint16, replicas)
+	for r := range assignment {
+		ring.Replica2Part2Dev[r] = make([]uint16, parts)
+		for p, id := range assignment[r] {
+			ring.Replica2Part2Dev[r][p] = uint16(id)
+		}
+	}
+	return ring, nil
+}
+
+// getQuota returns the number of partition replicas each device should hold,
+// using the largest remainder method so the quotas add up to the total
+func getQuota(devices []*Device, total int) ([]int, error) {
+	weight := 0.0
+	for _, d := range devices {
+		if d != nil {
+			if d.Weight < 0 {
+				return nil, fmt.Errorf("negative weight of device %s", d.key())
+			}
+			weight += d.Weight
+		}
+	}
+	if weight == 0 {
+		return nil, errors.New("no devices with a weight")
+	}
+
+	quota := make([]int, len(devices))
+	remainders := []int{}
+	sum := 0
+	for _, d := range devices {
+		if d == nil {
+			continue
+		}
+		exact := d.Weight / weight * float64(total)
+		quota[d.ID] = int(math.Floor(exact))
+		sum += quota[d.ID]
+		if d.Weight > 0 {
+			remainders = append(remainders, d.ID)
+		}
+	}
+	sort.SliceStable(remainders, func(i, j int) bool {
+		a := devices[remainders[i]].Weight/weight*float64(total) - float64(quota[remainders[i]])
+		b := devices[remainders[j]].Weight/weight*float64(total) - float64(quota[remainders[j]])
+		if a != b {
+			return a > b
+		}
+		return remainders[i] < remainders[j]
+	})
+	for i := 0; sum < total; i++ {
+		quota[remainders[i%len(remainders)]]++
+		sum++
+	}
+	return quota, nil
+}
+
+func partitionHas(assignment [][]int, part int, id int) bool {
+	for r := range assignment {
+		if assignment[r][part] == id {
+			return true
+		}
+	}
+	return false
+}
+
+func countInPartition(devices []*Device, assignment [][]int, part int, match func(*Device) bool) int {
+	count := 0
+	for r := range assignment {
+		if id := assignment[r][part]; id >= 0 && match(devices[id]) {
+			count++
+		}
+	}
+	return count
+}
+
+func lessScore(a [5]int, b [5]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// Balance returns the largest difference in percent between the number of
+// partition replicas a device holds and the number it should hold given its
+// weight, the same metric as reported by swift-ring-builder
+func (ring *Ring) Balance() float64 {
+	weight := 0.0
+	for _, d := range ring.Devices {
+		if d != nil {
+			weight += d.Weight
+		}
+	}
+	if weight == 0 {
+		return 0
+	}
+
+	assigned := make([]int, len(ring.Devices))
+	total := 0
+	for _, part2dev := range ring.Replica2Part2Dev {
+		for _, id := range part2dev {
+			assigned[id]++
+			total++
+		}
+	}
+
+	balance := 0.0
+	for _, d := range ring.Devices {
+		if d == nil || d.Weight == 0 {
+			continue
+		}
+		wanted := d.Weight / weight * float64(total)
+		balance = math.Max(balance, math.Abs(float64(assigned[d.ID])-wanted)/wanted*100)
+	}
+	return balance
+}
+
+// Dispersion returns the percentage of partitions with more than one replica
+// on the same host, while there are enough hosts to avoid it
+func (ring *Ring) Dispersion() float64 {
+	hosts := map[string]bool{}
+	for _, d := range ring.Devices {
+		if d != nil && d.Weight > 0 {
+			hosts[d.IP] = true
+		}
+	}
+	replicas := len(ring.Replica2Part2Dev)
+	if replicas == 0 || len(hosts) < replicas {
+		return 0
+	}
+
+	parts := len(ring.Replica2Part2Dev[0])
+	dispersed := 0
+	for p := 0; p < parts; p++ {
+		seen := map[string]bool{}
+		for r := 0; r < replicas; r++ {
+			ip := ring.Devices[ring.Replica2Part2Dev[r][p]].IP
+			if seen[ip] {
+				dispersed++
+				break
+			}
+			seen[ip] = true
+		}
+	}
+	return float64(dispersed) / float64(parts) * 100
+}
+
+// Write serializes the ring in the gzipped v1 format of Swift. The gzip
+// header carries no timestamp, so the same ring always gives the same bytes.
+func (ring *Ring) Write(w io.Writer) error {
+	header, err := json.Marshal(ringHeader{
+		Devs:         ring.Devices,
+		PartShift:    32 - ring.PartPower,
+		ReplicaCount: len(ring.Replica2Part2Dev),
+		ByteOrder:    "little",
+	})
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	buf := &bytes.Buffer{}
+	buf.WriteString(ringMagic)
+	_ = binary.Write(buf, binary.BigEndian, uint16(ringVersion))
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(header)))
+	buf.Write(header)
+	for _, part2dev := range ring.Replica2Part2Dev {
+		_ = binary.Write(buf, binary.LittleEndian, part2dev)
+	}
+	if _, err := gz.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Read parses a ring written by Swift or by Write. The part power, the
+// replicas and the device IDs of the partitions are checked, a corrupt ring
+// is returned as an error.
+func Read(r io.Reader) (*Ring, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	magic := make([]byte, len(ringMagic))
+	if _, err := io.ReadFull(gz, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != ringMagic {
+		return nil, errors.New("not a ring file")
+	}
+	var version uint16
+	if err := binary.Read(gz, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != ringVersion {
+		return nil, fmt.Errorf("unsupported ring version %d", version)
+	}
+	var headerLen uint32
+	if err := binary.Read(gz, binary.BigEndian, &headerLen); err != nil {
+		return nil, err
+	}
+	if headerLen > maxHeaderLen {
+		return nil, fmt.Errorf("ring header of %d bytes exceeds %d", headerLen, maxHeaderLen)
+	}
+	data := make([]byte, headerLen)
+	if _, err := io.ReadFull(gz, data); err != nil {
+		return nil, err
+	}
+	header := ringHeader{}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	if header.PartShift < 32-maxPartPower || header.PartShift > 32 {
+		return nil, fmt.Errorf("invalid part shift %d", header.PartShift)
+	}
+	if header.ReplicaCount < 1 || header.ReplicaCount > maxReplicas || header.ReplicaCount > len(header.Devs) {
+		return nil, fmt.Errorf("invalid replica count %d for %d devices", header.ReplicaCount, len(header.Devs))
+	}
+	for i, d := range header.Devs {
+		if d != nil && d.ID != i {
+			return nil, fmt.Errorf("device %d has the ID %d", i, d.ID)
+		}
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if header.ByteOrder == "big" {
+		order = binary.BigEndian
+	}
+	ring := &Ring{
+		PartPower:        32 - header.PartShift,
+		Devices:          header.Devs,
+		Replica2Part2Dev: make([][]uint16, header.ReplicaCount),
+	}
+	for r := range ring.Replica2Part2Dev {
+		ring.Replica2Part2Dev[r] = make([]uint16, 1<<ring.PartPower)
+		if err := binary.Read(gz, order, ring.Replica2Part2Dev[r]); err != nil {
+			return nil, err
+		}
+		for part, id := range ring.Replica2Part2Dev[r] {
+			if int(id) >= len(ring.Devices) || ring.Devices[id] == nil {
+				return nil, fmt.Errorf("replica %d of partition %d is assigned to the unknown device %d", r, part, id)
+			}
+		}
+	}
+	return ring, nil
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ringbuilder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// newDevices returns a device per "<zone>/<ip>" entry, all with weight 100
+func newDevices(entries ...string) []Device {
+	devices := []Device{}
+	for i, e := range entries {
+		var zone int
+		var ip string
+		if _, err := fmt.Sscanf(e, "%d/%s", &zone, &ip); err != nil {
+			panic(err)
+		}
+		devices = append(devices, Device{
+			Region: 1,
+			Zone:   zone,
+			IP:     ip,
+			Port:   6200,
+			Device: fmt.Sprintf("d%d", i),
+			Weight: 100,
+		})
+	}
+	return devices
+}
+
+func mustBuild(t *testing.T, partPower int, replicas int, devices []Device, previous *Ring) *Ring {
+	t.Helper()
+	ring, err := Build(partPower, replicas, devices, previous)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return ring
+}
+
+func mustWrite(t *testing.T, ring *Ring) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	if err := ring.Write(buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// getAssigned returns the number of partition replicas of each device key
+func getAssigned(ring *Ring) map[string]int {
+	assigned := map[string]int{}
+	for _, part2dev := range ring.Replica2Part2Dev {
+		for _, id := range part2dev {
+			assigned[ring.Devices[id].key()]++
+		}
+	}
+	return assigned
+}
+
+// getMoved returns the number of replicas of each partition that are on
+// another device in the new ring
+func getMoved(previous *Ring, ring *Ring) []int {
+	moved := make([]int, len(ring.Replica2Part2Dev[0]))
+	for p := range moved {
+		before := map[string]bool{}
+		for r := range previous.Replica2Part2Dev {
+			before[previous.Devices[previous.Replica2Part2Dev[r][p]].key()] = true
+		}
+		for r := range ring.Replica2Part2Dev {
+			if !before[ring.Devices[ring.Replica2Part2Dev[r][p]].key()] {
+				moved[p]++
+			}
+		}
+	}
+	return moved
+}
+
+func TestGetQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+		total   int
+		want    []int
+		wantErr bool
+	}{
+		{name: "equal weights", weights: []float64{1, 1, 1}, total: 768, want: []int{256, 256, 256}},
+		{name: "remainders by ID", weights: []float64{1, 1, 1}, total: 10, want: []int{4, 3, 3}},
+		{name: "largest remainder", weights: []float64{1, 2, 2}, total: 8, want: []int{2, 3, 3}},
+		{name: "zero weight", weights: []float64{2, 1, 0}, total: 9, want: []int{6, 3, 0}},
+		{name: "removed device", weights: []float64{1, -1, 3}, total: 8, want: []int{2, 0, 6}},
+		{name: "no weight", weights: []float64{0, 0}, total: 8, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			devices := []*Device{}
+			for i, w := range tt.weights {
+				// A weight of -1 stands for the nil entry of a removed
+				// device
+				if w < 0 {
+					devices = append(devices, nil)
+					continue
+				}
+				devices = append(devices, &Device{ID: i, IP: "10.0.0.1", Device: fmt.Sprintf("d%d", i), Weight: w})
+			}
+			quota, err := getQuota(devices, tt.total)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("getQuota() = %v, want an error", quota)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getQuota() failed: %v", err)
+			}
+			if !reflect.DeepEqual(quota, tt.want) {
+				t.Errorf("getQuota() = %v, want %v", quota, tt.want)
+			}
+			sum := 0
+			for _, q := range quota {
+				sum += q
+			}
+			if sum != tt.total {
+				t.Errorf("getQuota() sums up to %d, want %d", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		partPower int
+		replicas  int
+		devices   []Device
+	}{
+		{name: "part power too low", partPower: 0, replicas: 1, devices: newDevices("1/10.0.0.1")},
+		{name: "part power too high", partPower: 24, replicas: 1, devices: newDevices("1/10.0.0.1")},
+		{name: "no replicas", partPower: 8, replicas: 0, devices: newDevices("1/10.0.0.1")},
+		{name: "no devices", partPower: 8, replicas: 1, devices: []Device{}},
+		{name: "duplicate device", partPower: 8, replicas: 1, devices: append(newDevices("1/10.0.0.1"), newDevices("1/10.0.0.1")...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Build(tt.partPower, tt.replicas, tt.devices, nil); err == nil {
+				t.Errorf("Build() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestBuildDeterministic(t *testing.T) {
+	devices := newDevices("1/10.0.0.1", "1/10.0.0.2", "2/10.0.0.3", "2/10.0.0.4", "3/10.0.0.5")
+	reversed := []Device{}
+	for i := len(devices) - 1; i >= 0; i-- {
+		reversed = append(reversed, devices[i])
+	}
+
+	first := mustWrite(t, mustBuild(t, 8, 3, devices, nil))
+	if second := mustWrite(t, mustBuild(t, 8, 3, devices, nil)); !bytes.Equal(first, second) {
+		t.Errorf("Build() of the same devices gave different rings")
+	}
+	if third := mustWrite(t, mustBuild(t, 8, 3, reversed, nil)); !bytes.Equal(first, third) {
+		t.Errorf("Build() depends on the order of the devices")
+	}
+}
+
+func TestBuildStableIDs(t *testing.T) {
+	devices := newDevices("1/10.0.0.2", "1/10.0.0.3", "1/10.0.0.4")
+	previous := mustBuild(t, 8, 2, devices, nil)
+
+	// 10.0.0.1 sorts first but gets the ID freed by 10.0.0.3, the other
+	// devices keep their IDs
+	changed := append(newDevices("1/10.0.0.1"), devices[0], devices[2])
+	changed[0].Device = "new"
+	ring := mustBuild(t, 8, 2, changed, previous)
+
+	ids := map[string]int{}
+	for _, d := range ring.Devices {
+		if d != nil {
+			ids[d.key()] = d.ID
+		}
+	}
+	for _, d := range previous.Devices {
+		if d.IP == "10.0.0.3" {
+			if ids[changed[0].key()] != d.ID {
+				t.Errorf("new device got ID %d, want the free ID %d", ids[changed[0].key()], d.ID)
+			}
+			continue
+		}
+		if ids[d.key()] != d.ID {
+			t.Errorf("device %s changed its ID from %d to %d", d.key(), d.ID, ids[d.key()])
+		}
+	}
+}
+
+func TestBuildMovement(t *testing.T) {
+	devices := newDevices("1/10.0.0.1", "1/10.0.0.2", "2/10.0.0.3", "2/10.0.0.4", "3/10.0.0.5", "3/10.0.0.6")
+	tests := []struct {
+		name    string
+		devices []Device
+	}{
+		{name: "device added", devices: append(append([]Device{}, devices...), newDevices("3/10.0.0.7")...)},
+		{name: "device removed", devices: devices[:5]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := mustBuild(t, 8, 3, devices, nil)
+			ring := mustBuild(t, 8, 3, tt.devices, previous)
+
+			total := 0
+			for p, m := range getMoved(previous, ring) {
+				if m > 1 {
+					t.Errorf("%d replicas of partition %d moved, want at most 1", m, p)
+				}
+				total += m
+			}
+
+			// Only the replicas gained by a device are moved, the devices
+			// that keep or lose replicas are not refilled
+			before := getAssigned(previous)
+			gained := 0
+			for key, n := range getAssigned(ring) {
+				if n > before[key] {
+					gained += n - before[key]
+				}
+			}
+			if total != gained {
+				t.Errorf("%d replicas moved, want %d", total, gained)
+			}
+		})
+	}
+}
+
+func TestBuildConverges(t *testing.T) {
+	devices := newDevices("1/10.0.0.1", "2/10.0.0.2", "3/10.0.0.3")
+	ring := mustBuild(t, 8, 3, devices, nil)
+
+	// Doubling the devices requires moving half of the replicas, more than
+	// one per partition, so it takes several builds
+	devices = append(devices, newDevices("1/10.0.0.4", "2/10.0.0.5", "3/10.0.0.6")...)
+	for i := 0; i < 3; i++ {
+		ring = mustBuild(t, 8, 3, devices, ring)
+	}
+	for key, n := range getAssigned(ring) {
+		if n != 128 {
+			t.Errorf("device %s has %d replicas after 3 builds, want 128", key, n)
+		}
+	}
+}
+
+func TestBuildDispersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int
+		devices  []Device
+		// distinct returns the failure domain of a device
+		distinct func(*Device) string
+	}{
+		{
+			name:     "zones",
+			replicas: 3,
+			devices:  newDevices("1/10.0.0.1", "1/10.0.0.2", "2/10.0.0.3", "2/10.0.0.4", "3/10.0.0.5", "3/10.0.0.6"),
+			distinct: func(d *Device) string { return fmt.Sprint(d.Zone) },
+		},
+		{
+			name:     "hosts",
+			replicas: 2,
+			devices:  newDevices("1/10.0.0.1", "1/10.0.0.1", "1/10.0.0.2"),
+			distinct: func(d *Device) string { return d.IP },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Devices on the same host need distinct names
+			for i := range tt.devices {
+				tt.devices[i].Device = fmt.Sprintf("d%d", i)
+			}
+			ring := mustBuild(t, 8, tt.replicas, tt.devices, nil)
+			for p := range ring.Replica2Part2Dev[0] {
+				seen := map[string]bool{}
+				for r := range ring.Replica2Part2Dev {
+					domain := tt.distinct(ring.Devices[ring.Replica2Part2Dev[r][p]])
+					if seen[domain] {
+						t.Fatalf("partition %d has 2 replicas in %s", p, domain)
+					}
+					seen[domain] = true
+				}
+			}
+			if dispersion := ring.Dispersion(); dispersion != 0 {
+				t.Errorf("Dispersion() = %.2f, want 0", dispersion)
+			}
+		})
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	ring := mustBuild(t, 6, 3, newDevices("1/10.0.0.1", "2/10.0.0.2", "3/10.0.0.3", "3/10.0.0.4"), nil)
+	read, err := Read(bytes.NewReader(mustWrite(t, ring)))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !reflect.DeepEqual(read, ring) {
+		t.Errorf("Read() = %+v, want %+v", read, ring)
+	}
+}
+
+// testdata/object.ring.gz is serialized like RingData.serialize_v1 of Swift
+// does, with a sorted JSON header, a removed device and a replication
+// network
+func TestReadSwiftRing(t *testing.T) {
+	data, err := os.ReadFile("testdata/object.ring.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	want := &Ring{
+		PartPower: 2,
+		Devices: []*Device{
+			{ID: 0, Region: 1, Zone: 1, IP: "10.0.0.1", Port: 6200, ReplicationIP: "10.0.0.1", ReplicationPort: 6200, Device: "d1", Weight: 100},
+			nil,
+			{ID: 2, Region: 1, Zone: 2, IP: "10.0.0.2", Port: 6200, ReplicationIP: "10.0.1.2", ReplicationPort: 6300, Device: "d2", Weight: 50.5, Meta: "ssd"},
+		},
+		Replica2Part2Dev: [][]uint16{{0, 2, 0, 2}, {2, 0, 2, 0}},
+	}
+	if !reflect.DeepEqual(ring, want) {
+		t.Errorf("Read() = %+v, want %+v", ring, want)
+	}
+	if balance := ring.Balance(); balance < 49 || balance > 49.1 {
+		t.Errorf("Balance() = %.2f, want about 49.03", balance)
+	}
+
+	// Written again, the ring reads back the same
+	read, err := Read(bytes.NewReader(mustWrite(t, ring)))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !reflect.DeepEqual(read, want) {
+		t.Errorf("Read() after Write() = %+v, want %+v", read, want)
+	}
+}
+
+func TestBuildUnchanged(t *testing.T) {
+	devices := newDevices("1/10.0.0.1", "1/10.0.0.2", "2/10.0.0.3", "2/10.0.0.4", "3/10.0.0.5")
+	previous := mustBuild(t, 8, 3, devices, nil)
+	ring := mustBuild(t, 8, 3, devices, previous)
+	if !reflect.DeepEqual(ring.Replica2Part2Dev, previous.Replica2Part2Dev) {
+		t.Errorf("Build() with the same devices moved partitions of the previous ring")
+	}
+}
+
+// writeRaw serializes a ring like Write does, without checking it
+func writeRaw(t *testing.T, header string, replica2Part2Dev [][]uint16) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	raw := &bytes.Buffer{}
+	raw.WriteString(ringMagic)
+	_ = binary.Write(raw, binary.BigEndian, uint16(ringVersion))
+	_ = binary.Write(raw, binary.BigEndian, uint32(len(header)))
+	raw.WriteString(header)
+	for _, part2dev := range replica2Part2Dev {
+		_ = binary.Write(raw, binary.LittleEndian, part2dev)
+	}
+	if _, err := gz.Write(raw.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadCorrupt(t *testing.T) {
+	devs := `"devs": [{"id": 0, "ip": "10.0.0.1", "device": "d0"}, {"id": 1, "ip": "10.0.0.2", "device": "d1"}]`
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "not gzip", data: []byte("R1NG")},
+		{name: "negative part shift", data: writeRaw(t, `{`+devs+`, "part_shift": -1, "replica_count": 1}`, nil)},
+		{name: "part shift above 32", data: writeRaw(t, `{`+devs+`, "part_shift": 33, "replica_count": 1}`, nil)},
+		{name: "part power too high", data: writeRaw(t, `{`+devs+`, "part_shift": 0, "replica_count": 1}`, nil)},
+		{name: "negative replica count", data: writeRaw(t, `{`+devs+`, "part_shift": 31, "replica_count": -1}`, nil)},
+		{name: "more replicas than devices", data: writeRaw(t, `{`+devs+`, "part_shift": 31, "replica_count": 3}`, nil)},
+		{name: "device ID mismatch", data: writeRaw(t, `{"devs": [{"id": 1, "ip": "10.0.0.1", "device": "d0"}], "part_shift": 31, "replica_count": 1}`, [][]uint16{{0, 0}})},
+		{name: "unknown device", data: writeRaw(t, `{`+devs+`, "part_shift": 31, "replica_count": 1}`, [][]uint16{{0, 2}})},
+		{name: "removed device", data: writeRaw(t, `{"devs": [{"id": 0, "ip": "10.0.0.1", "device": "d0"}, null], "part_shift": 31, "replica_count": 1}`, [][]uint16{{0, 1}})},
+		{name: "truncated partitions", data: writeRaw(t, `{`+devs+`, "part_shift": 31, "replica_count": 2}`, [][]uint16{{0, 1}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ring, err := Read(bytes.NewReader(tt.data)); err == nil {
+				t.Errorf("Read() = %+v, want an error", ring)
+			}
+		})
+	}
+
+	// The same header with valid partitions is read
+	if _, err := Read(bytes.NewReader(writeRaw(t, `{`+devs+`, "part_shift": 31, "replica_count": 2}`, [][]uint16{{0, 1}, {1, 0}}))); err != nil {
+		t.Errorf("Read() of a valid ring failed: %v", err)
+	}
+}
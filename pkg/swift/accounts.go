@@ -0,0 +1,62 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"encoding/json"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetLabelsAccount returns the labels of the SwiftAccount Jobs
+func GetLabelsAccount() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftAccount"}
+}
+
+// GetLabelsContainer returns the labels of the SwiftContainer Jobs
+func GetLabelsContainer() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftContainer"}
+}
+
+// GetAccountName returns the Swift account of the SwiftAccount
+func GetAccountName(instance *swiftv1beta1.SwiftAccount) string {
+	return "AUTH_" + instance.Spec.ProjectID
+}
+
+// GetAccountACL returns the account ACL in the JSON format of the account
+// access control system metadata, empty without any grant
+func GetAccountACL(acl *swiftv1beta1.SwiftAccountACL) string {
+	if acl == nil {
+		return ""
+	}
+	grants := map[string][]string{}
+	for key, users := range map[string][]string{
+		"read-only":  acl.ReadOnly,
+		"read-write": acl.ReadWrite,
+		"admin":      acl.Admin,
+	} {
+		if len(users) > 0 {
+			grants[key] = users
+		}
+	}
+	if len(grants) == 0 {
+		return ""
+	}
+	// json.Marshal sorts the keys, the value is stable
+	data, _ := json.Marshal(grants)
+	return string(data)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+)
+
+// AdoptObject attaches a controller reference to a pre-existing object
+// without one, e.g. created by hand before the operator was deployed. Only
+// objects carrying the expected labels are adopted. The managed fields of
+// the previous owner are reset, so the hand-made spec is not reported as
+// drift afterwards.
+func AdoptObject(
+	ctx context.Context,
+	h *helper.Helper,
+	obj client.Object,
+	labels map[string]string,
+) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		if owner.UID == h.GetBeforeObject().GetUID() {
+			return nil
+		}
+		return fmt.Errorf("%s %s is controlled by %s %s, not adopting it", kind, obj.GetName(), owner.Kind, owner.Name)
+	}
+
+	if !k8slabels.SelectorFromSet(labels).Matches(k8slabels.Set(obj.GetLabels())) {
+		return fmt.Errorf("%s %s exists without the labels %v, not adopting it", kind, obj.GetName(), labels)
+	}
+
+	err := h.GetClient().Patch(ctx, obj, client.RawPatch(types.MergePatchType, []byte(`{"metadata":{"managedFields":[{}]}}`)))
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if err := controllerutil.SetControllerReference(h.GetBeforeObject(), obj, h.GetScheme()); err != nil {
+		return err
+	}
+	if err := h.GetClient().Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+
+	h.GetLogger().Info(fmt.Sprintf("Adopted %s %s", kind, obj.GetName()))
+	return nil
+}
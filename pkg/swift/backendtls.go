@@ -0,0 +1,165 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// BackendCAConfigMapName is the ConfigMap with the CA certificates of
+	// the storage servers serving TLS, shared by all SwiftStorages of the
+	// namespace. It also has the sitecustomize.py that makes the Python
+	// clients of the storage servers connect to them with TLS.
+	BackendCAConfigMapName = "swift-backend-ca"
+
+	// BackendTLSMountPath is the path of the backend CA ConfigMap, it is
+	// on the PYTHONPATH of the clients of the storage servers
+	BackendTLSMountPath = "/var/lib/config-data/backend-tls"
+
+	backendTLSVolume = "backend-tls"
+	sitecustomizeKey = "sitecustomize.py"
+)
+
+// getBackendCAKeys returns the keys of the CA certificate of a SwiftStorage,
+// one per headless Service of its pods
+func getBackendCAKeys(instance *swiftv1beta1.SwiftStorage) []string {
+	keys := []string{instance.Name + ".crt"}
+	if instance.Spec.MetadataTier != nil {
+		keys = append(keys, GetMetadataTierName(instance.Name)+".crt")
+	}
+	return keys
+}
+
+// EnsureBackendCA sets the ca.crt of the storage server certificate of the
+// SwiftStorage in the backend CA ConfigMap, or removes it if TLS is
+// disabled. The certificates of a deleted SwiftStorage are kept until the
+// last owner of the ConfigMap is deleted. It returns false while the certificate Secret does not exist
+// yet, e.g. until cert-manager issued it.
+func EnsureBackendCA(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftStorage,
+) (bool, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BackendCAConfigMapName,
+			Namespace: instance.Namespace,
+		},
+	}
+
+	if instance.Spec.TLS == nil {
+		err := h.GetClient().Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, cm)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+		_, err = controllerutil.CreateOrPatch(ctx, h.GetClient(), cm, func() error {
+			for _, k := range getBackendCAKeys(instance) {
+				delete(cm.Data, k)
+			}
+			return nil
+		})
+		return err == nil, err
+	}
+
+	secret := &corev1.Secret{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: GetStorageTLSSecretName(instance), Namespace: instance.Namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	ca, ok := secret.Data["ca.crt"]
+	if !ok {
+		return false, fmt.Errorf("Secret %s has no ca.crt, the clients of the storage servers can not verify them", secret.Name)
+	}
+
+	sitecustomize, err := util.ExecuteTemplateFile("common/"+sitecustomizeKey, nil)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = controllerutil.CreateOrPatch(ctx, h.GetClient(), cm, func() error {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[sitecustomizeKey] = sitecustomize
+		for _, k := range getBackendCAKeys(instance) {
+			cm.Data[k] = string(ca)
+		}
+
+		// Every SwiftStorage with TLS owns the ConfigMap, it is deleted
+		// with the last of them
+		return controllerutil.SetOwnerReference(h.GetBeforeObject(), cm, h.GetScheme())
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AddBackendTLS mounts the backend CA ConfigMap in all containers of the
+// pod and puts it on their PYTHONPATH. The ConfigMap is optional, without
+// a SwiftStorage serving TLS all clients connect with HTTP.
+func AddBackendTLS(spec *corev1.PodSpec) {
+	optional := true
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: backendTLSVolume,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: BackendCAConfigMapName,
+				},
+				Optional: &optional,
+			},
+		},
+	})
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      backendTLSVolume,
+			MountPath: BackendTLSMountPath,
+			ReadOnly:  true,
+		})
+		addPythonPath(c, BackendTLSMountPath)
+	}
+}
+
+// addPythonPath appends the path to the PYTHONPATH of the container
+func addPythonPath(c *corev1.Container, path string) {
+	for i := range c.Env {
+		if c.Env[i].Name == "PYTHONPATH" {
+			c.Env[i].Value = c.Env[i].Value + ":" + path
+			return
+		}
+	}
+	c.Env = append(c.Env, corev1.EnvVar{Name: "PYTHONPATH", Value: path})
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// SetBreakGlassCondition sets the BreakGlassEnabled condition while the
+// break-glass pool is deployed and removes it otherwise
+func SetBreakGlassCondition(conditions *condition.Conditions, breakGlass *swiftv1beta1.SwiftProxyBreakGlass, service string) {
+	if breakGlass == nil {
+		conditions.Remove(swiftv1beta1.BreakGlassEnabledCondition)
+		return
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.BreakGlassEnabledCondition,
+		fmt.Sprintf(swiftv1beta1.BreakGlassEnabledMessage, service, metav1.FormatLabelSelector(&breakGlass.AllowedClients))))
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// The cert-manager API is not vendored, unstructured objects are used to
+// avoid a dependency on the cert-manager module
+var certificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// TLSMountPath is the path of the server certificate in the storage and
+// proxy pods
+const TLSMountPath = "/var/lib/config-data/tls"
+
+func newCertificate(name string, namespace string) *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(certificateGVK)
+	cert.SetName(name)
+	cert.SetNamespace(namespace)
+	return cert
+}
+
+// GetStorageTLSSecretName returns the name of the Secret with the storage
+// server certificate, empty if TLS is disabled
+func GetStorageTLSSecretName(instance *swiftv1beta1.SwiftStorage) string {
+	if instance.Spec.TLS == nil {
+		return ""
+	}
+	if instance.Spec.TLS.SecretName != "" {
+		return GetLocalSecretName(instance.Spec.TLS.SecretName)
+	}
+	return instance.Name + "-tls"
+}
+
+// GetProxyTLSSecretName returns the name of the Secret with the proxy
+// certificate, empty if TLS is disabled
+func GetProxyTLSSecretName(instance *swiftv1beta1.SwiftProxy) string {
+	if instance.Spec.TLS == nil {
+		return ""
+	}
+	if instance.Spec.TLS.SecretName != "" {
+		return GetLocalSecretName(instance.Spec.TLS.SecretName)
+	}
+	return instance.Name + "-tls"
+}
+
+// GetProxyRouteTLSSecretName returns the name of the Secret with the
+// certificate of the Route, empty if the Route serves the router certificate
+func GetProxyRouteTLSSecretName(instance *swiftv1beta1.SwiftProxy) string {
+	if instance.Spec.TLS == nil {
+		return ""
+	}
+	if instance.Spec.TLS.RouteSecretName != "" {
+		return GetLocalSecretName(instance.Spec.TLS.RouteSecretName)
+	}
+	if instance.Spec.TLS.RouteIssuer != "" {
+		return instance.Name + "-route-tls"
+	}
+	return ""
+}
+
+// CreateOrPatchStorageCertificate requests the storage server certificate
+// from the cert-manager issuer. The certificate is valid for the Services and
+// the names of the storage pods in the headless Services, including the one
+// of the metadata tier.
+func CreateOrPatchStorageCertificate(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftStorage,
+	labels map[string]string,
+) error {
+	services := []string{instance.Name}
+	if instance.Spec.MetadataTier != nil {
+		services = append(services, GetMetadataTierName(instance.Name))
+	}
+	dnsNames := []interface{}{}
+	for _, name := range services {
+		dnsNames = append(dnsNames,
+			name,
+			fmt.Sprintf("*.%s", name),
+			fmt.Sprintf("%s.%s.svc", name, instance.Namespace),
+			fmt.Sprintf("*.%s.%s.svc", name, instance.Namespace))
+	}
+	return createOrPatchCertificate(ctx, h, instance.Name, instance.Namespace, labels,
+		GetStorageTLSSecretName(instance), dnsNames,
+		instance.Spec.TLS.Issuer, instance.Spec.TLS.IssuerKind)
+}
+
+// CreateOrPatchProxyCertificate requests the proxy certificate from the
+// cert-manager issuer. The certificate is valid for the Services of all
+// endpoints, which are shared by the proxy pools.
+func CreateOrPatchProxyCertificate(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftProxy,
+	labels map[string]string,
+) error {
+	dnsNames := []interface{}{}
+	for _, e := range []string{"admin", "internal", "public"} {
+		name := fmt.Sprintf("%s-%s", ServiceName, e)
+		dnsNames = append(dnsNames, name, fmt.Sprintf("%s.%s.svc", name, instance.Namespace))
+	}
+	return createOrPatchCertificate(ctx, h, instance.Name, instance.Namespace, labels,
+		GetProxyTLSSecretName(instance), dnsNames,
+		instance.Spec.TLS.Issuer, instance.Spec.TLS.IssuerKind)
+}
+
+// CreateOrPatchProxyRouteCertificate requests the certificate of the Route
+// of the public endpoint from the cert-manager issuer of the Route
+func CreateOrPatchProxyRouteCertificate(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftProxy,
+	labels map[string]string,
+	hostname string,
+) error {
+	return createOrPatchCertificate(ctx, h, instance.Name+"-route", instance.Namespace, labels,
+		GetProxyRouteTLSSecretName(instance), []interface{}{hostname},
+		instance.Spec.TLS.RouteIssuer, instance.Spec.TLS.RouteIssuerKind)
+}
+
+func createOrPatchCertificate(
+	ctx context.Context,
+	h *helper.Helper,
+	name string,
+	namespace string,
+	labels map[string]string,
+	secretName string,
+	dnsNames []interface{},
+	issuer string,
+	issuerKind string,
+) error {
+	cert := newCertificate(name, namespace)
+
+	op, err := controllerutil.CreateOrPatch(ctx, h.GetClient(), cert, func() error {
+		cert.SetLabels(labels)
+		err := unstructured.SetNestedMap(cert.Object, map[string]interface{}{
+			"secretName": secretName,
+			"dnsNames":   dnsNames,
+			"issuerRef": map[string]interface{}{
+				"group": "cert-manager.io",
+				"kind":  issuerKind,
+				"name":  issuer,
+			},
+		}, "spec")
+		if err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(h.GetBeforeObject(), cert, h.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		h.GetLogger().Info(fmt.Sprintf("Certificate %s - %s", name, op))
+	}
+	return nil
+}
+
+// DeleteCertificate deletes the Certificate of a storage or proxy, not
+// finding it or the CRD being missing is not an error
+func DeleteCertificate(
+	ctx context.Context,
+	h *helper.Helper,
+	name string,
+	namespace string,
+) error {
+	err := h.GetClient().Delete(ctx, newCertificate(name, namespace))
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("Error deleting Certificate %s: %w", name, err)
+	}
+	return nil
+}
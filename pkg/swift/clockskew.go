@@ -0,0 +1,72 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetClockSkewedPods returns the names of the pods matching the given labels
+// whose clock differs from the API server clock by more than threshold
+// seconds. The ring-sync script records the difference as pod annotation.
+func GetClockSkewedPods(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	labels map[string]string,
+	threshold int64,
+) ([]string, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	skewed := []string{}
+	for _, p := range podList.Items {
+		skew, err := strconv.ParseInt(p.Annotations[ClockSkewAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		if skew > threshold || skew < -threshold {
+			skewed = append(skewed, p.Name)
+		}
+	}
+	sort.Strings(skewed)
+	return skewed, nil
+}
+
+// SetClockSkewCondition sets the ClockSkewDetected condition if any pod is
+// skewed and removes it otherwise
+func SetClockSkewCondition(conditions *condition.Conditions, skewed []string, threshold int64) {
+	if len(skewed) == 0 {
+		conditions.Remove(swiftv1beta1.ClockSkewDetectedCondition)
+		return
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.ClockSkewDetectedCondition,
+		fmt.Sprintf(swiftv1beta1.ClockSkewDetectedMessage, threshold, strings.Join(skewed, ", "))))
+}
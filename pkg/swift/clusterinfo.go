@@ -0,0 +1,241 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// ClusterInfoPath is the path the cluster info summary is served on
+const ClusterInfoPath = "/swift-cluster-info"
+
+// ClusterInfo - summary of all Swift CRs managed by the operator
+type ClusterInfo struct {
+	Swifts        []SwiftInfo        `json:"swifts"`
+	SwiftRings    []SwiftRingInfo    `json:"swiftRings"`
+	SwiftStorages []SwiftStorageInfo `json:"swiftStorages"`
+	SwiftProxies  []SwiftProxyInfo   `json:"swiftProxies"`
+}
+
+// ObjectInfo - common fields of all summaries
+type ObjectInfo struct {
+	Name       string               `json:"name"`
+	Namespace  string               `json:"namespace"`
+	Owner      string               `json:"owner,omitempty"`
+	Ready      bool                 `json:"ready"`
+	Conditions condition.Conditions `json:"conditions,omitempty"`
+}
+
+// SwiftInfo - summary of a Swift CR
+type SwiftInfo struct {
+	ObjectInfo `json:",inline"`
+}
+
+// SwiftRingInfo - summary of a SwiftRing CR
+type SwiftRingInfo struct {
+	ObjectInfo   `json:",inline"`
+	RingReplicas int64             `json:"ringReplicas"`
+	Hash         map[string]string `json:"hash,omitempty"`
+}
+
+// SwiftStorageInfo - summary of a SwiftStorage CR
+type SwiftStorageInfo struct {
+	ObjectInfo `json:",inline"`
+	Replicas   int32                                  `json:"replicas"`
+	Capacity   string                                 `json:"capacity"`
+	RingSync   map[string]swiftv1beta1.RingSyncStatus `json:"ringSync,omitempty"`
+}
+
+// SwiftProxyInfo - summary of a SwiftProxy CR
+type SwiftProxyInfo struct {
+	ObjectInfo   `json:",inline"`
+	Replicas     int32                        `json:"replicas"`
+	APIEndpoints map[string]map[string]string `json:"apiEndpoints,omitempty"`
+}
+
+// ClusterInfoServer serves a read-only JSON summary of all Swift CRs, their
+// rings, capacity and health, intended for dashboards and automation
+type ClusterInfoServer struct {
+	client  client.Reader
+	address string
+	log     logr.Logger
+}
+
+// NewClusterInfoServer returns an initialized ClusterInfoServer.
+func NewClusterInfoServer(
+	c client.Reader,
+	address string,
+	log logr.Logger,
+) *ClusterInfoServer {
+	return &ClusterInfoServer{
+		client:  c,
+		address: address,
+		log:     log,
+	}
+}
+
+// NeedLeaderElection - the summary is served by all operator replicas
+func (s *ClusterInfoServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the cluster info until the context is cancelled
+func (s *ClusterInfoServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(ClusterInfoPath, s)
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ln, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			s.log.Error(err, "Error shutting down cluster info server")
+		}
+	}()
+
+	s.log.Info("Serving Swift cluster info", "address", s.address, "path", ClusterInfoPath)
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *ClusterInfoServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	info, err := GetClusterInfo(req.Context(), s.client)
+	if err != nil {
+		s.log.Error(err, "Error collecting Swift cluster info")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		s.log.Error(err, "Error writing Swift cluster info")
+	}
+}
+
+// GetClusterInfo collects the summary of all Swift CRs in all namespaces
+func GetClusterInfo(ctx context.Context, c client.Reader) (*ClusterInfo, error) {
+	info := &ClusterInfo{
+		Swifts:        []SwiftInfo{},
+		SwiftRings:    []SwiftRingInfo{},
+		SwiftStorages: []SwiftStorageInfo{},
+		SwiftProxies:  []SwiftProxyInfo{},
+	}
+
+	swifts := &swiftv1beta1.SwiftList{}
+	if err := c.List(ctx, swifts); err != nil {
+		return nil, err
+	}
+	for _, cr := range swifts.Items {
+		info.Swifts = append(info.Swifts, SwiftInfo{
+			ObjectInfo: getObjectInfo(cr.ObjectMeta, cr.Status.Conditions),
+		})
+	}
+
+	swiftRings := &swiftv1beta1.SwiftRingList{}
+	if err := c.List(ctx, swiftRings); err != nil {
+		return nil, err
+	}
+	for _, cr := range swiftRings.Items {
+		info.SwiftRings = append(info.SwiftRings, SwiftRingInfo{
+			ObjectInfo:   getObjectInfo(cr.ObjectMeta, cr.Status.Conditions),
+			RingReplicas: cr.Spec.RingReplicas,
+			Hash:         cr.Status.Hash,
+		})
+	}
+
+	swiftStorages := &swiftv1beta1.SwiftStorageList{}
+	if err := c.List(ctx, swiftStorages); err != nil {
+		return nil, err
+	}
+	for _, cr := range swiftStorages.Items {
+		info.SwiftStorages = append(info.SwiftStorages, SwiftStorageInfo{
+			ObjectInfo: getObjectInfo(cr.ObjectMeta, cr.Status.Conditions),
+			Replicas:   cr.Spec.Replicas,
+			Capacity:   getRequestedCapacity(cr.Spec.StorageRequest, cr.Spec.Replicas),
+			RingSync:   cr.Status.RingSync,
+		})
+	}
+
+	swiftProxies := &swiftv1beta1.SwiftProxyList{}
+	if err := c.List(ctx, swiftProxies); err != nil {
+		return nil, err
+	}
+	for _, cr := range swiftProxies.Items {
+		info.SwiftProxies = append(info.SwiftProxies, SwiftProxyInfo{
+			ObjectInfo:   getObjectInfo(cr.ObjectMeta, cr.Status.Conditions),
+			Replicas:     cr.Spec.Replicas,
+			APIEndpoints: cr.Status.APIEndpoints,
+		})
+	}
+
+	return info, nil
+}
+
+func getObjectInfo(meta metav1.ObjectMeta, conditions condition.Conditions) ObjectInfo {
+	info := ObjectInfo{
+		Name:       meta.Name,
+		Namespace:  meta.Namespace,
+		Ready:      conditions.IsTrue(condition.ReadyCondition),
+		Conditions: conditions,
+	}
+	if owner := metav1.GetControllerOfNoCopy(&meta); owner != nil {
+		info.Owner = owner.Kind + "/" + owner.Name
+	}
+	return info
+}
+
+// getRequestedCapacity returns the total capacity requested by all replicas,
+// or an empty string if the storage request can not be parsed
+func getRequestedCapacity(storageRequest string, replicas int32) string {
+	q, err := resource.ParseQuantity(storageRequest)
+	if err != nil {
+		return ""
+	}
+	return resource.NewQuantity(q.Value()*int64(replicas), q.Format).String()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetLabelsConsistencyCheck returns the labels of the consistency check pods
+func GetLabelsConsistencyCheck() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftConsistencyCheck"}
+}
+
+// GetConsistencyResult returns the latest result recorded by the consistency
+// check pods matching the given labels, or nil if no check finished yet
+func GetConsistencyResult(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	labels map[string]string,
+) (*swiftv1beta1.SwiftProxyConsistencyResult, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *swiftv1beta1.SwiftProxyConsistencyResult
+	for _, p := range podList.Items {
+		annotation, ok := p.Annotations[ConsistencyAnnotation]
+		if !ok {
+			continue
+		}
+		result := &swiftv1beta1.SwiftProxyConsistencyResult{}
+		if err := json.Unmarshal([]byte(annotation), result); err != nil {
+			// invalid results are skipped
+			continue
+		}
+		if latest == nil || result.Timestamp > latest.Timestamp {
+			latest = result
+		}
+	}
+	return latest, nil
+}
+
+// SetConsistencyCondition sets the ConsistencyDivergenceDetected condition if
+// the result shows any divergence and removes it otherwise
+func SetConsistencyCondition(conditions *condition.Conditions, result *swiftv1beta1.SwiftProxyConsistencyResult) {
+	if result == nil || (result.ContainerCountMismatches == 0 && result.ObjectsMissing == 0 && result.ObjectsUnderReplicated == 0) {
+		conditions.Remove(swiftv1beta1.ConsistencyDivergenceDetectedCondition)
+		return
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.ConsistencyDivergenceDetectedCondition,
+		fmt.Sprintf(swiftv1beta1.ConsistencyDivergenceDetectedMessage, result.Timestamp,
+			result.ContainerCountMismatches, result.ObjectsMissing, result.ObjectsUnderReplicated)))
+}
@@ -15,6 +15,10 @@ limitations under the License.
 
 package swift
 
+import (
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
 const (
 	RunAsUser     int64 = 42445
 	ProxyPort     int32 = 8080
@@ -24,11 +28,64 @@ const (
 	ContainerServerPort int32 = 6201
 	ObjectServerPort    int32 = 6200
 	RsyncPort           int32 = 873
+	RsyncMetricsPort    int32 = 9102
+	StorageMetricsPort  int32 = 9103
+	ProxyMetricsPort    int32 = 9104
+	ProxyStatsdPort     int32 = 8125
 
 	ServiceName        = "swift"
 	ServiceType        = "object-store"
 	ServiceAccount     = "swift-swift"
 	ServiceDescription = "Swift Object Storage"
 
-	ClaimName = "srv"
+	ClaimName      = "srv"
+	CrashClaimName = "crash"
+
+	// DefaultStorageRequest is the size of the storage PVCs when the
+	// defaulting webhook did not set the storage request
+	DefaultStorageRequest = "10Gi"
+
+	DebugContainerName = "swift-debug"
+
+	ProxyRequestRateMetric = "swift_proxy_server_requests_per_second"
+	ProxyLatencyMetric     = "swift_proxy_server_request_p95_latency_seconds"
+
+	RingMd5Annotation           = "swift.openstack.org/ring-md5"
+	RingSyncTimestampAnnotation = "swift.openstack.org/ring-sync-timestamp"
+	ClockSkewAnnotation         = "swift.openstack.org/clock-skew"
+	ConsistencyAnnotation       = "swift.openstack.org/consistency"
+	PartitionsAnnotation        = "swift.openstack.org/partitions"
+	ReplicationAnnotation       = "swift.openstack.org/replication-last"
+	HealthAnnotation            = "swift.openstack.org/health"
+	CertificateHashAnnotation   = "swift.openstack.org/certificate-hash"
+	DisksAnnotation             = "swift.openstack.org/disks"
+
+	ProxyPoolLabel = "swift.openstack.org/proxy-pool"
+	ProxyPipeline  = "catch_errors gatekeeper healthcheck proxy-logging cache listing_formats container_sync bulk tempurl ratelimit authtoken keystone copy container-quotas account-quotas slo dlo versioned_writes proxy-logging proxy-server"
+	// ProxyPipelineS3API is ProxyPipeline with the S3 API, s3token has to
+	// validate the S3 signature before authtoken
+	ProxyPipelineS3API = "catch_errors gatekeeper healthcheck proxy-logging cache listing_formats container_sync bulk tempurl ratelimit s3api s3token authtoken keystone copy container-quotas account-quotas slo dlo versioned_writes proxy-logging proxy-server"
+	// ProxyPipelineNoAuth is ProxyPipeline without any auth middleware, used
+	// by the break-glass pool
+	ProxyPipelineNoAuth = "catch_errors gatekeeper healthcheck proxy-logging cache listing_formats bulk copy slo dlo versioned_writes proxy-logging proxy-server"
+	// BreakGlassPoolName is the name of the proxy pool without auth
+	BreakGlassPoolName = swiftv1beta1.BreakGlassPoolName
+	// ConfigOverwriteSuffix is appended to the config-data keys of the
+	// defaultConfigOverwrite snippets
+	ConfigOverwriteSuffix = ".overwrite"
+	// CanaryContainer is the container of the canary objects in the
+	// account of the service user
+	CanaryContainer = "swift-operator-canary"
+	// VerificationContainer is the container of the objects verified after
+	// ring changes, in the account of the service user
+	VerificationContainer = "swift-operator-verification"
+	// StorageTierLabel is set on the pods of the storage and the metadata
+	// tier of a SwiftStorage
+	StorageTierLabel = "swift.openstack.org/storage-tier"
+	// StorageTier is the StorageTierLabel of the pods of the storage
+	// StatefulSet
+	StorageTier = "storage"
+	// MetadataTier is the StorageTierLabel of the account and container
+	// server pods and the suffix of their StatefulSet
+	MetadataTier = "metadata"
 )
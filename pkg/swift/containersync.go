@@ -0,0 +1,153 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/secret"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// containerSyncRealmsFile is the realms config read by the proxy and
+	// the container sync daemon
+	containerSyncRealmsFile = "container-sync-realms.conf"
+	// containerSyncMountPath is copied to /etc/swift by swift-init
+	containerSyncMountPath = "/var/lib/config-data/container-sync"
+)
+
+// GetContainerSyncSecretName returns the name of the Secret with the
+// container sync realms of a SwiftStorage or SwiftProxy
+func GetContainerSyncSecretName(name string) string {
+	return name + "-container-sync-realms"
+}
+
+// GetContainerSyncRealms renders container-sync-realms.conf with the keys of
+// the realm Secrets
+func GetContainerSyncRealms(ctx context.Context, h *helper.Helper, namespace string, cs *swiftv1beta1.SwiftContainerSync) (string, error) {
+	var conf strings.Builder
+	conf.WriteString("[DEFAULT]\n")
+	for _, realm := range cs.Realms {
+		keys, _, err := secret.GetSecret(ctx, h, realm.KeySecret, namespace)
+		if err != nil {
+			return "", err
+		}
+		key := strings.TrimSpace(string(keys.Data["key"]))
+		if key == "" {
+			return "", fmt.Errorf("container sync key Secret %s of realm %s has no key", realm.KeySecret, realm.Name)
+		}
+		conf.WriteString(fmt.Sprintf("\n[%s]\nkey = %s\n", realm.Name, key))
+		if key2 := strings.TrimSpace(string(keys.Data["key2"])); key2 != "" {
+			conf.WriteString(fmt.Sprintf("key2 = %s\n", key2))
+		}
+		for _, cluster := range realm.Clusters {
+			conf.WriteString(fmt.Sprintf("cluster_%s = %s\n", cluster.Name, cluster.Endpoint))
+		}
+	}
+	return conf.String(), nil
+}
+
+// GetContainerSyncTemplates returns the template of the Secret with the
+// container sync realms of the owner, none if container sync is not
+// configured. A missing key Secret is returned as a NotFound error.
+func GetContainerSyncTemplates(
+	ctx context.Context,
+	h *helper.Helper,
+	owner client.Object,
+	kind string,
+	cs *swiftv1beta1.SwiftContainerSync,
+	labels map[string]string,
+) ([]util.Template, error) {
+	if cs == nil {
+		return nil, nil
+	}
+	realms, err := GetContainerSyncRealms(ctx, h, owner.GetNamespace(), cs)
+	if err != nil {
+		return nil, err
+	}
+	return []util.Template{
+		{
+			Name:         GetContainerSyncSecretName(owner.GetName()),
+			Namespace:    owner.GetNamespace(),
+			Type:         util.TemplateTypeNone,
+			InstanceType: kind,
+			Labels:       labels,
+			CustomData:   map[string]string{containerSyncRealmsFile: realms},
+		},
+	}, nil
+}
+
+// EnsureContainerSyncSecret creates the Secret with the container sync realms
+// of the owner, or deletes it if container sync is not configured. A missing
+// key Secret is returned as a NotFound error.
+func EnsureContainerSyncSecret(
+	ctx context.Context,
+	h *helper.Helper,
+	owner client.Object,
+	kind string,
+	cs *swiftv1beta1.SwiftContainerSync,
+	labels map[string]string,
+) error {
+	if cs == nil {
+		name := GetContainerSyncSecretName(owner.GetName())
+		err := h.GetClient().Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()}})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	tpl, err := GetContainerSyncTemplates(ctx, h, owner, kind, cs, labels)
+	if err != nil {
+		return err
+	}
+	return secret.EnsureSecrets(ctx, h, owner, tpl, nil)
+}
+
+// GetContainerSyncVolume returns the volume of the container sync realms
+// Secret of a SwiftStorage or SwiftProxy
+func GetContainerSyncVolume(name string) corev1.Volume {
+	return corev1.Volume{
+		Name: "container-sync",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: GetContainerSyncSecretName(name),
+			},
+		},
+	}
+}
+
+// GetContainerSyncVolumeMount returns the mount of the container sync realms
+// in the swift-init container
+func GetContainerSyncVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "container-sync",
+		MountPath: containerSyncMountPath,
+		ReadOnly:  true,
+	}
+}
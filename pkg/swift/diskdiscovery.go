@@ -0,0 +1,151 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// Disk states reported by the discovery pods
+const (
+	// DiskStateAvailable - no filesystem, partitions or mounts
+	DiskStateAvailable = "Available"
+	// DiskStateFormatted - formatted by the discovery, not mounted
+	DiskStateFormatted = "Formatted"
+	// DiskStateMounted - formatted by the discovery and mounted below the
+	// mount root
+	DiskStateMounted = "Mounted"
+	// DiskStateInUse - used by something else, never touched
+	DiskStateInUse = "InUse"
+)
+
+// DiscoveredDisk is a local disk of a node reported by a discovery pod
+type DiscoveredDisk struct {
+	Node       string `json:"node"`
+	Device     string `json:"device"`
+	Size       int64  `json:"size"`
+	Rotational bool   `json:"rotational"`
+	Serial     string `json:"serial"`
+	State      string `json:"state"`
+}
+
+// GetDiskDiscoveryName returns the name of the discovery DaemonSet
+func GetDiskDiscoveryName(instance *swiftv1beta1.SwiftStorage) string {
+	return instance.Name + "-disk-discovery"
+}
+
+// GetDiskInventoryName returns the name of the disk inventory ConfigMap
+func GetDiskInventoryName(instance *swiftv1beta1.SwiftStorage) string {
+	return instance.Name + "-disk-inventory"
+}
+
+// GetDiscoveredDisks returns the disks recorded by the discovery pods
+// matching the given labels, sorted by node and device. Pods that did not
+// report their disks yet are skipped.
+func GetDiscoveredDisks(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	labels map[string]string,
+) ([]DiscoveredDisk, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	disks := []DiscoveredDisk{}
+	for _, p := range podList.Items {
+		value, ok := p.Annotations[DisksAnnotation]
+		if !ok || p.Spec.NodeName == "" {
+			continue
+		}
+		podDisks := []DiscoveredDisk{}
+		if err := json.Unmarshal([]byte(value), &podDisks); err != nil {
+			h.GetLogger().Info(fmt.Sprintf("Ignoring invalid disks of pod %s: %s", p.Name, err))
+			continue
+		}
+		for _, d := range podDisks {
+			d.Node = p.Spec.NodeName
+			disks = append(disks, d)
+		}
+	}
+	sort.Slice(disks, func(i, j int) bool {
+		if disks[i].Node != disks[j].Node {
+			return disks[i].Node < disks[j].Node
+		}
+		return disks[i].Device < disks[j].Device
+	})
+	return disks, nil
+}
+
+// IsDiskApproved returns true if the disk is listed in the approvedDisks
+func IsDiskApproved(dd *swiftv1beta1.SwiftStorageDiskDiscovery, disk DiscoveredDisk) bool {
+	for _, approved := range dd.ApprovedDisks {
+		if approved == disk.Node+"/"+disk.Serial {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDiskInventory returns the disks.csv content of the disk inventory
+// ConfigMap, one "node,device,size,rotational,serial,state,approved" entry
+// per disk
+func GetDiskInventory(dd *swiftv1beta1.SwiftStorageDiskDiscovery, disks []DiscoveredDisk) string {
+	var inventory strings.Builder
+	for _, d := range disks {
+		fmt.Fprintf(&inventory, "%s,%s,%d,%t,%s,%s,%t\n",
+			d.Node, d.Device, d.Size, d.Rotational, d.Serial, d.State, IsDiskApproved(dd, d))
+	}
+	return inventory.String()
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// GetDiscoveredSwiftDiskName returns the name of the SwiftDisk of an
+// approved disk, derived from its node and serial
+func GetDiscoveredSwiftDiskName(disk DiscoveredDisk) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(disk.Node+"-"+disk.Serial), "-")
+	return strings.Trim(name, "-")
+}
+
+// GetDiscoveredSwiftDiskSpec returns the SwiftDisk of an approved disk
+// mounted by the discovery, claimed by the SwiftStorage through its
+// StorageClass
+func GetDiscoveredSwiftDiskSpec(instance *swiftv1beta1.SwiftStorage, disk DiscoveredDisk) swiftv1beta1.SwiftDiskSpec {
+	return swiftv1beta1.SwiftDiskSpec{
+		NodeName:     disk.Node,
+		Path:         path.Join(instance.Spec.DiskDiscovery.MountRoot, disk.Serial),
+		Capacity:     *resource.NewQuantity(disk.Size, resource.BinarySI),
+		StorageClass: instance.Spec.StorageClass,
+		Region:       instance.Spec.Region,
+		Zone:         1,
+	}
+}
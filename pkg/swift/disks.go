@@ -0,0 +1,120 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetSwiftDiskVolumeName returns the name of the PersistentVolume of the
+// SwiftDisk, unique across the namespaces
+func GetSwiftDiskVolumeName(disk *swiftv1beta1.SwiftDisk) string {
+	return fmt.Sprintf("swiftdisk-%s-%s", disk.Namespace, disk.Name)
+}
+
+// GetSwiftDiskVolume returns the local PersistentVolume of the SwiftDisk on
+// its node. It is retained on release, the objects on the disk are only
+// removed by the operator of the node.
+func GetSwiftDiskVolume(disk *swiftv1beta1.SwiftDisk) *corev1.PersistentVolume {
+	volumeMode := corev1.PersistentVolumeFilesystem
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: GetSwiftDiskVolumeName(disk),
+			Labels: map[string]string{
+				swiftv1beta1.SwiftDiskLabel:          disk.Name,
+				swiftv1beta1.SwiftDiskNamespaceLabel: disk.Namespace,
+			},
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: disk.Spec.Capacity,
+			},
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			StorageClassName:              disk.Spec.StorageClass,
+			VolumeMode:                    &volumeMode,
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				Local: &corev1.LocalVolumeSource{
+					Path: disk.Spec.Path,
+				},
+			},
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      corev1.LabelHostname,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{disk.Spec.NodeName},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// GetClaimSwiftDisk returns the SwiftDisk bound to the claim, nil if the
+// claim is not bound to the PersistentVolume of a SwiftDisk
+func GetClaimSwiftDisk(ctx context.Context, h *helper.Helper, claim *corev1.PersistentVolumeClaim) (*swiftv1beta1.SwiftDisk, error) {
+	if claim.Spec.VolumeName == "" {
+		return nil, nil
+	}
+	pv := &corev1.PersistentVolume{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: claim.Spec.VolumeName}, pv)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	name, ok := pv.Labels[swiftv1beta1.SwiftDiskLabel]
+	if !ok {
+		return nil, nil
+	}
+	disk := &swiftv1beta1.SwiftDisk{}
+	err = h.GetClient().Get(ctx, types.NamespacedName{Name: name, Namespace: pv.Labels[swiftv1beta1.SwiftDiskNamespaceLabel]}, disk)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return disk, nil
+}
+
+// GetSwiftDiskDeviceListEntry returns the devices.csv line of a device of the
+// given storage replica on a SwiftDisk, with the region, zone and weight of
+// the disk. Drained devices get a weight of zero.
+func GetSwiftDiskDeviceListEntry(instance *swiftv1beta1.SwiftStorage, replica int, device string, disk *swiftv1beta1.SwiftDisk, drain bool) string {
+	host := fmt.Sprintf("%s-%d.%s", instance.Name, replica, instance.Name)
+	weight := disk.Spec.GetWeight()
+	if drain {
+		weight = 0
+	}
+	// The port field is empty, the default ring ports are used
+	return fmt.Sprintf("%s,%s,%d,,%d,%d\n", host, device, weight, disk.Spec.Region, disk.Spec.Zone)
+}
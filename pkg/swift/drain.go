@@ -0,0 +1,142 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/swift-operator/pkg/ringbuilder"
+)
+
+// GetRingDrainPending returns the number of partitions still assigned to the
+// given hosts in the rings of the ring ConfigMap. Hosts with a weight above
+// zero count as pending until the rings are rebuilt, hosts not in a ring are
+// drained. Rings that can not be read are skipped, the partitions on the
+// devices are still checked.
+func GetRingDrainPending(ringCM *corev1.ConfigMap, hosts []string) (int, error) {
+	tarball, ok := ringCM.BinaryData["swiftrings.tar.gz"]
+	if !ok {
+		return 0, fmt.Errorf("no rings in ConfigMap %s", ringCM.Name)
+	}
+	files, err := ReadTarGz(tarball)
+	if err != nil {
+		return 0, err
+	}
+
+	draining := map[string]bool{}
+	for _, h := range hosts {
+		draining[h] = true
+	}
+
+	pending := 0
+	for name, data := range files {
+		if !strings.HasSuffix(name, ".ring.gz") {
+			continue
+		}
+		ring, err := ringbuilder.Read(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		ids := map[uint16]bool{}
+		for _, d := range ring.Devices {
+			if d == nil || !draining[d.IP] {
+				continue
+			}
+			if d.Weight > 0 {
+				return -1, nil
+			}
+			ids[uint16(d.ID)] = true
+		}
+		for _, part2Dev := range ring.Replica2Part2Dev {
+			for _, id := range part2Dev {
+				if ids[id] {
+					pending++
+				}
+			}
+		}
+	}
+	return pending, nil
+}
+
+// GetDevicePartitions returns the number of partitions stored on the device
+// of each of the given pods. The ring-sync script records these as pod
+// annotations, pods without the annotation are reported with -1.
+func GetDevicePartitions(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	labels map[string]string,
+	pods []string,
+) (map[string]int, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := map[string]int{}
+	for _, name := range pods {
+		partitions[name] = -1
+	}
+	for _, p := range podList.Items {
+		if _, ok := partitions[p.Name]; !ok {
+			continue
+		}
+		count, err := strconv.Atoi(p.Annotations[PartitionsAnnotation])
+		if err != nil {
+			continue
+		}
+		partitions[p.Name] = count
+	}
+	return partitions, nil
+}
+
+// SetScaleDownCondition sets the ScaleDownInProgress condition with the given
+// progress message and removes it if message is empty
+func SetScaleDownCondition(conditions *condition.Conditions, message string) {
+	if message == "" {
+		conditions.Remove(swiftv1beta1.ScaleDownInProgressCondition)
+		return
+	}
+	conditions.Set(condition.TrueCondition(swiftv1beta1.ScaleDownInProgressCondition, message))
+}
+
+// FormatDevicePartitions formats the partitions left on each pod, pods that
+// did not report yet are shown as unknown
+func FormatDevicePartitions(partitions map[string]int) string {
+	result := make([]string, 0, len(partitions))
+	for name, count := range partitions {
+		if count < 0 {
+			result = append(result, fmt.Sprintf("%s=unknown", name))
+		} else {
+			result = append(result, fmt.Sprintf("%s=%d", name, count))
+		}
+	}
+	sort.Strings(result)
+	return strings.Join(result, ", ")
+}
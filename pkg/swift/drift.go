@@ -0,0 +1,155 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetDriftManagers returns the field managers, other than the operator, that
+// own fields of the spec the operator renders in desiredSpec. Fields the
+// operator does not set, e.g. a restartedAt annotation, are not reverted and
+// are not drift. The operator is identified as the manager owning the
+// controller reference to the given owner.
+func GetDriftManagers(obj metav1.Object, ownerUID types.UID, desiredSpec interface{}) ([]string, error) {
+	raw, err := json.Marshal(desiredSpec)
+	if err != nil {
+		return nil, err
+	}
+	var desired interface{}
+	if err := json.Unmarshal(raw, &desired); err != nil {
+		return nil, err
+	}
+	desiredPaths := map[string]bool{}
+	getDesiredPaths(desired, "", desiredPaths)
+
+	managedFields := obj.GetManagedFields()
+
+	operator := ""
+	for _, mf := range managedFields {
+		fields := getManagedFields(mf)
+		metadata, _ := fields["f:metadata"].(map[string]interface{})
+		ownerRefs, _ := metadata["f:ownerReferences"].(map[string]interface{})
+		for k := range ownerRefs {
+			if strings.Contains(k, string(ownerUID)) {
+				operator = mf.Manager
+			}
+		}
+	}
+
+	managers := []string{}
+	for _, mf := range managedFields {
+		if mf.Manager == operator || mf.Subresource == "status" {
+			continue
+		}
+		spec, ok := getManagedFields(mf)["f:spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		paths := map[string]bool{}
+		getManagedPaths(spec, "", paths)
+		if hasDesiredPath(paths, desiredPaths) {
+			managers = append(managers, mf.Manager)
+		}
+	}
+	sort.Strings(managers)
+	return managers, nil
+}
+
+// getDesiredPaths collects the paths of the fields set in the JSON of a
+// spec. Lists are not descended into, their items are identified by keys
+// in the managed fields.
+func getDesiredPaths(value interface{}, path string, paths map[string]bool) {
+	fields, ok := value.(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		paths[path] = true
+		return
+	}
+	for k, v := range fields {
+		getDesiredPaths(v, path+"/"+k, paths)
+	}
+}
+
+// getManagedPaths collects the paths of the fields owned in a managed fields
+// entry, with the same granularity as getDesiredPaths
+func getManagedPaths(fields map[string]interface{}, path string, paths map[string]bool) {
+	if len(fields) == 0 {
+		paths[path] = true
+		return
+	}
+	for k, v := range fields {
+		switch {
+		case k == ".":
+			continue
+		case strings.HasPrefix(k, "f:"):
+			child, _ := v.(map[string]interface{})
+			getManagedPaths(child, path+"/"+strings.TrimPrefix(k, "f:"), paths)
+		default:
+			// a list item, owning an item changes the list
+			paths[path] = true
+		}
+	}
+}
+
+// hasDesiredPath returns true if a managed path is a desired field or
+// contains one
+func hasDesiredPath(managed map[string]bool, desired map[string]bool) bool {
+	for m := range managed {
+		for d := range desired {
+			if d == m || strings.HasPrefix(d, m+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getManagedFields(mf metav1.ManagedFieldsEntry) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if mf.FieldsV1 != nil {
+		// invalid entries are treated as empty
+		_ = json.Unmarshal(mf.FieldsV1.Raw, &fields)
+	}
+	return fields
+}
+
+// FormatDrift returns a summary of the managers that changed an object
+func FormatDrift(kind string, name string, managers []string) string {
+	return fmt.Sprintf("%s %s changed by %s", kind, name, strings.Join(managers, ", "))
+}
+
+// SetDriftCondition sets the DriftDetected condition if any out-of-band
+// change is left in place and removes it otherwise
+func SetDriftCondition(conditions *condition.Conditions, drift []string) {
+	if len(drift) == 0 {
+		conditions.Remove(swiftv1beta1.DriftDetectedCondition)
+		return
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.DriftDetectedCondition,
+		fmt.Sprintf(swiftv1beta1.DriftDetectedMessage, strings.Join(drift, "; "))))
+}
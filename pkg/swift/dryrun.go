@@ -0,0 +1,211 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// DryRunFieldOwner is the field manager used for the server-side dry-run
+const DryRunFieldOwner = "swift-operator-dry-run"
+
+// IsDryRun returns true if the dry-run annotation is set on the object
+func IsDryRun(obj metav1.Object) bool {
+	return obj.GetAnnotations()[swiftv1beta1.DryRunAnnotation] == "true"
+}
+
+// SetDryRun sets or removes the dry-run annotation on the object
+func SetDryRun(obj metav1.Object, dryRun bool) {
+	annotations := obj.GetAnnotations()
+	if dryRun {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[swiftv1beta1.DryRunAnnotation] = "true"
+	} else {
+		delete(annotations, swiftv1beta1.DryRunAnnotation)
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// GetDryRunDiff returns the changes applying the desired object would make to
+// the current one. The result is computed with a server-side dry-run apply,
+// so it includes the defaults and admission changes of the API server. Only
+// the changed keys of a Secret are reported, not their values.
+func GetDryRunDiff(
+	ctx context.Context,
+	h *helper.Helper,
+	desired client.Object,
+) ([]string, error) {
+	gvk, err := apiutil.GVKForObject(desired, h.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s %s", gvk.Kind, desired.GetName())
+
+	current := desired.DeepCopyObject().(client.Object)
+	err = h.GetClient().Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if apierrors.IsNotFound(err) {
+		return []string{fmt.Sprintf("%s: would be created", name)}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	applied := desired.DeepCopyObject().(client.Object)
+	applied.GetObjectKind().SetGroupVersionKind(gvk)
+	applied.SetManagedFields(nil)
+	err = h.GetClient().Patch(ctx, applied, client.Apply,
+		client.DryRunAll, client.ForceOwnership, client.FieldOwner(DryRunFieldOwner))
+	if err != nil {
+		return nil, err
+	}
+
+	currentMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return nil, err
+	}
+	appliedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := []string{}
+	if gvk.Kind == "Secret" {
+		diff = append(diff, diffSecretData(name+": data", currentMap["data"], appliedMap["data"])...)
+	} else {
+		for _, field := range []string{"spec", "data"} {
+			diff = append(diff, diffValues(name+": "+field, currentMap[field], appliedMap[field])...)
+		}
+	}
+	for _, field := range []string{"labels", "annotations"} {
+		diff = append(diff, diffValues(
+			name+": metadata."+field,
+			currentMap["metadata"].(map[string]interface{})[field],
+			appliedMap["metadata"].(map[string]interface{})[field])...)
+	}
+	return diff, nil
+}
+
+// GetTemplateObjects returns the ConfigMaps, or the Secrets if secrets is
+// set, lib-common renders for the templates
+func GetTemplateObjects(tpls []util.Template, secrets bool) ([]client.Object, error) {
+	objs := []client.Object{}
+	for _, tpl := range tpls {
+		data, err := util.GetTemplateData(tpl)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range tpl.CustomData {
+			data[k] = v
+		}
+		meta := metav1.ObjectMeta{
+			Name:        tpl.Name,
+			Namespace:   tpl.Namespace,
+			Labels:      tpl.Labels,
+			Annotations: tpl.Annotations,
+		}
+		if !secrets {
+			objs = append(objs, &corev1.ConfigMap{ObjectMeta: meta, Data: data})
+			continue
+		}
+		secretData := map[string][]byte{}
+		for k, v := range data {
+			secretData[k] = []byte(v)
+		}
+		objs = append(objs, &corev1.Secret{ObjectMeta: meta, Data: secretData, Type: tpl.SecretType})
+	}
+	return objs, nil
+}
+
+// diffSecretData returns a line per added or changed key of the Secret data,
+// the values are not included
+func diffSecretData(path string, a interface{}, b interface{}) []string {
+	aMap, _ := a.(map[string]interface{})
+	bMap, _ := b.(map[string]interface{})
+	keys := make([]string, 0, len(bMap))
+	for k := range bMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	diff := []string{}
+	for _, k := range keys {
+		if v, ok := aMap[k]; !ok {
+			diff = append(diff, fmt.Sprintf("%s.%s: would be added", path, k))
+		} else if !reflect.DeepEqual(v, bMap[k]) {
+			diff = append(diff, fmt.Sprintf("%s.%s: would be changed", path, k))
+		}
+	}
+	return diff
+}
+
+// diffValues returns a line per changed leaf value between a and b
+func diffValues(path string, a interface{}, b interface{}) []string {
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+
+	aMap, aIsMap := a.(map[string]interface{})
+	bMap, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := map[string]bool{}
+		for k := range aMap {
+			keys[k] = true
+		}
+		for k := range bMap {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		diff := []string{}
+		for _, k := range sorted {
+			diff = append(diff, diffValues(path+"."+k, aMap[k], bMap[k])...)
+		}
+		return diff
+	}
+
+	aList, aIsList := a.([]interface{})
+	bList, bIsList := b.([]interface{})
+	if aIsList && bIsList && len(aList) == len(bList) {
+		diff := []string{}
+		for i := range aList {
+			diff = append(diff, diffValues(fmt.Sprintf("%s[%d]", path, i), aList[i], bList[i])...)
+		}
+		return diff
+	}
+
+	return []string{fmt.Sprintf("%s: %v -> %v", path, a, b)}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// EffectiveConfigKey is the key of the effective config in its ConfigMap
+const EffectiveConfigKey = "effective-config.yaml"
+
+// EffectiveConfig - spec of the Swift sub-CRs after defaulting and the values
+// derived by the operator
+type EffectiveConfig struct {
+	SwiftRing    swiftv1beta1.SwiftRingSpec    `json:"swiftRing"`
+	SwiftStorage swiftv1beta1.SwiftStorageSpec `json:"swiftStorage"`
+	SwiftProxy   swiftv1beta1.SwiftProxySpec   `json:"swiftProxy"`
+	Ports        map[string]int32              `json:"ports"`
+	Devices      string                        `json:"devices,omitempty"`
+}
+
+// GetEffectiveConfig renders the effective config of the given sub-CRs as
+// YAML. The device list is only included once the storage pods are ready.
+func GetEffectiveConfig(
+	ctx context.Context,
+	h *helper.Helper,
+	ring *swiftv1beta1.SwiftRing,
+	storage *swiftv1beta1.SwiftStorage,
+	proxy *swiftv1beta1.SwiftProxy,
+) (string, error) {
+	config := EffectiveConfig{
+		SwiftRing:    ring.Spec,
+		SwiftStorage: storage.Spec,
+		SwiftProxy:   proxy.Spec,
+		Ports: map[string]int32{
+			"proxy":     ProxyPort,
+			"memcached": MemcachedPort,
+			"account":   AccountServerPort,
+			"container": ContainerServerPort,
+			"object":    ObjectServerPort,
+			"rsync":     RsyncPort,
+		},
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.DeviceConfigMapName, Namespace: storage.Namespace}, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	config.Devices = cm.Data["devices.csv"]
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
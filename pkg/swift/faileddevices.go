@@ -0,0 +1,138 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/swift-operator/pkg/ringbuilder"
+)
+
+// GetFailedDevicesEnv returns the sorted "<host>/<device>" entries of the
+// failed devices, as used by the rebalance Job
+func GetFailedDevicesEnv(devices []string) string {
+	entries := append([]string{}, devices...)
+	sort.Strings(entries)
+	return strings.Join(entries, " ")
+}
+
+// RemoveFailedDevices returns the devices without the failed ones
+func RemoveFailedDevices(devices []ringbuilder.Device, failed []string) []ringbuilder.Device {
+	removed := map[string]bool{}
+	for _, name := range failed {
+		removed[name] = true
+	}
+	result := []ringbuilder.Device{}
+	for _, d := range devices {
+		if !removed[fmt.Sprintf("%s/%s", d.IP, d.Device)] {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// RingsHaveDevice returns true if the device is in any ring of the ring
+// ConfigMap. Rings that can not be read are skipped.
+func RingsHaveDevice(ringCM *corev1.ConfigMap, host string, device string) (bool, error) {
+	tarball, ok := ringCM.BinaryData["swiftrings.tar.gz"]
+	if !ok {
+		return false, fmt.Errorf("no rings in ConfigMap %s", ringCM.Name)
+	}
+	files, err := ReadTarGz(tarball)
+	if err != nil {
+		return false, err
+	}
+	for name, data := range files {
+		if !strings.HasSuffix(name, ".ring.gz") {
+			continue
+		}
+		ring, err := ringbuilder.Read(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		for _, d := range ring.Devices {
+			if d != nil && d.IP == host && d.Device == device {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// GetFailedDeviceClaim returns the PVC and the name of the storage pod of the
+// failed <host>/<device>. The host is <pod>.<SwiftStorage>, or
+// <pod>.<SwiftStorage>-metadata for the metadata tier. The PVC is the one of
+// the VolumeClaimTemplate of the device. The PVC is nil if it does not
+// exist.
+func GetFailedDeviceClaim(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	name string,
+) (*corev1.PersistentVolumeClaim, string, error) {
+	host, device, _ := strings.Cut(name, "/")
+	podName, storageName, found := strings.Cut(host, ".")
+	if !found {
+		return nil, "", fmt.Errorf("invalid host %q of failed device %s, expected <pod>.<SwiftStorage>", host, name)
+	}
+
+	storage := &swiftv1beta1.SwiftStorage{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: storageName, Namespace: namespace}, storage)
+	if apierrors.IsNotFound(err) && strings.HasSuffix(storageName, "-"+MetadataTier) {
+		storageName = strings.TrimSuffix(storageName, "-"+MetadataTier)
+		err = h.GetClient().Get(ctx, types.NamespacedName{Name: storageName, Namespace: namespace}, storage)
+		// The metadata pods have a single device
+		storage.Spec.DisksPerReplica = 1
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	claimName := ""
+	for i, d := range GetDeviceNames(storage) {
+		if d == device {
+			claimName = GetDeviceClaimName(i, device)
+		}
+	}
+	if db := storage.Spec.DatabaseDevice; db != nil && db.DeviceName == device {
+		claimName = GetDatabaseDeviceClaimName(device)
+	}
+	if claimName == "" {
+		return nil, "", fmt.Errorf("device %s of failed device %s not found in SwiftStorage %s", device, name, storageName)
+	}
+
+	claim := &corev1.PersistentVolumeClaim{}
+	claimName = fmt.Sprintf("%s-%s", claimName, podName)
+	err = h.GetClient().Get(ctx, types.NamespacedName{Name: claimName, Namespace: namespace}, claim)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, podName, nil
+		}
+		return nil, "", err
+	}
+	return claim, podName, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// FeatureRoutes exposes the public endpoint with an OpenShift Route
+	FeatureRoutes = "routes"
+	// FeatureAutoscaling creates a HorizontalPodAutoscaler for the proxy
+	FeatureAutoscaling = "autoscaling"
+	// FeatureKeystoneEndpoints registers the service and its endpoints in
+	// the Keystone catalog
+	FeatureKeystoneEndpoints = "keystone-endpoints"
+	// FeatureKEDA scales the object expirer with a KEDA ScaledObject
+	FeatureKEDA = "keda"
+
+	// FeatureDisabled is reported for features disabled in the operator
+	FeatureDisabled = "disabled in the operator"
+	// FeatureForbidden is reported for features missing RBAC permissions
+	// or their API group
+	FeatureForbidden = "missing permissions or API"
+)
+
+// Features are the optional features enabled with operator flags. Each of
+// them needs the RBAC rules of its API group, see config/rbac.
+type Features struct {
+	Routes            bool
+	Autoscaling       bool
+	KeystoneEndpoints bool
+	KEDA              bool
+}
+
+// IsPermissionError returns true if the error is caused by missing RBAC
+// permissions or by an API group that is not installed
+func IsPermissionError(err error) bool {
+	return apierrors.IsForbidden(err) || meta.IsNoMatchError(err)
+}
+
+// SetPermissionsCondition sets the FeaturesUnavailable condition if any
+// requested feature is disabled or misses permissions and removes it
+// otherwise
+func SetPermissionsCondition(conditions *condition.Conditions, unavailable map[string]string) {
+	if len(unavailable) == 0 {
+		conditions.Remove(swiftv1beta1.FeaturesUnavailableCondition)
+		return
+	}
+	features := make([]string, 0, len(unavailable))
+	for feature, reason := range unavailable {
+		features = append(features, fmt.Sprintf("%s (%s)", feature, reason))
+	}
+	sort.Strings(features)
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.FeaturesUnavailableCondition,
+		fmt.Sprintf(swiftv1beta1.FeaturesUnavailableMessage, strings.Join(features, ", "))))
+}
@@ -16,8 +16,14 @@ limitations under the License.
 package swift
 
 import (
+	"fmt"
 	corev1 "k8s.io/api/core/v1"
 	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
 )
 
 func GetSecurityContext() corev1.SecurityContext {
@@ -41,10 +47,38 @@ func GetLabelsProxy() map[string]string {
 	return map[string]string{"app.kubernetes.io/name": "SwiftProxy"}
 }
 
+func GetLabelsProxyPools() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftProxyPool"}
+}
+
+func GetLabelsProxyPool(pool string) map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftProxyPool", ProxyPoolLabel: pool}
+}
+
 func GetLabelsStorage() map[string]string {
 	return map[string]string{"app.kubernetes.io/name": "SwiftStorage"}
 }
 
+// GetLabelsStorageTier returns the labels of the storage StatefulSet pods
+// without the metadata tier pods, GetLabelsStorage selects both
+func GetLabelsStorageTier() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftStorage", StorageTierLabel: StorageTier}
+}
+
+// GetLabelsStorageMetadata returns the labels of the metadata tier pods,
+// they are storage pods too
+func GetLabelsStorageMetadata() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftStorage", StorageTierLabel: MetadataTier}
+}
+
+func GetLabelsExpirer() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftObjectExpirer"}
+}
+
+func GetLabelsDiskDiscovery() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftDiskDiscovery"}
+}
+
 func GetLabelsRing() map[string]string {
 	return map[string]string{"app.kubernetes.io/name": "SwiftRing"}
 }
@@ -62,3 +96,206 @@ func RandomString(length int) string {
 	}
 	return string(str)
 }
+
+// ApplyContainerEnv appends the user provided environment to the containers
+// with a matching name
+func ApplyContainerEnv(containers []corev1.Container, containerEnv map[string]swiftv1beta1.ContainerEnv) []corev1.Container {
+	for i := range containers {
+		e, ok := containerEnv[containers[i].Name]
+		if !ok {
+			continue
+		}
+		containers[i].Env = append(containers[i].Env, e.Env...)
+		containers[i].EnvFrom = append(containers[i].EnvFrom, e.EnvFrom...)
+	}
+	return containers
+}
+
+// GetContainerGroup returns the role group of a container, the plural of the
+// part after the last dash, e.g. replicators for object-replicator
+func GetContainerGroup(name string) string {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return ""
+	}
+	return name[i+1:] + "s"
+}
+
+// ApplyResources sets the resource requirements of the containers, keyed by
+// the container name or by its role group
+func ApplyResources(containers []corev1.Container, resources map[string]corev1.ResourceRequirements) []corev1.Container {
+	for i := range containers {
+		r, ok := resources[containers[i].Name]
+		if !ok {
+			r, ok = resources[GetContainerGroup(containers[i].Name)]
+		}
+		if ok {
+			containers[i].Resources = *r.DeepCopy()
+		}
+	}
+	return containers
+}
+
+// ApplyExtraMounts adds the extra volumes and mounts them into the
+// containers selected by name or role group. Pass nil volumes to only mount
+// them, e.g. into the init containers of the same pod.
+func ApplyExtraMounts(containers []corev1.Container, volumes []corev1.Volume, extraMounts []swiftv1beta1.ExtraVolMounts) ([]corev1.Container, []corev1.Volume) {
+	for _, em := range extraMounts {
+		if volumes != nil {
+			for i := range em.Volumes {
+				volumes = append(volumes, em.Volumes[i].ToCoreVolume())
+			}
+		}
+		for i := range containers {
+			selected := len(em.Propagation) == 0
+			for _, p := range em.Propagation {
+				selected = selected || p == containers[i].Name || p == GetContainerGroup(containers[i].Name)
+			}
+			if selected {
+				containers[i].VolumeMounts = append(containers[i].VolumeMounts, em.Mounts...)
+			}
+		}
+	}
+	return containers, volumes
+}
+
+// ApplyNofileLimits wraps the command of the containers with a matching name
+// in a script raising the open file limit before starting the service
+func ApplyNofileLimits(containers []corev1.Container, nofileLimits map[string]int64) []corev1.Container {
+	for i := range containers {
+		limit, ok := nofileLimits[containers[i].Name]
+		if !ok {
+			continue
+		}
+		containers[i].Command = append([]string{"/usr/local/bin/container-scripts/nofile-exec.sh"}, containers[i].Command...)
+		containers[i].Env = append(containers[i].Env, corev1.EnvVar{
+			Name:  "NOFILE_LIMIT",
+			Value: strconv.FormatInt(limit, 10),
+		})
+	}
+	return containers
+}
+
+// GetConfigOverwriteData returns the config-data entries of the
+// defaultConfigOverwrite snippets. They are merged into the config files by
+// swift-init.sh.
+func GetConfigOverwriteData(overwrite map[string]string) map[string]string {
+	if len(overwrite) == 0 {
+		return nil
+	}
+	data := map[string]string{}
+	for file, snippet := range overwrite {
+		data[file+ConfigOverwriteSuffix] = snippet
+	}
+	return data
+}
+
+// GetStorageTemplateParameters returns the parameters used to render the
+// SwiftStorage config templates
+func GetStorageTemplateParameters(instance *swiftv1beta1.SwiftStorage) map[string]interface{} {
+	templateParameters := make(map[string]interface{})
+	templateParameters["NodeRoot"] = instance.Spec.NodeRoot
+	templateParameters["RsyncMetrics"] = instance.Spec.RsyncMetrics
+	templateParameters["TLS"] = instance.Spec.TLS != nil
+	templateParameters["TLSPath"] = TLSMountPath
+	templateParameters["PauseBackgroundDaemons"] = instance.Spec.PauseBackgroundDaemons
+	templateParameters["RestartDaemons"] = instance.Spec.RestartDaemons
+	templateParameters["ExpirerProcesses"] = instance.Spec.Expirer.Processes
+	templateParameters["ExpirerInterval"] = instance.Spec.Expirer.Interval
+	templateParameters["ExpirerConcurrency"] = instance.Spec.Expirer.Concurrency
+	templateParameters["ExpirerDelayReaping"] = instance.Spec.Expirer.DelayReaping
+	// The controller sets the servers of the MemcachedInstance
+	templateParameters["MemcachedServers"] = LocalMemcachedServers
+	templateParameters["ContainerSyncInterval"] = int32(0)
+	if instance.Spec.ContainerSync != nil {
+		templateParameters["ContainerSyncInterval"] = instance.Spec.ContainerSync.Interval
+	}
+
+	// An invalid scrub schedule is rejected by the validating webhook and
+	// reported by the controller before the templates are rendered
+	scrubState, err := GetScrubState(instance, time.Now())
+	if err != nil {
+		scrubState = scrubNone
+	}
+	templateParameters["ScrubState"] = scrubState
+	scrub := instance.Spec.Scrub
+	templateParameters["Scrub"] = scrub != nil
+	if scrub != nil {
+		templateParameters["AuditorFilesPerSecond"] = scrub.FilesPerSecond
+		templateParameters["AuditorBytesPerSecond"] = scrub.BytesPerSecond
+		templateParameters["AuditorZeroByteFilesPerSecond"] = scrub.ZeroByteFilesPerSecond
+		templateParameters["AuditorInterval"] = scrub.Interval
+	}
+	return templateParameters
+}
+
+// GetDeviceListEntry returns the devices.csv line of a device of the given
+// storage replica, the weight is the device capacity in GB. A zone of 0
+// leaves the device in the default ring zone.
+func GetDeviceListEntry(instance *swiftv1beta1.SwiftStorage, replica int, device string, capacity int64, zone int32) string {
+	host := fmt.Sprintf("%s-%d.%s", instance.Name, replica, instance.Name)
+	if zone > 0 || instance.Spec.Region > 1 {
+		region := instance.Spec.Region
+		if region < 1 {
+			region = 1
+		}
+		if zone == 0 {
+			zone = 1
+		}
+		// The port field is empty, the default ring ports are used
+		return fmt.Sprintf("%s,%s,%d,,%d,%d\n", host, device, capacity/(1000*1000*1000), region, zone)
+	}
+	return fmt.Sprintf("%s,%s,%d\n", host, device, capacity/(1000*1000*1000))
+}
+
+// GetDatabaseDeviceClaimName returns the name of the VolumeClaimTemplate of
+// the account and container database device
+func GetDatabaseDeviceClaimName(device string) string {
+	return fmt.Sprintf("%s-%s", ClaimName, device)
+}
+
+// GetMetadataTierName returns the name of the StatefulSet and the headless
+// Service of the metadata tier of the SwiftStorage
+func GetMetadataTierName(name string) string {
+	return fmt.Sprintf("%s-%s", name, MetadataTier)
+}
+
+// GetMetadataDeviceListEntry returns the devices.csv line of the device of
+// the given metadata tier replica, only added to the account and container
+// rings. The weight is the device capacity in GB.
+func GetMetadataDeviceListEntry(instance *swiftv1beta1.SwiftStorage, replica int, capacity int64) string {
+	name := GetMetadataTierName(instance.Name)
+	region := instance.Spec.Region
+	if region < 1 {
+		region = 1
+	}
+	// The port field is empty, the default ring ports are used
+	return fmt.Sprintf("%s-%d.%s,%s,%d,,%d,1,%s\n",
+		name, replica, name, instance.Spec.DeviceName, capacity/(1000*1000*1000), region, DeviceRingsMetadata)
+}
+
+// GetDeviceNames returns the names of the devices of each storage pod. The
+// first one is DeviceName, the others continue its trailing number or start
+// at 2 without one, e.g. d1, d2, d3 or data, data2, data3.
+func GetDeviceNames(instance *swiftv1beta1.SwiftStorage) []string {
+	names := []string{instance.Spec.DeviceName}
+	prefix := strings.TrimRight(instance.Spec.DeviceName, "0123456789")
+	first, err := strconv.Atoi(strings.TrimPrefix(instance.Spec.DeviceName, prefix))
+	if err != nil {
+		first = 1
+	}
+	for i := 1; i < int(instance.Spec.DisksPerReplica); i++ {
+		names = append(names, fmt.Sprintf("%s%d", prefix, first+i))
+	}
+	return names
+}
+
+// GetDeviceClaimName returns the name of the VolumeClaimTemplate of the
+// device with the given index. The first device keeps the name used before
+// multiple disks were supported.
+func GetDeviceClaimName(index int, device string) string {
+	if index == 0 {
+		return ClaimName
+	}
+	return fmt.Sprintf("%s-%s", ClaimName, device)
+}
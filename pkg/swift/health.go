@@ -0,0 +1,108 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetLabelsHealthCheck returns the labels of the health check pods
+func GetLabelsHealthCheck() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftHealthCheck"}
+}
+
+// GetStorageHealth returns the latest result recorded by the health check
+// pods matching the given labels, or nil if no check finished yet
+func GetStorageHealth(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	labels map[string]string,
+) (*swiftv1beta1.SwiftStorageHealth, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *swiftv1beta1.SwiftStorageHealth
+	for _, p := range podList.Items {
+		annotation, ok := p.Annotations[HealthAnnotation]
+		if !ok {
+			continue
+		}
+		result := &swiftv1beta1.SwiftStorageHealth{}
+		if err := json.Unmarshal([]byte(annotation), result); err != nil {
+			// invalid results are skipped
+			continue
+		}
+		if latest == nil || result.Timestamp > latest.Timestamp {
+			latest = result
+		}
+	}
+	return latest, nil
+}
+
+// SetHealthCondition sets the Health condition from the result of the last
+// health check and removes it if no check finished yet
+func SetHealthCondition(conditions *condition.Conditions, health *swiftv1beta1.SwiftStorageHealth, lagThreshold int64) {
+	if health == nil {
+		conditions.Remove(swiftv1beta1.HealthCondition)
+		return
+	}
+
+	problems := []string{}
+	servers := make([]string, 0, len(health.ReplicationLag))
+	for server := range health.ReplicationLag {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+	for _, server := range servers {
+		if lag := health.ReplicationLag[server]; lag > lagThreshold {
+			problems = append(problems, fmt.Sprintf("%s replication lag %ds", server, lag))
+		}
+	}
+	if len(health.UnmountedDrives) > 0 {
+		problems = append(problems, fmt.Sprintf("unmounted drives %s", strings.Join(health.UnmountedDrives, ", ")))
+	}
+	if len(health.UnreachableServers) > 0 {
+		problems = append(problems, fmt.Sprintf("unreachable servers %s", strings.Join(health.UnreachableServers, ", ")))
+	}
+
+	if len(problems) == 0 {
+		conditions.Set(condition.TrueCondition(
+			swiftv1beta1.HealthCondition,
+			fmt.Sprintf(swiftv1beta1.HealthMessage, health.Timestamp)))
+		return
+	}
+	conditions.Set(condition.FalseCondition(
+		swiftv1beta1.HealthCondition,
+		condition.ErrorReason,
+		condition.SeverityWarning,
+		swiftv1beta1.HealthErrorMessage,
+		health.Timestamp,
+		strings.Join(problems, "; ")))
+}
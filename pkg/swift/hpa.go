@@ -0,0 +1,101 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// HasProxyCustomMetrics returns true if the proxy is scaled on the request
+// rate or latency, which are exported by the proxy-exporter sidecar
+func HasProxyCustomMetrics(instance *swiftv1beta1.SwiftProxy) bool {
+	as := instance.Spec.Autoscaling
+	return as != nil && (as.RequestRate != nil || as.Latency != nil)
+}
+
+type HorizontalPodAutoscaler struct {
+	hpa     *autoscalingv2.HorizontalPodAutoscaler
+	timeout time.Duration
+}
+
+// NewHorizontalPodAutoscaler returns an initialized HorizontalPodAutoscaler.
+func NewHorizontalPodAutoscaler(
+	hpa *autoscalingv2.HorizontalPodAutoscaler,
+	timeout time.Duration,
+) *HorizontalPodAutoscaler {
+	return &HorizontalPodAutoscaler{
+		hpa:     hpa,
+		timeout: timeout,
+	}
+}
+
+func (a *HorizontalPodAutoscaler) CreateOrPatch(
+	ctx context.Context,
+	h *helper.Helper,
+) (ctrl.Result, error) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      a.hpa.Name,
+			Namespace: a.hpa.Namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrPatch(ctx, h.GetClient(), hpa, func() error {
+		hpa.Labels = a.hpa.Labels
+		hpa.Spec = a.hpa.Spec
+		err := controllerutil.SetControllerReference(h.GetBeforeObject(), hpa, h.GetScheme())
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		h.GetLogger().Error(err, "Error creating HorizontalPodAutoscaler")
+		return ctrl.Result{}, err
+	}
+
+	if op != controllerutil.OperationResultNone {
+		h.GetLogger().Info(fmt.Sprintf("HorizontalPodAutoscaler %s - %s", a.hpa.Name, op))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Delete - delete the HorizontalPodAutoscaler, not finding it is not an error
+func (a *HorizontalPodAutoscaler) Delete(
+	ctx context.Context,
+	h *helper.Helper,
+) error {
+	err := h.GetClient().Delete(ctx, a.hpa)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("Error deleting HorizontalPodAutoscaler %s: %w", a.hpa.Name, err)
+	}
+	return nil
+}
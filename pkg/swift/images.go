@@ -0,0 +1,129 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetLabelsPreUpgradeCheck returns the labels of the pre-upgrade check pods,
+// which query the recon middleware of the storage servers
+func GetLabelsPreUpgradeCheck() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftPreUpgradeCheck"}
+}
+
+// getImageFields returns the image fields of the spec keyed by component
+func getImageFields(spec *swiftv1beta1.SwiftSpec) map[string]*string {
+	return map[string]*string{
+		"ring":              &spec.SwiftRing.ContainerImage,
+		"account":           &spec.SwiftStorage.ContainerImageAccount,
+		"container":         &spec.SwiftStorage.ContainerImageContainer,
+		"object":            &spec.SwiftStorage.ContainerImageObject,
+		"storage-proxy":     &spec.SwiftStorage.ContainerImageProxy,
+		"storage-memcached": &spec.SwiftStorage.ContainerImageMemcached,
+		"proxy":             &spec.SwiftProxy.ContainerImageProxy,
+		"proxy-memcached":   &spec.SwiftProxy.ContainerImageMemcached,
+	}
+}
+
+// GetImages returns the images of the spec keyed by component
+func GetImages(spec *swiftv1beta1.SwiftSpec) map[string]string {
+	images := map[string]string{}
+	for component, image := range getImageFields(spec) {
+		images[component] = *image
+	}
+	return images
+}
+
+// SetImages sets the images of the components in the spec, components
+// missing in images are kept
+func SetImages(spec *swiftv1beta1.SwiftSpec, images map[string]string) {
+	for component, image := range getImageFields(spec) {
+		if i, ok := images[component]; ok && i != "" {
+			*image = i
+		}
+	}
+}
+
+// GetImageDigests returns the images resolved to the digests run by the ring,
+// storage and proxy pods, so a rollback deploys the same images even if their
+// tags were moved. Images not run by any pod are kept as they are.
+func GetImageDigests(ctx context.Context, h *helper.Helper, namespace string, images map[string]string) (map[string]string, error) {
+	digests := map[string]string{}
+	for _, labels := range []map[string]string{
+		GetLabelsRing(),
+		GetLabelsStorage(),
+		GetLabelsProxy(),
+		GetLabelsProxyPools(),
+	} {
+		pods := &corev1.PodList{}
+		err := h.GetClient().List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(labels))
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods.Items {
+			specImages := map[string]string{}
+			for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+				specImages[c.Name] = c.Image
+			}
+			for _, s := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+				imageID := strings.TrimPrefix(s.ImageID, "docker-pullable://")
+				if strings.Contains(imageID, "@sha256:") {
+					digests[specImages[s.Name]] = imageID
+				}
+			}
+		}
+	}
+
+	resolved := map[string]string{}
+	for component, image := range images {
+		if digest, ok := digests[image]; ok {
+			resolved[component] = digest
+		} else {
+			resolved[component] = image
+		}
+	}
+	return resolved, nil
+}
+
+// SetRollbackCondition sets the RollbackInProgress condition while the images
+// are rolled back and removes it otherwise
+func SetRollbackCondition(conditions *condition.Conditions, images map[string]string) {
+	if len(images) == 0 {
+		conditions.Remove(swiftv1beta1.RollbackInProgressCondition)
+		return
+	}
+	components := make([]string, 0, len(images))
+	for component, image := range images {
+		components = append(components, fmt.Sprintf("%s=%s", component, image))
+	}
+	sort.Strings(components)
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.RollbackInProgressCondition,
+		fmt.Sprintf(swiftv1beta1.RollbackInProgressMessage, strings.Join(components, ", "))))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// The KEDA API is not vendored, unstructured objects are used to avoid a
+// dependency on the KEDA module
+var scaledObjectGVK = schema.GroupVersionKind{
+	Group:   "keda.sh",
+	Version: "v1alpha1",
+	Kind:    "ScaledObject",
+}
+
+// ExpirerTasksDueMetric is the metric of the storage-metrics sidecars with
+// the number of expiration tasks due
+const ExpirerTasksDueMetric = "swift_expirer_tasks_due"
+
+func newScaledObject(name string, namespace string) *unstructured.Unstructured {
+	so := &unstructured.Unstructured{}
+	so.SetGroupVersionKind(scaledObjectGVK)
+	so.SetName(name)
+	so.SetNamespace(namespace)
+	return so
+}
+
+// CreateOrPatchExpirerScaledObject creates or patches the ScaledObject of the
+// object expirer StatefulSet with the given name. It scales on the tasks due
+// reported by the storage pods, they all report the same queue.
+func CreateOrPatchExpirerScaledObject(
+	ctx context.Context,
+	h *helper.Helper,
+	name string,
+	namespace string,
+	labels map[string]string,
+	autoscaling *swiftv1beta1.SwiftStorageExpirerAutoscaling,
+) error {
+	so := newScaledObject(name, namespace)
+
+	op, err := controllerutil.CreateOrPatch(ctx, h.GetClient(), so, func() error {
+		so.SetLabels(labels)
+		err := unstructured.SetNestedMap(so.Object, map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"name":       name,
+			},
+			"minReplicaCount": int64(autoscaling.MinReplicas),
+			"maxReplicaCount": int64(autoscaling.MaxReplicas),
+			"triggers": []interface{}{
+				map[string]interface{}{
+					"type": "prometheus",
+					"metadata": map[string]interface{}{
+						"serverAddress": autoscaling.ServerAddress,
+						"query":         fmt.Sprintf("max(%s{namespace=\"%s\"})", ExpirerTasksDueMetric, namespace),
+						"threshold":     fmt.Sprint(autoscaling.TasksPerReplica),
+					},
+				},
+			},
+		}, "spec")
+		if err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(h.GetBeforeObject(), so, h.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		h.GetLogger().Info(fmt.Sprintf("ScaledObject %s - %s", name, op))
+	}
+	return nil
+}
+
+// DeleteScaledObject deletes the ScaledObject, not finding it or the CRD
+// being missing is not an error
+func DeleteScaledObject(
+	ctx context.Context,
+	h *helper.Helper,
+	name string,
+	namespace string,
+) error {
+	err := h.GetClient().Delete(ctx, newScaledObject(name, namespace))
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) && !apierrors.IsForbidden(err) {
+		return fmt.Errorf("Error deleting ScaledObject %s: %w", name, err)
+	}
+	return nil
+}
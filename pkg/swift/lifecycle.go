@@ -0,0 +1,53 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"strings"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// LifecycleMiddlewarePath is the PYTHONPATH of the lifecycle middleware,
+// which is shipped in the scripts Secret of the proxy
+const LifecycleMiddlewarePath = "/usr/local/bin/container-scripts"
+
+// GetLifecyclePipeline returns the proxy pipeline with the lifecycle
+// middleware if the lifecycle is enabled. It is added right before the
+// proxy-server app, so the object PUTs of all middlewares get the
+// expiration.
+func GetLifecyclePipeline(pipeline string, lifecycle *swiftv1beta1.SwiftProxyLifecycle) string {
+	if lifecycle == nil {
+		return pipeline
+	}
+	filters := strings.Fields(pipeline)
+	if len(filters) == 0 {
+		return pipeline
+	}
+	app := filters[len(filters)-1]
+	filters = append(filters[:len(filters)-1], "lifecycle", app)
+	return strings.Join(filters, " ")
+}
+
+// GetLifecycleExpireAfter returns the default expiration of new objects in
+// seconds, 0 keeps them
+func GetLifecycleExpireAfter(lifecycle *swiftv1beta1.SwiftProxyLifecycle) int64 {
+	if lifecycle == nil {
+		return 0
+	}
+	return int64(lifecycle.DefaultExpireAfterDays) * 24 * 60 * 60
+}
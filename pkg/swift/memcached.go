@@ -0,0 +1,68 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+)
+
+// The Memcached API of the infra-operator is not vendored, unstructured
+// objects are used to avoid a dependency on the infra-operator module
+var memcachedGVK = schema.GroupVersionKind{
+	Group:   "memcached.openstack.org",
+	Version: "v1beta1",
+	Kind:    "Memcached",
+}
+
+// LocalMemcachedServers are the memcache servers without a Memcached CR, the
+// memcached container of each pod
+var LocalMemcachedServers = fmt.Sprintf("127.0.0.1:%d", MemcachedPort)
+
+// GetMemcachedServers returns the memcache_servers of the Memcached CR with
+// the given name from its status, or LocalMemcachedServers without a name.
+// It is empty while the Memcached CR does not exist or has no servers yet.
+func GetMemcachedServers(ctx context.Context, h *helper.Helper, namespace string, name string) (string, error) {
+	if name == "" {
+		return LocalMemcachedServers, nil
+	}
+
+	memcached := &unstructured.Unstructured{}
+	memcached.SetGroupVersionKind(memcachedGVK)
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, memcached)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	} else if meta.IsNoMatchError(err) {
+		return "", fmt.Errorf("memcachedInstance %s requires the Memcached CRD of the infra-operator: %w", name, err)
+	} else if err != nil {
+		return "", err
+	}
+	servers, _, err := unstructured.NestedStringSlice(memcached.Object, "status", "serverList")
+	if err != nil {
+		return "", fmt.Errorf("invalid server list of Memcached %s: %w", name, err)
+	}
+	return strings.Join(servers, ","), nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// isPropagated returns true for the label and annotation keys passed on to
+// the child resources. The keys of the operator, kubectl and the name label
+// used in the selectors are not.
+func isPropagated(key string) bool {
+	return !strings.HasPrefix(key, "swift.openstack.org/") &&
+		!strings.HasPrefix(key, "kubectl.kubernetes.io/") &&
+		key != "app.kubernetes.io/name"
+}
+
+// GetPropagatedMetadata returns the labels or annotations passed on to the
+// child resources
+func GetPropagatedMetadata(m map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range m {
+		if isPropagated(k) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// PropagateMetadata sets the labels and annotations of the Swift CR on a
+// child CR. They take precedence over the ones set on the child directly,
+// the ones removed from the Swift CR are removed from the child.
+func PropagateMetadata(parent metav1.Object, child metav1.Object) {
+	annotations := child.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	labels := propagateMap(GetPropagatedMetadata(parent.GetLabels()), child.GetLabels(), annotations, swiftv1beta1.PropagatedLabelsAnnotation)
+	annotations = propagateMap(GetPropagatedMetadata(parent.GetAnnotations()), annotations, annotations, swiftv1beta1.PropagatedAnnotationsAnnotation)
+	child.SetLabels(labels)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	child.SetAnnotations(annotations)
+}
+
+// propagateMap sets the propagated values on target and removes the ones
+// listed in the record annotation that are no longer propagated
+func propagateMap(propagated map[string]string, target map[string]string, annotations map[string]string, record string) map[string]string {
+	if target == nil {
+		target = map[string]string{}
+	}
+	for _, k := range strings.Split(annotations[record], ",") {
+		if _, ok := propagated[k]; k != "" && !ok {
+			delete(target, k)
+		}
+	}
+	keys := make([]string, 0, len(propagated))
+	for k, v := range propagated {
+		target[k] = v
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		annotations[record] = strings.Join(keys, ",")
+	} else {
+		delete(annotations, record)
+	}
+	if len(target) == 0 {
+		return nil
+	}
+	return target
+}
+
+// AddPropagatedMetadata adds the labels and annotations of a SwiftStorage or
+// SwiftProxy to a generated resource or pod template. The labels and
+// annotations set by the operator take precedence.
+func AddPropagatedMetadata(owner metav1.Object, meta *metav1.ObjectMeta) {
+	meta.Labels = util.MergeStringMaps(meta.Labels, GetPropagatedMetadata(owner.GetLabels()))
+	meta.Annotations = util.MergeStringMaps(meta.Annotations, GetPropagatedMetadata(owner.GetAnnotations()))
+}
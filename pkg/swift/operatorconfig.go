@@ -0,0 +1,94 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"sync"
+	"time"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// DefaultRequeueInterval is used without a SwiftOperatorConfig
+	DefaultRequeueInterval = 10 * time.Second
+	// DefaultResyncInterval is used without a SwiftOperatorConfig
+	DefaultResyncInterval = 60 * time.Second
+)
+
+var (
+	operatorConfig     = swiftv1beta1.SwiftOperatorConfigSpec{}
+	operatorConfigLock sync.RWMutex
+)
+
+// SetOperatorConfig sets the SwiftOperatorConfig used by the controllers, nil
+// resets it to the defaults
+func SetOperatorConfig(spec *swiftv1beta1.SwiftOperatorConfigSpec) {
+	operatorConfigLock.Lock()
+	defer operatorConfigLock.Unlock()
+	if spec == nil {
+		operatorConfig = swiftv1beta1.SwiftOperatorConfigSpec{}
+		return
+	}
+	operatorConfig = *spec.DeepCopy()
+}
+
+func getOperatorConfig() swiftv1beta1.SwiftOperatorConfigSpec {
+	operatorConfigLock.RLock()
+	defer operatorConfigLock.RUnlock()
+	return operatorConfig
+}
+
+// RequeueInterval returns the interval between the reconciles while waiting
+// for a dependency
+func RequeueInterval() time.Duration {
+	if i := getOperatorConfig().RequeueInterval; i > 0 {
+		return time.Duration(i) * time.Second
+	}
+	return DefaultRequeueInterval
+}
+
+// ResyncInterval returns the interval between the reconciles while waiting
+// for pods
+func ResyncInterval() time.Duration {
+	if i := getOperatorConfig().ResyncInterval; i > 0 {
+		return time.Duration(i) * time.Second
+	}
+	return DefaultResyncInterval
+}
+
+// WithOperatorConfig returns the features without the ones disabled in the
+// SwiftOperatorConfig
+func (f Features) WithOperatorConfig() Features {
+	config := getOperatorConfig().Features
+	disabled := func(enabled *bool) bool {
+		return enabled != nil && !*enabled
+	}
+	if disabled(config.Routes) {
+		f.Routes = false
+	}
+	if disabled(config.Autoscaling) {
+		f.Autoscaling = false
+	}
+	if disabled(config.KeystoneEndpoints) {
+		f.KeystoneEndpoints = false
+	}
+	if disabled(config.KEDA) {
+		f.KEDA = false
+	}
+	return f
+}
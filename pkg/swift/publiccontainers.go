@@ -0,0 +1,50 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"fmt"
+	"strings"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetStaticWebPipeline returns the proxy pipeline with the staticweb
+// middleware if there are public containers. It has to follow the auth
+// middlewares.
+func GetStaticWebPipeline(pipeline string, containers []swiftv1beta1.SwiftPublicContainer) string {
+	if len(containers) == 0 {
+		return pipeline
+	}
+	filters := []string{}
+	for _, f := range strings.Fields(pipeline) {
+		filters = append(filters, f)
+		if f == "keystone" {
+			filters = append(filters, "staticweb")
+		}
+	}
+	return strings.Join(filters, " ")
+}
+
+// GetPublicContainerURLs returns the public URLs of the containers
+func GetPublicContainerURLs(containers []swiftv1beta1.SwiftPublicContainer, baseURL string) []string {
+	urls := []string{}
+	for _, c := range containers {
+		urls = append(urls, fmt.Sprintf("%s/v1/%s/%s/", baseURL, c.Account, c.Container))
+	}
+	return urls
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetReadOnlyPipeline returns the proxy pipeline with the read_only
+// middleware in read-only mode. It is added right before the proxy-server
+// app, after the auth middlewares.
+func GetReadOnlyPipeline(pipeline string, readOnly *swiftv1beta1.SwiftProxyReadOnly) string {
+	if readOnly == nil {
+		return pipeline
+	}
+	filters := strings.Fields(pipeline)
+	if len(filters) == 0 {
+		return pipeline
+	}
+	app := filters[len(filters)-1]
+	filters = append(filters[:len(filters)-1], "read_only", app)
+	return strings.Join(filters, " ")
+}
+
+// GetWritableAccounts returns the sorted accounts that stay writable in
+// read-only mode
+func GetWritableAccounts(readOnly *swiftv1beta1.SwiftProxyReadOnly) []string {
+	if readOnly == nil {
+		return nil
+	}
+	accounts := map[string]bool{}
+	for _, a := range readOnly.WritableAccounts {
+		accounts[a] = true
+	}
+	writable := []string{}
+	for a := range accounts {
+		writable = append(writable, a)
+	}
+	sort.Strings(writable)
+	return writable
+}
+
+// WritableAccountsChanged returns true if the writable accounts differ from
+// the ones applied by the last Job
+func WritableAccountsChanged(readOnly *swiftv1beta1.SwiftProxyReadOnly, applied []string) bool {
+	writable := GetWritableAccounts(readOnly)
+	if len(writable) != len(applied) {
+		return true
+	}
+	for i := range writable {
+		if writable[i] != applied[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// SetReadOnlyCondition sets the ClusterReadOnly condition in read-only mode
+// and removes it otherwise
+func SetReadOnlyCondition(conditions *condition.Conditions, readOnly *swiftv1beta1.SwiftProxyReadOnly) {
+	if readOnly == nil {
+		conditions.Remove(swiftv1beta1.ClusterReadOnlyCondition)
+		return
+	}
+	writable := "none"
+	if accounts := GetWritableAccounts(readOnly); len(accounts) > 0 {
+		writable = strings.Join(accounts, ", ")
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.ClusterReadOnlyCondition,
+		fmt.Sprintf(swiftv1beta1.ClusterReadOnlyMessage, writable)))
+}
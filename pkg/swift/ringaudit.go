@@ -0,0 +1,211 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/swift-operator/pkg/ringbuilder"
+)
+
+// RingAuditMaxEntries is the number of ring changes kept in the ring audit
+// ConfigMap, which is limited to 1MiB like any other object
+const RingAuditMaxEntries = 50
+
+// RingAuditEntry is a ring change recorded in the ring audit ConfigMap
+type RingAuditEntry struct {
+	Timestamp string `json:"timestamp"`
+	// RingMd5 of the ring tarball, as reported in the ring sync status
+	RingMd5 string `json:"ringMd5"`
+	// Builder used to build the rings, Job or Native
+	Builder string                     `json:"builder"`
+	Rings   map[string]RingAuditChange `json:"rings"`
+}
+
+// RingAuditChange are the changes of a single ring
+type RingAuditChange struct {
+	// Devices and their weight after the change
+	Devices       map[string]float64 `json:"devices"`
+	Added         []string           `json:"added,omitempty"`
+	Removed       []string           `json:"removed,omitempty"`
+	WeightChanged []string           `json:"weightChanged,omitempty"`
+	PartPower     int                `json:"partPower"`
+	Replicas      int                `json:"replicas"`
+	Balance       float64            `json:"balance"`
+	Dispersion    float64            `json:"dispersion"`
+	// Error reading the ring, if any
+	Error string `json:"error,omitempty"`
+}
+
+// RecordRingAudit appends an entry to the ring audit ConfigMap if the rings
+// changed since the last entry. Entries are never modified, the keys are
+// increasing sequence numbers and only the last RingAuditMaxEntries are kept.
+// The ConfigMap is not owned by the SwiftRing, the history is kept when the
+// SwiftRing is deleted and recreated.
+func RecordRingAudit(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	builder string,
+	labels map[string]string,
+) error {
+	ringCM := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: namespace}, ringCM)
+	if err != nil {
+		return err
+	}
+	tarball, ok := ringCM.BinaryData["swiftrings.tar.gz"]
+	if !ok {
+		return nil
+	}
+
+	audit := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      swiftv1beta1.RingAuditConfigMapName,
+			Namespace: namespace,
+		},
+	}
+	err = h.GetClient().Get(ctx, types.NamespacedName{Name: audit.Name, Namespace: namespace}, audit)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	keys := make([]string, 0, len(audit.Data))
+	for k := range audit.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	last := RingAuditEntry{}
+	seq := 0
+	if len(keys) > 0 {
+		if err := yaml.Unmarshal([]byte(audit.Data[keys[len(keys)-1]]), &last); err != nil {
+			return err
+		}
+		if seq, err = strconv.Atoi(keys[len(keys)-1]); err != nil {
+			return fmt.Errorf("invalid key %s in ConfigMap %s: %w", keys[len(keys)-1], audit.Name, err)
+		}
+	}
+	md5 := GetRingMd5(ringCM)
+	if last.RingMd5 == md5 {
+		return nil
+	}
+
+	files, err := ReadTarGz(tarball)
+	if err != nil {
+		return err
+	}
+	entry := RingAuditEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RingMd5:   md5,
+		Builder:   builder,
+		Rings:     map[string]RingAuditChange{},
+	}
+	for file, data := range files {
+		if !strings.HasSuffix(file, ".ring.gz") {
+			continue
+		}
+		name := strings.TrimSuffix(file, ".ring.gz")
+		ring, err := ringbuilder.Read(bytes.NewReader(data))
+		if err != nil {
+			// Still record the change, the ring may use a newer format
+			entry.Rings[name] = RingAuditChange{Error: err.Error()}
+			continue
+		}
+		entry.Rings[name] = getRingAuditChange(ring, last.Rings[name].Devices)
+	}
+
+	content, err := yaml.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	owner := h.GetBeforeObject()
+	_, err = controllerutil.CreateOrPatch(ctx, h.GetClient(), audit, func() error {
+		audit.Labels = labels
+		if audit.Data == nil {
+			audit.Data = map[string]string{}
+		}
+		audit.Data[fmt.Sprintf("%06d", seq+1)] = string(content)
+		for i := 0; i <= len(keys)-RingAuditMaxEntries; i++ {
+			delete(audit.Data, keys[i])
+		}
+		// Drop the owner reference set by earlier versions
+		refs := []metav1.OwnerReference{}
+		for _, ref := range audit.OwnerReferences {
+			if ref.UID != owner.GetUID() {
+				refs = append(refs, ref)
+			}
+		}
+		audit.OwnerReferences = refs
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	h.GetLogger().Info(fmt.Sprintf("Recorded ring change %s in ConfigMap %s", md5, audit.Name))
+	return nil
+}
+
+// getRingAuditChange compares the devices of the ring to the ones recorded in
+// the previous entry
+func getRingAuditChange(ring *ringbuilder.Ring, previous map[string]float64) RingAuditChange {
+	change := RingAuditChange{
+		Devices:    map[string]float64{},
+		PartPower:  ring.PartPower,
+		Replicas:   len(ring.Replica2Part2Dev),
+		Balance:    math.Round(ring.Balance()*100) / 100,
+		Dispersion: math.Round(ring.Dispersion()*100) / 100,
+	}
+	for _, d := range ring.Devices {
+		if d == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s:%d/%s", d.IP, d.Port, d.Device)
+		change.Devices[name] = d.Weight
+		old, ok := previous[name]
+		if !ok {
+			change.Added = append(change.Added, name)
+		} else if old != d.Weight {
+			change.WeightChanged = append(change.WeightChanged, fmt.Sprintf("%s: %v -> %v", name, old, d.Weight))
+		}
+	}
+	for name := range previous {
+		if _, ok := change.Devices[name]; !ok {
+			change.Removed = append(change.Removed, name)
+		}
+	}
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	sort.Strings(change.WeightChanged)
+	return change
+}
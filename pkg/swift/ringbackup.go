@@ -0,0 +1,132 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// getRingBackupOwner returns the UID recorded in the ring backup Secret,
+// the one of the Swift CR of the SwiftRing as the SwiftRing is recreated
+// by it, or the one of a SwiftRing without a Swift CR
+func getRingBackupOwner(instance *swiftv1beta1.SwiftRing) types.UID {
+	if owner := metav1.GetControllerOf(instance); owner != nil {
+		return owner.UID
+	}
+	return instance.UID
+}
+
+// BackupRings copies the rings and builder files of the ring ConfigMap to
+// the ring backup Secret if they changed. The Secret has no owner, so the
+// rings can be restored after the SwiftRing or its ConfigMap are deleted.
+func BackupRings(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftRing,
+	labels map[string]string,
+) error {
+	namespace := instance.Namespace
+	ringCM := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: namespace}, ringCM)
+	if err != nil {
+		return err
+	}
+	if _, ok := ringCM.BinaryData["swiftrings.tar.gz"]; !ok {
+		return nil
+	}
+
+	backup := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      swiftv1beta1.RingBackupSecretName,
+			Namespace: namespace,
+		},
+	}
+	op, err := controllerutil.CreateOrPatch(ctx, h.GetClient(), backup, func() error {
+		backup.Labels = labels
+		if backup.Annotations == nil {
+			backup.Annotations = map[string]string{}
+		}
+		backup.Annotations[swiftv1beta1.RingBackupOwnerAnnotation] = string(getRingBackupOwner(instance))
+		backup.Data = ringCM.BinaryData
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		h.GetLogger().Info(fmt.Sprintf("Backed up ring %s to Secret %s", GetRingMd5(ringCM), backup.Name))
+	}
+	return nil
+}
+
+// RestoreRings recreates a lost ring ConfigMap from the ring backup Secret,
+// so the next rebalance continues with the backed up builder files. Only the
+// backup of the same Swift CR or SwiftRing is restored. Returns true if the
+// ConfigMap was restored.
+func RestoreRings(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftRing,
+	labels map[string]string,
+) (bool, error) {
+	namespace := instance.Namespace
+	ringCM := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: namespace}, ringCM)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	backup := &corev1.Secret{}
+	err = h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingBackupSecretName, Namespace: namespace}, backup)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if owner := backup.Annotations[swiftv1beta1.RingBackupOwnerAnnotation]; owner != string(getRingBackupOwner(instance)) {
+		h.GetLogger().Info(fmt.Sprintf("Not restoring the rings from Secret %s, it belongs to %q instead of %q",
+			backup.Name, owner, getRingBackupOwner(instance)))
+		return false, nil
+	}
+
+	ringCM = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      swiftv1beta1.RingConfigMapName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		BinaryData: backup.Data,
+	}
+	if err := controllerutil.SetControllerReference(h.GetBeforeObject(), ringCM, h.GetScheme()); err != nil {
+		return false, err
+	}
+	if err := h.GetClient().Create(ctx, ringCM); err != nil {
+		return false, err
+	}
+	h.GetLogger().Info(fmt.Sprintf("Restored ring %s from Secret %s", GetRingMd5(ringCM), backup.Name))
+	return true, nil
+}
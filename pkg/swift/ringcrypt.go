@@ -0,0 +1,56 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// RingBuildersKey is the ring ConfigMap key of the encrypted builder files
+const RingBuildersKey = "swiftbuilders.enc"
+
+// encryptedBuilders is the envelope of the builder files written by the ring
+// rebalance script. The data key is wrapped with the key encryption key
+// KeyID, both use AES-256-GCM with the nonce prepended to the ciphertext.
+type encryptedBuilders struct {
+	Version    int    `json:"version"`
+	KeyID      string `json:"keyId"`
+	WrappedKey []byte `json:"wrappedKey"`
+	Data       []byte `json:"data"`
+}
+
+// GetBuildersKeyID returns the key encryption key used for the builders
+func GetBuildersKeyID(blob []byte) (string, error) {
+	e := encryptedBuilders{}
+	if err := json.Unmarshal(blob, &e); err != nil {
+		return "", err
+	}
+	return e.KeyID, nil
+}
+
+// GetBarbicanKeysEnv returns the Barbican keys as sorted "<name>:<uuid>"
+// entries for the BARBICAN_KEYS environment variable of the rebalance Job
+func GetBarbicanKeysEnv(keys map[string]string) string {
+	entries := make([]string, 0, len(keys))
+	for name, uuid := range keys {
+		entries = append(entries, name+":"+uuid)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// RingBuildQueue serializes the device list changes of the SwiftStorages
+// feeding the rings of one Swift. The SwiftStorage at the head of a queue
+// updates the device list and keeps its position until the rings include
+// it, so the rebalances of several SwiftStorages do not interleave. The
+// queue is kept in memory only and rebuilt after an operator restart. The
+// validating webhook still admits a single SwiftStorage per namespace, as
+// the storage pods share their labels, the queue then only holds it.
+type RingBuildQueue struct {
+	mu     sync.Mutex
+	queues map[string][]string
+}
+
+var ringBuildQueue = &RingBuildQueue{queues: map[string][]string{}}
+
+// GetRingBuildQueue returns the ring build queue of the operator
+func GetRingBuildQueue() *RingBuildQueue {
+	return ringBuildQueue
+}
+
+// GetRingBuildQueueKey returns the queue key of a SwiftStorage, the Swift
+// it belongs to or its namespace if it is deployed on its own
+func GetRingBuildQueueKey(instance *swiftv1beta1.SwiftStorage) string {
+	if owner := metav1.GetControllerOf(instance); owner != nil && owner.Kind == "Swift" {
+		return fmt.Sprintf("%s/%s", instance.Namespace, owner.Name)
+	}
+	return instance.Namespace
+}
+
+// Enqueue adds the SwiftStorage to the queue if it is not queued yet and
+// returns its position, starting at 1 for the head of the queue
+func (q *RingBuildQueue) Enqueue(key string, name string) int32 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, n := range q.queues[key] {
+		if n == name {
+			return int32(i + 1)
+		}
+	}
+	q.queues[key] = append(q.queues[key], name)
+	return int32(len(q.queues[key]))
+}
+
+// Position returns the position of the SwiftStorage in the queue, 0 if it is
+// not queued
+func (q *RingBuildQueue) Position(key string, name string) int32 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, n := range q.queues[key] {
+		if n == name {
+			return int32(i + 1)
+		}
+	}
+	return 0
+}
+
+// Release removes the SwiftStorage from the queue
+func (q *RingBuildQueue) Release(key string, name string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queue := []string{}
+	for _, n := range q.queues[key] {
+		if n != name {
+			queue = append(queue, n)
+		}
+	}
+	if len(queue) == 0 {
+		delete(q.queues, key)
+		return
+	}
+	q.queues[key] = queue
+}
+
+// Prune removes the SwiftStorages that no longer exist from the queue, a
+// deleted SwiftStorage would block the queue otherwise
+func (q *RingBuildQueue) Prune(key string, existing map[string]bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queue := []string{}
+	for _, n := range q.queues[key] {
+		if existing[n] {
+			queue = append(queue, n)
+		}
+	}
+	q.queues[key] = queue
+}
+
+// getDeviceListPoolKey returns the key of the devices of a SwiftStorage in
+// the device list ConfigMap
+func getDeviceListPoolKey(name string) string {
+	return fmt.Sprintf("devices-%s.csv", name)
+}
+
+// getScaleDownListPoolKey returns the key of the devices of a SwiftStorage
+// drained for a scale down in the device list ConfigMap
+func getScaleDownListPoolKey(name string) string {
+	return fmt.Sprintf("scale-down-%s.csv", name)
+}
+
+// GetDeviceListPool returns the devices of a SwiftStorage in the device list
+// ConfigMap
+func GetDeviceListPool(cm *corev1.ConfigMap, name string) (string, bool) {
+	devices, ok := cm.Data[getDeviceListPoolKey(name)]
+	return devices, ok
+}
+
+// GetScaleDownListPool returns the devices of a SwiftStorage drained for a
+// scale down in the device list ConfigMap
+func GetScaleDownListPool(cm *corev1.ConfigMap, name string) string {
+	return cm.Data[getScaleDownListPoolKey(name)]
+}
+
+// EnsureDeviceList sets the devices of the SwiftStorage in the device list
+// ConfigMap shared by all SwiftStorages of the namespace. devices.csv
+// consists of the devices of every SwiftStorage, ordered by name. The
+// devices of a deleted SwiftStorage are kept until its entry is removed from
+// the ConfigMap, the rings are not changed by deleting a SwiftStorage.
+// scale-down.csv lists the "<host>,<device>" of the devices drained for a
+// scale down, the rebalance moves all their partitions at once.
+func EnsureDeviceList(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftStorage,
+	devices string,
+	scaleDown string,
+) (string, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      swiftv1beta1.DeviceConfigMapName,
+			Namespace: instance.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, h.GetClient(), cm, func() error {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[getDeviceListPoolKey(instance.Name)] = devices
+		cm.Data[getScaleDownListPoolKey(instance.Name)] = scaleDown
+		cm.Data["devices.csv"] = mergeDeviceListPools(cm, "devices-")
+		cm.Data["scale-down.csv"] = mergeDeviceListPools(cm, "scale-down-")
+
+		// Every SwiftStorage owns the device list, it is deleted with
+		// the last of them
+		return controllerutil.SetOwnerReference(h.GetBeforeObject(), cm, h.GetScheme())
+	})
+	if err != nil {
+		return "", err
+	}
+	return cm.Data["devices.csv"], nil
+}
+
+// mergeDeviceListPools returns the lists of all SwiftStorages with the given
+// key prefix, ordered by name
+func mergeDeviceListPools(cm *corev1.ConfigMap, prefix string) string {
+	keys := []string{}
+	for k := range cm.Data {
+		if strings.HasPrefix(k, prefix) && strings.HasSuffix(k, ".csv") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var merged strings.Builder
+	for _, k := range keys {
+		merged.WriteString(cm.Data[k])
+	}
+	return merged.String()
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/swift-operator/pkg/ringbuilder"
+)
+
+// RingPartPower is the part power of the account and container rings and the
+// default one of the object rings, the same as used by the rebalance Job
+const RingPartPower = 8
+
+// RingPorts are the server ports of the account, container and object rings
+var RingPorts = map[string]int32{
+	"account":   AccountServerPort,
+	"container": ContainerServerPort,
+	"object":    ObjectServerPort,
+}
+
+// DeviceRingsMetadata and DeviceRingsObject are the rings fields of the
+// devices of the metadata and the object tier in devices.csv
+const (
+	DeviceRingsMetadata = "account:container"
+	DeviceRingsObject   = "object"
+)
+
+// ParseDeviceList parses the devices.csv content, one "host,device,weight"
+// entry per line. Devices of the device inventory and devices placed in the
+// zone of their node have additional port, region and zone fields. Devices
+// of a storage tier have a seventh field with the ":" separated ring kinds
+// they are added to, all rings if it is empty.
+func ParseDeviceList(devices string) ([]ringbuilder.Device, error) {
+	result := []ringbuilder.Device{}
+	for _, line := range strings.Split(devices, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 && len(fields) != 6 && len(fields) != 7 {
+			return nil, fmt.Errorf("invalid device list entry %q", line)
+		}
+		weight, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight in device list entry %q: %w", line, err)
+		}
+		device := ringbuilder.Device{
+			Region: 1,
+			Zone:   1,
+			IP:     fields[0],
+			Device: fields[1],
+			Weight: weight,
+		}
+		if len(fields) >= 6 {
+			// An empty port keeps the default ring ports
+			if fields[3] != "" {
+				port, err := strconv.ParseInt(fields[3], 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid port in device list entry %q: %w", line, err)
+				}
+				device.Port = int32(port)
+			}
+			var err error
+			if device.Region, err = strconv.Atoi(fields[4]); err != nil {
+				return nil, fmt.Errorf("invalid region in device list entry %q: %w", line, err)
+			}
+			if device.Zone, err = strconv.Atoi(fields[5]); err != nil {
+				return nil, fmt.Errorf("invalid zone in device list entry %q: %w", line, err)
+			}
+		}
+		if len(fields) == 7 && fields[6] != "" {
+			device.Rings = strings.Split(fields[6], ":")
+		}
+		result = append(result, device)
+	}
+	return result, nil
+}
+
+// SetDeviceListEntryRings returns the devices.csv line restricted to the
+// given ":" separated ring kinds, e.g. DeviceRingsObject
+func SetDeviceListEntryRings(entry string, rings string) string {
+	entry = strings.TrimSuffix(entry, "\n")
+	if strings.Count(entry, ",") == 2 {
+		// The port field is empty, the default ring ports are used
+		entry += ",,1,1"
+	}
+	return fmt.Sprintf("%s,%s\n", entry, rings)
+}
+
+// IsRingDevice returns true if the device is added to the ring with the
+// given name. The object-<index> rings of the storage policies are object
+// rings.
+func IsRingDevice(d ringbuilder.Device, ring string) bool {
+	if len(d.Rings) == 0 {
+		return true
+	}
+	kind, _, _ := strings.Cut(ring, "-")
+	for _, r := range d.Rings {
+		if r == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// GetInventoryDeviceListEntry returns the devices.csv line of a device of the
+// device inventory
+func GetInventoryDeviceListEntry(d swiftv1beta1.InventoryDevice) string {
+	return fmt.Sprintf("%s,%s,%s,%d,%d,%d\n",
+		d.Host, d.Device, strconv.FormatFloat(d.Weight, 'f', -1, 64), d.Port, d.Region, d.Zone)
+}
+
+// GetRingDeviceDiff compares the devices of each ring in the ring ConfigMap
+// to the devices of the device list added to it. It returns the devices
+// missing in a ring prefixed with "+" and the devices not expected in a ring
+// prefixed with "-", sorted and without duplicates. Rings that can not be
+// read are skipped.
+func GetRingDeviceDiff(ringCM *corev1.ConfigMap, devices []ringbuilder.Device) ([]string, error) {
+	// All devices are expected without a ring name
+	getExpected := func(ring string) map[string]bool {
+		expected := map[string]bool{}
+		for _, d := range devices {
+			if ring == "" || IsRingDevice(d, ring) {
+				expected[fmt.Sprintf("%s/%s", d.IP, d.Device)] = true
+			}
+		}
+		return expected
+	}
+
+	diff := map[string]bool{}
+	tarball, ok := ringCM.BinaryData["swiftrings.tar.gz"]
+	if !ok {
+		for name := range getExpected("") {
+			diff["+"+name] = true
+		}
+	} else {
+		files, err := ReadTarGz(tarball)
+		if err != nil {
+			return nil, err
+		}
+		for name, data := range files {
+			if !strings.HasSuffix(name, ".ring.gz") {
+				continue
+			}
+			ring, err := ringbuilder.Read(bytes.NewReader(data))
+			if err != nil {
+				continue
+			}
+			expected := getExpected(strings.TrimSuffix(name, ".ring.gz"))
+			found := map[string]bool{}
+			for _, d := range ring.Devices {
+				if d == nil {
+					continue
+				}
+				found[fmt.Sprintf("%s/%s", d.IP, d.Device)] = true
+			}
+			for name := range expected {
+				if !found[name] {
+					diff["+"+name] = true
+				}
+			}
+			for name := range found {
+				if !expected[name] {
+					diff["-"+name] = true
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(diff))
+	for name := range diff {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// SetRingUpdatePendingCondition sets the RingUpdatePending condition while
+// the rings do not match the device list and removes it otherwise
+func SetRingUpdatePendingCondition(conditions *condition.Conditions, diff []string) {
+	if len(diff) == 0 {
+		conditions.Remove(swiftv1beta1.RingUpdatePendingCondition)
+		return
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.RingUpdatePendingCondition,
+		fmt.Sprintf(swiftv1beta1.RingUpdatePendingMessage, strings.Join(diff, ", "))))
+}
+
+// ringSpec are the replicas and the server port of a ring
+type ringSpec struct {
+	name     string
+	replicas int
+	port     int32
+}
+
+// getRingSpecs returns the account, container and object rings followed by
+// an object-<index> ring per storage policy, with one replica per fragment
+func getRingSpecs(replicas int, policies []swiftv1beta1.SwiftStoragePolicy) []ringSpec {
+	specs := []ringSpec{}
+	for _, name := range []string{"account", "container", "object"} {
+		specs = append(specs, ringSpec{name: name, replicas: replicas, port: RingPorts[name]})
+	}
+	for _, p := range policies {
+		specs = append(specs, ringSpec{
+			name:     fmt.Sprintf("object-%d", p.Index),
+			replicas: int(p.ECNumDataFragments + p.ECNumParityFragments),
+			port:     ObjectServerPort,
+		})
+	}
+	return specs
+}
+
+// getPartPower returns the part power of the ring, the account and container
+// rings keep RingPartPower
+func (spec ringSpec) getPartPower(partPower int) int {
+	if spec.name == "account" || spec.name == "container" {
+		return RingPartPower
+	}
+	return partPower
+}
+
+// getDevices returns the devices added to the ring with the server port of
+// the ring. Inventory devices have the object server port, the other servers
+// keep the offset of the default ports.
+func (spec ringSpec) getDevices(devices []ringbuilder.Device) []ringbuilder.Device {
+	ringDevices := []ringbuilder.Device{}
+	for _, d := range devices {
+		if !IsRingDevice(d, spec.name) {
+			continue
+		}
+		if d.Port != 0 {
+			d.Port += spec.port - ObjectServerPort
+		} else {
+			d.Port = spec.port
+		}
+		ringDevices = append(ringDevices, d)
+	}
+	return ringDevices
+}
+
+// GetPartPower returns the part power of the object rings set in the
+// SwiftRing spec, RingPartPower if it is not set
+func GetPartPower(spec *swiftv1beta1.SwiftRingSpec) int32 {
+	if spec.PartPower == 0 {
+		return RingPartPower
+	}
+	return spec.PartPower
+}
+
+// GetStoragePoliciesEnv returns the "<index>:<replicas>" entries of the
+// storage policy rings, as used by the rebalance Job
+func GetStoragePoliciesEnv(policies []swiftv1beta1.SwiftStoragePolicy) string {
+	entries := []string{}
+	for _, p := range policies {
+		entries = append(entries, fmt.Sprintf("%d:%d", p.Index, p.ECNumDataFragments+p.ECNumParityFragments))
+	}
+	return strings.Join(entries, " ")
+}
+
+// GetDeviceWeightsEnv returns the sorted "<host>/<device>:<weight>" entries
+// of the device weights, as used by the rebalance Job
+func GetDeviceWeightsEnv(weights map[string]int64) string {
+	entries := []string{}
+	for name, weight := range weights {
+		entries = append(entries, fmt.Sprintf("%s:%d", name, weight))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, " ")
+}
+
+// ApplyDeviceWeights replaces the weights of the devices with the ones set
+// for them in weights
+func ApplyDeviceWeights(devices []ringbuilder.Device, weights map[string]int64) {
+	for i, d := range devices {
+		if weight, ok := weights[fmt.Sprintf("%s/%s", d.IP, d.Device)]; ok {
+			devices[i].Weight = float64(weight)
+		}
+	}
+}
+
+// BuildRings builds the account, container and object rings and the rings
+// of the storage policies from the device list and returns them as the
+// swiftrings.tar.gz content. The object rings use the given part power, the
+// account and container rings RingPartPower. The rings in the previous
+// tarball, if any, are used to minimize the partitions moved, their part
+// power can not be changed as the objects would not be relinked. The result
+// only depends on the arguments.
+func BuildRings(devices []ringbuilder.Device, replicas int, partPower int, policies []swiftv1beta1.SwiftStoragePolicy, previous []byte) ([]byte, error) {
+	previousRings := map[string]*ringbuilder.Ring{}
+	if len(previous) > 0 {
+		files, err := ReadTarGz(previous)
+		if err != nil {
+			return nil, err
+		}
+		for name, data := range files {
+			if !strings.HasSuffix(name, ".ring.gz") {
+				continue
+			}
+			ring, err := ringbuilder.Read(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s: %w", name, err)
+			}
+			previousRings[strings.TrimSuffix(name, ".ring.gz")] = ring
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for _, spec := range getRingSpecs(replicas, policies) {
+		name := spec.name
+		ringPartPower := spec.getPartPower(partPower)
+		if p := previousRings[name]; p != nil && p.PartPower != ringPartPower {
+			return nil, fmt.Errorf("the %s ring has part power %d, the native ring builder can not change it to %d",
+				name, p.PartPower, ringPartPower)
+		}
+		ring, err := ringbuilder.Build(ringPartPower, spec.replicas, spec.getDevices(devices), previousRings[name])
+		if err != nil {
+			return nil, fmt.Errorf("error building the %s ring: %w", name, err)
+		}
+		data := &bytes.Buffer{}
+		if err := ring.Write(data); err != nil {
+			return nil, err
+		}
+		err = tw.WriteHeader(&tar.Header{
+			Name: name + ".ring.gz",
+			Mode: 0644,
+			Size: int64(data.Len()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(data.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetRingBuilderCommands returns the swift-ring-builder commands creating
+// the rings BuildRings builds from the same arguments, as run by the
+// rebalance Job for new rings
+func GetRingBuilderCommands(devices []ringbuilder.Device, replicas int, partPower int, policies []swiftv1beta1.SwiftStoragePolicy) []string {
+	specs := getRingSpecs(replicas, policies)
+	commands := []string{}
+	for _, spec := range specs {
+		commands = append(commands, fmt.Sprintf("swift-ring-builder %s.builder create %d %d 1",
+			spec.name, spec.getPartPower(partPower), spec.replicas))
+	}
+	for _, spec := range specs {
+		for _, d := range spec.getDevices(devices) {
+			commands = append(commands, fmt.Sprintf(
+				"swift-ring-builder %s.builder add --region %d --zone %d --ip %s --port %d --device %s --weight %s",
+				spec.name, d.Region, d.Zone, d.IP, d.Port, d.Device, strconv.FormatFloat(d.Weight, 'f', -1, 64)))
+		}
+	}
+	for _, spec := range specs {
+		commands = append(commands, fmt.Sprintf("swift-ring-builder %s.builder rebalance", spec.name))
+	}
+	return commands
+}
+
+// ReadTarGz returns the content of the regular files in a tar.gz archive
+func ReadTarGz(data []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[hdr.Name] = content
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/env"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// GetRingSyncStatus returns the ring version last synced by each pod
+// matching the given labels. The ring-sync script records these as pod
+// annotations, pods that did not sync a ring yet are skipped.
+func GetRingSyncStatus(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	labels map[string]string,
+) (map[string]swiftv1beta1.RingSyncStatus, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	ringSync := map[string]swiftv1beta1.RingSyncStatus{}
+	for _, p := range podList.Items {
+		md5, ok := p.Annotations[RingMd5Annotation]
+		if !ok {
+			continue
+		}
+		ringSync[p.Name] = swiftv1beta1.RingSyncStatus{
+			Md5:             md5,
+			LastSync:        p.Annotations[RingSyncTimestampAnnotation],
+			LastReplication: p.Annotations[ReplicationAnnotation],
+		}
+	}
+	return ringSync, nil
+}
+
+// GetRingMd5 returns the md5 of the ring tarball in the given ring ConfigMap,
+// matching the value recorded by the ring-sync script
+func GetRingMd5(cm *corev1.ConfigMap) string {
+	return fmt.Sprintf("%x", md5.Sum(cm.BinaryData["swiftrings.tar.gz"]))
+}
+
+// IsRingSynced returns true if all expected pods synced the given ring version
+func IsRingSynced(ringSync map[string]swiftv1beta1.RingSyncStatus, md5 string, pods int) bool {
+	synced := 0
+	for _, s := range ringSync {
+		if s.Md5 == md5 {
+			synced++
+		}
+	}
+	return synced >= pods
+}
+
+// IsReplicationComplete returns true if all expected pods synced the given
+// ring version and completed a replication cycle afterwards
+func IsReplicationComplete(ringSync map[string]swiftv1beta1.RingSyncStatus, md5 string, pods int) bool {
+	complete := 0
+	for _, s := range ringSync {
+		if s.Md5 != md5 {
+			continue
+		}
+		lastSync, err := time.Parse(time.RFC3339, s.LastSync)
+		if err != nil {
+			continue
+		}
+		lastReplication, err := time.Parse(time.RFC3339, s.LastReplication)
+		if err != nil {
+			continue
+		}
+		if lastReplication.After(lastSync) {
+			complete++
+		}
+	}
+	return complete >= pods
+}
+
+// SetScaleUpCondition sets the ScaleUpInProgress condition while fewer than
+// the requested pods are deployed and removes it otherwise
+func SetScaleUpCondition(conditions *condition.Conditions, instance *swiftv1beta1.SwiftStorage, replicas int32) {
+	if replicas >= instance.Spec.Replicas {
+		conditions.Remove(swiftv1beta1.ScaleUpInProgressCondition)
+		return
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.ScaleUpInProgressCondition,
+		fmt.Sprintf(swiftv1beta1.ScaleUpInProgressMessage, instance.Spec.Replicas, instance.Spec.ScaleUpBatchSize, replicas)))
+}
+
+// GetRingSyncEnvVars returns the environment variables needed by the
+// ring-sync script to annotate its own pod
+func GetRingSyncEnvVars() []corev1.EnvVar {
+	return env.MergeEnvs([]corev1.EnvVar{}, map[string]env.Setter{
+		"POD_NAME":  env.DownwardAPI("metadata.name"),
+		"NAMESPACE": env.DownwardAPI("metadata.namespace"),
+	})
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// rolloutLogLines is the number of log lines of a crash-looping container
+// reported in the RolloutStuck condition
+const rolloutLogLines = 5
+
+// StuckRollout is a StatefulSet rollout stuck on a crash-looping pod
+type StuckRollout struct {
+	// Revision of the StatefulSet rolled out
+	Revision string
+	// Revision the StatefulSet is rolled back to
+	CurrentRevision string
+	Pod             string
+	Container       string
+	// Logs is the tail of the log of the last crashed container
+	Logs string
+}
+
+// GetStuckRollout returns the rollout of the StatefulSet if a pod of the new
+// revision is crash-looping for longer than timeout, nil otherwise
+func GetStuckRollout(
+	ctx context.Context,
+	h *helper.Helper,
+	sset *appsv1.StatefulSet,
+	timeout time.Duration,
+) (*StuckRollout, error) {
+	if sset.Status.UpdateRevision == "" || sset.Status.UpdateRevision == sset.Status.CurrentRevision {
+		return nil, nil
+	}
+	podList, err := pod.GetPodListWithLabel(ctx, h, sset.Namespace, sset.Spec.Selector.MatchLabels)
+	if err != nil {
+		return nil, err
+	}
+	pods := getControlledPods(podList.Items, sset)
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	for _, p := range pods {
+		if p.Labels[appsv1.ControllerRevisionHashLabelKey] != sset.Status.UpdateRevision ||
+			time.Since(p.CreationTimestamp.Time) < timeout {
+			continue
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			if cs.State.Waiting == nil || cs.State.Waiting.Reason != "CrashLoopBackOff" {
+				continue
+			}
+			return &StuckRollout{
+				Revision:        sset.Status.UpdateRevision,
+				CurrentRevision: sset.Status.CurrentRevision,
+				Pod:             p.Name,
+				Container:       cs.Name,
+				Logs:            getLogTail(ctx, h, p.Namespace, p.Name, cs.Name),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// getLogTail returns the last lines of the log of the previous, crashed,
+// instance of the container on a single line
+func getLogTail(ctx context.Context, h *helper.Helper, namespace string, podName string, container string) string {
+	lines := int64(rolloutLogLines)
+	raw, err := h.GetKClient().CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  true,
+		TailLines: &lines,
+	}).Do(ctx).Raw()
+	if err != nil {
+		return fmt.Sprintf("log not available: %s", err)
+	}
+	return strings.Join(strings.Split(strings.TrimSpace(string(raw)), "\n"), " | ")
+}
+
+// getControlledPods returns the pods of the StatefulSet, its selector also
+// matches the pods of the metadata tier
+func getControlledPods(pods []corev1.Pod, sset *appsv1.StatefulSet) []corev1.Pod {
+	controlled := []corev1.Pod{}
+	for i := range pods {
+		if metav1.IsControlledBy(&pods[i], sset) {
+			controlled = append(controlled, pods[i])
+		}
+	}
+	return controlled
+}
+
+// GetStatefulSetRevisions returns the current and update revisions of the
+// StatefulSet and the number of its pods running each revision
+func GetStatefulSetRevisions(
+	ctx context.Context,
+	h *helper.Helper,
+	sset *appsv1.StatefulSet,
+) (*swiftv1beta1.SwiftStorageRevisions, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, sset.Namespace, sset.Spec.Selector.MatchLabels)
+	if err != nil {
+		return nil, err
+	}
+	revisions := &swiftv1beta1.SwiftStorageRevisions{
+		CurrentRevision:    sset.Status.CurrentRevision,
+		UpdateRevision:     sset.Status.UpdateRevision,
+		ObservedGeneration: sset.Status.ObservedGeneration,
+		Pods:               map[string]int32{},
+	}
+	for _, p := range getControlledPods(podList.Items, sset) {
+		if revision, ok := p.Labels[appsv1.ControllerRevisionHashLabelKey]; ok {
+			revisions.Pods[revision]++
+		}
+	}
+	return revisions, nil
+}
+
+// RollbackStatefulSet restores the pod template of the current revision of
+// the StatefulSet and deletes its pods of the stuck revision. The
+// StatefulSet controller does not replace pods that never became ready.
+func RollbackStatefulSet(
+	ctx context.Context,
+	h *helper.Helper,
+	sset *appsv1.StatefulSet,
+	stuck *StuckRollout,
+) error {
+	revision := &appsv1.ControllerRevision{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: stuck.CurrentRevision, Namespace: sset.Namespace}, revision)
+	if err != nil {
+		return err
+	}
+	// The revision data is a strategic merge patch replacing the template
+	err = h.GetClient().Patch(ctx, sset, client.RawPatch(types.StrategicMergePatchType, revision.Data.Raw))
+	if err != nil {
+		return err
+	}
+
+	podList, err := pod.GetPodListWithLabel(ctx, h, sset.Namespace, sset.Spec.Selector.MatchLabels)
+	if err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		p := &podList.Items[i]
+		if !metav1.IsControlledBy(p, sset) || p.Labels[appsv1.ControllerRevisionHashLabelKey] != stuck.Revision {
+			continue
+		}
+		if err := h.GetClient().Delete(ctx, p); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	h.GetLogger().Info(fmt.Sprintf("Rolled back StatefulSet %s from revision %s to %s", sset.Name, stuck.Revision, stuck.CurrentRevision))
+	return nil
+}
+
+// SetRolloutStuckCondition sets the RolloutStuck condition if the rollout is
+// stuck and removes it otherwise
+func SetRolloutStuckCondition(conditions *condition.Conditions, stuck *StuckRollout, rolledBack bool) {
+	if stuck == nil {
+		conditions.Remove(swiftv1beta1.RolloutStuckCondition)
+		return
+	}
+	if rolledBack {
+		conditions.Set(condition.TrueCondition(
+			swiftv1beta1.RolloutStuckCondition,
+			fmt.Sprintf(swiftv1beta1.RolloutStuckRolledBackMessage, stuck.CurrentRevision, stuck.Container, stuck.Pod, stuck.Logs)))
+		return
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.RolloutStuckCondition,
+		fmt.Sprintf(swiftv1beta1.RolloutStuckMessage, stuck.Revision, stuck.Container, stuck.Pod, stuck.Logs)))
+}
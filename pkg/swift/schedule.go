@@ -0,0 +1,64 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"fmt"
+	"time"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// IsScheduleActive returns true if the cron schedule started a window of the
+// given duration that did not end yet at now. The schedule is in UTC.
+func IsScheduleActive(schedule string, duration time.Duration, now time.Time) (bool, error) {
+	_, active, err := GetScheduleWindowStart(schedule, duration, now)
+	return active, err
+}
+
+// GetScheduleWindowStart returns the start of the window of the given
+// duration in effect at now, the second value is false outside of the
+// windows. The schedule is in UTC.
+func GetScheduleWindowStart(schedule string, duration time.Duration, now time.Time) (time.Time, bool, error) {
+	s, err := swiftv1beta1.ParseSchedule(schedule)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	now = now.UTC().Truncate(time.Minute)
+	for t := now; now.Sub(t) < duration; t = t.Add(-time.Minute) {
+		if s.Matches(t) {
+			return t, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+// GetActiveSchedules returns the names of the scaling schedule windows in
+// effect at now
+func GetActiveSchedules(schedules []swiftv1beta1.SwiftScalingSchedule, now time.Time) ([]string, error) {
+	var active []string
+	for _, s := range schedules {
+		ok, err := IsScheduleActive(s.Schedule, s.Duration.Duration, now)
+		if err != nil {
+			return nil, fmt.Errorf("scaling schedule %s: %w", s.Name, err)
+		}
+		if ok {
+			active = append(active, s.Name)
+		}
+	}
+	return active, nil
+}
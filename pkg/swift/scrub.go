@@ -0,0 +1,85 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// ScrubChecksumZeroByteOnly only runs the zero byte file passes of the
+	// object auditor
+	ScrubChecksumZeroByteOnly = "ZeroByteOnly"
+
+	// scrubAll and scrubNone are the scrub states allowing all or no pods
+	// to run the object auditor
+	scrubAll  = "all"
+	scrubNone = "none"
+)
+
+// GetScrubState returns the ordinals of the storage pods allowed to run the
+// object auditor at now, separated by spaces. The scrub window is split into
+// equal slots and each slot is assigned the pods whose ordinal modulo the
+// number of slots is the slot index, so that at most MaxConcurrentPods pods
+// audit at the same time.
+func GetScrubState(instance *swiftv1beta1.SwiftStorage, now time.Time) (string, error) {
+	if instance.Spec.Scrub == nil || instance.Spec.Scrub.Window == nil {
+		return scrubAll, nil
+	}
+	window := instance.Spec.Scrub.Window
+	start, active, err := GetScheduleWindowStart(window.Schedule, window.Duration.Duration, now)
+	if err != nil || !active {
+		return scrubNone, err
+	}
+
+	replicas := int64(instance.Spec.Replicas)
+	concurrent := int64(window.MaxConcurrentPods)
+	if concurrent < 1 {
+		concurrent = 1
+	}
+	slots := (replicas + concurrent - 1) / concurrent
+	if slots < 1 {
+		return scrubNone, nil
+	}
+	slot := int64(now.UTC().Sub(start) / (window.Duration.Duration / time.Duration(slots)))
+	if slot >= slots {
+		slot = slots - 1
+	}
+
+	ordinals := []string{}
+	for o := slot; o < replicas; o += slots {
+		ordinals = append(ordinals, strconv.FormatInt(o, 10))
+	}
+	return strings.Join(ordinals, " "), nil
+}
+
+// GetObjectAuditorArgs returns the extra arguments of the object auditor,
+// -z only runs the zero byte file passes at the given rate
+func GetObjectAuditorArgs(scrub *swiftv1beta1.SwiftStorageScrub) []string {
+	if scrub == nil || scrub.Checksum != ScrubChecksumZeroByteOnly {
+		return nil
+	}
+	zbf := scrub.ZeroByteFilesPerSecond
+	if zbf < 1 {
+		zbf = 50
+	}
+	return []string{"-z", strconv.Itoa(int(zbf))}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// GrantedSecretNamespaceLabel is the namespace of the Secret a granted
+	// Secret is copied from
+	GrantedSecretNamespaceLabel = "swift.openstack.org/granted-secret-namespace"
+	// GrantedSecretAnnotation is the <namespace>/<name> reference of the
+	// Secret a granted Secret is copied from
+	GrantedSecretAnnotation = "swift.openstack.org/granted-secret"
+)
+
+// GetLocalSecretName returns the name of the Secret in the namespace of the
+// referencing resource. Secrets of other namespaces, referenced as
+// <namespace>/<name>, are copied to <namespace>-<name>.
+func GetLocalSecretName(ref string) string {
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		return ref
+	}
+	return fmt.Sprintf("%s-%s", namespace, name)
+}
+
+// IsSecretGranted returns true if a SwiftSecretGrant in the namespace of the
+// Secret grants it to the given namespace
+func IsSecretGranted(ctx context.Context, c client.Client, secretNamespace string, secretName string, namespace string) (bool, error) {
+	grants := &swiftv1beta1.SwiftSecretGrantList{}
+	if err := c.List(ctx, grants, client.InNamespace(secretNamespace)); err != nil {
+		return false, err
+	}
+	for i := range grants.Items {
+		if grants.Items[i].DeletionTimestamp.IsZero() && grants.Items[i].Grants(namespace, secretName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EnsureGrantedSecret copies the Secret referenced as <namespace>/<name> to
+// the namespace of the instance of the helper, if a SwiftSecretGrant allows
+// it. The instance is added as an owner of the copy, which is kept in sync
+// with the Secret by the SwiftSecretGrant controller. Local references are
+// not changed. An existing Secret with the name of the copy that is not a
+// copy of the referenced Secret is never overwritten.
+func EnsureGrantedSecret(ctx context.Context, h *helper.Helper, ref string) error {
+	secretNamespace, secretName, found := strings.Cut(ref, "/")
+	owner := h.GetBeforeObject()
+	if !found || secretNamespace == owner.GetNamespace() {
+		return nil
+	}
+
+	granted, err := IsSecretGranted(ctx, h.GetClient(), secretNamespace, secretName, owner.GetNamespace())
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return fmt.Errorf("Secret %s is not granted to namespace %s by a SwiftSecretGrant", ref, owner.GetNamespace())
+	}
+
+	source := &corev1.Secret{}
+	err = h.GetClient().Get(ctx, types.NamespacedName{Name: secretName, Namespace: secretNamespace}, source)
+	if err != nil {
+		return err
+	}
+
+	copied := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetLocalSecretName(ref),
+			Namespace: owner.GetNamespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, h.GetClient(), copied, func() error {
+		if !copied.CreationTimestamp.IsZero() && copied.Annotations[GrantedSecretAnnotation] != ref {
+			return fmt.Errorf("Secret %s/%s exists and is not a copy of the granted Secret %s",
+				copied.Namespace, copied.Name, ref)
+		}
+		if copied.Labels == nil {
+			copied.Labels = map[string]string{}
+		}
+		copied.Labels[GrantedSecretNamespaceLabel] = secretNamespace
+		if copied.Annotations == nil {
+			copied.Annotations = map[string]string{}
+		}
+		copied.Annotations[GrantedSecretAnnotation] = ref
+		copied.Type = source.Type
+		copied.Data = source.Data
+		// The copy is shared by the resources referencing the Secret
+		return controllerutil.SetOwnerReference(owner, copied, h.GetScheme())
+	})
+	return err
+}
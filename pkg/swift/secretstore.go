@@ -0,0 +1,73 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// SecretStoreCSIDriver is the name of the Secrets Store CSI driver
+	SecretStoreCSIDriver = "secrets-store.csi.k8s.io"
+
+	// ServicePasswordPlaceholder is rendered into the proxy config instead
+	// of the password if it is mounted from a secret store. swift-init.sh
+	// replaces it with the content of SERVICE_PASSWORD_FILE.
+	ServicePasswordPlaceholder = "%SERVICE_PASSWORD%"
+
+	// CredentialsMountPath is where the Keystone credentials of a secret
+	// store are mounted
+	CredentialsMountPath = "/var/lib/config-data/credentials"
+)
+
+// GetSecretStoreVolumeSource returns a Secrets Store CSI volume using the
+// SecretProviderClass of the store
+func GetSecretStoreVolumeSource(store *swiftv1beta1.SecretStore) corev1.VolumeSource {
+	readOnly := true
+	csi := &corev1.CSIVolumeSource{
+		Driver:   SecretStoreCSIDriver,
+		ReadOnly: &readOnly,
+		VolumeAttributes: map[string]string{
+			"secretProviderClass": store.SecretProviderClass,
+		},
+	}
+	if store.NodePublishSecretRef != "" {
+		csi.NodePublishSecretRef = &corev1.LocalObjectReference{Name: store.NodePublishSecretRef}
+	}
+	return corev1.VolumeSource{CSI: csi}
+}
+
+// GetSwiftConfVolume returns the swiftconf volume, mounted from the secret
+// store if one is given and from the Secret otherwise
+func GetSwiftConfVolume(secretName string, store *swiftv1beta1.SecretStore) corev1.Volume {
+	if store != nil {
+		return corev1.Volume{
+			Name:         "swiftconf",
+			VolumeSource: GetSecretStoreVolumeSource(store),
+		}
+	}
+	return corev1.Volume{
+		Name: "swiftconf",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// imagePullReasons are the waiting reasons of containers whose image can not
+// be pulled
+var imagePullReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+	"InvalidImageName": true,
+}
+
+// GetStallDiagnosis returns the known causes a SwiftStorage is waiting for:
+// a missing ring ConfigMap, rings not matching the device list, Pending
+// PVCs, unschedulable pods and containers failing to pull their image or
+// crash-looping
+func GetStallDiagnosis(
+	ctx context.Context,
+	h *helper.Helper,
+	instance *swiftv1beta1.SwiftStorage,
+	labels map[string]string,
+) ([]string, error) {
+	diagnosis := []string{}
+
+	ringCM := &corev1.ConfigMap{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: swiftv1beta1.RingConfigMapName, Namespace: instance.Namespace}, ringCM)
+	if apierrors.IsNotFound(err) {
+		diagnosis = append(diagnosis, fmt.Sprintf("ring ConfigMap %s missing", swiftv1beta1.RingConfigMapName))
+	} else if err != nil {
+		return nil, err
+	}
+	if c := instance.Status.Conditions.Get(swiftv1beta1.RingUpdatePendingCondition); c != nil {
+		diagnosis = append(diagnosis, c.Message)
+	}
+
+	podList, err := pod.GetPodListWithLabel(ctx, h, instance.Namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	for _, p := range pods {
+		for _, v := range p.Spec.Volumes {
+			if v.PersistentVolumeClaim == nil {
+				continue
+			}
+			claim := &corev1.PersistentVolumeClaim{}
+			err := h.GetClient().Get(ctx, types.NamespacedName{Name: v.PersistentVolumeClaim.ClaimName, Namespace: p.Namespace}, claim)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			if err == nil && claim.Status.Phase == corev1.ClaimPending {
+				diagnosis = append(diagnosis, fmt.Sprintf("PersistentVolumeClaim %s is Pending", claim.Name))
+			}
+		}
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+				diagnosis = append(diagnosis, fmt.Sprintf("pod %s is unschedulable: %s", p.Name, c.Message))
+			}
+		}
+		statuses := append(p.Status.InitContainerStatuses, p.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			w := cs.State.Waiting
+			if w == nil {
+				continue
+			}
+			if imagePullReasons[w.Reason] {
+				diagnosis = append(diagnosis, fmt.Sprintf("container %s of pod %s can not pull image %s: %s", cs.Name, p.Name, cs.Image, w.Reason))
+			} else if w.Reason == "CrashLoopBackOff" {
+				diagnosis = append(diagnosis, fmt.Sprintf("container %s of pod %s is crash-looping", cs.Name, p.Name))
+			}
+		}
+	}
+	return diagnosis, nil
+}
+
+// SetStalledCondition sets the Stalled condition with the diagnosis of the
+// wait
+func SetStalledCondition(conditions *condition.Conditions, deadline int32, diagnosis []string) {
+	message := swiftv1beta1.StalledUnknownMessage
+	if len(diagnosis) > 0 {
+		message = strings.Join(diagnosis, "; ")
+	}
+	conditions.Set(condition.TrueCondition(
+		swiftv1beta1.StalledCondition,
+		fmt.Sprintf(swiftv1beta1.StalledMessage, deadline, message)))
+}
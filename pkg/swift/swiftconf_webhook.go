@@ -0,0 +1,112 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// SwiftConfSecretValidatorPath is the path the swift.conf Secret
+	// validation webhook is served on
+	SwiftConfSecretValidatorPath = "/validate-swift-conf-secret"
+
+	// SwiftConfSecretLabel is set on the swift.conf Secrets created by the
+	// operator, only the updates of these are sent to the webhook
+	SwiftConfSecretLabel = "swift.openstack.org/swift-conf"
+)
+
+// The objectSelector on SwiftConfSecretLabel is added by
+// config/webhook/swiftconf_webhook_patch.yaml. Failing closed only blocks the
+// updates of the labeled swift.conf Secrets while the operator is down, the
+// other Secrets are not sent to the webhook.
+//+kubebuilder:webhook:path=/validate-swift-conf-secret,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=secrets,verbs=update,versions=v1,name=vswiftconf.kb.io,admissionReviewVersions=v1
+
+// SwiftConfSecretValidator rejects changes of swift_hash_path_prefix and
+// swift_hash_path_suffix in existing swift.conf Secrets. The hashes define
+// where objects are placed, changing them makes all stored data unreachable.
+// A swift.conf Secret supplied by the user is not labeled, the Swift
+// controller verifies it against the hash path recorded in its status.
+type SwiftConfSecretValidator struct {
+	decoder *admission.Decoder
+}
+
+// NewSwiftConfSecretValidator returns an initialized SwiftConfSecretValidator
+func NewSwiftConfSecretValidator(decoder *admission.Decoder) *SwiftConfSecretValidator {
+	return &SwiftConfSecretValidator{
+		decoder: decoder,
+	}
+}
+
+// Handle implements admission.Handler
+func (v *SwiftConfSecretValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	secret := &corev1.Secret{}
+	if err := v.decoder.Decode(req, secret); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	oldSecret := &corev1.Secret{}
+	if err := v.decoder.DecodeRaw(req.OldObject, oldSecret); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	oldConf, ok := oldSecret.Data["swift.conf"]
+	if !ok || oldSecret.Labels[SwiftConfSecretLabel] != "true" {
+		return admission.Allowed("")
+	}
+
+	for _, key := range []string{"swift_hash_path_prefix", "swift_hash_path_suffix"} {
+		if GetSwiftConfValue(oldConf, key) == GetSwiftConfValue(secret.Data["swift.conf"], key) {
+			continue
+		}
+		if secret.Annotations[swiftv1beta1.HashChangeAnnotation] == "true" {
+			return admission.Allowed(fmt.Sprintf("%s change allowed by the %s annotation", key, swiftv1beta1.HashChangeAnnotation))
+		}
+		return admission.Denied(fmt.Sprintf(
+			"changing %s of an existing Swift cluster makes all stored objects unreachable, set the %s annotation to \"true\" to force it",
+			key, swiftv1beta1.HashChangeAnnotation))
+	}
+
+	return admission.Allowed("")
+}
+
+// GetSwiftConfValue returns the value of the given option in swift.conf, or
+// an empty string if it is not set
+func GetSwiftConfValue(conf []byte, key string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(conf))
+	for scanner.Scan() {
+		k, v, found := strings.Cut(scanner.Text(), "=")
+		if found && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/pod"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// terminationMessageLength is the number of bytes of a termination message
+// reported in the status, the end of the message is kept
+const terminationMessageLength = 512
+
+// SetTerminationMessagePolicy makes the containers of the pod fall back to
+// the tail of their log as termination message when they fail without
+// writing one
+func SetTerminationMessagePolicy(spec *corev1.PodSpec) {
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+	}
+}
+
+// GetContainerTerminations returns the last failed termination of the
+// containers of the pods matching the given labels, keyed by
+// "<pod>/<container>". Containers that did not fail are skipped.
+func GetContainerTerminations(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	labels map[string]string,
+) (map[string]swiftv1beta1.ContainerTermination, error) {
+	podList, err := pod.GetPodListWithLabel(ctx, h, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	terminations := map[string]swiftv1beta1.ContainerTermination{}
+	for _, p := range podList.Items {
+		statuses := append(p.Status.InitContainerStatuses, p.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			t := cs.State.Terminated
+			if t == nil || t.ExitCode == 0 {
+				t = cs.LastTerminationState.Terminated
+			}
+			if t == nil || t.ExitCode == 0 {
+				continue
+			}
+			message := t.Message
+			if len(message) > terminationMessageLength {
+				message = message[len(message)-terminationMessageLength:]
+			}
+			terminations[fmt.Sprintf("%s/%s", p.Name, cs.Name)] = swiftv1beta1.ContainerTermination{
+				ExitCode:   t.ExitCode,
+				Reason:     t.Reason,
+				Message:    message,
+				FinishedAt: t.FinishedAt.UTC().Format(time.RFC3339),
+			}
+		}
+	}
+	return terminations, nil
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/configmap"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+	"github.com/openstack-k8s-operators/lib-common/modules/common/util"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+const (
+	// TopologyAwareHintsAnnotation enables topology aware hints up to
+	// Kubernetes 1.26
+	TopologyAwareHintsAnnotation = "service.kubernetes.io/topology-aware-hints"
+	// TopologyModeAnnotation enables topology aware routing since
+	// Kubernetes 1.27
+	TopologyModeAnnotation = "service.kubernetes.io/topology-mode"
+	// SelectedNodeAnnotation is set by the scheduler on claims bound when
+	// their first pod is scheduled
+	SelectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+	// ZoneAffinityMountPath is where swift-init.sh looks up the ring zones
+	// of the node of a proxy pod
+	ZoneAffinityMountPath = "/var/lib/config-data/zone-affinity"
+)
+
+// SetTopologyAwareRouting sets or removes the topology aware routing
+// annotations of the Service, so kube-proxy prefers endpoints in the zone of
+// the client, and sets its internal traffic policy, Cluster if empty. They
+// are patched separately as the Service helpers of lib-common do not remove
+// annotations.
+func SetTopologyAwareRouting(
+	ctx context.Context,
+	h *helper.Helper,
+	name string,
+	namespace string,
+	enabled bool,
+	internalTrafficPolicy string,
+) error {
+	svc := &corev1.Service{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, svc)
+	if err != nil {
+		return err
+	}
+
+	original := svc.DeepCopy()
+	_, hasHints := svc.Annotations[TopologyAwareHintsAnnotation]
+	_, hasMode := svc.Annotations[TopologyModeAnnotation]
+	if enabled && (!hasHints || !hasMode) {
+		if svc.Annotations == nil {
+			svc.Annotations = map[string]string{}
+		}
+		svc.Annotations[TopologyAwareHintsAnnotation] = "auto"
+		svc.Annotations[TopologyModeAnnotation] = "Auto"
+	} else if !enabled && (hasHints || hasMode) {
+		delete(svc.Annotations, TopologyAwareHintsAnnotation)
+		delete(svc.Annotations, TopologyModeAnnotation)
+	}
+	policy := corev1.ServiceInternalTrafficPolicyCluster
+	if internalTrafficPolicy != "" {
+		policy = corev1.ServiceInternalTrafficPolicyType(internalTrafficPolicy)
+	}
+	svc.Spec.InternalTrafficPolicy = &policy
+	if equality.Semantic.DeepEqual(original, svc) {
+		return nil
+	}
+
+	if err := h.GetClient().Patch(ctx, svc, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	h.GetLogger().Info(fmt.Sprintf("Service %s - topology aware routing set to %t, internal traffic policy %s", name, enabled, policy))
+	return nil
+}
+
+// GetZoneAffinityConfigMapName returns the name of the ConfigMap with the
+// ring zones of the nodes of a SwiftProxy
+func GetZoneAffinityConfigMapName(name string) string {
+	return name + "-zone-affinity"
+}
+
+// GetZoneAffinities returns the ring regions and zones of the storage pods
+// in the topology zone of each node as "r<region>z<zone>" lists by node
+// name. The ring zones are the ones SwiftStorages with ZoneAwareRings
+// assigned to the topology zones, the list of nodes in other zones is empty.
+func GetZoneAffinities(ctx context.Context, h *helper.Helper, namespace string) (map[string]string, error) {
+	storages := &swiftv1beta1.SwiftStorageList{}
+	if err := h.GetClient().List(ctx, storages, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	ringZones := map[string][]string{}
+	for _, storage := range storages.Items {
+		region := storage.Spec.Region
+		if region < 1 {
+			region = 1
+		}
+		for zone, id := range storage.Status.RingZones {
+			ringZones[zone] = append(ringZones[zone], fmt.Sprintf("r%dz%d", region, id))
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := h.GetClient().List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	affinities := map[string]string{}
+	for _, node := range nodes.Items {
+		zones := ringZones[node.Labels[corev1.LabelTopologyZone]]
+		sort.Strings(zones)
+		affinities[node.Name] = strings.Join(zones, ", ")
+	}
+	return affinities, nil
+}
+
+// GetZoneAffinityTemplates returns the template of the ConfigMap with the
+// ring zones of the nodes, none if topology aware routing is disabled
+func GetZoneAffinityTemplates(
+	ctx context.Context,
+	h *helper.Helper,
+	owner client.Object,
+	kind string,
+	enabled bool,
+	labels map[string]string,
+) ([]util.Template, error) {
+	if !enabled {
+		return nil, nil
+	}
+	affinities, err := GetZoneAffinities(ctx, h, owner.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	return []util.Template{
+		{
+			Name:         GetZoneAffinityConfigMapName(owner.GetName()),
+			Namespace:    owner.GetNamespace(),
+			Type:         util.TemplateTypeNone,
+			InstanceType: kind,
+			Labels:       labels,
+			CustomData:   affinities,
+		},
+	}, nil
+}
+
+// EnsureZoneAffinityConfigMap creates the ConfigMap with the ring zones of
+// the nodes the proxy pods prefer with read and write affinity, or deletes
+// it if topology aware routing is disabled
+func EnsureZoneAffinityConfigMap(
+	ctx context.Context,
+	h *helper.Helper,
+	owner client.Object,
+	kind string,
+	enabled bool,
+	labels map[string]string,
+) error {
+	if !enabled {
+		name := GetZoneAffinityConfigMapName(owner.GetName())
+		err := h.GetClient().Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()}})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	tpl, err := GetZoneAffinityTemplates(ctx, h, owner, kind, enabled, labels)
+	if err != nil {
+		return err
+	}
+	return configmap.EnsureConfigMaps(ctx, h, owner, tpl, nil)
+}
+
+// GetZoneAffinityVolume returns the volume of the ring zones of the nodes,
+// mounted in the swift-init container of the proxy pods
+func GetZoneAffinityVolume(name string) corev1.Volume {
+	optional := true
+	return corev1.Volume{
+		Name: "zone-affinity",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: GetZoneAffinityConfigMapName(name),
+				},
+				Optional: &optional,
+			},
+		},
+	}
+}
+
+// GetZoneAffinityVolumeMount returns the mount of the ring zones of the
+// nodes in the swift-init container
+func GetZoneAffinityVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "zone-affinity",
+		MountPath: ZoneAffinityMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// GetNodeZone returns the topology zone of the node hosting the pod, or of
+// the node selected for the claim if the pod does not exist, e.g. a pod
+// removed by a scale down. Nodes without a zone label return "".
+func GetNodeZone(
+	ctx context.Context,
+	h *helper.Helper,
+	namespace string,
+	podName string,
+	claim *corev1.PersistentVolumeClaim,
+) (string, error) {
+	nodeName := ""
+	p := &corev1.Pod{}
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: podName, Namespace: namespace}, p)
+	if err == nil {
+		nodeName = p.Spec.NodeName
+	} else if !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	if nodeName == "" {
+		nodeName = claim.Annotations[SelectedNodeAnnotation]
+	}
+	if nodeName == "" {
+		return "", fmt.Errorf("node of pod %s not known", podName)
+	}
+
+	node := &corev1.Node{}
+	err = h.GetClient().Get(ctx, types.NamespacedName{Name: nodeName}, node)
+	if err != nil {
+		return "", err
+	}
+	return node.Labels[corev1.LabelTopologyZone], nil
+}
+
+// GetRingZone returns the ring zone of the topology zone. Topology zones not
+// in zones get the next free ring zone, starting at 1. Ring zones are never
+// reassigned, as the devices already in a ring keep their zone.
+func GetRingZone(zones map[string]int32, zone string) int32 {
+	if id, ok := zones[zone]; ok {
+		return id
+	}
+	next := int32(1)
+	for _, id := range zones {
+		if id >= next {
+			next = id + 1
+		}
+	}
+	zones[zone] = next
+	return next
+}
@@ -0,0 +1,26 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+// GetLabelsUsageExport returns the labels of the usage export pods
+func GetLabelsUsageExport() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": "SwiftUsageExport"}
+}
+
+// UsageExportProxySelector selects the pods of the proxy and of its pools,
+// whose access logs are exported
+const UsageExportProxySelector = "app.kubernetes.io/name in (SwiftProxy,SwiftProxyPool)"
@@ -0,0 +1,37 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"github.com/openstack-k8s-operators/lib-common/modules/common/condition"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// SetInvalidSpecCondition sets the given ready conditions to False with the
+// error of a spec the validating webhook would have rejected. Nothing is
+// reconciled until the spec is fixed.
+func SetInvalidSpecCondition(conditions *condition.Conditions, err error, types ...condition.Type) {
+	for _, t := range types {
+		conditions.Set(condition.FalseCondition(
+			t,
+			condition.ErrorReason,
+			condition.SeverityError,
+			swiftv1beta1.InvalidSpecMessage,
+			err.Error()))
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swift
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openstack-k8s-operators/lib-common/modules/common/helper"
+
+	swiftv1beta1 "github.com/openstack-k8s-operators/swift-operator/api/v1beta1"
+)
+
+// The VerticalPodAutoscaler API is not vendored, unstructured objects are
+// used to avoid a dependency on the autoscaler module
+var vpaGVK = schema.GroupVersionKind{
+	Group:   "autoscaling.k8s.io",
+	Version: "v1",
+	Kind:    "VerticalPodAutoscaler",
+}
+
+type vpaStatus struct {
+	Recommendation struct {
+		ContainerRecommendations []struct {
+			ContainerName string              `json:"containerName"`
+			Target        corev1.ResourceList `json:"target,omitempty"`
+			LowerBound    corev1.ResourceList `json:"lowerBound,omitempty"`
+			UpperBound    corev1.ResourceList `json:"upperBound,omitempty"`
+		} `json:"containerRecommendations,omitempty"`
+	} `json:"recommendation,omitempty"`
+}
+
+func newVPA(name string, namespace string) *unstructured.Unstructured {
+	vpa := &unstructured.Unstructured{}
+	vpa.SetGroupVersionKind(vpaGVK)
+	vpa.SetName(name)
+	vpa.SetNamespace(namespace)
+	return vpa
+}
+
+// IsVPANotInstalled returns true if the error is caused by the
+// VerticalPodAutoscaler CRD missing in the cluster
+func IsVPANotInstalled(err error) bool {
+	return meta.IsNoMatchError(err)
+}
+
+// CreateOrPatchRecommendationVPA creates or patches a VerticalPodAutoscaler in
+// recommendation-only mode targeting the given StatefulSet
+func CreateOrPatchRecommendationVPA(
+	ctx context.Context,
+	h *helper.Helper,
+	statefulSetName string,
+	namespace string,
+	labels map[string]string,
+) error {
+	vpa := newVPA(statefulSetName, namespace)
+
+	op, err := controllerutil.CreateOrPatch(ctx, h.GetClient(), vpa, func() error {
+		vpa.SetLabels(labels)
+		err := unstructured.SetNestedMap(vpa.Object, map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"name":       statefulSetName,
+			},
+			"updatePolicy": map[string]interface{}{
+				"updateMode": "Off",
+			},
+		}, "spec")
+		if err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(h.GetBeforeObject(), vpa, h.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		h.GetLogger().Info(fmt.Sprintf("VerticalPodAutoscaler %s - %s", statefulSetName, op))
+	}
+	return nil
+}
+
+// GetVPARecommendations returns the resources recommended per container by
+// the VerticalPodAutoscaler
+func GetVPARecommendations(
+	ctx context.Context,
+	h *helper.Helper,
+	name string,
+	namespace string,
+) (map[string]swiftv1beta1.ContainerResourceRecommendation, error) {
+	vpa := newVPA(name, namespace)
+	err := h.GetClient().Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, vpa)
+	if err != nil {
+		return nil, err
+	}
+
+	statusMap, found, err := unstructured.NestedMap(vpa.Object, "status")
+	if err != nil || !found {
+		return nil, err
+	}
+	status := vpaStatus{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusMap, &status); err != nil {
+		return nil, err
+	}
+
+	recommendations := map[string]swiftv1beta1.ContainerResourceRecommendation{}
+	for _, r := range status.Recommendation.ContainerRecommendations {
+		recommendations[r.ContainerName] = swiftv1beta1.ContainerResourceRecommendation{
+			Target:     r.Target,
+			LowerBound: r.LowerBound,
+			UpperBound: r.UpperBound,
+		}
+	}
+	return recommendations, nil
+}
+
+// DeleteVPA deletes the VerticalPodAutoscaler, not finding it or the CRD
+// being missing is not an error
+func DeleteVPA(
+	ctx context.Context,
+	h *helper.Helper,
+	name string,
+	namespace string,
+) error {
+	err := h.GetClient().Delete(ctx, newVPA(name, namespace))
+	if err != nil && !apierrors.IsNotFound(err) && !IsVPANotInstalled(err) {
+		return fmt.Errorf("Error deleting VerticalPodAutoscaler %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+#!/bin/sh
+# Raise the soft open file limit to NOFILE_LIMIT and run the given command.
+# The soft limit can only be raised up to the hard limit of the container
+# runtime, refuse to start the service if that is too low.
+if [ -n "${NOFILE_LIMIT}" ]; then
+	if ! ulimit -Sn ${NOFILE_LIMIT}; then
+		echo "Unable to raise the open file limit to ${NOFILE_LIMIT}, hard limit is $(ulimit -Hn)"
+		exit 1
+	fi
+	echo "Open file limit is $(ulimit -Sn)"
+fi
+
+exec "$@"
@@ -1,14 +1,97 @@
 #!/bin/sh
 TARFILE="/var/lib/config-data/rings/swiftrings.tar.gz"
 MTIME="0"
+CLOCK_SKEW=""
+PARTITIONS=""
+REPLICATION=""
+
+# Credentials to be used by curl
+export CURL_CA_BUNDLE=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+TOKEN=$(cat /var/run/secrets/kubernetes.io/serviceaccount/token)
+
+# Record the given annotations on this pod, the operator collects these and
+# reports them in the CR status
+annotate_pod() {
+	PATCH_JSON='{
+		"metadata":{
+			"annotations":{'${1}'}
+		}
+	}'
+
+	/usr/bin/curl -s -o /dev/null \
+		-H "Authorization: Bearer $TOKEN" \
+		--data-binary "${PATCH_JSON}" \
+		-H 'Content-Type: application/merge-patch+json' \
+		-X PATCH "https://kubernetes.default.svc/api/v1/namespaces/${NAMESPACE}/pods/${POD_NAME}"
+}
+
+# Print the difference in seconds between the local clock and the clock of
+# the API server
+get_clock_skew() {
+	SERVER_DATE=$(/usr/bin/curl -s -I \
+		-H "Authorization: Bearer $TOKEN" \
+		"https://kubernetes.default.svc/version" | sed -n 's/^[Dd]ate: //p' | tr -d '\r')
+	if [ -n "${SERVER_DATE}" ]; then
+		echo $(( $(date -u +%s) - $(date -u -d "${SERVER_DATE}" +%s) ))
+	fi
+}
 
 while true; do
 	if [ -e $TARFILE ] ; then
 		_MTIME=$(stat -L --printf "%Y" $TARFILE)
 		if [ $MTIME != $_MTIME ]; then
 			tar -xvzf $TARFILE -C etc/swift/
+			annotate_pod '"swift.openstack.org/ring-md5":"'$(md5sum $TARFILE | cut -f1 -d' ')'",
+				"swift.openstack.org/ring-sync-timestamp":"'$(date -u +%Y-%m-%dT%H:%M:%SZ)'"'
+			# Cached account and container info may refer to the devices of
+			# the previous rings, the first sync of a pod has nothing cached.
+			# Only the memcached container of this pod is flushed, a shared
+			# Memcached is never flushed.
+			if [ "${FLUSH_MEMCACHED}" = "true" ] && [ $MTIME != "0" ]; then
+				python3 -c '
+import socket
+s = socket.create_connection(("127.0.0.1", 11211), timeout=5)
+s.sendall(b"flush_all\r\n")
+print("memcached 127.0.0.1:11211 flush_all: %s" % s.recv(64).decode().strip())
+' || echo "Unable to flush memcached"
+			fi
 		fi
 		MTIME=$_MTIME
 	fi
+
+	_CLOCK_SKEW=$(get_clock_skew)
+	if [ -n "${_CLOCK_SKEW}" ] && [ "${_CLOCK_SKEW}" != "${CLOCK_SKEW}" ]; then
+		annotate_pod '"swift.openstack.org/clock-skew":"'${_CLOCK_SKEW}'"'
+		CLOCK_SKEW=$_CLOCK_SKEW
+	fi
+
+	# Number of partitions stored on the devices of a storage pod, used to
+	# wait for the replicators to drain the devices before a scale down
+	if [ -n "${NODE_ROOT}" ]; then
+		_PARTITIONS=$(ls -d ${NODE_ROOT}/*/accounts/* ${NODE_ROOT}/*/containers/* ${NODE_ROOT}/*/objects/* 2>/dev/null | grep -c '/[0-9][0-9]*$')
+		if [ "${_PARTITIONS}" != "${PARTITIONS}" ]; then
+			annotate_pod '"swift.openstack.org/partitions":"'${_PARTITIONS}'"'
+			PARTITIONS=$_PARTITIONS
+		fi
+	fi
+
+	# Oldest completion time of the last account, container and object
+	# replication cycles from the recon cache, used to wait for replication
+	# between the steps of a scale up
+	_REPLICATION=$(python3 -c '
+import json
+last = []
+for name, key in (("account", "replication_last"), ("container", "replication_last"), ("object", "object_replication_last")):
+    try:
+        with open("/var/cache/swift/%s.recon" % name) as f:
+            last.append(int(json.load(f)[key]))
+    except (OSError, ValueError, KeyError, TypeError):
+        last.append(0)
+print(min(last))
+' 2>/dev/null)
+	if [ -n "${_REPLICATION}" ] && [ "${_REPLICATION}" != "0" ] && [ "${_REPLICATION}" != "${REPLICATION}" ]; then
+		annotate_pod '"swift.openstack.org/replication-last":"'$(date -u -d @${_REPLICATION} +%Y-%m-%dT%H:%M:%SZ)'"'
+		REPLICATION=$_REPLICATION
+	fi
 	sleep 60
 done
@@ -0,0 +1,45 @@
+# Loaded by the Python processes of the storage and proxy pods and the Jobs
+# of the checks, from the backend CA ConfigMap on their PYTHONPATH. The proxy,
+# the replicators, the updaters, the expirer and every other client of the
+# storage servers connect through swift.common.bufferedhttp. A connection to
+# the storage pod "<pod>.<service>" uses TLS if the ConfigMap has the CA
+# certificate "<service>.crt" of the SwiftStorage of the pod. The files are
+# read on every connection, the kubelet updates them in running pods.
+import os
+
+CA_DIR = os.path.dirname(os.path.abspath(__file__))
+
+
+def backend_ca(host):
+    """Returns the CA certificate file of the storage pod, None for HTTP"""
+    parts = host.split(".")
+    if len(parts) < 2:
+        return None
+    path = os.path.join(CA_DIR, parts[1] + ".crt")
+    if os.path.exists(path):
+        return path
+    return None
+
+
+def _patch_bufferedhttp():
+    try:
+        from swift.common import bufferedhttp
+    except ImportError:
+        return
+    connect = bufferedhttp.BufferedHTTPConnection.connect
+
+    def tls_connect(self):
+        ret = connect(self)
+        ca = backend_ca(self.host)
+        if ca:
+            # Imported on use, the eventlet monkey patching of the servers
+            # happens after this module is loaded
+            from eventlet.green import ssl
+            context = ssl.create_default_context(cafile=ca)
+            self.sock = context.wrap_socket(self.sock, server_hostname=self.host)
+        return ret
+
+    bufferedhttp.BufferedHTTPConnection.connect = tls_connect
+
+
+_patch_bufferedhttp()
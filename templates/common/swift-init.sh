@@ -3,6 +3,91 @@ TARFILE=/var/lib/config-data/rings/swiftrings.tar.gz
 
 cp -t /etc/swift/ /var/lib/config-data/default/* /var/lib/config-data/swiftconf/*
 
+# The container sync realms include their keys and are mounted from a Secret
+if [ -d /var/lib/config-data/container-sync ]; then
+	cp -t /etc/swift/ /var/lib/config-data/container-sync/*
+fi
+
+# Prefer the storage nodes in the ring zones of the node of the proxy pod.
+# The entry of a new node is added when the operator sees the pod, it is
+# empty for nodes in no ring zone.
+ZONES=/var/lib/config-data/zone-affinity
+if [ -n "${NODE_NAME}" ] && [ -d ${ZONES} ] && [ -f /etc/swift/proxy-server.conf ]; then
+	for i in $(seq 12); do
+		[ -e ${ZONES}/${NODE_NAME} ] && break
+		sleep 5
+	done
+	if [ -s ${ZONES}/${NODE_NAME} ]; then
+		echo "Setting the read and write affinity to $(cat ${ZONES}/${NODE_NAME})"
+		python3 -c '
+import configparser, sys
+zones = [z.strip() for z in open(sys.argv[2]).read().split(",") if z.strip()]
+c = configparser.ConfigParser(interpolation=None)
+c.optionxform = str
+c.read(sys.argv[1])
+c["app:proxy-server"]["sorting_method"] = "affinity"
+c["app:proxy-server"]["read_affinity"] = ", ".join("%s=100" % z for z in zones)
+c["app:proxy-server"]["write_affinity"] = ", ".join(zones)
+with open(sys.argv[1], "w") as f:
+    c.write(f)
+' /etc/swift/proxy-server.conf ${ZONES}/${NODE_NAME} || exit 1
+	else
+		echo "Node ${NODE_NAME} is in no ring zone, not setting the affinity"
+	fi
+fi
+
+# Merge the defaultConfigOverwrite snippets of all pods
+for f in /etc/swift/*.overwrite; do
+	[ -f "$f" ] || continue
+	echo "Merging default config overwrite $(basename ${f%.overwrite})"
+	python3 -c '
+import configparser, sys
+c = configparser.ConfigParser(interpolation=None)
+c.optionxform = str
+c.read(sys.argv[1:])
+with open(sys.argv[1], "w") as f:
+    c.write(f)
+' ${f%.overwrite} $f || exit 1
+	rm -f $f
+done
+
+# Fill in the service password mounted from a secret store
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	python3 -c '
+import glob, sys
+with open(sys.argv[1]) as f:
+    password = f.read().strip()
+for conf in glob.glob("/etc/swift/*.conf"):
+    with open(conf) as f:
+        content = f.read()
+    if "%SERVICE_PASSWORD%" in content:
+        with open(conf, "w") as f:
+            f.write(content.replace("%SERVICE_PASSWORD%", password))
+' "${SERVICE_PASSWORD_FILE}" || exit 1
+fi
+
+# Merge the config overrides of this pod, selected by its ordinal
+OVERRIDES=/var/lib/config-data/overrides/${POD_NAME##*-}
+if [ -n "${POD_NAME}" ] && [ -d "${OVERRIDES}" ]; then
+	for f in ${OVERRIDES}/*; do
+		echo "Merging config override $(basename $f)"
+		python3 -c '
+import configparser, sys
+c = configparser.ConfigParser(interpolation=None)
+c.optionxform = str
+c.read(sys.argv[1:])
+with open(sys.argv[1], "w") as f:
+    c.write(f)
+' /etc/swift/$(basename $f) $f || exit 1
+	done
+fi
+
+# Each object expirer pod processes the part of the expiration tasks of its
+# ordinal
+if [ -n "${EXPIRER_PROCESSES}" ] && [ -f /etc/swift/object-expirer.conf ]; then
+	sed -i "s/^process = .*/process = ${POD_NAME##*-}/" /etc/swift/object-expirer.conf
+fi
+
 cd /etc/swift
 
 if [ ! -f $TARFILE ]; then
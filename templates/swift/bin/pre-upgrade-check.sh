@@ -0,0 +1,70 @@
+#!/bin/sh
+# Checks the storage servers are safe to upgrade: all of them use the current
+# rings, no part power change is pending, object replication ran in the last
+# REPLICATION_LAG seconds and no disk is used above DISK_USAGE_LIMIT percent.
+# The servers are queried through their recon middleware.
+RINGS=$(mktemp -d)
+tar -xzf /var/lib/config-data/rings/swiftrings.tar.gz -C "${RINGS}" || exit 1
+
+exec python3 -u -c '
+import hashlib, json, os, ssl, sys, time, urllib.request
+from swift.common.ring import RingData
+
+# The servers of a SwiftStorage with TLS are verified with its CA
+try:
+    from sitecustomize import backend_ca
+except ImportError:
+    def backend_ca(host):
+        return None
+
+rings = sys.argv[1]
+lag = int(os.environ["REPLICATION_LAG"])
+limit = int(os.environ["DISK_USAGE_LIMIT"])
+failures = []
+
+md5s = {}
+servers = set()
+for name in ("account", "container", "object"):
+    path = os.path.join(rings, name + ".ring.gz")
+    with open(path, "rb") as f:
+        md5s[name + ".ring.gz"] = hashlib.md5(f.read()).hexdigest()
+    ring = RingData.load(path)
+    if getattr(ring, "next_part_power", None) is not None:
+        failures.append("%s ring has a pending part power change" % name)
+    if name == "object":
+        servers = {(d["ip"], d["port"]) for d in ring.devs if d}
+
+def recon(ip, port, check):
+    ca = backend_ca(ip)
+    scheme = "https" if ca else "http"
+    context = ssl.create_default_context(cafile=ca) if ca else None
+    url = "%s://%s:%d/recon/%s" % (scheme, ip, port, check)
+    with urllib.request.urlopen(url, timeout=10, context=context) as r:
+        return json.load(r)
+
+now = time.time()
+for ip, port in sorted(servers):
+    try:
+        for path, md5 in recon(ip, port, "ringmd5").items():
+            name = os.path.basename(path)
+            if name in md5s and md5 != md5s[name]:
+                failures.append("%s uses an outdated %s" % (ip, name))
+        last = recon(ip, port, "replication/object").get("object_replication_last")
+        if last is None:
+            print("%s did not finish an object replication pass yet" % ip)
+        elif now - last > lag:
+            failures.append("%s replicated objects %d seconds ago" % (ip, now - last))
+        for disk in recon(ip, port, "diskusage") or []:
+            if disk.get("mounted") and disk.get("size"):
+                used = 100 * disk["used"] / disk["size"]
+                if used > limit:
+                    failures.append("%s device %s is %d%% full" % (ip, disk["device"], used))
+    except Exception as e:
+        failures.append("%s recon failed: %s" % (ip, e))
+
+for f in failures:
+    print(f)
+if failures:
+    sys.exit(1)
+print("Pre-upgrade checks passed on %d servers" % len(servers))
+' "${RINGS}"
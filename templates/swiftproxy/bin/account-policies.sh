@@ -0,0 +1,49 @@
+#!/bin/sh
+# Creates the containers of the managed accounts with their storage policy.
+# ACCOUNT_POLICIES contains one "account container policy" entry per line.
+
+# The password may be mounted from a secret store instead
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	OS_PASSWORD=$(cat "${SERVICE_PASSWORD_FILE}")
+	export OS_PASSWORD
+fi
+
+BODY=$(python3 -c '
+import json, os
+print(json.dumps({"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"],
+        "domain": {"id": "default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"id": "default"}}}}}))
+')
+
+TOKEN=$(curl -s -i -X POST -H "Content-Type: application/json" -d "${BODY}" \
+	"${OS_AUTH_URL}/v3/auth/tokens" | awk 'tolower($1) == "x-subject-token:" {print $2}' | tr -d '\r')
+if [ -z "${TOKEN}" ]; then
+	echo "Failed to get a Keystone token"
+	exit 1
+fi
+
+RC=0
+echo "${ACCOUNT_POLICIES}" | while read ACCOUNT CONTAINER POLICY; do
+	[ -z "${ACCOUNT}" ] && continue
+	STATUS=$(curl -s -o /dev/null -w '%{http_code}' -X PUT ${SWIFT_CACERT:+--cacert "${SWIFT_CACERT}"} \
+		-H "X-Auth-Token: ${TOKEN}" -H "X-Storage-Policy: ${POLICY}" \
+		"${SWIFT_URL}/v1/${ACCOUNT}/${CONTAINER}")
+	case ${STATUS} in
+		201|202)
+			echo "Container ${ACCOUNT}/${CONTAINER} uses policy ${POLICY}"
+			;;
+		409)
+			echo "Container ${ACCOUNT}/${CONTAINER} already exists with a different policy than ${POLICY}"
+			exit 1
+			;;
+		*)
+			echo "Creating container ${ACCOUNT}/${CONTAINER} failed with status ${STATUS}"
+			exit 1
+			;;
+	esac
+done || RC=1
+
+exit ${RC}
@@ -0,0 +1,50 @@
+#!/bin/sh
+# Writes, reads back and deletes a canary object in the account of the service
+# user through the proxy. Swift is only reported ready for consumers like
+# Glance or cinder-backup once this end-to-end check passed.
+
+# The password may be mounted from a secret store instead
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	OS_PASSWORD=$(cat "${SERVICE_PASSWORD_FILE}")
+	export OS_PASSWORD
+fi
+
+exec python3 -u -c '
+import json, os, ssl, sys, urllib.request, uuid
+
+# The proxy may use a certificate of a private CA
+swift_context = None
+if os.environ.get("SWIFT_CACERT"):
+    swift_context = ssl.create_default_context(cafile=os.environ["SWIFT_CACERT"])
+
+def request(method, url, headers=None, data=None):
+    req = urllib.request.Request(url, method=method, headers=headers or {}, data=data)
+    context = swift_context if url.startswith(os.environ["SWIFT_URL"]) else None
+    return urllib.request.urlopen(req, context=context, timeout=30)
+
+body = json.dumps({"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"],
+        "domain": {"id": "default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"id": "default"}}}}}).encode()
+with request("POST", os.environ["OS_AUTH_URL"] + "/v3/auth/tokens",
+             {"Content-Type": "application/json"}, body) as r:
+    token = r.headers["X-Subject-Token"]
+    project = json.load(r)["token"]["project"]["id"]
+
+auth = {"X-Auth-Token": token}
+container = "%s/v1/AUTH_%s/%s" % (os.environ["SWIFT_URL"], project, os.environ["CANARY_CONTAINER"])
+obj = container + "/canary-" + uuid.uuid4().hex
+data = uuid.uuid4().hex.encode()
+try:
+    request("PUT", container, auth).close()
+    request("PUT", obj, auth, data).close()
+    with request("GET", obj, auth) as r:
+        if r.read() != data:
+            sys.exit("Canary object %s read back with different content" % obj)
+    request("DELETE", obj, auth).close()
+except Exception as e:
+    sys.exit("Canary write to %s failed: %s" % (obj, e))
+print("Canary write to %s succeeded" % obj)
+'
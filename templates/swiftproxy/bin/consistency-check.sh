@@ -0,0 +1,112 @@
+#!/bin/sh
+# Samples containers of the ACCOUNTS (default: the account of the service
+# user), compares their object count with their listing and checks a sample
+# of the listed objects on their primary object servers. The result is
+# recorded as an annotation on this pod, the operator reports it in the
+# SwiftProxy status.
+
+# The password may be mounted from a secret store instead
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	OS_PASSWORD=$(cat "${SERVICE_PASSWORD_FILE}")
+	export OS_PASSWORD
+fi
+cp /var/lib/config-data/swiftconf/swift.conf /etc/swift/swift.conf || exit 1
+tar -xzf /var/lib/config-data/rings/swiftrings.tar.gz -C /etc/swift || exit 1
+
+exec python3 -u -c '
+import json, os, random, ssl, time, urllib.parse, urllib.request
+from swift.common import direct_client
+from swift.common.storage_policy import POLICIES
+
+# The proxy may use a certificate of a private CA
+swift_context = None
+if os.environ.get("SWIFT_CACERT"):
+    swift_context = ssl.create_default_context(cafile=os.environ["SWIFT_CACERT"])
+
+def request(method, url, headers=None, data=None):
+    req = urllib.request.Request(url, method=method, headers=headers or {}, data=data)
+    context = swift_context if url.startswith(os.environ["SWIFT_URL"]) else None
+    return urllib.request.urlopen(req, context=context, timeout=30)
+
+body = json.dumps({"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"],
+        "domain": {"id": "default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"id": "default"}}}}}).encode()
+with request("POST", os.environ["OS_AUTH_URL"] + "/v3/auth/tokens",
+             {"Content-Type": "application/json"}, body) as r:
+    token = r.headers["X-Subject-Token"]
+    project = json.load(r)["token"]["project"]["id"]
+
+auth = {"X-Auth-Token": token}
+accounts = os.environ.get("ACCOUNTS", "").split() or ["AUTH_" + project]
+containers = int(os.environ["CONTAINERS"])
+objects = int(os.environ["OBJECTS"])
+result = {"containersSampled": 0, "containerCountMismatches": 0, "objectsSampled": 0,
+          "objectsMissing": 0, "objectsUnderReplicated": 0, "errors": 0}
+
+for account in accounts:
+    url = "%s/v1/%s" % (os.environ["SWIFT_URL"], urllib.parse.quote(account))
+    try:
+        with request("GET", url + "?format=json&limit=10000", auth) as r:
+            listing = json.load(r)
+    except Exception as e:
+        print("Listing account %s failed: %s" % (account, e))
+        result["errors"] += 1
+        continue
+    for c in random.sample(listing, min(containers, len(listing))):
+        name = c["name"]
+        curl = url + "/" + urllib.parse.quote(name)
+        try:
+            with request("GET", curl + "?format=json&limit=10000", auth) as r:
+                count = int(r.headers["X-Container-Object-Count"])
+                policy = POLICIES.get_by_name(r.headers["X-Storage-Policy"])
+                objs = json.load(r)
+        except Exception as e:
+            print("Listing container %s/%s failed: %s" % (account, name, e))
+            result["errors"] += 1
+            continue
+        result["containersSampled"] += 1
+        if len(objs) < 10000 and len(objs) != count:
+            print("Container %s/%s reports %d objects but lists %d" % (account, name, count, len(objs)))
+            result["containerCountMismatches"] += 1
+
+        ring = policy.load_ring("/etc/swift")
+        headers = {"X-Backend-Storage-Policy-Index": str(int(policy))}
+        for o in random.sample(objs, min(objects, len(objs))):
+            part, nodes = ring.get_nodes(account, name, o["name"])
+            found = 0
+            for node in nodes:
+                try:
+                    direct_client.direct_head_object(node, part, account, name, o["name"], headers=headers)
+                    found += 1
+                except direct_client.ClientException as e:
+                    if e.http_status != 404:
+                        result["errors"] += 1
+                except Exception:
+                    result["errors"] += 1
+            result["objectsSampled"] += 1
+            if found == 0:
+                print("Object %s/%s/%s is listed but missing" % (account, name, o["name"]))
+                result["objectsMissing"] += 1
+            elif found < len(nodes):
+                result["objectsUnderReplicated"] += 1
+
+result["timestamp"] = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime())
+print(json.dumps(result))
+
+# Record the result on this pod
+sa = "/var/run/secrets/kubernetes.io/serviceaccount"
+with open(sa + "/token") as f:
+    sa_token = f.read()
+patch = json.dumps({"metadata": {"annotations": {
+    "swift.openstack.org/consistency": json.dumps(result)}}}).encode()
+req = urllib.request.Request(
+    "https://kubernetes.default.svc/api/v1/namespaces/%s/pods/%s" % (
+        os.environ["NAMESPACE"], os.environ["POD_NAME"]),
+    method="PATCH", data=patch, headers={
+        "Authorization": "Bearer " + sa_token,
+        "Content-Type": "application/merge-patch+json"})
+urllib.request.urlopen(req, context=ssl.create_default_context(cafile=sa + "/ca.crt"), timeout=30)
+'
@@ -0,0 +1,68 @@
+#!/bin/sh
+# Verifies a random sample of the verification objects written before a ring
+# change with HEAD requests through the proxy, then writes the missing ones.
+# The objects are kept in the account of the service user, their number is
+# recorded in the container metadata once they were all written.
+
+# The password may be mounted from a secret store instead
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	OS_PASSWORD=$(cat "${SERVICE_PASSWORD_FILE}")
+	export OS_PASSWORD
+fi
+
+exec python3 -u -c '
+import hashlib, json, os, random, ssl, sys, urllib.error, urllib.request
+
+# The proxy may use a certificate of a private CA
+swift_context = None
+if os.environ.get("SWIFT_CACERT"):
+    swift_context = ssl.create_default_context(cafile=os.environ["SWIFT_CACERT"])
+
+def request(method, url, headers=None, data=None):
+    req = urllib.request.Request(url, method=method, headers=headers or {}, data=data)
+    context = swift_context if url.startswith(os.environ["SWIFT_URL"]) else None
+    return urllib.request.urlopen(req, context=context, timeout=30)
+
+body = json.dumps({"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"],
+        "domain": {"id": "default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"id": "default"}}}}}).encode()
+with request("POST", os.environ["OS_AUTH_URL"] + "/v3/auth/tokens",
+             {"Content-Type": "application/json"}, body) as r:
+    token = r.headers["X-Subject-Token"]
+    project = json.load(r)["token"]["project"]["id"]
+
+auth = {"X-Auth-Token": token}
+container = "%s/v1/AUTH_%s/%s" % (os.environ["SWIFT_URL"], project, os.environ["VERIFICATION_CONTAINER"])
+objects = int(os.environ["VERIFICATION_OBJECTS"])
+sample = int(os.environ["VERIFICATION_SAMPLE"])
+
+def name(i):
+    return "verification-%06d" % i
+
+request("PUT", container, auth).close()
+with request("HEAD", container, auth) as r:
+    recorded = int(r.headers.get("X-Container-Meta-Verification-Objects") or 0)
+
+# The content of each object is its name, so the expected ETag is known
+failed = []
+checked = random.sample(range(recorded), min(sample, recorded))
+for i in checked:
+    try:
+        with request("HEAD", container + "/" + name(i), auth) as r:
+            if r.headers["Etag"].strip("\"") != hashlib.md5(name(i).encode()).hexdigest():
+                failed.append("%s: unexpected ETag %s" % (name(i), r.headers["Etag"]))
+    except urllib.error.HTTPError as e:
+        failed.append("%s: %s" % (name(i), e))
+if failed:
+    sys.exit("Verification of %d of %d sampled objects failed: %s" % (len(failed), len(checked), ", ".join(failed)))
+print("Verified %d sampled objects of %d" % (len(checked), recorded))
+
+for i in range(recorded, objects):
+    request("PUT", container + "/" + name(i), auth, name(i).encode()).close()
+if objects > recorded:
+    request("POST", container, dict(auth, **{"X-Container-Meta-Verification-Objects": str(objects)})).close()
+    print("Wrote %d verification objects" % (objects - recorded))
+'
@@ -0,0 +1,62 @@
+#!/bin/sh
+# Receive the statsd timings of the proxy server on STATSD_PORT and expose
+# the request rate and the p95 request latency of the last WINDOW seconds as
+# Prometheus metrics on METRICS_PORT. The names are the ones the
+# HorizontalPodAutoscaler of the proxy scales on.
+exec python3 -u -c '
+import collections, http.server, os, re, socket, threading, time
+
+STATSD_PORT = int(os.environ["STATSD_PORT"])
+METRICS_PORT = int(os.environ["METRICS_PORT"])
+WINDOW = int(os.environ.get("WINDOW", "60"))
+
+# proxy-server.<type>.<verb>.<status>.timing, one per request. The per policy
+# and first-byte timings would count the requests twice.
+TIMING = re.compile(r"^proxy-server\.[a-z]+\.[A-Z]+\.\d+\.timing:([0-9.]+)\|ms(?:\|@([0-9.]+))?$")
+
+lock = threading.Lock()
+requests = collections.deque()
+
+def receive():
+    sock = socket.socket(socket.AF_INET, socket.SOCK_DGRAM)
+    sock.bind(("127.0.0.1", STATSD_PORT))
+    while True:
+        data = sock.recv(65535).decode(errors="ignore")
+        for line in data.splitlines():
+            m = TIMING.match(line.strip())
+            if not m:
+                continue
+            count = 1 / float(m.group(2)) if m.group(2) else 1
+            with lock:
+                requests.append((time.time(), float(m.group(1)) / 1000, count))
+
+def metrics():
+    now = time.time()
+    with lock:
+        while requests and requests[0][0] < now - WINDOW:
+            requests.popleft()
+        samples = list(requests)
+    count = sum(s[2] for s in samples)
+    durations = sorted(s[1] for s in samples)
+    p95 = durations[int(0.95 * (len(durations) - 1))] if durations else 0
+    return "\n".join([
+        "# TYPE swift_proxy_server_requests_per_second gauge",
+        "swift_proxy_server_requests_per_second %f" % (count / WINDOW),
+        "# TYPE swift_proxy_server_request_p95_latency_seconds gauge",
+        "swift_proxy_server_request_p95_latency_seconds %f" % p95,
+    ]) + "\n"
+
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_GET(self):
+        body = metrics().encode()
+        self.send_response(200)
+        self.send_header("Content-Type", "text/plain; version=0.0.4")
+        self.end_headers()
+        self.wfile.write(body)
+
+    def log_message(self, *args):
+        pass
+
+threading.Thread(target=receive, daemon=True).start()
+http.server.HTTPServer(("", METRICS_PORT), Handler).serve_forever()
+'
@@ -0,0 +1,50 @@
+#!/bin/sh
+# Creates the public containers and sets their read ACL and staticweb
+# metadata. PUBLIC_CONTAINERS contains one "account container index listings"
+# entry per line, index is "-" if not set.
+
+# The password may be mounted from a secret store instead
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	OS_PASSWORD=$(cat "${SERVICE_PASSWORD_FILE}")
+	export OS_PASSWORD
+fi
+
+BODY=$(python3 -c '
+import json, os
+print(json.dumps({"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"],
+        "domain": {"id": "default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"id": "default"}}}}}))
+')
+
+TOKEN=$(curl -s -i -X POST -H "Content-Type: application/json" -d "${BODY}" \
+	"${OS_AUTH_URL}/v3/auth/tokens" | awk 'tolower($1) == "x-subject-token:" {print $2}' | tr -d '\r')
+if [ -z "${TOKEN}" ]; then
+	echo "Failed to get a Keystone token"
+	exit 1
+fi
+
+RC=0
+echo "${PUBLIC_CONTAINERS}" | while read ACCOUNT CONTAINER INDEX LISTINGS; do
+	[ -z "${ACCOUNT}" ] && continue
+	ACL=".r:*"
+	[ "${LISTINGS}" = "true" ] && ACL=".r:*,.rlistings"
+	[ "${INDEX}" = "-" ] && INDEX=""
+	STATUS=$(curl -s -o /dev/null -w '%{http_code}' -X PUT ${SWIFT_CACERT:+--cacert "${SWIFT_CACERT}"} \
+		-H "X-Auth-Token: ${TOKEN}" -H "X-Container-Read: ${ACL}" \
+		-H "X-Container-Meta-Web-Index: ${INDEX}" -H "X-Container-Meta-Web-Listings: ${LISTINGS}" \
+		"${SWIFT_URL}/v1/${ACCOUNT}/${CONTAINER}")
+	case ${STATUS} in
+		201|202)
+			echo "Container ${ACCOUNT}/${CONTAINER} is public"
+			;;
+		*)
+			echo "Publishing container ${ACCOUNT}/${CONTAINER} failed with status ${STATUS}"
+			exit 1
+			;;
+	esac
+done || RC=1
+
+exit ${RC}
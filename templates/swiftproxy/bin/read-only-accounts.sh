@@ -0,0 +1,24 @@
+#!/bin/sh
+# Marks the accounts that stay writable in read-only mode with the read-only
+# account system metadata, which overrides the read_only middleware, and
+# clears it on the accounts no longer writable. System metadata can only be
+# set by an internal client talking to the storage servers directly.
+# WRITABLE_ACCOUNTS and RESET_ACCOUNTS contain one account per line.
+
+python3 -c '
+import os
+from swift.common.internal_client import InternalClient
+
+client = InternalClient("/etc/swift/internal-client.conf", "swift-operator", 3)
+for env, value in (("WRITABLE_ACCOUNTS", "false"), ("RESET_ACCOUNTS", "")):
+    for account in os.environ.get(env, "").split():
+        resp = client.make_request(
+            "POST", client.make_path(account),
+            {"X-Account-Sysmeta-Read-Only": value}, (2, 404))
+        if resp.status_int == 404:
+            print("Account %s not found" % account)
+        elif value:
+            print("Account %s is writable in read-only mode" % account)
+        else:
+            print("Account %s is read-only in read-only mode" % account)
+'
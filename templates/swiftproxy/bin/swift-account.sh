@@ -0,0 +1,59 @@
+#!/bin/sh
+# Creates the ACCOUNT of a SwiftAccount and applies its quota, temp URL keys
+# and ACL with the internal client, which may set the metadata reserved to
+# the reseller admin and the ACL system metadata. Empty values remove the
+# metadata. With S3_USER_ID the EC2 credentials are registered in Keystone.
+
+# The password may be mounted from a secret store instead
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	OS_PASSWORD=$(cat "${SERVICE_PASSWORD_FILE}")
+	export OS_PASSWORD
+fi
+
+exec python3 -u -c '
+import json, os, sys, urllib.parse, urllib.request
+from swift.common.internal_client import InternalClient
+
+account = os.environ["ACCOUNT"]
+client = InternalClient("/etc/swift/internal-client.conf", "swift-operator", 3)
+# The account is created on the first POST by account_autocreate
+client.make_request("POST", client.make_path(account), {
+    "X-Account-Meta-Quota-Bytes": os.environ.get("QUOTA_BYTES", ""),
+    "X-Account-Meta-Temp-Url-Key": os.environ.get("TEMP_URL_KEY", ""),
+    "X-Account-Meta-Temp-Url-Key-2": os.environ.get("TEMP_URL_KEY_2", ""),
+    "X-Account-Sysmeta-Core-Access-Control": os.environ.get("ACL", ""),
+}, (2,))
+print("Account %s is set up" % account)
+
+if not os.environ.get("S3_USER_ID"):
+    sys.exit(0)
+
+def request(method, url, headers, data=None):
+    req = urllib.request.Request(url, method=method, headers=headers, data=data)
+    return urllib.request.urlopen(req, timeout=30)
+
+body = json.dumps({"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"],
+        "domain": {"id": "default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"id": "default"}}}}}).encode()
+with request("POST", os.environ["OS_AUTH_URL"] + "/v3/auth/tokens",
+             {"Content-Type": "application/json"}, body) as r:
+    auth = {"X-Auth-Token": r.headers["X-Subject-Token"], "Content-Type": "application/json"}
+
+credentials = os.environ["OS_AUTH_URL"] + "/v3/credentials"
+query = urllib.parse.urlencode({"user_id": os.environ["S3_USER_ID"], "type": "ec2"})
+with request("GET", credentials + "?" + query, auth) as r:
+    for c in json.load(r)["credentials"]:
+        if json.loads(c["blob"]).get("access") == os.environ["S3_ACCESS"]:
+            print("EC2 credentials of user %s exist" % os.environ["S3_USER_ID"])
+            sys.exit(0)
+
+blob = json.dumps({"access": os.environ["S3_ACCESS"], "secret": os.environ["S3_SECRET"]})
+body = json.dumps({"credential": {
+    "type": "ec2", "user_id": os.environ["S3_USER_ID"],
+    "project_id": os.environ["PROJECT_ID"], "blob": blob}}).encode()
+request("POST", credentials, auth, body).close()
+print("Created the EC2 credentials of user %s" % os.environ["S3_USER_ID"])
+'
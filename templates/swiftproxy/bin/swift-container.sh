@@ -0,0 +1,47 @@
+#!/bin/sh
+# Creates the CONTAINER of a SwiftContainer in ACCOUNT and applies its ACLs,
+# versioning, quota and lifecycle with the internal client. The versioning
+# and lifecycle are set in the system metadata of the versioned_writes and
+# lifecycle middlewares, which are not in the pipeline of the internal
+# client. Empty values remove the metadata.
+
+exec python3 -u -c '
+import os, sys
+from swift.common.internal_client import InternalClient
+
+account = os.environ["ACCOUNT"]
+container = os.environ["CONTAINER"]
+policy = os.environ.get("STORAGE_POLICY", "")
+versions = os.environ.get("VERSIONS_CONTAINER", "")
+client = InternalClient("/etc/swift/internal-client.conf", "swift-operator", 3)
+
+def ensure(name, headers):
+    path = client.make_path(account, name)
+    resp = client.make_request("HEAD", path, {}, (2, 404))
+    if resp.status_int == 404:
+        if policy:
+            headers = dict(headers, **{"X-Storage-Policy": policy})
+        client.make_request("PUT", path, headers, (2,))
+        print("Created container %s/%s" % (account, name))
+        return
+    current = resp.headers.get("X-Storage-Policy", "")
+    if policy and current.lower() != policy.lower():
+        sys.exit("Container %s/%s uses policy %s instead of %s, the policy can not be changed" % (
+            account, name, current, policy))
+    if headers:
+        client.make_request("POST", path, headers, (2,))
+
+if versions:
+    ensure(versions, {})
+ensure(container, {
+    "X-Container-Read": os.environ.get("READ_ACL", ""),
+    "X-Container-Write": os.environ.get("WRITE_ACL", ""),
+    "X-Container-Meta-Quota-Bytes": os.environ.get("QUOTA_BYTES", ""),
+    "X-Container-Meta-Quota-Count": os.environ.get("QUOTA_COUNT", ""),
+    "X-Container-Sysmeta-Versions-Location": versions,
+    "X-Container-Sysmeta-Versions-Mode": "stack" if versions else "",
+    "X-Container-Sysmeta-Lifecycle-Expire-After": os.environ.get("LIFECYCLE_EXPIRE_AFTER", ""),
+    "X-Container-Sysmeta-Lifecycle-Delete-At": os.environ.get("LIFECYCLE_DELETE_AT", ""),
+})
+print("Container %s/%s is set up" % (account, container))
+'
@@ -0,0 +1,45 @@
+# Lifecycle middleware of the proxy. New objects uploaded without an
+# expiration get the one of their container, set by a SwiftContainer in the
+# X-Container-Sysmeta-Lifecycle-Delete-At and -Expire-After system metadata,
+# or else the expire_after default of the filter. The object-expirer deletes
+# them once expired.
+import time
+
+from swift.common.swob import Request
+from swift.proxy.controllers.base import get_container_info
+
+
+class LifecycleMiddleware(object):
+    def __init__(self, app, conf):
+        self.app = app
+        self.expire_after = int(conf.get("expire_after", 0))
+
+    def __call__(self, env, start_response):
+        req = Request(env)
+        if req.method != "PUT" or "X-Delete-At" in req.headers or \
+                "X-Delete-After" in req.headers:
+            return self.app(env, start_response)
+        try:
+            req.split_path(4, 4, True)
+        except ValueError:
+            return self.app(env, start_response)
+
+        sysmeta = get_container_info(
+            env, self.app, swift_source="LC").get("sysmeta", {})
+        delete_at = int(sysmeta.get("lifecycle-delete-at") or 0)
+        expire_after = int(sysmeta.get("lifecycle-expire-after") or
+                           self.expire_after)
+        if delete_at > time.time():
+            req.headers["X-Delete-At"] = str(delete_at)
+        elif expire_after > 0:
+            req.headers["X-Delete-After"] = str(expire_after)
+        return self.app(env, start_response)
+
+
+def filter_factory(global_conf, **local_conf):
+    conf = global_conf.copy()
+    conf.update(local_conf)
+
+    def lifecycle_filter(app):
+        return LifecycleMiddleware(app, conf)
+    return lifecycle_filter
@@ -0,0 +1,108 @@
+#!/bin/sh
+# Aggregates the access logs of the proxy pods per account since the previous
+# export and stores the report in the CONTAINER of the service user account.
+# The end of the reported period is kept in the container metadata, so each
+# request is reported once.
+
+# The password may be mounted from a secret store instead
+if [ -n "${SERVICE_PASSWORD_FILE}" ]; then
+	OS_PASSWORD=$(cat "${SERVICE_PASSWORD_FILE}")
+	export OS_PASSWORD
+fi
+
+exec python3 -u -c '
+import csv, io, json, os, ssl, time, urllib.error, urllib.parse, urllib.request
+
+# The proxy may use a certificate of a private CA
+swift_context = None
+if os.environ.get("SWIFT_CACERT"):
+    swift_context = ssl.create_default_context(cafile=os.environ["SWIFT_CACERT"])
+sa = "/var/run/secrets/kubernetes.io/serviceaccount"
+kube_context = ssl.create_default_context(cafile=sa + "/ca.crt")
+with open(sa + "/token") as f:
+    kube_auth = {"Authorization": "Bearer " + f.read()}
+
+def request(method, url, headers=None, data=None):
+    req = urllib.request.Request(url, method=method, headers=headers or {}, data=data)
+    context = None
+    if url.startswith(os.environ["SWIFT_URL"]):
+        context = swift_context
+    elif url.startswith("https://kubernetes.default.svc"):
+        context = kube_context
+    return urllib.request.urlopen(req, context=context, timeout=60)
+
+body = json.dumps({"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"],
+        "domain": {"id": "default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"id": "default"}}}}}).encode()
+with request("POST", os.environ["OS_AUTH_URL"] + "/v3/auth/tokens",
+             {"Content-Type": "application/json"}, body) as r:
+    token = r.headers["X-Subject-Token"]
+    project = json.load(r)["token"]["project"]["id"]
+
+auth = {"X-Auth-Token": token}
+url = "%s/v1/AUTH_%s/%s" % (os.environ["SWIFT_URL"], project, urllib.parse.quote(os.environ["CONTAINER"]))
+request("PUT", url, auth).close()
+with request("HEAD", url, auth) as r:
+    since = r.headers.get("X-Container-Meta-Usage-Last-Export")
+end = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime())
+
+kube = "https://kubernetes.default.svc/api/v1/namespaces/" + os.environ["NAMESPACE"]
+with request("GET", kube + "/pods?labelSelector=" + urllib.parse.quote(os.environ["PROXY_SELECTOR"]), kube_auth) as r:
+    pods = [p["metadata"]["name"] for p in json.load(r)["items"]]
+
+# proxy_logging access log fields, see the Swift logs documentation
+methods = ("GET", "HEAD", "PUT", "POST", "DELETE", "COPY", "OPTIONS")
+usage = {}
+for pod in pods:
+    query = {"container": "proxy-server", "timestamps": "true"}
+    if since:
+        query["sinceTime"] = since
+    try:
+        with request("GET", "%s/pods/%s/log?%s" % (kube, pod, urllib.parse.urlencode(query)), kube_auth) as r:
+            lines = r.read().decode(errors="replace").splitlines()
+    except urllib.error.HTTPError as e:
+        print("Reading the logs of pod %s failed: %s" % (pod, e))
+        continue
+    for line in lines:
+        timestamp, _, message = line.partition(" ")
+        if not message.startswith("proxy-server: ") or timestamp[:19] >= end[:19] or \
+                (since and timestamp[:19] < since[:19]):
+            continue
+        fields = message[len("proxy-server: "):].split(" ")
+        # Subrequests of the middlewares are logged with their source
+        if len(fields) < 17 or fields[3] not in methods or fields[16] != "-":
+            continue
+        parts = urllib.parse.unquote(fields[4]).split("?")[0].split("/")
+        if len(parts) < 3 or parts[1] != "v1" or not parts[2]:
+            continue
+        account = usage.setdefault(parts[2], {
+            "account": parts[2], "requests": 0, "errors": 0, "bytesReceived": 0, "bytesSent": 0})
+        account["requests"] += 1
+        if fields[6].isdigit() and int(fields[6]) >= 500:
+            account["errors"] += 1
+        for key, value in (("bytesReceived", fields[10]), ("bytesSent", fields[11])):
+            if value.isdigit():
+                account[key] += int(value)
+
+rows = sorted(usage.values(), key=lambda a: a["account"])
+report = {"start": since or "", "end": end, "accounts": rows}
+if os.environ["FORMAT"] == "json":
+    data = json.dumps(report).encode()
+    content_type = "application/json"
+else:
+    out = io.StringIO()
+    writer = csv.DictWriter(out, fieldnames=["start", "end", "account", "requests", "errors", "bytesReceived", "bytesSent"])
+    writer.writeheader()
+    for row in rows:
+        writer.writerow(dict(row, start=since or "", end=end))
+    data = out.getvalue().encode()
+    content_type = "text/csv"
+
+name = "usage-%s.%s" % (end, os.environ["FORMAT"])
+request("PUT", url + "/" + name, dict(auth, **{"Content-Type": content_type}), data).close()
+request("POST", url, dict(auth, **{"X-Container-Meta-Usage-Last-Export": end})).close()
+print("Exported the usage of %d accounts to %s/%s" % (len(rows), os.environ["CONTAINER"], name))
+'
@@ -0,0 +1,70 @@
+#!/bin/sh
+# Copy the files of the ring ConfigMap to an S3-compatible bucket. Like IRSA,
+# temporary credentials are requested with the projected service account
+# token in AWS_WEB_IDENTITY_TOKEN_FILE for AWS_ROLE_ARN, no static keys are
+# used. The objects are named <prefix><ring md5>/<file>.
+exec python3 -c '
+import datetime, hashlib, hmac, os, sys, urllib.parse, urllib.request
+import xml.etree.ElementTree as ET
+
+RINGS = "/var/lib/config-data/rings"
+
+def assume_role():
+    with open(os.environ["AWS_WEB_IDENTITY_TOKEN_FILE"]) as f:
+        token = f.read().strip()
+    body = urllib.parse.urlencode({
+        "Action": "AssumeRoleWithWebIdentity",
+        "Version": "2011-06-15",
+        "RoleArn": os.environ["AWS_ROLE_ARN"],
+        "RoleSessionName": "swift-ring-backup",
+        "WebIdentityToken": token,
+        "DurationSeconds": "900",
+    }).encode()
+    with urllib.request.urlopen(os.environ["STS_ENDPOINT"], body, timeout=30) as r:
+        root = ET.fromstring(r.read())
+    creds = {}
+    for e in root.iter():
+        name = e.tag.rsplit("}", 1)[-1]
+        if name in ("AccessKeyId", "SecretAccessKey", "SessionToken"):
+            creds[name] = e.text
+    return creds
+
+def sign(key, msg):
+    return hmac.new(key, msg.encode(), hashlib.sha256).digest()
+
+def put(creds, key, data):
+    endpoint = urllib.parse.urlparse(os.environ["S3_ENDPOINT"])
+    region = os.environ["AWS_REGION"]
+    path = urllib.parse.quote("%s/%s/%s" % (endpoint.path.rstrip("/"), os.environ["S3_BUCKET"], key), safe="/~")
+    now = datetime.datetime.utcnow()
+    amz_date = now.strftime("%Y%m%dT%H%M%SZ")
+    scope = "%s/%s/s3/aws4_request" % (now.strftime("%Y%m%d"), region)
+    payload = hashlib.sha256(data).hexdigest()
+    headers = {
+        "host": endpoint.netloc,
+        "x-amz-content-sha256": payload,
+        "x-amz-date": amz_date,
+        "x-amz-security-token": creds["SessionToken"],
+    }
+    signed = ";".join(sorted(headers))
+    canonical = "\n".join(["PUT", path, ""] +
+                          ["%s:%s" % (k, headers[k]) for k in sorted(headers)] +
+                          ["", signed, payload])
+    to_sign = "\n".join(["AWS4-HMAC-SHA256", amz_date, scope, hashlib.sha256(canonical.encode()).hexdigest()])
+    k = sign(("AWS4" + creds["SecretAccessKey"]).encode(), now.strftime("%Y%m%d"))
+    for part in (region, "s3", "aws4_request"):
+        k = sign(k, part)
+    headers["Authorization"] = "AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s" % (
+        creds["AccessKeyId"], scope, signed, hmac.new(k, to_sign.encode(), hashlib.sha256).hexdigest())
+    req = urllib.request.Request("%s://%s%s" % (endpoint.scheme, endpoint.netloc, path), data=data, headers=headers, method="PUT")
+    with urllib.request.urlopen(req, timeout=60) as r:
+        print("Uploaded %s: %d" % (key, r.status))
+
+creds = assume_role()
+for name in sorted(os.listdir(RINGS)):
+    path = os.path.join(RINGS, name)
+    if name.startswith(".") or not os.path.isfile(path):
+        continue
+    with open(path, "rb") as f:
+        put(creds, "%s%s/%s" % (os.environ.get("S3_PREFIX", ""), os.environ["RING_MD5"], name), f.read())
+'
@@ -4,25 +4,243 @@ cd /etc/swift
 cp -t /etc/swift/ /var/lib/config-data/swiftconf/*
 tar -xvzf /var/lib/config-data/rings/swiftrings.tar.gz -C /etc/swift/
 
-for f in account.builder container.builder object.builder; do
+# The builder files are envelope encrypted with ACTIVE_KEY if ring encryption
+# is enabled, they are never stored unencrypted. The keys are files named
+# after the keys, mounted from the Secret or fetched from Barbican into a
+# memory backed volume. The format is a JSON document with the data key
+# wrapped by keyId, both AES-256-GCM with the nonce prepended.
+KEYS=/var/lib/config-data/ring-keys
+BUILDERS=/var/lib/config-data/rings/swiftbuilders.enc
+fetch_keys() {
+	python3 -c '
+import json, os, sys, urllib.request
+
+auth = {"auth": {
+    "identity": {"methods": ["password"], "password": {"user": {
+        "name": os.environ["OS_USERNAME"], "domain": {"name": "Default"},
+        "password": os.environ["OS_PASSWORD"]}}},
+    "scope": {"project": {"name": "service", "domain": {"name": "Default"}}}}}
+req = urllib.request.Request(os.environ["OS_AUTH_URL"].rstrip("/") + "/v3/auth/tokens",
+                             data=json.dumps(auth).encode(), headers={"Content-Type": "application/json"})
+with urllib.request.urlopen(req, timeout=30) as r:
+    token = r.headers["X-Subject-Token"]
+    catalog = json.load(r)["token"]["catalog"]
+barbican = [e["url"] for s in catalog if s["type"] == "key-manager"
+            for e in s["endpoints"] if e["interface"] == "internal"][0]
+for entry in os.environ["BARBICAN_KEYS"].split(","):
+    name, uuid = entry.split(":", 1)
+    req = urllib.request.Request("%s/v1/secrets/%s/payload" % (barbican.rstrip("/"), uuid),
+                                 headers={"X-Auth-Token": token, "Accept": "application/octet-stream"})
+    with urllib.request.urlopen(req, timeout=30) as r:
+        key = r.read()
+    with open(os.path.join(sys.argv[1], name), "wb") as f:
+        f.write(key)
+' "$@"
+}
+crypt_builders() {
+	python3 -c '
+import base64, json, os, sys
+from cryptography.hazmat.primitives.ciphers.aead import AESGCM
+
+def key(name):
+    with open(os.path.join(sys.argv[2], name), "rb") as f:
+        k = f.read()
+    if len(k) != 32:
+        sys.exit("key %s must be 32 bytes long" % name)
+    return AESGCM(k)
+
+if sys.argv[1] == "decrypt":
+    with open(sys.argv[3]) as f:
+        e = json.load(f)
+    wrapped = base64.b64decode(e["wrappedKey"])
+    data_key = key(e["keyId"]).decrypt(wrapped[:12], wrapped[12:], None)
+    data = base64.b64decode(e["data"])
+    sys.stdout.buffer.write(AESGCM(data_key).decrypt(data[:12], data[12:], None))
+else:
+    key_id = sys.argv[3]
+    data_key = AESGCM.generate_key(bit_length=256)
+    nonce, key_nonce = os.urandom(12), os.urandom(12)
+    data = nonce + AESGCM(data_key).encrypt(nonce, sys.stdin.buffer.read(), None)
+    wrapped = key_nonce + key(key_id).encrypt(key_nonce, data_key, None)
+    print(json.dumps({"version": 1, "keyId": key_id,
+                      "wrappedKey": base64.b64encode(wrapped).decode(),
+                      "data": base64.b64encode(data).decode()}))
+' "$@"
+}
+
+if [ -n "${BARBICAN_KEYS}" ]; then
+	fetch_keys ${KEYS} || exit 1
+fi
+if [ -s ${BUILDERS} ]; then
+	if [ -z "${ACTIVE_KEY}" ]; then
+		echo "The ring builders are encrypted but ring encryption is disabled"
+		exit 1
+	fi
+	crypt_builders decrypt ${KEYS} ${BUILDERS} > /tmp/swiftbuilders.tar.gz || exit 1
+	tar -xvzf /tmp/swiftbuilders.tar.gz -C /etc/swift/ || exit 1
+	rm -f /tmp/swiftbuilders.tar.gz
+fi
+if [ -n "${ACTIVE_KEY}" ] && [ ! -s ${KEYS}/${ACTIVE_KEY} ]; then
+	echo "Active key ${ACTIVE_KEY} not found"
+	exit 1
+fi
+
+# The object rings are created with PART_POWER, the part power of existing
+# rings is only changed by the PART_POWER_ACTION steps
+for f in account.builder container.builder; do
 	[ ! -e $f ] && swift-ring-builder $f create 8 ${SWIFT_REPLICAS} 1
 done
+[ ! -e object.builder ] && swift-ring-builder object.builder create ${PART_POWER:-8} ${SWIFT_REPLICAS} 1
 
-for DEV in $(cat /var/lib/config-data/ring-devices/devices.csv); do
-	HOST=$(echo $DEV | cut -f1 -d,)
-	DEVICE_NAME=$(echo $DEV | cut -f2 -d,)
-	WEIGHT=$(echo $DEV | cut -f3 -d,)
-
-	swift-ring-builder account.builder add --region 1 --zone 1 --ip $HOST --port 6202 --device $DEVICE_NAME --weight $WEIGHT
-	swift-ring-builder container.builder add --region 1 --zone 1 --ip $HOST --port 6201 --device $DEVICE_NAME --weight $WEIGHT
-	swift-ring-builder object.builder add --region 1 --zone 1 --ip $HOST --port 6200 --device $DEVICE_NAME --weight $WEIGHT
+# Erasure coding storage policies use an object-<index> ring with one
+# replica per fragment, STORAGE_POLICIES are "<index>:<replicas>" entries
+OBJECT_RINGS="object:6200"
+for POLICY in ${STORAGE_POLICIES}; do
+	f=object-${POLICY%:*}.builder
+	[ ! -e $f ] && swift-ring-builder $f create ${PART_POWER:-8} ${POLICY#*:} 1
+	OBJECT_RINGS="${OBJECT_RINGS} object-${POLICY%:*}:6200"
 done
 
+# A part power increase step only changes the part power of the object
+# rings, the devices are not changed until the increase is finished.
+# PART_POWER_ACTION is prepare_increase_partition_power,
+# increase_partition_power or finish_increase_partition_power.
+if [ -n "${PART_POWER_ACTION}" ]; then
+	for f in object*.builder; do
+		swift-ring-builder $f ${PART_POWER_ACTION}
+		# Exit code 1 is a warning
+		if [ $? -gt 1 ]; then
+			exit 1
+		fi
+	done
+else
+	# FAILED_DEVICES are "<host>/<device>" entries of failed devices, they are
+	# removed from the rings until their PVC is replaced
+	DEVICES=/var/lib/config-data/ring-devices/devices.csv
+	if [ -n "${FAILED_DEVICES}" ]; then
+		cp ${DEVICES} /etc/swift/devices.csv
+		DEVICES=/etc/swift/devices.csv
+		for F in ${FAILED_DEVICES}; do
+			grep -v "^${F%%/*},${F#*/}," ${DEVICES} > ${DEVICES}.tmp
+			mv ${DEVICES}.tmp ${DEVICES}
+		done
+	fi
+
+	# Devices of the device inventory have additional port, region and zone
+	# fields, the port is the one of the object server. Devices placed in the
+	# zone of their node have an empty port. The zone of a device already in a
+	# ring is not changed. Devices of a storage tier have a seventh field with
+	# the ":" separated kinds of the rings they are added to.
+	for DEV in $(cat ${DEVICES}); do
+		HOST=$(echo $DEV | cut -f1 -d,)
+		DEVICE_NAME=$(echo $DEV | cut -f2 -d,)
+		WEIGHT=$(echo $DEV | cut -f3 -d,)
+		BASE_PORT=$(echo $DEV | cut -s -f4 -d,)
+		REGION=$(echo $DEV | cut -s -f5 -d,)
+		ZONE=$(echo $DEV | cut -s -f6 -d,)
+		RINGS=$(echo $DEV | cut -s -f7 -d,)
+
+		# DEVICE_WEIGHTS are "<host>/<device>:<weight>" entries replacing the
+		# weight of the device list
+		for W in ${DEVICE_WEIGHTS}; do
+			[ "${W%:*}" = "${HOST}/${DEVICE_NAME}" ] && WEIGHT=${W##*:}
+		done
+
+		for RING in account:6202 container:6201 ${OBJECT_RINGS}; do
+			f=${RING%:*}.builder
+			KIND=${RING%%[-:]*}
+			if [ -n "${RINGS}" ]; then
+				case ":${RINGS}:" in
+				*":${KIND}:"*) ;;
+				*) continue ;;
+				esac
+			fi
+			PORT=${RING#*:}
+			[ -n "${BASE_PORT}" ] && PORT=$((BASE_PORT + PORT - 6200))
+			if swift-ring-builder $f search --ip $HOST --port $PORT --device $DEVICE_NAME >/dev/null 2>&1; then
+				swift-ring-builder $f set_weight --ip $HOST --port $PORT --device $DEVICE_NAME $WEIGHT
+			else
+				swift-ring-builder $f add --region ${REGION:-1} --zone ${ZONE:-1} --ip $HOST --port $PORT --device $DEVICE_NAME --weight $WEIGHT
+			fi
+		done
+	done
+
+	# Devices no longer in the device list were drained before, they are removed
+	# from the rings. Devices of a storage tier are removed from the rings of
+	# the other tier.
+	for f in *.builder; do
+		KIND=${f%%[-.]*}
+		for DEV in $(python3 -c '
+import sys
+from swift.common.ring import RingBuilder
+for d in RingBuilder.load(sys.argv[1]).devs:
+    if d:
+        print("%s,%s,%s" % (d["ip"], d["port"], d["device"]))
+' $f); do
+			HOST=$(echo $DEV | cut -f1 -d,)
+			PORT=$(echo $DEV | cut -f2 -d,)
+			DEVICE_NAME=$(echo $DEV | cut -f3 -d,)
+			LINE=$(grep "^${HOST},${DEVICE_NAME}," ${DEVICES} | head -n 1)
+			RINGS=$(echo $LINE | cut -s -f7 -d,)
+			if [ -z "${LINE}" ]; then
+				swift-ring-builder $f remove --ip $HOST --port $PORT --device $DEVICE_NAME
+			elif [ -n "${RINGS}" ]; then
+				case ":${RINGS}:" in
+				*":${KIND}:"*) ;;
+				*) swift-ring-builder $f remove --ip $HOST --port $PORT --device $DEVICE_NAME ;;
+				esac
+			fi
+		done
+	done
+
+	# The devices of pods removed by a scale down are listed by the SwiftStorage
+	# controller, all their partitions are moved at once instead of one replica
+	# per min_part_hours. Only the builders with such a device skip it.
+	SCALE_DOWN=/var/lib/config-data/ring-devices/scale-down.csv
+
+	for f in *.builder; do
+		DRAIN=""
+		if [ -s ${SCALE_DOWN} ]; then
+			for DEV in $(python3 -c '
+import sys
+from swift.common.ring import RingBuilder
+for d in RingBuilder.load(sys.argv[1]).devs:
+    if d:
+        print("%s,%s" % (d["ip"], d["device"]))
+' $f); do
+				grep -qx "${DEV}" ${SCALE_DOWN} && DRAIN=1
+			done
+		fi
+		[ -n "${DRAIN}" ] && swift-ring-builder $f pretend_min_part_hours_passed
+		swift-ring-builder $f rebalance
+		# Exit code 1 is a warning, e.g. no partitions moved within min_part_hours
+		if [ $? -gt 1 ]; then
+			exit 1
+		fi
+	done
+fi
+
+# swift-ring-builder writes a backup of each builder per rebalance, only the
+# last ones are kept so the ConfigMap stays below the object size limit
+BUILDER_BACKUPS=3
 for f in *.builder; do
-	swift-ring-builder $f rebalance
+	ls -1 backups 2>/dev/null | grep "^[0-9]*\.${f}$" | sort -t. -k1,1nr | \
+		tail -n +$((BUILDER_BACKUPS + 1)) | sed 's|^|backups/|' | xargs -r rm -f
 done
 
-TARFILE=`tar cvz *.builder *.ring.gz backups/*.builder | /usr/bin/base64 -w 0`
+if [ -n "${ACTIVE_KEY}" ]; then
+	TARFILE=`tar cvz *.ring.gz | /usr/bin/base64 -w 0`
+	BUILDERS_ENC=`tar cvz *.builder backups/*.builder | crypt_builders encrypt ${KEYS} ${ACTIVE_KEY} | /usr/bin/base64 -w 0`
+	if [ -z "${BUILDERS_ENC}" ]; then
+		echo "Encrypting the ring builders failed"
+		exit 1
+	fi
+	BUILDERS_DATA=',
+		"swiftbuilders.enc": "'${BUILDERS_ENC}'"'
+else
+	TARFILE=`tar cvz *.builder *.ring.gz backups/*.builder | /usr/bin/base64 -w 0`
+	BUILDERS_DATA=""
+fi
 
 CONFIGMAP_JSON='{
 	"apiVersion":"v1",
@@ -40,7 +258,7 @@ CONFIGMAP_JSON='{
 		]
 	},
 	"binaryData":{
-		"swiftrings.tar.gz": "'${TARFILE}'"
+		"swiftrings.tar.gz": "'${TARFILE}'"'"${BUILDERS_DATA}"'
 	}
 }'
 
@@ -0,0 +1,70 @@
+#!/bin/sh
+# Run the given background daemon unless it is paused by a scaling schedule.
+# The object auditor is also paused outside of the scrub slot of the pod.
+# The state is read from the mounted config ConfigMap, which is updated in
+# place, pausing and resuming does not restart the pod. A new restart token
+# of the daemon restarts it with the current config.
+STATE=/var/lib/config-data/default/background-daemons
+SCRUB=/var/lib/config-data/default/scrub
+RESTART=/var/lib/config-data/default/restart-daemons
+ORDINAL=${HOSTNAME##*-}
+NAME=$(basename "$1")
+NAME=${NAME#swift-}
+PID=""
+
+paused() {
+	[ "$(cat ${STATE} 2>/dev/null)" = "pause" ] && return 0
+	case "$1" in
+	*/swift-object-auditor)
+		scrub=$(cat ${SCRUB} 2>/dev/null)
+		[ "${scrub}" = "all" ] && return 1
+		for o in ${scrub}; do
+			[ "${o}" = "${ORDINAL}" ] && return 1
+		done
+		return 0
+		;;
+	esac
+	return 1
+}
+
+restart_token() {
+	awk -v name="${NAME}" '$1 == name { print $2 }' ${RESTART} 2>/dev/null
+}
+
+# Re-run swift-init to apply the current config, one daemon at a time as
+# they share /etc/swift
+refresh_config() {
+	POD_NAME=${HOSTNAME} flock /etc/swift/.swift-init.lock \
+		/usr/local/bin/container-scripts/swift-init.sh >/dev/null
+}
+
+trap '[ -n "${PID}" ] && kill ${PID} 2>/dev/null; exit 0' TERM INT
+
+TOKEN=$(restart_token)
+while true; do
+	while paused "$1"; do
+		sleep 10
+	done
+	"$@" &
+	PID=$!
+	while kill -0 ${PID} 2>/dev/null && ! paused "$1" && [ "$(restart_token)" = "${TOKEN}" ]; do
+		sleep 10
+	done
+	if kill -0 ${PID} 2>/dev/null; then
+		if [ "$(restart_token)" != "${TOKEN}" ]; then
+			echo "Restarting $1"
+		else
+			echo "Pausing $1"
+		fi
+		kill ${PID}
+		wait ${PID}
+		PID=""
+	else
+		wait ${PID}
+		exit $?
+	fi
+	if [ "$(restart_token)" != "${TOKEN}" ]; then
+		TOKEN=$(restart_token)
+		refresh_config || exit 1
+	fi
+done
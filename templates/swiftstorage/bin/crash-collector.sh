@@ -0,0 +1,30 @@
+#!/bin/sh
+# Preserve the logs of crashed containers of this pod. The previous log of a
+# restarted container is only available until it restarts again.
+CRASH_DIR="/var/crash/swift"
+API="https://kubernetes.default.svc/api/v1/namespaces/${NAMESPACE}/pods/${POD_NAME}"
+
+# Credentials to be used by curl
+export CURL_CA_BUNDLE=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+TOKEN=$(cat /var/run/secrets/kubernetes.io/serviceaccount/token)
+
+mkdir -p $CRASH_DIR
+
+while true; do
+	/usr/bin/curl -s -H "Authorization: Bearer $TOKEN" $API | python3 -c '
+import json, sys
+for s in json.load(sys.stdin).get("status", {}).get("containerStatuses", []):
+    if s.get("restartCount", 0) > 0:
+        print(s["name"], s["restartCount"])
+' | while read NAME COUNT; do
+		LOGFILE="${CRASH_DIR}/${POD_NAME}-${NAME}-${COUNT}.log"
+		if [ ! -e $LOGFILE ]; then
+			echo "Saving log of crashed container ${NAME} to ${LOGFILE}"
+			/usr/bin/curl -s -H "Authorization: Bearer $TOKEN" \
+				"${API}/log?container=${NAME}&previous=true" > $LOGFILE
+		fi
+	done
+
+	find $CRASH_DIR -type f -mtime +${RETENTION_DAYS} -delete
+	sleep 60
+done
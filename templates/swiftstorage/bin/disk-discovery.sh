@@ -0,0 +1,68 @@
+#!/bin/sh
+# Inventories the local disks of the node and records them as an annotation
+# of this pod, the operator collects them into the disk inventory ConfigMap.
+# Approved disks are formatted, if they have no filesystem yet, and mounted
+# below MOUNT_ROOT. Disks with partitions, mounts or a filesystem not created
+# here are never touched.
+exec python3 -u -c '
+import json, os, ssl, subprocess, time, urllib.request
+
+LABEL = "swift-disk"
+mount_root = os.environ["MOUNT_ROOT"]
+node = os.environ["NODE_NAME"]
+approved = set(os.environ.get("APPROVED_DISKS", "").split())
+uid = int(os.environ["SWIFT_UID"])
+
+def lsblk():
+    out = subprocess.check_output(["lsblk", "-J", "-b", "-o",
+        "NAME,SIZE,ROTA,SERIAL,TYPE,FSTYPE,LABEL,MOUNTPOINT"])
+    return json.loads(out)["blockdevices"]
+
+def get_state(d, target):
+    if d.get("children") or (d.get("fstype") and d.get("label") != LABEL):
+        return "InUse"
+    if d.get("mountpoint"):
+        return "Mounted" if d["mountpoint"] == target else "InUse"
+    return "Formatted" if d.get("fstype") else "Available"
+
+def record(disks):
+    sa = "/var/run/secrets/kubernetes.io/serviceaccount"
+    with open(sa + "/token") as f:
+        sa_token = f.read()
+    patch = json.dumps({"metadata": {"annotations": {
+        "swift.openstack.org/disks": json.dumps(disks)}}}).encode()
+    req = urllib.request.Request(
+        "https://kubernetes.default.svc/api/v1/namespaces/%s/pods/%s" % (
+            os.environ["NAMESPACE"], os.environ["POD_NAME"]),
+        method="PATCH", data=patch, headers={
+            "Authorization": "Bearer " + sa_token,
+            "Content-Type": "application/merge-patch+json"})
+    urllib.request.urlopen(req, context=ssl.create_default_context(cafile=sa + "/ca.crt"), timeout=30)
+
+while True:
+    disks = []
+    try:
+        for d in lsblk():
+            serial = (d.get("serial") or "").strip()
+            if d.get("type") != "disk" or not serial:
+                continue
+            dev = "/dev/" + d["name"]
+            target = os.path.join(mount_root, serial)
+            state = get_state(d, target)
+            if "%s/%s" % (node, serial) in approved and state in ("Available", "Formatted"):
+                if state == "Available":
+                    print("Formatting approved disk %s (%s)" % (dev, serial))
+                    subprocess.check_call(["mkfs.xfs", "-L", LABEL, dev])
+                os.makedirs(target, exist_ok=True)
+                print("Mounting approved disk %s (%s) on %s" % (dev, serial, target))
+                subprocess.check_call(["mount", "-t", "xfs", "-o", "noatime", dev, target])
+                os.chown(target, uid, uid)
+                state = "Mounted"
+            disks.append({"device": d["name"], "size": int(d["size"]),
+                          "rotational": d.get("rota") in (True, "1", 1),
+                          "serial": serial, "state": state})
+        record(disks)
+    except Exception as e:
+        print("Disk discovery failed: %s" % e)
+    time.sleep(60)
+'
@@ -0,0 +1,73 @@
+#!/bin/sh
+# Queries the recon middleware of every storage server in the rings and
+# records the replication lag, unmounted drives and quarantined items as an
+# annotation of this pod, the operator reports it in the SwiftStorage status.
+RINGS=$(mktemp -d)
+tar -xzf /var/lib/config-data/rings/swiftrings.tar.gz -C "${RINGS}" || exit 1
+
+exec python3 -u -c '
+import glob, json, os, ssl, sys, time, urllib.request
+from swift.common.ring import RingData
+
+servers = set()
+for path in glob.glob(os.path.join(sys.argv[1], "*.ring.gz")):
+    servers |= {d["ip"] for d in RingData.load(path).devs if d}
+
+# The servers of a SwiftStorage with TLS are verified with its CA
+try:
+    from sitecustomize import backend_ca
+except ImportError:
+    def backend_ca(host):
+        return None
+
+port = int(os.environ["RECON_PORT"])
+
+def recon(ip, check):
+    ca = backend_ca(ip)
+    scheme = "https" if ca else "http"
+    context = ssl.create_default_context(cafile=ca) if ca else None
+    url = "%s://%s:%d/recon/%s" % (scheme, ip, port, check)
+    with urllib.request.urlopen(url, timeout=10, context=context) as r:
+        return json.load(r)
+
+now = time.time()
+result = {"replicationLag": {}, "unmountedDrives": [], "unreachableServers": [],
+          "quarantinedAccounts": 0, "quarantinedContainers": 0, "quarantinedObjects": 0}
+for ip in sorted(servers):
+    try:
+        for server, key in (("account", "replication_last"), ("container", "replication_last"), ("object", "object_replication_last")):
+            last = recon(ip, "replication/" + server).get(key)
+            if last is None:
+                continue
+            lag = int(now - last)
+            result["replicationLag"][server] = max(result["replicationLag"].get(server, 0), lag)
+        for disk in recon(ip, "unmounted") or []:
+            result["unmountedDrives"].append("%s/%s" % (ip, disk["device"]))
+        quarantined = recon(ip, "quarantined")
+        result["quarantinedAccounts"] += quarantined.get("accounts", 0)
+        result["quarantinedContainers"] += quarantined.get("containers", 0)
+        for count in (quarantined.get("policies") or {}).values():
+            result["quarantinedObjects"] += count.get("objects", 0)
+        if not quarantined.get("policies"):
+            result["quarantinedObjects"] += quarantined.get("objects", 0)
+    except Exception as e:
+        print("%s recon failed: %s" % (ip, e))
+        result["unreachableServers"].append(ip)
+
+result["timestamp"] = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime())
+print(json.dumps(result))
+
+# Record the result on this pod
+sa = "/var/run/secrets/kubernetes.io/serviceaccount"
+with open(sa + "/token") as f:
+    sa_token = f.read()
+patch = json.dumps({"metadata": {"annotations": {
+    "swift.openstack.org/health": json.dumps(result)}}}).encode()
+req = urllib.request.Request(
+    "https://kubernetes.default.svc/api/v1/namespaces/%s/pods/%s" % (
+        os.environ["NAMESPACE"], os.environ["POD_NAME"]),
+    method="PATCH", data=patch, headers={
+        "Authorization": "Bearer " + sa_token,
+        "Content-Type": "application/merge-patch+json"})
+urllib.request.urlopen(req, context=ssl.create_default_context(cafile=sa + "/ca.crt"), timeout=30)
+' "${RINGS}"
@@ -0,0 +1,70 @@
+#!/bin/sh
+# Follow the rsync daemon log, print it to stdout and expose the transferred
+# bytes, transfers and errors as Prometheus metrics on METRICS_PORT. The log
+# file is truncated once it grows over 10MB.
+exec python3 -u -c '
+import http.server, os, re, threading, time
+
+LOG = "/var/log/rsync/rsyncd.log"
+MAX_SIZE = 10 * 1024 * 1024
+TRANSFER = re.compile(r"\] transfer (\S+) (\S+) (\d+) ")
+
+lock = threading.Lock()
+transfer_bytes = {}
+transfers = {}
+errors = [0]
+
+def follow():
+    pos = 0
+    while True:
+        try:
+            if os.path.getsize(LOG) < pos:
+                pos = 0
+            with open(LOG, errors="replace") as f:
+                f.seek(pos)
+                while True:
+                    line = f.readline()
+                    if not line.endswith("\n"):
+                        break
+                    pos = f.tell()
+                    print(line, end="")
+                    m = TRANSFER.search(line)
+                    with lock:
+                        if m:
+                            key = (m.group(2), m.group(1))
+                            transfer_bytes[key] = transfer_bytes.get(key, 0) + int(m.group(3))
+                            transfers[key] = transfers.get(key, 0) + 1
+                        elif "rsync error:" in line or "rsync: " in line:
+                            errors[0] += 1
+            if pos > MAX_SIZE:
+                os.truncate(LOG, 0)
+                pos = 0
+        except OSError:
+            pass
+        time.sleep(1)
+
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_GET(self):
+        lines = []
+        with lock:
+            lines.append("# TYPE swift_rsync_transfer_bytes_total counter")
+            for (module, op), v in sorted(transfer_bytes.items()):
+                lines.append("swift_rsync_transfer_bytes_total{module=\"%s\",operation=\"%s\"} %d" % (module, op, v))
+            lines.append("# TYPE swift_rsync_transfers_total counter")
+            for (module, op), v in sorted(transfers.items()):
+                lines.append("swift_rsync_transfers_total{module=\"%s\",operation=\"%s\"} %d" % (module, op, v))
+            lines.append("# TYPE swift_rsync_errors_total counter")
+            lines.append("swift_rsync_errors_total %d" % errors[0])
+        body = ("\n".join(lines) + "\n").encode()
+        self.send_response(200)
+        self.send_header("Content-Type", "text/plain; version=0.0.4")
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+    def log_message(self, *args):
+        pass
+
+threading.Thread(target=follow, daemon=True).start()
+http.server.HTTPServer(("", int(os.environ["METRICS_PORT"])), Handler).serve_forever()
+'
@@ -0,0 +1,86 @@
+#!/bin/sh
+# Expose the replication lag, async pendings, quarantined items and disk
+# usage of the devices in NODE_ROOT as Prometheus metrics on METRICS_PORT.
+# The replication times are read from the recon cache of the replicators,
+# the expiration tasks due from the expiring objects account.
+exec python3 -u -c '
+import glob, http.server, json, os, time
+
+NODE_ROOT = os.environ["NODE_ROOT"]
+RECON = "/var/cache/swift"
+REPLICATION = (("account", "replication_last"), ("container", "replication_last"), ("object", "object_replication_last"))
+EXPIRER_INTERVAL = 60
+expirer_cache = [0, None]
+
+def recon(name):
+    try:
+        with open(os.path.join(RECON, name + ".recon")) as f:
+            return json.load(f)
+    except (OSError, ValueError):
+        return {}
+
+def expirer_tasks_due():
+    # The listing of the expiring objects account is not cheap, it is
+    # cached for EXPIRER_INTERVAL seconds
+    now = time.time()
+    if now - expirer_cache[0] < EXPIRER_INTERVAL:
+        return expirer_cache[1]
+    expirer_cache[0] = now
+    try:
+        from swift.common.internal_client import InternalClient
+        client = InternalClient("/etc/swift/internal-client.conf", "swift-operator", 3)
+        expirer_cache[1] = sum(
+            c["count"] for c in client.iter_containers(".expiring_objects")
+            if c["name"].isdigit() and int(c["name"]) <= now)
+    except Exception:
+        expirer_cache[1] = None
+    return expirer_cache[1]
+
+def metrics():
+    lines = []
+    now = time.time()
+    lines.append("# TYPE swift_replication_lag_seconds gauge")
+    for server, key in REPLICATION:
+        last = recon(server).get(key)
+        if last:
+            lines.append("swift_replication_lag_seconds{server=\"%s\"} %d" % (server, now - float(last)))
+
+    devices = sorted(d for d in os.listdir(NODE_ROOT) if os.path.isdir(os.path.join(NODE_ROOT, d)))
+    lines.append("# TYPE swift_async_pendings gauge")
+    for d in devices:
+        count = 0
+        for path in glob.glob(os.path.join(NODE_ROOT, d, "async_pending*")):
+            for _, _, files in os.walk(path):
+                count += len(files)
+        lines.append("swift_async_pendings{device=\"%s\"} %d" % (d, count))
+    lines.append("# TYPE swift_quarantined_items gauge")
+    for d in devices:
+        for item in ("accounts", "containers", "objects"):
+            count = len(glob.glob(os.path.join(NODE_ROOT, d, "quarantined", item + "*", "*")))
+            lines.append("swift_quarantined_items{device=\"%s\",type=\"%s\"} %d" % (d, item, count))
+    lines.append("# TYPE swift_disk_size_bytes gauge")
+    lines.append("# TYPE swift_disk_used_bytes gauge")
+    for d in devices:
+        st = os.statvfs(os.path.join(NODE_ROOT, d))
+        lines.append("swift_disk_size_bytes{device=\"%s\"} %d" % (d, st.f_blocks * st.f_frsize))
+        lines.append("swift_disk_used_bytes{device=\"%s\"} %d" % (d, (st.f_blocks - st.f_bfree) * st.f_frsize))
+    tasks = expirer_tasks_due()
+    if tasks is not None:
+        lines.append("# TYPE swift_expirer_tasks_due gauge")
+        lines.append("swift_expirer_tasks_due %d" % tasks)
+    return lines
+
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_GET(self):
+        body = ("\n".join(metrics()) + "\n").encode()
+        self.send_response(200)
+        self.send_header("Content-Type", "text/plain; version=0.0.4")
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+    def log_message(self, *args):
+        pass
+
+http.server.HTTPServer(("", int(os.environ["METRICS_PORT"])), Handler).serve_forever()
+'